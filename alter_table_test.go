@@ -0,0 +1,70 @@
+package dynamosql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlterTableExec(t *testing.T) {
+	ctx := context.Background()
+	newMock := func(updates *[]*dynamodb.UpdateTableInput) *mockDynamoDB {
+		return &mockDynamoDB{
+			tables: map[string]*dynamodb.CreateTableInput{
+				"movies": {
+					TableName: aws.String("movies"),
+					AttributeDefinitions: []*dynamodb.AttributeDefinition{
+						{AttributeName: aws.String("title"), AttributeType: aws.String("S")},
+						{AttributeName: aws.String("year"), AttributeType: aws.String("N")},
+					},
+					KeySchema: []*dynamodb.KeySchemaElement{
+						{AttributeName: aws.String("title"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+						{AttributeName: aws.String("year"), KeyType: aws.String(dynamodb.KeyTypeRange)},
+					},
+				},
+			},
+			updateTable: func(ctx aws.Context, in *dynamodb.UpdateTableInput) (*dynamodb.UpdateTableOutput, error) {
+				*updates = append(*updates, in)
+				return &dynamodb.UpdateTableOutput{}, nil
+			},
+		}
+	}
+
+	t.Run("index keys are defined from the table", func(t *testing.T) {
+		var updates []*dynamodb.UpdateTableInput
+		c := newMockConn(newMock(&updates))
+		_, err := c.ExecContext(ctx, `ALTER TABLE movies ADD director STRING, `+
+			`ADD GLOBAL SECONDARY INDEX director_year HASH(director) RANGE(year) PROJECTION ALL`, nil)
+		require.NoError(t, err)
+		require.Len(t, updates, 1)
+		require.Equal(t, []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("director"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("year"), AttributeType: aws.String("N")},
+		}, updates[0].AttributeDefinitions)
+	})
+
+	t.Run("undeclared index keys are rejected", func(t *testing.T) {
+		var updates []*dynamodb.UpdateTableInput
+		c := newMockConn(newMock(&updates))
+		_, err := c.ExecContext(ctx, `ALTER TABLE movies ADD GLOBAL SECONDARY INDEX director_year HASH(director) RANGE(year) PROJECTION ALL`, nil)
+		require.EqualError(t, err, `the type of attribute "director" of index "director_year" is unknown, declare it with ADD director STRING, NUMBER or BINARY`)
+		require.Empty(t, updates)
+	})
+
+	t.Run("throughput", func(t *testing.T) {
+		var updates []*dynamodb.UpdateTableInput
+		c := newMockConn(newMock(&updates))
+		_, err := c.ExecContext(ctx, `ALTER TABLE movies SET PROVISIONED THROUGHPUT READ 10 WRITE 20`, nil)
+		require.NoError(t, err)
+		require.Equal(t, &dynamodb.UpdateTableInput{
+			TableName: aws.String("movies"),
+			ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(10),
+				WriteCapacityUnits: aws.Int64(20),
+			},
+		}, updates[0])
+	})
+}
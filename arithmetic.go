@@ -0,0 +1,85 @@
+package dynamosql
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// evalArithmetic computes a computed column for the item. It is nil if an operand is not a number, including an
+// attribute that the item does not have, or if it divides by zero, so that one item can't fail the whole query.
+func evalArithmetic(expr *parser.Arithmetic, item map[string]*dynamodb.AttributeValue) *dynamodb.AttributeValue {
+	result := evalArithmeticRat(expr, &dynamodb.AttributeValue{M: item})
+	if result == nil {
+		return nil
+	}
+	if result.IsInt() {
+		return &dynamodb.AttributeValue{N: aws.String(result.Num().String())}
+	}
+	f, _ := result.Float64()
+	return &dynamodb.AttributeValue{N: aws.String(strconv.FormatFloat(f, 'g', -1, 64))}
+}
+
+// evalArithmeticRat computes the operations exactly, with * and / applied before + and -.
+func evalArithmeticRat(expr *parser.Arithmetic, doc *dynamodb.AttributeValue) *big.Rat {
+	sum := new(big.Rat)
+	sign := "+"
+	product := evalArithmeticOperand(expr.Operand, doc)
+	if product == nil {
+		return nil
+	}
+	add := func() {
+		if sign == "-" {
+			sum.Sub(sum, product)
+		} else {
+			sum.Add(sum, product)
+		}
+	}
+	for _, op := range expr.Ops {
+		operand := evalArithmeticOperand(op.Operand, doc)
+		if operand == nil {
+			return nil
+		}
+		switch op.Operator {
+		case "*":
+			product.Mul(product, operand)
+		case "/":
+			if operand.Sign() == 0 {
+				return nil
+			}
+			product.Quo(product, operand)
+		default:
+			// A + or - ends the product before it.
+			add()
+			sign, product = op.Operator, operand
+		}
+	}
+	add()
+	return sum
+}
+
+func evalArithmeticOperand(operand *parser.ArithmeticOperand, doc *dynamodb.AttributeValue) *big.Rat {
+	var n string
+	switch {
+	case operand.Parenthesized != nil:
+		return evalArithmeticRat(operand.Parenthesized, doc)
+	case operand.Number != nil:
+		// Parsed as written rather than converted with SetFloat64, so that 0.1 is exactly a tenth.
+		n = operand.NumberLiteral()
+	default:
+		av := pluckAttributeValue(doc, operand.DocumentPath)
+		if av == nil || av.N == nil {
+			return nil
+		}
+		n = *av.N
+	}
+	r, ok := new(big.Rat).SetString(n)
+	if !ok {
+		return nil
+	}
+	return r
+}
@@ -0,0 +1,105 @@
+// Package builder builds statements as *parser.AST nodes in code, rather than by concatenating SQL strings.
+//
+//	query, args, err := builder.Select("title", "year").
+//		From("movies").
+//		Where(builder.Eq("title", title), builder.Gt("year", 1990)).
+//		Limit(10).
+//		SQL()
+//	rows, err := db.QueryContext(ctx, query, args...)
+//
+// Values are never written into the statement. Each one is bound to a generated named placeholder, :v1, :v2 and so
+// on, and returned in args as a sql.NamedArg. Use Param to refer to a placeholder that is bound later instead, such
+// as when the same statement is prepared once and run with different values.
+//
+// Attributes are document paths, written the same way as in SQL, such as info.rating, info.actors[0] or
+// info['release date']. Use Attr to refer to a top level attribute whose name is not an identifier.
+package builder
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// Param is a named placeholder that is bound when the statement is run, rather than a value.
+type Param string
+
+// Attr returns a path to the top level attribute with the given name, which may contain dots, spaces and other
+// characters that are not allowed in an identifier. It can be followed by the rest of a path, as in
+// Attr("first.name") + "[0]".
+func Attr(name string) string {
+	return "`" + name + "`"
+}
+
+// state is shared by everything that builds a single statement. It collects the values bound by the statement and
+// the first error.
+type state struct {
+	args   []interface{}
+	params map[string]bool
+	err    error
+}
+
+func (s *state) fail(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *state) path(path string) *parser.DocumentPath {
+	parsed, err := parser.ParsePath(path)
+	if err != nil {
+		s.fail(fmt.Errorf("invalid path %q: %w", path, err))
+		return &parser.DocumentPath{Fragment: []*parser.PathFragment{{Symbol: path}}}
+	}
+	return parsed
+}
+
+// value returns the placeholder that v is bound to.
+func (s *state) value(v interface{}) *parser.Value {
+	if param, ok := v.(Param); ok {
+		name := strings.TrimPrefix(string(param), ":")
+		if s.params == nil {
+			s.params = map[string]bool{}
+		}
+		s.params[name] = true
+		return &parser.Value{PlaceHolder: placeholder(name)}
+	}
+	name := fmt.Sprintf("v%d", len(s.args)+1)
+	s.args = append(s.args, sql.Named(name, v))
+	return &parser.Value{PlaceHolder: placeholder(name)}
+}
+
+func (s *state) operand(v interface{}) *parser.Operand {
+	return &parser.Operand{Value: s.value(v)}
+}
+
+// finish validates the statement, the same way as one that is parsed, and returns the values it binds.
+func (s *state) finish(ast *parser.AST) (*parser.AST, []interface{}, error) {
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+	for _, arg := range s.args {
+		if name := arg.(sql.NamedArg).Name; s.params[name] {
+			return nil, nil, fmt.Errorf("parameter :%s clashes with a generated placeholder, use another name", name)
+		}
+	}
+	if err := parser.Validate(ast); err != nil {
+		return nil, nil, err
+	}
+	return ast, s.args, nil
+}
+
+// format returns the SQL of a built statement.
+func format(ast *parser.AST, args []interface{}, err error) (string, []interface{}, error) {
+	if err != nil {
+		return "", nil, err
+	}
+	return parser.Format(ast), args, nil
+}
+
+func placeholder(name string) *string {
+	name = ":" + name
+	return &name
+}
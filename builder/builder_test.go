@@ -0,0 +1,121 @@
+package builder
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/querybuilder"
+	"github.com/mightyguava/dynamosql/schema"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+func TestBuilders(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		builder interface {
+			SQL() (string, []interface{}, error)
+		}
+		sql  string
+		args []interface{}
+	}{
+		{"select",
+			Select("GameTitle", "Name['first name']", Attr("foo.bar")).From("gamescores").
+				Where(Eq("UserId", "103"), Gt("TopScore", 1000)).Where(Or(IsNull("Wins"), Not(In("Losses", 1, 2)))).
+				Limit(10).Desc().Consistent(),
+			`SELECT GameTitle, Name["first name"], ` + "`foo.bar`" + ` FROM gamescores WHERE UserId = :v1 AND TopScore > :v2 AND (Wins IS NULL OR NOT Losses IN (:v3, :v4)) DESC LIMIT 10 WITH (CONSISTENT)`,
+			[]interface{}{sql.Named("v1", "103"), sql.Named("v2", 1000), sql.Named("v3", 1), sql.Named("v4", 2)}},
+		{"select count with functions and params",
+			SelectCount().From("gamescores").Index("UserWinsIndex").
+				Where(Eq("UserId", Param("user")), Between("Wins", 1, Param("max")), BeginsWith("GameTitle", "Galaxy"),
+					And(Size("Scores", ">=", 3), AttributeType("Name", "M"), AttributeNotExists("deleted"))).
+				OrderBy("Wins").Desc().Offset(5),
+			`SELECT COUNT(*) FROM gamescores USE INDEX (UserWinsIndex) WHERE UserId = :user AND Wins BETWEEN :v1 AND :max AND begins_with(GameTitle, :v2) AND (size(Scores) >= :v3 AND attribute_type(Name, "M") AND attribute_not_exists(deleted)) ORDER BY Wins DESC OFFSET 5`,
+			[]interface{}{sql.Named("v1", 1), sql.Named("v2", "Galaxy"), sql.Named("v3", 3)}},
+		{"scan",
+			Select("Wins").From("gamescores").Where(Eq("UserId", "103")).Scan().Segments(2),
+			`SELECT Wins FROM gamescores WHERE UserId = :v1 WITH (SCAN, SEGMENTS = 2)`,
+			[]interface{}{sql.Named("v1", "103")}},
+		{"insert",
+			Insert("movies").Values(map[string]interface{}{"title": "Heat"}, map[string]interface{}{"title": "Ronin"}),
+			`INSERT INTO movies VALUES (:v1)`,
+			[]interface{}{sql.Named("v1", []interface{}{map[string]interface{}{"title": "Heat"}, map[string]interface{}{"title": "Ronin"}})}},
+		{"insert param",
+			Insert("movies").Values(Param("movies")),
+			`INSERT INTO movies VALUES (:movies)`,
+			nil},
+		{"replace",
+			Replace("movies").Values(map[string]interface{}{"title": "Heat"}),
+			`REPLACE INTO movies VALUES (:v1)`,
+			[]interface{}{sql.Named("v1", map[string]interface{}{"title": "Heat"})}},
+		{"update",
+			Update("movies").Set("info.rating", 7.5).Add("views", 1).Remove("plot", "info.actors[0]").Delete("tags", Param("tags")).
+				Where(Eq("title", "Heat"), Eq("year", 1995), Contains("tags", "crime")),
+			`UPDATE movies SET info.rating = :v1 ADD views :v2 REMOVE plot, info.actors[0] DELETE tags :tags WHERE title = :v3 AND year = :v4 AND contains(tags, :v5)`,
+			[]interface{}{sql.Named("v1", 7.5), sql.Named("v2", 1), sql.Named("v3", "Heat"), sql.Named("v4", 1995), sql.Named("v5", "crime")}},
+		{"update version",
+			Update("movies").Set("plot", "A heist").Where(Eq("title", "Heat"), Eq("year", 1995)).Version("version", Param("version")),
+			`UPDATE movies SET plot = :v1 ADD version :v2 WHERE title = :v3 AND year = :v4 AND version = :version`,
+			[]interface{}{sql.Named("v1", "A heist"), sql.Named("v2", 1), sql.Named("v3", "Heat"), sql.Named("v4", 1995)}},
+		{"delete version",
+			Delete("movies").Where(Eq("title", "Heat")).Version("version", 3),
+			`DELETE FROM movies WHERE title = :v1 AND version = :v2`,
+			[]interface{}{sql.Named("v1", "Heat"), sql.Named("v2", 3)}},
+		{"delete",
+			Delete("movies").Where(Eq("title", "Heat"), Eq("year", 1995), AttributeExists("title")),
+			`DELETE FROM movies WHERE title = :v1 AND year = :v2 AND attribute_exists(title)`,
+			[]interface{}{sql.Named("v1", "Heat"), sql.Named("v2", 1995)}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			query, args, err := test.builder.SQL()
+			require.NoError(t, err)
+			require.Equal(t, test.sql, query)
+			require.Equal(t, test.args, args)
+			// The SQL parses back to a statement that formats the same.
+			ast, err := parser.Parse(query)
+			require.NoError(t, err)
+			require.Equal(t, query, parser.Format(ast))
+		})
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		builder interface {
+			Build() (*parser.AST, []interface{}, error)
+		}
+		err string
+	}{
+		{"invalid path", Select("a[").From("t"), `invalid path "a[": 1:3: unexpected token "<EOF>" (expected <number> | <string>)`},
+		{"unknown operator", Select().From("t").Where(Compare("a", "==", 1)), `unknown comparison operator "==", expected =, <>, <, <=, > or >=`},
+		{"empty IN", Select().From("t").Where(In("a")), `IN on "a" needs at least one value`},
+		{"empty OR", Select().From("t").Where(Or()), "AND and OR need at least one condition"},
+		{"param clash", Select().From("t").Where(Eq("a", 1), Eq("b", Param("v1"))), "parameter :v1 clashes with a generated placeholder, use another name"},
+		{"validated like parsed SQL", Select().From("t").Where(AttributeType("a", "MAP")),
+			`unknown attribute type "MAP" in attribute_type(a, "MAP"), expected one of S, N, B, BOOL, NULL, L, M, SS, NS or BS`},
+		{"update without actions", Update("t").Where(Eq("a", 1)), `UPDATE of "t" needs at least one of SET, ADD, REMOVE or DELETE`},
+		{"insert without items", Insert("t"), `INSERT into "t" needs at least one item`},
+		{"insert param with other items", Insert("t").Values(Param("a"), 1), "a Param must be the only item of an INSERT"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := test.builder.Build()
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestBuiltStatementsCompile(t *testing.T) {
+	table := schema.NewTableFromCreate(fixtures.GameScores.Create)
+	built, _, err := Select().From("gamescores").Where(Eq("UserId", Param("user")), Gt("TopScore", 1000)).Build()
+	require.NoError(t, err)
+	parsed, err := parser.Parse(`SELECT * FROM gamescores WHERE UserId = :user AND TopScore > :v1`)
+	require.NoError(t, err)
+	fromBuilt, err := querybuilder.PrepareSelect(table, built.Select)
+	require.NoError(t, err)
+	fromParsed, err := querybuilder.PrepareSelect(table, parsed.Select)
+	require.NoError(t, err)
+	require.Equal(t, fromParsed, fromBuilt)
+}
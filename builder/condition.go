@@ -0,0 +1,200 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// Condition is a condition of a WHERE clause.
+type Condition struct {
+	build func(s *state) *parser.Condition
+}
+
+var operators = map[string]bool{"=": true, "<>": true, "<": true, "<=": true, ">": true, ">=": true}
+
+// Compare compares an attribute with a value, with one of =, <>, <, <=, > or >=.
+func Compare(path, operator string, value interface{}) Condition {
+	return Condition{func(s *state) *parser.Condition {
+		return &parser.Condition{Operand: &parser.ConditionOperand{
+			Operand:      s.path(path),
+			ConditionRHS: compareRHS(s, operator, value),
+		}}
+	}}
+}
+
+// Eq is the condition path = value.
+func Eq(path string, value interface{}) Condition { return Compare(path, "=", value) }
+
+// Ne is the condition path <> value.
+func Ne(path string, value interface{}) Condition { return Compare(path, "<>", value) }
+
+// Lt is the condition path < value.
+func Lt(path string, value interface{}) Condition { return Compare(path, "<", value) }
+
+// Le is the condition path <= value.
+func Le(path string, value interface{}) Condition { return Compare(path, "<=", value) }
+
+// Gt is the condition path > value.
+func Gt(path string, value interface{}) Condition { return Compare(path, ">", value) }
+
+// Ge is the condition path >= value.
+func Ge(path string, value interface{}) Condition { return Compare(path, ">=", value) }
+
+// Between is the condition path BETWEEN start AND end.
+func Between(path string, start, end interface{}) Condition {
+	return Condition{func(s *state) *parser.Condition {
+		return &parser.Condition{Operand: &parser.ConditionOperand{
+			Operand: s.path(path),
+			ConditionRHS: &parser.ConditionRHS{
+				Between: &parser.Between{Start: s.operand(start), End: s.operand(end)},
+			},
+		}}
+	}}
+}
+
+// In is the condition path IN (values...).
+func In(path string, values ...interface{}) Condition {
+	return Condition{func(s *state) *parser.Condition {
+		if len(values) == 0 {
+			s.fail(fmt.Errorf("IN on %q needs at least one value", path))
+		}
+		in := &parser.In{}
+		for _, v := range values {
+			in.Values = append(in.Values, s.value(v))
+		}
+		return &parser.Condition{Operand: &parser.ConditionOperand{
+			Operand:      s.path(path),
+			ConditionRHS: &parser.ConditionRHS{In: in},
+		}}
+	}}
+}
+
+// IsNull is the condition path IS NULL, which is true if the attribute does not exist.
+func IsNull(path string) Condition {
+	return is(path, false)
+}
+
+// IsNotNull is the condition path IS NOT NULL, which is true if the attribute exists.
+func IsNotNull(path string) Condition {
+	return is(path, true)
+}
+
+func is(path string, not bool) Condition {
+	return Condition{func(s *state) *parser.Condition {
+		return &parser.Condition{Operand: &parser.ConditionOperand{
+			Operand:      s.path(path),
+			ConditionRHS: &parser.ConditionRHS{Is: &parser.Is{Not: not}},
+		}}
+	}}
+}
+
+// Size compares the size of an attribute with a value, as in Size("tags", ">", 3).
+func Size(path, operator string, value interface{}) Condition {
+	return Condition{func(s *state) *parser.Condition {
+		return &parser.Condition{Operand: &parser.ConditionOperand{
+			Size:         &parser.FunctionExpression{Function: "size", Args: []*parser.FunctionArgument{{DocumentPath: s.path(path)}}},
+			ConditionRHS: compareRHS(s, operator, value),
+		}}
+	}}
+}
+
+// BeginsWith is the condition begins_with(path, prefix).
+func BeginsWith(path string, prefix interface{}) Condition {
+	return function("begins_with", path, prefix)
+}
+
+// Contains is the condition contains(path, value).
+func Contains(path string, value interface{}) Condition {
+	return function("contains", path, value)
+}
+
+// AttributeExists is the condition attribute_exists(path).
+func AttributeExists(path string) Condition {
+	return function("attribute_exists", path)
+}
+
+// AttributeNotExists is the condition attribute_not_exists(path).
+func AttributeNotExists(path string) Condition {
+	return function("attribute_not_exists", path)
+}
+
+// AttributeType is the condition attribute_type(path, typ), where typ is a DynamoDB type code such as "S" or "SS".
+func AttributeType(path, typ string) Condition {
+	return Condition{func(s *state) *parser.Condition {
+		return &parser.Condition{Function: &parser.FunctionExpression{
+			Function: "attribute_type",
+			Args: []*parser.FunctionArgument{
+				{DocumentPath: s.path(path)},
+				{Value: &parser.Value{Scalar: parser.Scalar{Str: &typ}}},
+			},
+		}}
+	}}
+}
+
+func function(name, path string, values ...interface{}) Condition {
+	return Condition{func(s *state) *parser.Condition {
+		fn := &parser.FunctionExpression{Function: name, Args: []*parser.FunctionArgument{{DocumentPath: s.path(path)}}}
+		for _, v := range values {
+			fn.Args = append(fn.Args, &parser.FunctionArgument{Value: s.value(v)})
+		}
+		return &parser.Condition{Function: fn}
+	}}
+}
+
+// And is true if all of the conditions are true.
+func And(conditions ...Condition) Condition {
+	return Condition{func(s *state) *parser.Condition {
+		return &parser.Condition{Parenthesized: &parser.ParenthesizedExpression{
+			ConditionExpression: &parser.ConditionExpression{Or: []*parser.AndExpression{and(s, conditions)}},
+		}}
+	}}
+}
+
+// Or is true if any of the conditions is true.
+func Or(conditions ...Condition) Condition {
+	return Condition{func(s *state) *parser.Condition {
+		if len(conditions) == 0 {
+			s.fail(errors.New("AND and OR need at least one condition"))
+		}
+		or := &parser.ConditionExpression{}
+		for _, cond := range conditions {
+			or.Or = append(or.Or, and(s, []Condition{cond}))
+		}
+		return &parser.Condition{Parenthesized: &parser.ParenthesizedExpression{ConditionExpression: or}}
+	}}
+}
+
+// Not is true if the condition is false.
+func Not(condition Condition) Condition {
+	return Condition{func(s *state) *parser.Condition {
+		return &parser.Condition{Not: &parser.NotCondition{Condition: condition.build(s)}}
+	}}
+}
+
+func and(s *state, conditions []Condition) *parser.AndExpression {
+	if len(conditions) == 0 {
+		s.fail(errors.New("AND and OR need at least one condition"))
+	}
+	and := &parser.AndExpression{}
+	for _, cond := range conditions {
+		and.And = append(and.And, cond.build(s))
+	}
+	return and
+}
+
+// where returns the WHERE clause for the conditions, which are all true, or nil if there are none.
+func where(s *state, conditions []Condition) *parser.ConditionExpression {
+	if len(conditions) == 0 {
+		return nil
+	}
+	return &parser.ConditionExpression{Or: []*parser.AndExpression{and(s, conditions)}}
+}
+
+func compareRHS(s *state, operator string, value interface{}) *parser.ConditionRHS {
+	if !operators[operator] {
+		s.fail(fmt.Errorf("unknown comparison operator %q, expected =, <>, <, <=, > or >=", operator))
+	}
+	return &parser.ConditionRHS{Compare: &parser.Compare{Operator: operator, Operand: s.operand(value)}}
+}
@@ -0,0 +1,140 @@
+package builder
+
+import (
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// SelectBuilder builds a SELECT.
+type SelectBuilder struct {
+	columns    []string
+	count      bool
+	table      string
+	index      string
+	where      []Condition
+	orderBy    string
+	descending bool
+	limit      *int
+	offset     *int
+	consistent bool
+	segments   *int
+	scan       bool
+}
+
+// Select starts a SELECT of the given attributes, or of whole items if there are none.
+func Select(columns ...string) *SelectBuilder {
+	return &SelectBuilder{columns: columns}
+}
+
+// SelectCount starts a SELECT COUNT(*).
+func SelectCount() *SelectBuilder {
+	return &SelectBuilder{count: true}
+}
+
+// From sets the table to read.
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.table = table
+	return b
+}
+
+// Index reads a secondary index, as with USE INDEX (index).
+func (b *SelectBuilder) Index(index string) *SelectBuilder {
+	b.index = index
+	return b
+}
+
+// Where adds conditions to the WHERE clause. All the conditions of all calls to Where must be true.
+func (b *SelectBuilder) Where(conditions ...Condition) *SelectBuilder {
+	b.where = append(b.where, conditions...)
+	return b
+}
+
+// OrderBy sorts the rows by the sort key of the table or index, which is the only order DynamoDB supports.
+func (b *SelectBuilder) OrderBy(path string) *SelectBuilder {
+	b.orderBy = path
+	return b
+}
+
+// Desc reverses the order that rows are read in.
+func (b *SelectBuilder) Desc() *SelectBuilder {
+	b.descending = true
+	return b
+}
+
+// Limit sets the maximum number of rows to return.
+func (b *SelectBuilder) Limit(n int) *SelectBuilder {
+	b.limit = &n
+	return b
+}
+
+// Offset skips the first n rows.
+func (b *SelectBuilder) Offset(n int) *SelectBuilder {
+	b.offset = &n
+	return b
+}
+
+// Consistent makes the read strongly consistent, as with WITH (CONSISTENT).
+func (b *SelectBuilder) Consistent() *SelectBuilder {
+	b.consistent = true
+	return b
+}
+
+// Segments scans the table in n parallel segments, as with WITH (SEGMENTS = n).
+func (b *SelectBuilder) Segments(n int) *SelectBuilder {
+	b.segments = &n
+	return b
+}
+
+// Scan reads the table or index with a Scan even if it could be queried, as with WITH (SCAN).
+func (b *SelectBuilder) Scan() *SelectBuilder {
+	b.scan = true
+	return b
+}
+
+// Build returns the statement and the values it binds.
+func (b *SelectBuilder) Build() (*parser.AST, []interface{}, error) {
+	s := &state{}
+	stmt := &parser.Select{
+		Projection: &parser.ProjectionExpression{All: len(b.columns) == 0 && !b.count, Count: b.count},
+		From:       b.table,
+		Where:      where(s, b.where),
+		Offset:     b.offset,
+	}
+	if !b.count {
+		for _, column := range b.columns {
+			stmt.Projection.Columns = append(stmt.Projection.Columns, &parser.ProjectionColumn{DocumentPath: s.path(column)})
+		}
+	}
+	if b.index != "" {
+		index := b.index
+		stmt.Index = &index
+	}
+	var descending *parser.ScanDescending
+	if b.descending {
+		descending = new(parser.ScanDescending)
+		*descending = true
+	}
+	if b.orderBy != "" {
+		stmt.OrderBy = &parser.OrderBy{Path: s.path(b.orderBy), Descending: descending}
+	} else {
+		stmt.Descending = descending
+	}
+	if b.limit != nil {
+		limit := float64(*b.limit)
+		stmt.Limit = &parser.Limit{Value: &parser.Value{Scalar: parser.Scalar{Number: &limit}}}
+	}
+	if b.consistent {
+		stmt.Hints = append(stmt.Hints, &parser.SelectHint{Consistent: true})
+	}
+	if b.scan {
+		stmt.Hints = append(stmt.Hints, &parser.SelectHint{Scan: true})
+	}
+	if b.segments != nil {
+		stmt.Hints = append(stmt.Hints, &parser.SelectHint{Segments: b.segments})
+	}
+	return s.finish(&parser.AST{Select: stmt})
+}
+
+// SQL returns the statement as SQL, and the values it binds, to pass to database/sql.
+func (b *SelectBuilder) SQL() (string, []interface{}, error) {
+	return format(b.Build())
+}
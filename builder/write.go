@@ -0,0 +1,179 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// InsertBuilder builds an INSERT or a REPLACE.
+type InsertBuilder struct {
+	table   string
+	items   []interface{}
+	replace bool
+}
+
+// Insert starts an INSERT into the table, which fails if an item with the same key exists.
+func Insert(table string) *InsertBuilder {
+	return &InsertBuilder{table: table}
+}
+
+// Replace starts a REPLACE into the table, which overwrites any item with the same key.
+func Replace(table string) *InsertBuilder {
+	return &InsertBuilder{table: table, replace: true}
+}
+
+// Values adds items to write, such as maps or structs. The items are bound together as a single value, as INSERT
+// takes one placeholder for all of its items. A Param must be the only item, and is bound to one item or a slice.
+func (b *InsertBuilder) Values(items ...interface{}) *InsertBuilder {
+	b.items = append(b.items, items...)
+	return b
+}
+
+// Build returns the statement and the values it binds.
+func (b *InsertBuilder) Build() (*parser.AST, []interface{}, error) {
+	s := &state{}
+	if len(b.items) == 0 {
+		s.fail(fmt.Errorf("INSERT into %q needs at least one item", b.table))
+	}
+	var items interface{} = b.items
+	if len(b.items) == 1 {
+		items = b.items[0]
+	} else {
+		for _, item := range b.items {
+			if _, ok := item.(Param); ok {
+				s.fail(errors.New("a Param must be the only item of an INSERT"))
+			}
+		}
+	}
+	stmt := &parser.Insert{Into: b.table, Values: []*parser.InsertTerminal{{Value: *s.value(items)}}}
+	if b.replace {
+		return s.finish(&parser.AST{Replace: stmt})
+	}
+	return s.finish(&parser.AST{Insert: stmt})
+}
+
+// SQL returns the statement as SQL, and the values it binds, to pass to database/sql.
+func (b *InsertBuilder) SQL() (string, []interface{}, error) {
+	return format(b.Build())
+}
+
+// UpdateBuilder builds an UPDATE.
+type UpdateBuilder struct {
+	table   string
+	actions []func(s *state) *parser.UpdateAction
+	where   []Condition
+}
+
+// Update starts an UPDATE of the table.
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{table: table}
+}
+
+// Set sets an attribute to a value, as with SET path = value.
+func (b *UpdateBuilder) Set(path string, value interface{}) *UpdateBuilder {
+	b.actions = append(b.actions, func(s *state) *parser.UpdateAction {
+		return &parser.UpdateAction{Set: []*parser.SetExpression{{
+			Path:       s.path(path),
+			SetOperand: parser.SetOperand{Value: s.operand(value)},
+		}}}
+	})
+	return b
+}
+
+// Add adds a number to an attribute, or elements to a set, as with ADD path value.
+func (b *UpdateBuilder) Add(path string, value interface{}) *UpdateBuilder {
+	b.actions = append(b.actions, func(s *state) *parser.UpdateAction {
+		return &parser.UpdateAction{Add: []*parser.AddExpression{{Path: s.path(path), Value: s.value(value)}}}
+	})
+	return b
+}
+
+// Remove removes attributes, as with REMOVE path, ....
+func (b *UpdateBuilder) Remove(paths ...string) *UpdateBuilder {
+	b.actions = append(b.actions, func(s *state) *parser.UpdateAction {
+		action := &parser.UpdateAction{}
+		for _, path := range paths {
+			action.Remove = append(action.Remove, s.path(path))
+		}
+		return action
+	})
+	return b
+}
+
+// Delete removes elements from a set, as with DELETE path value.
+func (b *UpdateBuilder) Delete(path string, value interface{}) *UpdateBuilder {
+	b.actions = append(b.actions, func(s *state) *parser.UpdateAction {
+		return &parser.UpdateAction{Delete: []*parser.DeleteExpression{{Path: s.path(path), Value: s.value(value)}}}
+	})
+	return b
+}
+
+// Where adds conditions to the WHERE clause, which must include each key attribute of the item to update. All the
+// conditions of all calls to Where must be true.
+func (b *UpdateBuilder) Where(conditions ...Condition) *UpdateBuilder {
+	b.where = append(b.where, conditions...)
+	return b
+}
+
+// Version makes the update conditional on the number at path being expected, and adds one to it, for optimistic
+// concurrency. As the check is part of the condition of the write, an update that lost a race fails with
+// dynamosql.ErrConditionFailed.
+func (b *UpdateBuilder) Version(path string, expected interface{}) *UpdateBuilder {
+	return b.Add(path, 1).Where(Eq(path, expected))
+}
+
+// Build returns the statement and the values it binds.
+func (b *UpdateBuilder) Build() (*parser.AST, []interface{}, error) {
+	s := &state{}
+	if len(b.actions) == 0 {
+		s.fail(fmt.Errorf("UPDATE of %q needs at least one of SET, ADD, REMOVE or DELETE", b.table))
+	}
+	stmt := &parser.Update{Table: b.table}
+	for _, action := range b.actions {
+		stmt.Actions = append(stmt.Actions, action(s))
+	}
+	stmt.Where = where(s, b.where)
+	return s.finish(&parser.AST{Update: stmt})
+}
+
+// SQL returns the statement as SQL, and the values it binds, to pass to database/sql.
+func (b *UpdateBuilder) SQL() (string, []interface{}, error) {
+	return format(b.Build())
+}
+
+// DeleteBuilder builds a DELETE.
+type DeleteBuilder struct {
+	table string
+	where []Condition
+}
+
+// Delete starts a DELETE from the table.
+func Delete(table string) *DeleteBuilder {
+	return &DeleteBuilder{table: table}
+}
+
+// Where adds conditions to the WHERE clause, which must include each key attribute of the item to delete. All the
+// conditions of all calls to Where must be true.
+func (b *DeleteBuilder) Where(conditions ...Condition) *DeleteBuilder {
+	b.where = append(b.where, conditions...)
+	return b
+}
+
+// Version makes the delete conditional on the number at path being expected, as with UpdateBuilder.Version.
+func (b *DeleteBuilder) Version(path string, expected interface{}) *DeleteBuilder {
+	return b.Where(Eq(path, expected))
+}
+
+// Build returns the statement and the values it binds.
+func (b *DeleteBuilder) Build() (*parser.AST, []interface{}, error) {
+	s := &state{}
+	stmt := &parser.Delete{From: b.table, Where: where(s, b.where)}
+	return s.finish(&parser.AST{Delete: stmt})
+}
+
+// SQL returns the statement as SQL, and the values it binds, to pass to database/sql.
+func (b *DeleteBuilder) SQL() (string, []interface{}, error) {
+	return format(b.Build())
+}
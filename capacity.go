@@ -0,0 +1,179 @@
+package dynamosql
+
+import (
+	"database/sql/driver"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// CapacityReporter is implemented by the driver.Rows and driver.Result of statements run on a driver configured
+// with ReturnConsumedCapacity. They can be reached with (*sql.Conn).Raw, by calling QueryContext or ExecContext on
+// the driver connection directly. Statements run in a transaction do not report their capacity.
+type CapacityReporter interface {
+	// ConsumedCapacity returns the capacity units consumed by the statement, summed over every request made so far.
+	// For rows, that is every page read, so the total is only final once all rows have been read.
+	ConsumedCapacity() float64
+}
+
+// consumedCapacity sums the capacity units consumed by the requests of a statement. A nil *consumedCapacity
+// reports zero.
+type consumedCapacity struct {
+	lock  sync.Mutex
+	units float64
+}
+
+func (c *consumedCapacity) add(consumed ...*dynamodb.ConsumedCapacity) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, cc := range consumed {
+		if cc != nil {
+			c.units += aws.Float64Value(cc.CapacityUnits)
+		}
+	}
+}
+
+func (c *consumedCapacity) total() float64 {
+	if c == nil {
+		return 0
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.units
+}
+
+// withConsumedCapacity returns a client that asks for the total consumed capacity of the requests that statements
+// make, and sums it. If enabled is false, the client is returned as is with a nil total.
+func withConsumedCapacity(dynamo dynamodbiface.DynamoDBAPI, enabled bool) (dynamodbiface.DynamoDBAPI, *consumedCapacity) {
+	if !enabled {
+		return dynamo, nil
+	}
+	capacity := &consumedCapacity{}
+	return &capacityClient{DynamoDBAPI: dynamo, capacity: capacity}, capacity
+}
+
+type capacityClient struct {
+	dynamodbiface.DynamoDBAPI
+	capacity *consumedCapacity
+}
+
+var returnConsumedCapacityTotal = aws.String(dynamodb.ReturnConsumedCapacityTotal)
+
+func (c *capacityClient) QueryWithContext(ctx aws.Context, in *dynamodb.QueryInput, opts ...request.Option) (*dynamodb.QueryOutput, error) {
+	req := *in
+	req.ReturnConsumedCapacity = returnConsumedCapacityTotal
+	resp, err := c.DynamoDBAPI.QueryWithContext(ctx, &req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.capacity.add(resp.ConsumedCapacity)
+	return resp, nil
+}
+
+func (c *capacityClient) ScanWithContext(ctx aws.Context, in *dynamodb.ScanInput, opts ...request.Option) (*dynamodb.ScanOutput, error) {
+	req := *in
+	req.ReturnConsumedCapacity = returnConsumedCapacityTotal
+	resp, err := c.DynamoDBAPI.ScanWithContext(ctx, &req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.capacity.add(resp.ConsumedCapacity)
+	return resp, nil
+}
+
+func (c *capacityClient) GetItemWithContext(ctx aws.Context, in *dynamodb.GetItemInput, opts ...request.Option) (*dynamodb.GetItemOutput, error) {
+	req := *in
+	req.ReturnConsumedCapacity = returnConsumedCapacityTotal
+	resp, err := c.DynamoDBAPI.GetItemWithContext(ctx, &req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.capacity.add(resp.ConsumedCapacity)
+	return resp, nil
+}
+
+func (c *capacityClient) BatchGetItemWithContext(ctx aws.Context, in *dynamodb.BatchGetItemInput, opts ...request.Option) (*dynamodb.BatchGetItemOutput, error) {
+	req := *in
+	req.ReturnConsumedCapacity = returnConsumedCapacityTotal
+	resp, err := c.DynamoDBAPI.BatchGetItemWithContext(ctx, &req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.capacity.add(resp.ConsumedCapacity...)
+	return resp, nil
+}
+
+func (c *capacityClient) PutItemWithContext(ctx aws.Context, in *dynamodb.PutItemInput, opts ...request.Option) (*dynamodb.PutItemOutput, error) {
+	req := *in
+	req.ReturnConsumedCapacity = returnConsumedCapacityTotal
+	resp, err := c.DynamoDBAPI.PutItemWithContext(ctx, &req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.capacity.add(resp.ConsumedCapacity)
+	return resp, nil
+}
+
+func (c *capacityClient) UpdateItemWithContext(ctx aws.Context, in *dynamodb.UpdateItemInput, opts ...request.Option) (*dynamodb.UpdateItemOutput, error) {
+	req := *in
+	req.ReturnConsumedCapacity = returnConsumedCapacityTotal
+	resp, err := c.DynamoDBAPI.UpdateItemWithContext(ctx, &req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.capacity.add(resp.ConsumedCapacity)
+	return resp, nil
+}
+
+func (c *capacityClient) DeleteItemWithContext(ctx aws.Context, in *dynamodb.DeleteItemInput, opts ...request.Option) (*dynamodb.DeleteItemOutput, error) {
+	req := *in
+	req.ReturnConsumedCapacity = returnConsumedCapacityTotal
+	resp, err := c.DynamoDBAPI.DeleteItemWithContext(ctx, &req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.capacity.add(resp.ConsumedCapacity)
+	return resp, nil
+}
+
+func (c *capacityClient) BatchWriteItemWithContext(ctx aws.Context, in *dynamodb.BatchWriteItemInput, opts ...request.Option) (*dynamodb.BatchWriteItemOutput, error) {
+	req := *in
+	req.ReturnConsumedCapacity = returnConsumedCapacityTotal
+	resp, err := c.DynamoDBAPI.BatchWriteItemWithContext(ctx, &req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.capacity.add(resp.ConsumedCapacity...)
+	return resp, nil
+}
+
+func (c *capacityClient) TransactWriteItemsWithContext(ctx aws.Context, in *dynamodb.TransactWriteItemsInput, opts ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+	req := *in
+	req.ReturnConsumedCapacity = returnConsumedCapacityTotal
+	resp, err := c.DynamoDBAPI.TransactWriteItemsWithContext(ctx, &req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.capacity.add(resp.ConsumedCapacity...)
+	return resp, nil
+}
+
+// capacityResult is the driver.Result of a write run with ReturnConsumedCapacity.
+type capacityResult struct {
+	driver.Result
+	capacity *consumedCapacity
+}
+
+func (c *capacityResult) ConsumedCapacity() float64 {
+	return c.capacity.total()
+}
+
+var (
+	_ CapacityReporter = &capacityResult{}
+	_ CapacityReporter = &rows{}
+	_ CapacityReporter = &countRow{}
+	_ CapacityReporter = &oneRow{}
+)
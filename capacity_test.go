@@ -0,0 +1,116 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumedCapacity(t *testing.T) {
+	ctx := context.Background()
+	tables := map[string]*dynamodb.CreateTableInput{
+		"items": {
+			TableName: aws.String("items"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				{AttributeName: aws.String("seq"), KeyType: aws.String(dynamodb.KeyTypeRange)},
+			},
+		},
+	}
+	items := make([]map[string]*dynamodb.AttributeValue, 5)
+	for i := range items {
+		items[i] = map[string]*dynamodb.AttributeValue{"id": {N: aws.String(strconv.Itoa(i))}}
+	}
+	pages := pagedItems(items, 2)
+	// Each page of two items consumes 1.5 capacity units.
+	query := func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+		require.Equal(t, dynamodb.ReturnConsumedCapacityTotal, aws.StringValue(in.ReturnConsumedCapacity))
+		out, err := pages(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		out.ConsumedCapacity = &dynamodb.ConsumedCapacity{CapacityUnits: aws.Float64(1.5)}
+		return out, nil
+	}
+	newConn := func(m *mockDynamoDB) conn {
+		c := newMockConn(m)
+		c.returnCapacity = true
+		return c
+	}
+
+	t.Run("summed over all pages of a query", func(t *testing.T) {
+		c := newConn(&mockDynamoDB{tables: tables, query: query})
+		rows, err := c.QueryContext(ctx, `SELECT * FROM items WHERE id = 1`, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1.5, rows.(CapacityReporter).ConsumedCapacity())
+		dest := make([]driver.Value, 1)
+		for {
+			err := rows.Next(dest)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+		}
+		require.Equal(t, 4.5, rows.(CapacityReporter).ConsumedCapacity())
+	})
+
+	t.Run("summed over all pages of a count", func(t *testing.T) {
+		c := newConn(&mockDynamoDB{tables: tables, query: query})
+		rows, err := c.QueryContext(ctx, `SELECT COUNT(*) FROM items WHERE id = 1`, nil)
+		require.NoError(t, err)
+		require.Equal(t, 4.5, rows.(CapacityReporter).ConsumedCapacity())
+	})
+
+	t.Run("reported by the rows of a get", func(t *testing.T) {
+		c := newConn(&mockDynamoDB{tables: tables, getItem: func(ctx aws.Context, in *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			require.Equal(t, dynamodb.ReturnConsumedCapacityTotal, aws.StringValue(in.ReturnConsumedCapacity))
+			return &dynamodb.GetItemOutput{Item: items[1], ConsumedCapacity: &dynamodb.ConsumedCapacity{CapacityUnits: aws.Float64(0.5)}}, nil
+		}})
+		rows, err := c.QueryContext(ctx, `SELECT * FROM items WHERE id = 1 AND seq = 1`, nil)
+		require.NoError(t, err)
+		require.Equal(t, 0.5, rows.(CapacityReporter).ConsumedCapacity())
+	})
+
+	t.Run("reported by the result of a write", func(t *testing.T) {
+		c := newConn(&mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			require.Equal(t, dynamodb.ReturnConsumedCapacityTotal, aws.StringValue(in.ReturnConsumedCapacity))
+			return &dynamodb.PutItemOutput{ConsumedCapacity: &dynamodb.ConsumedCapacity{CapacityUnits: aws.Float64(2)}}, nil
+		}})
+		res, err := c.ExecContext(ctx, `REPLACE INTO items VALUES ({"id": 1})`, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2.0, res.(CapacityReporter).ConsumedCapacity())
+		n, err := res.RowsAffected()
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+	})
+
+	t.Run("summed over batches", func(t *testing.T) {
+		c := newConn(&mockDynamoDB{tables: tables, batchWrite: func(ctx aws.Context, in *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+			return &dynamodb.BatchWriteItemOutput{ConsumedCapacity: []*dynamodb.ConsumedCapacity{
+				{TableName: aws.String("items"), CapacityUnits: aws.Float64(float64(len(in.RequestItems["items"])))},
+			}}, nil
+		}})
+		docs := make([]map[string]interface{}, 30)
+		for i := range docs {
+			docs[i] = map[string]interface{}{"id": i}
+		}
+		res, err := c.ExecContext(ctx, `REPLACE INTO items VALUES (?)`, []driver.NamedValue{{Ordinal: 1, Value: docs}})
+		require.NoError(t, err)
+		require.Equal(t, 30.0, res.(CapacityReporter).ConsumedCapacity())
+	})
+
+	t.Run("not requested by default", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, query: func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			require.Nil(t, in.ReturnConsumedCapacity)
+			return &dynamodb.QueryOutput{}, nil
+		}})
+		_, err := c.QueryContext(ctx, `SELECT * FROM items WHERE id = 1`, nil)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,276 @@
+package dynamosql
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/querybuilder"
+)
+
+// evalCase returns the result of the first WHEN of a CASE whose condition holds for the item, or the ELSE result if
+// none does. It is nil if there is no ELSE, or if the result is an attribute that the item does not have.
+//
+// Conditions follow DynamoDB: a comparison with an attribute that does not exist, or of values of different types,
+// is false, and only <> holds for values of different types.
+func evalCase(expr *parser.CaseExpression, item map[string]*dynamodb.AttributeValue) *dynamodb.AttributeValue {
+	doc := &dynamodb.AttributeValue{M: item}
+	for _, when := range expr.Whens {
+		if evalConditionExpression(when.Condition, doc) {
+			return evalOperand(when.Result, doc)
+		}
+	}
+	if expr.Else != nil {
+		return evalOperand(expr.Else, doc)
+	}
+	return nil
+}
+
+func evalConditionExpression(expr *parser.ConditionExpression, doc *dynamodb.AttributeValue) bool {
+	for _, and := range expr.Or {
+		holds := true
+		for _, cond := range and.And {
+			if !evalCondition(cond, doc) {
+				holds = false
+				break
+			}
+		}
+		if holds {
+			return true
+		}
+	}
+	return false
+}
+
+func evalCondition(cond *parser.Condition, doc *dynamodb.AttributeValue) bool {
+	switch {
+	case cond.Parenthesized != nil:
+		return evalConditionExpression(cond.Parenthesized.ConditionExpression, doc)
+	case cond.Not != nil:
+		return !evalCondition(cond.Not.Condition, doc)
+	case cond.Operand != nil:
+		var lhs *dynamodb.AttributeValue
+		if cond.Operand.Size != nil {
+			lhs = attributeSize(evalArgument(cond.Operand.Size.Args[0], doc))
+		} else {
+			lhs = pluckAttributeValue(doc, cond.Operand.Operand)
+		}
+		return evalConditionRHS(lhs, cond.Operand.ConditionRHS, doc)
+	case cond.Function != nil:
+		return evalFunction(cond.Function, doc)
+	default:
+		return false
+	}
+}
+
+func evalConditionRHS(lhs *dynamodb.AttributeValue, rhs *parser.ConditionRHS, doc *dynamodb.AttributeValue) bool {
+	switch {
+	case rhs.Compare != nil:
+		return compareAttributeValues(lhs, rhs.Compare.Operator, evalOperand(rhs.Compare.Operand, doc))
+	case rhs.Between != nil:
+		return evalBetween(lhs, rhs.Between, doc)
+	case rhs.NotBetween != nil:
+		return !evalBetween(lhs, rhs.NotBetween, doc)
+	case rhs.In != nil:
+		return evalIn(lhs, rhs.In)
+	case rhs.NotIn != nil:
+		return !evalIn(lhs, rhs.NotIn)
+	case rhs.Is != nil:
+		// As in WHERE, IS NULL tests whether the attribute is absent.
+		return (lhs != nil) == rhs.Is.Not
+	case rhs.Like != nil:
+		pattern := scalarAttributeValue(&rhs.Like.Pattern.Scalar)
+		return lhs != nil && lhs.S != nil && pattern.S != nil && querybuilder.LikeRegexp(*pattern.S).MatchString(*lhs.S)
+	default:
+		return false
+	}
+}
+
+func evalBetween(lhs *dynamodb.AttributeValue, between *parser.Between, doc *dynamodb.AttributeValue) bool {
+	return compareAttributeValues(lhs, ">=", evalOperand(between.Start, doc)) &&
+		compareAttributeValues(lhs, "<=", evalOperand(between.End, doc))
+}
+
+func evalIn(lhs *dynamodb.AttributeValue, in *parser.In) bool {
+	for _, value := range in.Values {
+		if compareAttributeValues(lhs, "=", scalarAttributeValue(&value.Scalar)) {
+			return true
+		}
+	}
+	return false
+}
+
+func evalFunction(fn *parser.FunctionExpression, doc *dynamodb.AttributeValue) bool {
+	av := evalArgument(fn.Args[0], doc)
+	switch fn.Function {
+	case "attribute_exists":
+		return av != nil
+	case "attribute_not_exists":
+		return av == nil
+	case "attribute_type":
+		typ := evalArgument(fn.Args[1], doc)
+		return av != nil && typ != nil && typ.S != nil && attributeType(av) == *typ.S
+	case "begins_with":
+		prefix := evalArgument(fn.Args[1], doc)
+		switch {
+		case av == nil || prefix == nil:
+			return false
+		case av.S != nil && prefix.S != nil:
+			return strings.HasPrefix(*av.S, *prefix.S)
+		case av.B != nil && prefix.B != nil:
+			return bytes.HasPrefix(av.B, prefix.B)
+		default:
+			return false
+		}
+	case "contains":
+		return attributeContains(av, evalArgument(fn.Args[1], doc))
+	default:
+		return false
+	}
+}
+
+// attributeContains is contains(), which matches a substring of a string, or an element of a set or list.
+func attributeContains(av, operand *dynamodb.AttributeValue) bool {
+	if av == nil || operand == nil {
+		return false
+	}
+	switch {
+	case av.S != nil && operand.S != nil:
+		return strings.Contains(*av.S, *operand.S)
+	case av.B != nil && operand.B != nil:
+		return bytes.Contains(av.B, operand.B)
+	}
+	var elements []*dynamodb.AttributeValue
+	switch {
+	case av.SS != nil:
+		for _, s := range av.SS {
+			elements = append(elements, &dynamodb.AttributeValue{S: s})
+		}
+	case av.NS != nil:
+		for _, n := range av.NS {
+			elements = append(elements, &dynamodb.AttributeValue{N: n})
+		}
+	case av.BS != nil:
+		for _, b := range av.BS {
+			elements = append(elements, &dynamodb.AttributeValue{B: b})
+		}
+	case av.L != nil:
+		elements = av.L
+	}
+	for _, element := range elements {
+		if compareAttributeValues(element, "=", operand) {
+			return true
+		}
+	}
+	return false
+}
+
+// attributeSize is size(), the length of a string or binary, or the number of elements of a set, list or map. It is
+// nil for other types, which have no size.
+func attributeSize(av *dynamodb.AttributeValue) *dynamodb.AttributeValue {
+	var size int
+	switch {
+	case av == nil:
+		return nil
+	case av.S != nil:
+		size = len(*av.S)
+	case av.B != nil:
+		size = len(av.B)
+	case av.SS != nil:
+		size = len(av.SS)
+	case av.NS != nil:
+		size = len(av.NS)
+	case av.BS != nil:
+		size = len(av.BS)
+	case av.L != nil:
+		size = len(av.L)
+	case av.M != nil:
+		size = len(av.M)
+	default:
+		return nil
+	}
+	return &dynamodb.AttributeValue{N: aws.String(strconv.Itoa(size))}
+}
+
+// compareAttributeValues compares a with b with one of the operators of a comparison. Strings, numbers and binaries
+// are ordered, other types can only be tested for equality.
+func compareAttributeValues(a *dynamodb.AttributeValue, operator string, b *dynamodb.AttributeValue) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	cmp, ordered := 0, true
+	switch {
+	case a.S != nil && b.S != nil:
+		cmp = strings.Compare(*a.S, *b.S)
+	case a.N != nil && b.N != nil:
+		x, xok := new(big.Rat).SetString(*a.N)
+		y, yok := new(big.Rat).SetString(*b.N)
+		if !xok || !yok {
+			return false
+		}
+		cmp = x.Cmp(y)
+	case a.B != nil && b.B != nil:
+		cmp = bytes.Compare(a.B, b.B)
+	default:
+		ordered = false
+		if !reflect.DeepEqual(a, b) {
+			cmp = 1
+		}
+	}
+	switch operator {
+	case "=":
+		return cmp == 0
+	case "<>", "!=":
+		return cmp != 0
+	}
+	if !ordered {
+		return false
+	}
+	switch operator {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	default:
+		return false
+	}
+}
+
+func evalOperand(operand *parser.Operand, doc *dynamodb.AttributeValue) *dynamodb.AttributeValue {
+	if operand.SymbolRef != nil {
+		return pluckAttributeValue(doc, operand.SymbolRef)
+	}
+	return scalarAttributeValue(&operand.Value.Scalar)
+}
+
+func evalArgument(arg *parser.FunctionArgument, doc *dynamodb.AttributeValue) *dynamodb.AttributeValue {
+	if arg.DocumentPath != nil {
+		return pluckAttributeValue(doc, arg.DocumentPath)
+	}
+	return scalarAttributeValue(&arg.Value.Scalar)
+}
+
+// scalarAttributeValue converts a literal of a CASE to an attribute value. The parser rejects placeholders in a CASE,
+// so every value is a literal.
+func scalarAttributeValue(s *parser.Scalar) *dynamodb.AttributeValue {
+	switch {
+	case s.Number != nil:
+		return &dynamodb.AttributeValue{N: aws.String(s.NumberLiteral())}
+	case s.Str != nil:
+		return &dynamodb.AttributeValue{S: s.Str}
+	case s.Boolean != nil:
+		return &dynamodb.AttributeValue{BOOL: aws.Bool(bool(*s.Boolean))}
+	default:
+		return &dynamodb.AttributeValue{NULL: aws.Bool(true)}
+	}
+}
@@ -2,8 +2,15 @@ package dynamosql
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
+	"errors"
+	"fmt"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
 	"github.com/mightyguava/dynamosql/parser"
@@ -15,9 +22,34 @@ type conn struct {
 	dynamo      dynamodbiface.DynamoDBAPI
 	tables      *schema.TableLoader
 	mapToGoType bool
+	// numberAsString is set if numbers are returned as strings, for Config.NumberAsString.
+	numberAsString bool
+	// returnCapacity is set if statements report the capacity they consume.
+	returnCapacity bool
+	// waitForActive is set if CREATE TABLE waits for the table to become ACTIVE.
+	waitForActive bool
+	// disallowScan is set if a SELECT that would Scan without asking to with WITH (SCAN) is rejected.
+	disallowScan bool
+	// tablePrefix is prepended to the names of tables, for Config.TablePrefix.
+	tablePrefix string
+	// failOnMissing is set if an UPDATE or DELETE of an item that does not exist fails with ErrConditionFailed, for
+	// Config.ErrorOnMissingItem.
+	failOnMissing bool
+	// indexFallback is set if a SELECT is run without its USE INDEX when the index does not project the attributes
+	// it reads, for Config.UnprojectedIndexFallback.
+	indexFallback bool
+	// tx is the open transaction, if any. Writes executed while it is set are buffered in it.
+	tx *tx
 }
 
+// CheckNamedValue checks an argument with querybuilder.CheckArgument, so that the driver binds it rather than
+// database/sql converting it to a driver.Value.
 func (c conn) CheckNamedValue(value *driver.NamedValue) error {
+	v, err := querybuilder.CheckArgument(value.Value)
+	if err != nil {
+		return err
+	}
+	value.Value = v
 	return nil
 }
 
@@ -25,6 +57,10 @@ var (
 	_ driver.Conn               = &conn{}
 	_ driver.ConnPrepareContext = &conn{}
 	_ driver.NamedValueChecker  = &conn{}
+	_ driver.QueryerContext     = &conn{}
+	_ driver.ExecerContext      = &conn{}
+	_ driver.Pinger             = &conn{}
+	_ driver.ConnBeginTx        = &conn{}
 )
 
 func (c conn) Prepare(query string) (driver.Stmt, error) {
@@ -32,51 +68,302 @@ func (c conn) Prepare(query string) (driver.Stmt, error) {
 }
 
 func (c conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.prepare(ctx, query)
+}
+
+// QueryContext prepares and runs the query in one step, so database/sql does not need a separate Prepare round trip.
+func (c conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	stmt, err := c.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, args)
+}
+
+// ExecContext prepares and executes the statement in one step. A script of several statements separated by
+// semicolons is executed one statement at a time, and stops at the first error.
+func (c conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	asts, err := parser.ParseMulti(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(asts) != 1 {
+		return c.execScript(ctx, asts, args)
+	}
+	stmt, err := c.prepareAST(ctx, asts[0])
+	if err != nil {
+		return nil, err
+	}
+	return stmt.ExecContext(ctx, args)
+}
+
+// execScript executes the statements of a script in order. Arguments can't be bound to a script, as each statement
+// must be given exactly the arguments it uses.
+func (c conn) execScript(ctx context.Context, asts []*parser.AST, args []driver.NamedValue) (driver.Result, error) {
+	if len(asts) == 0 {
+		return nil, errors.New("no statement to execute")
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("a script of %d statements can't take arguments, execute its statements one at a time instead", len(asts))
+	}
+	script := &scriptResult{}
+	for i, ast := range asts {
+		stmt, err := c.prepareAST(ctx, ast)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i+1, err)
+		}
+		result, err := stmt.ExecContext(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i+1, err)
+		}
+		if err := script.add(result); err != nil {
+			return nil, err
+		}
+	}
+	if c.returnCapacity {
+		return &capacityResult{Result: script, capacity: &consumedCapacity{units: script.capacity}}, nil
+	}
+	return script, nil
+}
+
+// scriptResult sums the rows affected, and the capacity consumed, by the statements of a script.
+type scriptResult struct {
+	rowsAffected int64
+	capacity     float64
+}
+
+func (r *scriptResult) add(result driver.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	r.rowsAffected += rows
+	if reporter, ok := result.(CapacityReporter); ok {
+		r.capacity += reporter.ConsumedCapacity()
+	}
+	return nil
+}
+
+func (r *scriptResult) LastInsertId() (int64, error) {
+	return 0, ErrNoLastInsertID
+}
+
+func (r *scriptResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+func (c conn) prepare(ctx context.Context, query string) (fullStmt, error) {
 	ast, err := parser.Parse(query)
 	if err != nil {
 		return nil, err
 	}
+	return c.prepareAST(ctx, ast)
+}
+
+// stmtConfig returns the configuration the statements prepared on the connection share.
+func (c conn) stmtConfig() stmtConfig {
+	return stmtConfig{
+		dynamo:         c.dynamo,
+		mapToGoType:    c.mapToGoType,
+		numberAsString: c.numberAsString,
+		returnCapacity: c.returnCapacity,
+	}
+}
+
+func (c conn) prepareAST(ctx context.Context, ast *parser.AST) (fullStmt, error) {
+	querybuilder.PrefixTables(ast, c.tablePrefix)
+	if c.tx != nil && ast.Select != nil {
+		return nil, errors.New("SELECT is not supported in a transaction, DynamoDB transactions cannot mix reads and writes")
+	}
 	switch {
+	case ast.Insert != nil && ast.Insert.OnDuplicate != nil, ast.Replace != nil && ast.Replace.OnDuplicate != nil:
+		stmt, err := querybuilder.PrepareUpsert(ctx, c.tables, ast)
+		if err != nil {
+			return nil, err
+		}
+		return &execStmt{
+			stmtConfig:   c.stmtConfig(),
+			preparedStmt: stmt,
+			numInput:     stmt.NumInput(),
+			tx:           c.tx,
+		}, nil
+	case ast.Insert != nil && ast.Insert.Select != nil, ast.Replace != nil && ast.Replace.Select != nil:
+		if c.tx != nil {
+			return nil, errors.New("INSERT ... SELECT is not supported in a transaction, DynamoDB transactions cannot mix reads and writes")
+		}
+		stmt, err := querybuilder.PrepareInsertSelect(ctx, c.tables, ast)
+		if err != nil {
+			return nil, err
+		}
+		if c.disallowScan && stmt.Source.ScanReason != "" {
+			return nil, fmt.Errorf("%w: %s, or add WITH (SCAN) to scan anyway", ErrScanDisallowed, stmt.Source.ScanReason)
+		}
+		return &insertSelectStmt{
+			stmtConfig:   c.stmtConfig(),
+			preparedStmt: stmt,
+			numInput:     stmt.NumInput(),
+		}, nil
 	case ast.Insert != nil, ast.Replace != nil:
 		stmt, err := querybuilder.PrepareInsert(ctx, c.tables, ast)
 		if err != nil {
 			return nil, err
 		}
 		return &execStmt{
+			stmtConfig:   c.stmtConfig(),
+			preparedStmt: stmt,
+			numInput:     stmt.NumInput(),
+			tx:           c.tx,
+		}, nil
+	case ast.Update != nil:
+		stmt, err := querybuilder.PrepareUpdate(ctx, c.tables, ast)
+		if err != nil {
+			return nil, err
+		}
+		stmt.FailOnMissingItem = c.failOnMissing
+		return &execStmt{
+			stmtConfig:   c.stmtConfig(),
+			preparedStmt: stmt,
+			numInput:     stmt.NumInput(),
+			tx:           c.tx,
+		}, nil
+	case ast.Delete != nil:
+		stmt, err := querybuilder.PrepareDelete(ctx, c.tables, ast)
+		if err != nil {
+			return nil, err
+		}
+		stmt.FailOnMissingItem = c.failOnMissing
+		return &execStmt{
+			stmtConfig:   c.stmtConfig(),
 			preparedStmt: stmt,
-			dynamo:       c.dynamo,
-			mapToGoType:  c.mapToGoType,
+			numInput:     stmt.NumInput(),
+			tx:           c.tx,
 		}, nil
 	case ast.Select != nil:
-		prepared, err := querybuilder.PrepareQuery(ctx, c.tables, query)
+		prepared, err := querybuilder.PrepareQuery(ctx, c.tables, ast, false)
+		if errors.Is(err, querybuilder.ErrNotProjected) && c.indexFallback {
+			prepared, err = querybuilder.PrepareQuery(ctx, c.tables, ast, true)
+		}
 		if err != nil {
 			return nil, err
 		}
+		if c.disallowScan && prepared.ScanReason != "" {
+			return nil, fmt.Errorf("%w: %s, or add WITH (SCAN) to scan anyway", ErrScanDisallowed, prepared.ScanReason)
+		}
 		return &queryStmt{
+			stmtConfig:   c.stmtConfig(),
 			preparedStmt: prepared,
-			dynamo:       c.dynamo,
-			mapToGoType:  c.mapToGoType,
+			numInput:     prepared.NumInput(),
 		}, err
 	case ast.CreateTable != nil:
-		prepared, err := querybuilder.PrepareCreateTable(ast)
+		prepared, err := querybuilder.PrepareCreateTable(ast, c.tables, c.waitForActive)
 		if err != nil {
 			return nil, err
 		}
 		return &execStmt{
+			stmtConfig:   c.stmtConfig(),
 			preparedStmt: prepared,
-			dynamo:       c.dynamo,
-			mapToGoType:  c.mapToGoType,
+			tx:           c.tx,
 		}, err
 
+	case ast.DropTable != nil:
+		prepared, err := querybuilder.PrepareDropTable(ast, c.tables)
+		if err != nil {
+			return nil, err
+		}
+		return &execStmt{
+			stmtConfig:   c.stmtConfig(),
+			preparedStmt: prepared,
+			tx:           c.tx,
+		}, nil
+
+	case ast.AlterTable != nil:
+		prepared, err := querybuilder.PrepareAlterTable(ast, c.tables)
+		if err != nil {
+			return nil, err
+		}
+		return &execStmt{
+			stmtConfig:   c.stmtConfig(),
+			preparedStmt: prepared,
+			tx:           c.tx,
+		}, nil
+
+	case ast.Describe != nil:
+		prepared, err := querybuilder.PrepareDescribe(ast)
+		if err != nil {
+			return nil, err
+		}
+		return &rowsStmt{
+			name:   "DESCRIBE",
+			rows:   prepared.Rows,
+			cols:   querybuilder.DescribeColumns,
+			dynamo: c.dynamo,
+		}, nil
+
+	case ast.ShowTables != nil:
+		prepared, err := querybuilder.PrepareShowTables(ast)
+		if err != nil {
+			return nil, err
+		}
+		return &rowsStmt{
+			name:   "SHOW TABLES",
+			rows:   prepared.Rows,
+			cols:   querybuilder.ShowTablesColumns,
+			dynamo: c.dynamo,
+		}, nil
+
+	case ast.Explain != nil:
+		prepared, err := querybuilder.PrepareExplain(ctx, c.tables, ast)
+		if err != nil {
+			return nil, err
+		}
+		return &rowsStmt{
+			name:   "EXPLAIN",
+			rows:   prepared.Rows,
+			cols:   querybuilder.ExplainColumns,
+			dynamo: c.dynamo,
+		}, nil
+
 	default:
-		panic("unsupported statement")
+		return nil, fmt.Errorf("unsupported statement: %s", ast)
+	}
+}
+
+// Ping checks connectivity with a ListTables call for a single table. Network failures are reported as
+// driver.ErrBadConn so that database/sql discards the connection, other errors are returned as is.
+func (c conn) Ping(ctx context.Context) error {
+	_, err := c.dynamo.ListTablesWithContext(ctx, &dynamodb.ListTablesInput{Limit: aws.Int64(1)})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == request.ErrCodeRequestError {
+		return driver.ErrBadConn
 	}
+	return err
 }
 
 func (c conn) Close() error {
 	return nil
 }
 
-func (c conn) Begin() (driver.Tx, error) {
-	panic("implement me")
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx starts a transaction. INSERT, REPLACE, UPDATE and DELETE statements executed on it are buffered and written
+// with a single TransactWriteItems call on Commit, so that either all or none of them are applied. DynamoDB
+// transactions are always serializable and cannot contain reads, so SELECT, INSERT ... SELECT, read-only transactions
+// and weaker isolation levels are rejected. So are RETURNING, as nothing is written until Commit, INSERT ... ON
+// DUPLICATE KEY UPDATE, which makes two writes that depend on each other, and CREATE, DROP and ALTER TABLE. DESCRIBE,
+// SHOW TABLES and EXPLAIN do not write, and run at once outside of the transaction.
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if c.tx != nil {
+		return nil, errors.New("a transaction is already in progress")
+	}
+	if opts.ReadOnly {
+		return nil, errors.New("read-only transactions are not supported")
+	}
+	switch sql.IsolationLevel(opts.Isolation) {
+	case sql.LevelDefault, sql.LevelSerializable:
+	default:
+		return nil, fmt.Errorf("isolation level %s is not supported, DynamoDB transactions are serializable", sql.IsolationLevel(opts.Isolation))
+	}
+	c.tx = &tx{ctx: ctx, conn: c}
+	return c.tx, nil
 }
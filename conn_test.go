@@ -0,0 +1,397 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+func TestPing(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var limit int64
+		c := conn{dynamo: &mockDynamoDB{
+			listTables: func(in *dynamodb.ListTablesInput) (*dynamodb.ListTablesOutput, error) {
+				limit = *in.Limit
+				return &dynamodb.ListTablesOutput{}, nil
+			},
+		}}
+		require.NoError(t, c.Ping(context.Background()))
+		require.Equal(t, int64(1), limit)
+	})
+
+	t.Run("network failure", func(t *testing.T) {
+		sess := session.Must(session.NewSession(aws.NewConfig().
+			WithEndpoint("http://127.0.0.1:1").
+			WithRegion("us-west-2").
+			WithMaxRetries(0).
+			WithCredentials(credentials.NewStaticCredentials("fake", "secret", ""))))
+		c := conn{dynamo: dynamodb.New(sess)}
+		require.Equal(t, driver.ErrBadConn, c.Ping(context.Background()))
+	})
+
+	t.Run("other errors pass through", func(t *testing.T) {
+		accessDenied := awserr.New("AccessDeniedException", "denied", nil)
+		c := conn{dynamo: &mockDynamoDB{
+			listTables: func(in *dynamodb.ListTablesInput) (*dynamodb.ListTablesOutput, error) {
+				return nil, accessDenied
+			},
+		}}
+		require.Equal(t, accessDenied, c.Ping(context.Background()))
+	})
+}
+
+func TestPositionalPlaceholders(t *testing.T) {
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{
+			"items": {
+				TableName: aws.String("items"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+					{AttributeName: aws.String("sk"), KeyType: aws.String(dynamodb.KeyTypeRange)},
+				},
+			},
+		},
+	}
+	var values map[string]*dynamodb.AttributeValue
+	m.query = func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+		values = in.ExpressionAttributeValues
+		return &dynamodb.QueryOutput{}, nil
+	}
+	db := sql.OpenDB(&connector{driver: &Driver{}, dynamo: m, tables: schema.NewTableLoader(m)})
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT * FROM items WHERE pk = ? AND sk > ?`, "a", "b")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	require.Equal(t, map[string]*dynamodb.AttributeValue{
+		":_pos1": {S: aws.String("a")},
+		":_pos2": {S: aws.String("b")},
+	}, values)
+
+	_, err = db.Query(`SELECT * FROM items WHERE pk = ? AND sk > ?`, "a")
+	require.EqualError(t, err, "wrong number of arguments, expected 2, got 1")
+
+	_, err = db.Query(`SELECT * FROM items WHERE pk = ? AND sk > :sk`, "a", sql.Named("sk", "b"))
+	require.EqualError(t, err, "cannot mix positional params (?) with named params (:param)")
+}
+
+func TestCheckNamedValue(t *testing.T) {
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{
+			"items": {
+				TableName: aws.String("items"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				},
+			},
+		},
+	}
+	var values map[string]*dynamodb.AttributeValue
+	m.scan = func(ctx aws.Context, in *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+		values = in.ExpressionAttributeValues
+		return &dynamodb.ScanOutput{}, nil
+	}
+	db := sql.OpenDB(&connector{driver: &Driver{}, dynamo: m, tables: schema.NewTableLoader(m)})
+	defer db.Close()
+
+	score := int16(7)
+	rows, err := db.Query(`SELECT * FROM items WHERE a = ? AND b = ? AND c = ? AND d IN (?) AND e = ?`,
+		sql.NullString{String: "x", Valid: true}, &score, []byte("bin"), []string{"p", "q"},
+		map[string]interface{}{"k": 1})
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	require.Equal(t, map[string]*dynamodb.AttributeValue{
+		":_pos1":   {S: aws.String("x")},
+		":_pos2":   {N: aws.String("7")},
+		":_pos3":   {B: []byte("bin")},
+		":_pos4_0": {S: aws.String("p")},
+		":_pos4_1": {S: aws.String("q")},
+		":_pos5":   {M: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1")}}},
+	}, values)
+
+	_, err = db.Query(`SELECT * FROM items WHERE a = ?`, make(chan int))
+	require.EqualError(t, err, "sql: converting argument $1 type: invalid value type chan int")
+}
+
+func TestDropTable(t *testing.T) {
+	ctx := context.Background()
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{
+			"items": {
+				TableName: aws.String("items"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				},
+			},
+		},
+	}
+	c := newMockConn(m)
+	// Load the schema so we can check that it is forgotten.
+	_, err := c.tables.Get(ctx, "items")
+	require.NoError(t, err)
+
+	res, err := c.ExecContext(ctx, "DROP TABLE items", nil)
+	require.NoError(t, err)
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	_, err = c.tables.Get(ctx, "items")
+	require.Error(t, err)
+
+	_, err = c.ExecContext(ctx, "DROP TABLE items", nil)
+	require.Equal(t, dynamodb.ErrCodeResourceNotFoundException, err.(awserr.Error).Code())
+
+	res, err = c.ExecContext(ctx, "DROP TABLE IF EXISTS `items`;", nil)
+	require.NoError(t, err)
+	n, err = res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), n)
+}
+
+func TestCreateTableWaitForActive(t *testing.T) {
+	ctx := context.Background()
+	query := "CREATE TABLE items (pk STRING HASH KEY, BILLING MODE PAY_PER_REQUEST)"
+
+	t.Run("returns once created by default", func(t *testing.T) {
+		m := &mockDynamoDB{tables: map[string]*dynamodb.CreateTableInput{}}
+		c := newMockConn(m)
+		_, err := c.ExecContext(ctx, query, nil)
+		require.NoError(t, err)
+		require.Contains(t, m.tables, "items")
+		require.Empty(t, m.waited)
+	})
+
+	t.Run("waits for the table to become active", func(t *testing.T) {
+		m := &mockDynamoDB{tables: map[string]*dynamodb.CreateTableInput{}}
+		c := newMockConn(m)
+		c.waitForActive = true
+		_, err := c.ExecContext(ctx, query, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"items"}, m.waited)
+	})
+}
+
+func TestExecScript(t *testing.T) {
+	ctx := context.Background()
+	script := `
+-- a migration
+CREATE TABLE items (id NUMBER HASH KEY, BILLING MODE PAY_PER_REQUEST);
+INSERT INTO items VALUES ({"id": 1, "name": "a;b"});
+INSERT INTO items VALUES ({"id": 2});
+`
+	newConn := func(puts *[]*dynamodb.PutItemInput, failOn string) conn {
+		return newMockConn(&mockDynamoDB{
+			tables: map[string]*dynamodb.CreateTableInput{},
+			putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+				if *in.Item["id"].N == failOn {
+					return nil, errors.New("boom")
+				}
+				*puts = append(*puts, in)
+				return &dynamodb.PutItemOutput{}, nil
+			},
+		})
+	}
+
+	t.Run("statements run in order", func(t *testing.T) {
+		var puts []*dynamodb.PutItemInput
+		c := newConn(&puts, "")
+		result, err := c.ExecContext(ctx, script, nil)
+		require.NoError(t, err)
+		require.Contains(t, c.dynamo.(*mockDynamoDB).tables, "items")
+		require.Len(t, puts, 2)
+		require.Equal(t, "a;b", *puts[0].Item["name"].S)
+		affected, err := result.RowsAffected()
+		require.NoError(t, err)
+		require.Equal(t, int64(2), affected)
+	})
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		var puts []*dynamodb.PutItemInput
+		_, err := newConn(&puts, "1").ExecContext(ctx, script, nil)
+		require.EqualError(t, err, "statement 2: boom")
+		require.Empty(t, puts)
+	})
+
+	t.Run("arguments are rejected", func(t *testing.T) {
+		var puts []*dynamodb.PutItemInput
+		_, err := newConn(&puts, "").ExecContext(ctx, script, []driver.NamedValue{{Ordinal: 1, Value: 1}})
+		require.EqualError(t, err, "a script of 3 statements can't take arguments, execute its statements one at a time instead")
+	})
+
+	t.Run("syntax errors are reported before anything runs", func(t *testing.T) {
+		var puts []*dynamodb.PutItemInput
+		c := newConn(&puts, "")
+		_, err := c.ExecContext(ctx, script+"INSERT INTO items", nil)
+		require.EqualError(t, err, `6:18: unexpected token "<EOF>" (expected "VALUES" | "SELECT")`)
+		require.Empty(t, c.dynamo.(*mockDynamoDB).tables)
+	})
+}
+
+func TestTimeToLive(t *testing.T) {
+	ctx := context.Background()
+	query := "CREATE TABLE items (pk STRING HASH KEY, BILLING MODE PAY_PER_REQUEST) TTL (expires_at)"
+
+	t.Run("enabled after create", func(t *testing.T) {
+		var calls []*dynamodb.UpdateTimeToLiveInput
+		m := &mockDynamoDB{
+			tables: map[string]*dynamodb.CreateTableInput{},
+			updateTTL: func(ctx aws.Context, in *dynamodb.UpdateTimeToLiveInput) (*dynamodb.UpdateTimeToLiveOutput, error) {
+				calls = append(calls, in)
+				return &dynamodb.UpdateTimeToLiveOutput{}, nil
+			},
+		}
+		c := newMockConn(m)
+		_, err := c.ExecContext(ctx, query, nil)
+		require.NoError(t, err)
+		require.Contains(t, m.tables, "items")
+		require.Len(t, calls, 1)
+		require.Equal(t, "items", *calls[0].TableName)
+		require.Equal(t, "expires_at", *calls[0].TimeToLiveSpecification.AttributeName)
+		require.True(t, *calls[0].TimeToLiveSpecification.Enabled)
+	})
+
+	t.Run("reports UpdateTimeToLive errors", func(t *testing.T) {
+		m := &mockDynamoDB{
+			tables: map[string]*dynamodb.CreateTableInput{},
+			updateTTL: func(ctx aws.Context, in *dynamodb.UpdateTimeToLiveInput) (*dynamodb.UpdateTimeToLiveOutput, error) {
+				return nil, awserr.New(dynamodb.ErrCodeLimitExceededException, "too many requests", nil)
+			},
+		}
+		c := newMockConn(m)
+		_, err := c.ExecContext(ctx, query, nil)
+		require.EqualError(t, err, `table "items" was created but enabling TTL on "expires_at" failed: LimitExceededException: too many requests`)
+	})
+
+	t.Run("reports CreateTable errors", func(t *testing.T) {
+		m := &mockDynamoDB{tables: map[string]*dynamodb.CreateTableInput{"items": {TableName: aws.String("items")}}}
+		c := newMockConn(m)
+		_, err := c.ExecContext(ctx, query, nil)
+		require.Equal(t, dynamodb.ErrCodeResourceInUseException, err.(awserr.Error).Code())
+	})
+}
+
+func TestPreloadTables(t *testing.T) {
+	ctx := context.Background()
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{
+			"items": {
+				TableName: aws.String("items"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				},
+			},
+		},
+	}
+
+	c, err := New(Config{DynamoDB: m, PreloadTables: []string{"items"}}).OpenConnector("")
+	require.NoError(t, err)
+	_, err = c.Connect(ctx)
+	require.NoError(t, err)
+	// The schema is served from the cache once loaded, even if DescribeTable would now fail.
+	delete(m.tables, "items")
+	table, err := c.(*connector).tables.Get(ctx, "items")
+	require.NoError(t, err)
+	require.Equal(t, "pk", table.HashKey)
+
+	c, err = New(Config{DynamoDB: m, PreloadTables: []string{"missing"}}).OpenConnector("")
+	require.NoError(t, err)
+	_, err = c.Connect(ctx)
+	require.EqualError(t, err, `preloading schema of table "missing": table does not exist`)
+}
+
+func TestTablePrefix(t *testing.T) {
+	ctx := context.Background()
+	var (
+		gets []*dynamodb.GetItemInput
+		puts []*dynamodb.PutItemInput
+	)
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{
+			"prod_items": {
+				TableName: aws.String("prod_items"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				},
+			},
+		},
+		getItem: func(ctx aws.Context, in *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			gets = append(gets, in)
+			return &dynamodb.GetItemOutput{}, nil
+		},
+		putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			puts = append(puts, in)
+			return &dynamodb.PutItemOutput{}, nil
+		},
+	}
+	connector, err := New(Config{DynamoDB: m, TablePrefix: "prod_", PreloadTables: []string{"items"}}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO items VALUES ('{"pk": "a"}')`)
+	require.NoError(t, err)
+	require.Equal(t, "prod_items", *puts[0].TableName)
+
+	// A name that already has the prefix is not prefixed again.
+	for _, query := range []string{`SELECT * FROM items WHERE pk = 'a'`, "SELECT * FROM `prod_items` WHERE pk = 'a'"} {
+		rows, err := db.QueryContext(ctx, query)
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+	}
+	require.Len(t, gets, 2)
+	require.Equal(t, "prod_items", *gets[0].TableName)
+	require.Equal(t, "prod_items", *gets[1].TableName)
+
+	var table string
+	err = db.QueryRowContext(ctx, `EXPLAIN SELECT * FROM items WHERE pk = 'a'`).Scan(new(string), &table, new(string), new(string),
+		new(string), new(string), new(string), new(string), new(string), new(string))
+	require.NoError(t, err)
+	require.Equal(t, "prod_items", table)
+}
+
+// blockingDescribe is a client whose DescribeTable only returns once its context is done.
+type blockingDescribe struct {
+	*mockDynamoDB
+}
+
+func (b blockingDescribe) DescribeTableWithContext(ctx aws.Context, in *dynamodb.DescribeTableInput, opts ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestSchemaLoadingUsesContext(t *testing.T) {
+	queried := false
+	m := &mockDynamoDB{
+		query: func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			queried = true
+			return &dynamodb.QueryOutput{}, nil
+		},
+	}
+	dynamo := blockingDescribe{m}
+	c := conn{dynamo: dynamo, tables: schema.NewTableLoader(dynamo)}
+	const query = `SELECT * FROM items WHERE id = 'a' AND n > 1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := c.QueryContext(ctx, query, nil)
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = c.QueryContext(ctx, query, nil)
+	require.Equal(t, context.Canceled, err)
+	require.False(t, queried)
+}
@@ -0,0 +1,164 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteExec(t *testing.T) {
+	tables := map[string]*dynamodb.CreateTableInput{
+		"movies": {
+			TableName: aws.String("movies"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("title"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				{AttributeName: aws.String("year"), KeyType: aws.String(dynamodb.KeyTypeRange)},
+			},
+		},
+	}
+	ctx := context.Background()
+	const deleteMovie = `DELETE FROM movies WHERE title = :title AND year = :year AND rating < 5`
+	const deleteByKey = `DELETE FROM movies WHERE title = :title AND year = :year`
+	args := []driver.NamedValue{
+		{Name: "title", Value: "Rush Hour"},
+		{Name: "year", Value: 1998},
+	}
+
+	t.Run("deletes the item with DeleteItem", func(t *testing.T) {
+		var deletes []*dynamodb.DeleteItemInput
+		c := newMockConn(&mockDynamoDB{tables: tables, deleteItem: func(ctx aws.Context, in *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			deletes = append(deletes, in)
+			return &dynamodb.DeleteItemOutput{}, nil
+		}})
+		res, err := c.ExecContext(ctx, deleteMovie, args)
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(1), n)
+		require.Len(t, deletes, 1)
+		require.Equal(t, "attribute_exists(title) AND rating < :_gen1", *deletes[0].ConditionExpression)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			"title": {S: aws.String("Rush Hour")},
+			"year":  {N: aws.String("1998")},
+		}, deletes[0].Key)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{":_gen1": {N: aws.String("5")}}, deletes[0].ExpressionAttributeValues)
+	})
+
+	t.Run("missing items are not deleted", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, deleteItem: func(ctx aws.Context, in *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+		}})
+		res, err := c.ExecContext(ctx, deleteByKey, args)
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(0), n)
+	})
+
+	t.Run("ErrorOnMissingItem fails on missing items", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, deleteItem: func(ctx aws.Context, in *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+		}})
+		c.failOnMissing = true
+		_, err := c.ExecContext(ctx, deleteByKey, args)
+		require.True(t, errors.Is(err, ErrConditionFailed), "%+v", err)
+	})
+
+	t.Run("a failed condition fails with ErrConditionFailed", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, deleteItem: func(ctx aws.Context, in *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+		}})
+		_, err := c.ExecContext(ctx, deleteMovie, args)
+		require.True(t, errors.Is(err, ErrConditionFailed), "%+v", err)
+	})
+
+	t.Run("a version check on a placeholder is part of the condition", func(t *testing.T) {
+		tables := map[string]*dynamodb.CreateTableInput{
+			"accounts": {
+				TableName: aws.String("accounts"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				},
+			},
+		}
+		var deletes []*dynamodb.DeleteItemInput
+		c := newMockConn(&mockDynamoDB{tables: tables, deleteItem: func(ctx aws.Context, in *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			deletes = append(deletes, in)
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+		}})
+		_, err := c.ExecContext(ctx, `DELETE FROM accounts WHERE pk = :p AND version = :v`, []driver.NamedValue{
+			{Name: "p", Value: "acct-1"},
+			{Name: "v", Value: 3},
+		})
+		require.True(t, errors.Is(err, ErrConditionFailed), "%+v", err)
+		var awsErr awserr.Error
+		require.True(t, errors.As(err, &awsErr))
+		require.Equal(t, dynamodb.ErrCodeConditionalCheckFailedException, awsErr.Code())
+
+		require.Len(t, deletes, 1)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{"pk": {S: aws.String("acct-1")}}, deletes[0].Key)
+		require.Equal(t, "attribute_exists(pk) AND version = :v", *deletes[0].ConditionExpression)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{":v": {N: aws.String("3")}}, deletes[0].ExpressionAttributeValues)
+	})
+
+	t.Run("RETURNING ALL_OLD returns the deleted item", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, deleteItem: func(ctx aws.Context, in *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			require.Equal(t, dynamodb.ReturnValueAllOld, *in.ReturnValues)
+			return &dynamodb.DeleteItemOutput{Attributes: map[string]*dynamodb.AttributeValue{
+				"title":  {S: aws.String("Rush Hour")},
+				"year":   {N: aws.String("1998")},
+				"rating": {N: aws.String("3.5")},
+			}}, nil
+		}})
+		rows, err := c.QueryContext(ctx, deleteMovie+" RETURNING ALL_OLD", args)
+		require.NoError(t, err)
+		require.Equal(t, []string{"rating", "title", "year"}, rows.Columns())
+		dest := make([]driver.Value, 3)
+		require.NoError(t, rows.Next(dest))
+		require.Equal(t, []driver.Value{3.5, "Rush Hour", int64(1998)}, dest)
+		require.Equal(t, io.EOF, rows.Next(dest))
+	})
+
+	t.Run("deletes are buffered in a transaction", func(t *testing.T) {
+		var calls []*dynamodb.TransactWriteItemsInput
+		c := newMockConn(&mockDynamoDB{tables: tables, transact: func(ctx aws.Context, in *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+			calls = append(calls, in)
+			return &dynamodb.TransactWriteItemsOutput{}, nil
+		}})
+		tx, err := c.BeginTx(ctx, driver.TxOptions{})
+		require.NoError(t, err)
+		_, err = c.ExecContext(ctx, deleteMovie, args)
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+		require.Len(t, calls, 1)
+		del := calls[0].TransactItems[0].Delete
+		require.Equal(t, "attribute_exists(title) AND rating < :_gen1", *del.ConditionExpression)
+		require.Equal(t, "Rush Hour", *del.Key["title"].S)
+	})
+
+	t.Run("conditions can be grouped with OR in parentheses", func(t *testing.T) {
+		var deletes []*dynamodb.DeleteItemInput
+		c := newMockConn(&mockDynamoDB{tables: tables, deleteItem: func(ctx aws.Context, in *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			deletes = append(deletes, in)
+			return &dynamodb.DeleteItemOutput{}, nil
+		}})
+		_, err := c.ExecContext(ctx, `DELETE FROM movies WHERE (title = :title AND year = :year) AND (rating < 5 OR views = 0) AND attribute_exists(plot)`, args)
+		require.NoError(t, err)
+		require.Equal(t, "attribute_exists(title) AND (rating < :_gen1 OR #views = :_gen2) AND attribute_exists(plot)", *deletes[0].ConditionExpression)
+		require.Len(t, deletes[0].Key, 2)
+
+		_, err = c.ExecContext(ctx, `DELETE FROM movies WHERE title = :title AND year = :year AND rating < 5 OR views = 0`, args)
+		require.EqualError(t, err, "DELETE can not have OR at the top level of the WHERE clause, which must AND the key of the item with its other conditions, put the OR in parentheses, as in WHERE title = :param AND year = :param AND (a = :a OR b = :b)")
+	})
+
+	t.Run("the full key is required", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables})
+		_, err := c.ExecContext(ctx, `DELETE FROM movies WHERE title = :title`, args[:1])
+		require.EqualError(t, err, "DELETE requires each key attribute in the WHERE clause, in an equality condition, such as: WHERE title = :param AND year = :param")
+	})
+}
@@ -0,0 +1,48 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+func TestDescribe(t *testing.T) {
+	ctx := context.Background()
+	c := newMockConn(&mockDynamoDB{tables: map[string]*dynamodb.CreateTableInput{
+		"gamescores": fixtures.GameScores.Create,
+	}})
+
+	rows, err := c.QueryContext(ctx, "DESCRIBE gamescores", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"attribute", "type", "key_type", "index"}, rows.Columns())
+	var got [][]driver.Value
+	for {
+		dest := make([]driver.Value, 4)
+		err := rows.Next(dest)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, dest)
+	}
+	require.Equal(t, [][]driver.Value{
+		{"UserId", "S", "HASH", ""},
+		{"GameTitle", "S", "RANGE", ""},
+		{"UserId", "S", "HASH", "UserWinsIndex"},
+		{"Wins", "N", "RANGE", "UserWinsIndex"},
+		{"GameTitle", "S", "HASH", "GameTitleIndex"},
+		{"TopScore", "N", "RANGE", "GameTitleIndex"},
+	}, got)
+
+	_, err = c.ExecContext(ctx, "DESCRIBE gamescores", nil)
+	require.EqualError(t, err, "DESCRIBE returns rows, use Query() instead of Exec()")
+
+	_, err = c.QueryContext(ctx, "DESCRIBE missing", nil)
+	require.Error(t, err)
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"fmt"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
+	"github.com/mightyguava/dynamosql/querybuilder"
 	"github.com/mightyguava/dynamosql/schema"
 )
 
@@ -28,17 +32,71 @@ var _ driver.DriverContext = &Driver{}
 type Config struct {
 	// If set, the driver will use this DynamoDB client. The Session param and the connection string will be ignored
 	DynamoDB dynamodbiface.DynamoDBAPI
-	// If set, and DynamoDB is not set, the driver will try to create a DynamoDB client using this session.
+	// If set, and DynamoDB is not set, the driver will try to create a DynamoDB client using this session. The
+	// connection string will be ignored.
 	Session *session.Session
 	// If set, the wrapper collections []*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue will be mapped
 	// unmarshaled into using the dynamodbattribute package into []interface{} and map[string]interface{}, respectively.
 	AlwaysConvertCollectionsToGoType bool
+	// SchemaCacheTTL is how long the schema of a table is cached before DescribeTable is called again. Zero, the
+	// default, caches schemas for the life of the connector. Schemas of tables created or dropped through the driver
+	// are always refreshed immediately.
+	SchemaCacheTTL time.Duration
+	// PreloadTables are the tables whose schemas are loaded when a connection is opened, rather than on the first
+	// statement that uses them. Connect returns an error if any of them does not exist.
+	PreloadTables []string
+	// If set, requests ask DynamoDB for the capacity they consume, and the rows and results of statements implement
+	// CapacityReporter. It can also be enabled with consumed_capacity=true in the connection string.
+	ReturnConsumedCapacity bool
+	// If set, CREATE TABLE blocks until the new table is ACTIVE, so that statements that follow it can use the table.
+	// It can also be enabled with wait_for_active=true in the connection string.
+	WaitForActiveTables bool
+	// Retry configures how requests that DynamoDB throttles are retried. The zero value does not retry.
+	Retry RetryPolicy
+	// If set, a SELECT that would Scan because its WHERE clause can't be used to Query fails with ErrScanDisallowed
+	// instead, to guard against accidentally reading whole tables. WITH (SCAN) or WITH (SEGMENTS = n) still scan. It
+	// can also be enabled with disallow_scan=true in the connection string.
+	DisallowScan bool
+	// If set, numbers are returned as strings holding the exact decimal DynamoDB stores, rather than as int64 or
+	// float64, so that large integers such as IDs do not lose precision. Numbers within collections converted by
+	// AlwaysConvertCollectionsToGoType are returned as dynamodbattribute.Number. It can also be enabled with
+	// number_as_string=true in the connection string.
+	NumberAsString bool
+	// TablePrefix is prepended to the name of every table used by a statement, so that FROM orders reads
+	// prod_orders with a prefix of prod_, and the same statements can be run in each environment. Names that already
+	// start with the prefix, and PreloadTables, are prefixed the same way. It can also be set with table_prefix=prod_
+	// in the connection string, which takes precedence.
+	TablePrefix string
+	// If set, every conditional write, whether of a single item or in a transaction, asks DynamoDB to return the
+	// existing item if its condition fails, which ConditionFailedItem retrieves from the error. This saves reading the
+	// item again to find out why an optimistic write was rejected. It can also be enabled with
+	// return_item_on_condition_failure=true in the connection string.
+	ReturnItemOnConditionFailure bool
+	// If set, an UPDATE or DELETE whose WHERE clause only has the key fails with ErrConditionFailed if the item does
+	// not exist, rather than affecting no rows. It can also be enabled with error_on_missing_item=true in the
+	// connection string.
+	//
+	// An UPDATE or DELETE whose WHERE clause has conditions other than the key, such as the version check of
+	// UPDATE t SET v = :new, version = version + 1 WHERE id = :id AND version = :expected, fails with
+	// ErrConditionFailed whether or not this is set.
+	ErrorOnMissingItem bool
+	// If set, a SELECT whose USE INDEX names a global secondary index that does not project every attribute it reads
+	// is run as if it had no USE INDEX, which queries the table or an index that projects them, or scans the table,
+	// rather than failing with querybuilder.ErrNotProjected. It can also be enabled with unprojected_index_fallback=true
+	// in the connection string.
+	UnprojectedIndexFallback bool
+	// Logger, if set, is called with every request made to DynamoDB, its compiled expressions, attribute names and
+	// values, and how long it took, for troubleshooting statements that behave unexpectedly.
+	Logger Logger
+	// If set, the values bound by statements are left out of the requests passed to Logger, as they may be sensitive.
+	RedactLoggedValues bool
 }
 
 // New creates a Driver instance using a custom config. This may be easier to use than via sql.Open.
 // A driver created using this function can be used to obtain a sql.DB like so
-//  driver, err := New(Config{Session: sess}).OpenConnector("")
-//  db := sql.OpenDB(driver)
+//
+//	driver, err := New(Config{Session: sess}).OpenConnector("")
+//	db := sql.OpenDB(driver)
 func New(cfg Config) *Driver {
 	return &Driver{
 		cfg: cfg,
@@ -75,26 +133,85 @@ func (d *Driver) Open(connStr string) (driver.Conn, error) {
 
 // OpenConnector initializes and returns a Connector. The db/sql package will call this exactly once
 // per sql.Open() call. New connections to the database will use the returned Connector.
+//
+// The connection string is a list of semicolon separated key=value pairs. All keys are optional and an empty
+// connection string uses the default AWS session. Supported keys are
+//
+//	local              true to connect to DynamoDB Local, see below
+//	region             AWS region, such as us-west-2
+//	endpoint           DynamoDB endpoint, such as http://localhost:8000 for DynamoDB Local
+//	access_key         AWS access key ID, requires secret_key
+//	secret_key         AWS secret access key, requires access_key
+//	consumed_capacity  true to report consumed capacity, like Config.ReturnConsumedCapacity
+//	wait_for_active    true to wait for created tables to become ACTIVE, like Config.WaitForActiveTables
+//	disallow_scan      true to reject SELECTs that would Scan, like Config.DisallowScan
+//	number_as_string   true to return numbers as strings, like Config.NumberAsString
+//	table_prefix       prefix of the names of tables, like Config.TablePrefix
+//	return_item_on_condition_failure
+//	                   true to return the item of a failed condition, like Config.ReturnItemOnConditionFailure
+//	error_on_missing_item
+//	                   true to fail UPDATEs and DELETEs of missing items, like Config.ErrorOnMissingItem
+//	unprojected_index_fallback
+//	                   true to ignore USE INDEX of an index missing attributes, like Config.UnprojectedIndexFallback
+//
+// local=true is a shorthand for endpoint=http://localhost:8000 with dummy credentials, so that tests against
+// DynamoDB Local only need "local=true;region=us-east-1". An endpoint, access_key or secret_key given alongside it
+// takes precedence over the ones it implies.
 func (d *Driver) OpenConnector(connStr string) (driver.Connector, error) {
+	if err := d.cfg.Retry.validate(); err != nil {
+		return nil, err
+	}
 	var dynamo dynamodbiface.DynamoDBAPI
+	returnCapacity := d.cfg.ReturnConsumedCapacity
+	waitForActive := d.cfg.WaitForActiveTables
+	disallowScan := d.cfg.DisallowScan
+	numberAsString := d.cfg.NumberAsString
+	tablePrefix := d.cfg.TablePrefix
+	returnItem := d.cfg.ReturnItemOnConditionFailure
+	failOnMissing := d.cfg.ErrorOnMissingItem
+	indexFallback := d.cfg.UnprojectedIndexFallback
 	if d.cfg.DynamoDB != nil {
 		dynamo = d.cfg.DynamoDB
 	} else {
-		var err error
 		sess := d.cfg.Session
 		if sess == nil {
-			sess, err = session.NewSession(nil)
+			dsn, err := parseDSN(connStr)
+			if err != nil {
+				return nil, err
+			}
+			returnCapacity = returnCapacity || dsn.ConsumedCapacity
+			waitForActive = waitForActive || dsn.WaitForActive
+			disallowScan = disallowScan || dsn.DisallowScan
+			numberAsString = numberAsString || dsn.NumberAsString
+			returnItem = returnItem || dsn.ReturnItemOnConditionFailure
+			failOnMissing = failOnMissing || dsn.ErrorOnMissingItem
+			indexFallback = indexFallback || dsn.UnprojectedIndexFallback
+			if dsn.TablePrefix != "" {
+				tablePrefix = dsn.TablePrefix
+			}
+			sess, err = session.NewSession(dsn.AWSConfig())
 			if err != nil {
 				return nil, err
 			}
 		}
 		dynamo = dynamodb.New(sess)
 	}
+	dynamo = withLogger(dynamo, d.cfg.Logger, d.cfg.RedactLoggedValues)
+	dynamo = withRetries(dynamo, d.cfg.Retry)
+	dynamo = withConditionFailedItems(dynamo, returnItem)
 	return &connector{
-		dynamo:      dynamo,
-		driver:      d,
-		tables:      schema.NewTableLoader(dynamo),
-		mapToGoType: d.cfg.AlwaysConvertCollectionsToGoType,
+		dynamo:         dynamo,
+		driver:         d,
+		tables:         schema.NewTableLoaderWithTTL(dynamo, d.cfg.SchemaCacheTTL),
+		mapToGoType:    d.cfg.AlwaysConvertCollectionsToGoType,
+		preload:        d.cfg.PreloadTables,
+		returnCapacity: returnCapacity,
+		waitForActive:  waitForActive,
+		disallowScan:   disallowScan,
+		numberAsString: numberAsString,
+		tablePrefix:    tablePrefix,
+		failOnMissing:  failOnMissing,
+		indexFallback:  indexFallback,
 	}, nil
 }
 
@@ -103,12 +220,44 @@ type connector struct {
 	dynamo      dynamodbiface.DynamoDBAPI
 	tables      *schema.TableLoader
 	mapToGoType bool
+	// preload are the tables whose schemas are loaded by Connect.
+	preload        []string
+	returnCapacity bool
+	waitForActive  bool
+	disallowScan   bool
+	numberAsString bool
+	tablePrefix    string
+	failOnMissing  bool
+	indexFallback  bool
 }
 
 var _ driver.Connector = &connector{}
 
+// Connect opens a connection, first loading the schemas of the preloaded tables. Schemas are shared by all the
+// connections of a connector, so only the first Connect calls DescribeTable unless the schemas expire.
 func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
-	return &conn{dynamo: c.dynamo, tables: c.tables, mapToGoType: c.mapToGoType}, nil
+	for _, name := range c.preload {
+		name = querybuilder.PrefixTable(name, c.tablePrefix)
+		_, err := c.tables.Get(ctx, name)
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == dynamodb.ErrCodeResourceNotFoundException {
+			return nil, fmt.Errorf("preloading schema of table %q: table does not exist", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("preloading schema of table %q: %w", name, err)
+		}
+	}
+	return &conn{
+		dynamo:         c.dynamo,
+		tables:         c.tables,
+		mapToGoType:    c.mapToGoType,
+		returnCapacity: c.returnCapacity,
+		waitForActive:  c.waitForActive,
+		disallowScan:   c.disallowScan,
+		numberAsString: c.numberAsString,
+		tablePrefix:    c.tablePrefix,
+		failOnMissing:  c.failOnMissing,
+		indexFallback:  c.indexFallback,
+	}, nil
 }
 
 func (c *connector) Driver() driver.Driver {
@@ -3,6 +3,7 @@ package dynamosql
 import (
 	"bufio"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -64,6 +65,25 @@ func TestDriverBind(t *testing.T) {
 	})
 }
 
+func TestDriverScan(t *testing.T) {
+	sess := fixtures.SetUp(t, fixtures.GameScores)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+
+	rows, err := db.Query(`SELECT UserId, GameTitle FROM gamescores WHERE Wins > 30`)
+	require.NoError(t, err)
+	var games []string
+	for rows.Next() {
+		var userID, title string
+		require.NoError(t, rows.Scan(&userID, &title))
+		games = append(games, userID+":"+title)
+	}
+	require.NoError(t, rows.Err())
+	require.ElementsMatch(t, []string{"102:Alien Adventure", "103:Galaxy Invaders"}, games)
+}
+
 func TestDriverGolden(t *testing.T) {
 	sess := fixtures.SetUp(t, fixtures.GameScores, fixtures.Movies)
 
@@ -227,16 +247,18 @@ func TestInsertCannotOverwrite(t *testing.T) {
 	_, err = db.Exec("INSERT INTO movies VALUES (?)", []fixtures.Movie{
 		prisoners,
 	})
-	require.Error(t, err)
-	require.IsType(t, &dynamodb.ConditionalCheckFailedException{}, err)
+	require.True(t, errors.Is(err, ErrConditionFailed), "%+v", err)
+	var conditionErr *dynamodb.ConditionalCheckFailedException
+	require.True(t, errors.As(err, &conditionErr), "%+v", err)
 
 	// Failure is transactional
 	_, err = db.Exec("INSERT INTO movies VALUES (?)", []fixtures.Movie{
 		rushHour,
 		prisoners,
 	})
-	require.Error(t, err)
-	require.IsType(t, &dynamodb.TransactionCanceledException{}, err)
+	require.True(t, errors.Is(err, ErrConditionFailed), "%+v", err)
+	var cancelErr *dynamodb.TransactionCanceledException
+	require.True(t, errors.As(err, &cancelErr), "%+v", err)
 
 	row := db.QueryRow(`SELECT * FROM movies WHERE title = ?`, rushHour.Title)
 	var movie fixtures.Movie
@@ -0,0 +1,126 @@
+package dynamosql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// dsn holds the settings parsed from a connection string of the form
+//
+//	region=us-west-2;endpoint=http://localhost:8000;access_key=AKID;secret_key=SECRET
+type dsn struct {
+	Local                        bool
+	Region                       string
+	Endpoint                     string
+	AccessKey                    string
+	SecretKey                    string
+	ConsumedCapacity             bool
+	WaitForActive                bool
+	DisallowScan                 bool
+	NumberAsString               bool
+	TablePrefix                  string
+	ReturnItemOnConditionFailure bool
+	ErrorOnMissingItem           bool
+	UnprojectedIndexFallback     bool
+}
+
+const (
+	// localEndpoint is the endpoint of DynamoDB Local when started with its default port.
+	localEndpoint = "http://localhost:8000"
+	// localAccessKey and localSecretKey are dummy credentials, which DynamoDB Local accepts but does not check.
+	localAccessKey = "fake"
+	localSecretKey = "secret"
+)
+
+// parseDSN parses a connection string. With local=true, endpoint defaults to DynamoDB Local and access_key and
+// secret_key to dummy credentials, while an explicit endpoint or pair of keys takes precedence.
+func parseDSN(connStr string) (*dsn, error) {
+	d := &dsn{}
+	for _, pair := range strings.Split(connStr, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		eq := strings.Index(pair, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("invalid connection string parameter %q, expected key=value", pair)
+		}
+		key, value := strings.TrimSpace(pair[:eq]), strings.TrimSpace(pair[eq+1:])
+		var err error
+		switch key {
+		case "local":
+			err = parseBoolOption(key, value, &d.Local)
+		case "region":
+			d.Region = value
+		case "endpoint":
+			d.Endpoint = value
+		case "access_key":
+			d.AccessKey = value
+		case "secret_key":
+			d.SecretKey = value
+		case "consumed_capacity":
+			err = parseBoolOption(key, value, &d.ConsumedCapacity)
+		case "wait_for_active":
+			err = parseBoolOption(key, value, &d.WaitForActive)
+		case "disallow_scan":
+			err = parseBoolOption(key, value, &d.DisallowScan)
+		case "number_as_string":
+			err = parseBoolOption(key, value, &d.NumberAsString)
+		case "table_prefix":
+			d.TablePrefix = value
+		case "return_item_on_condition_failure":
+			err = parseBoolOption(key, value, &d.ReturnItemOnConditionFailure)
+		case "error_on_missing_item":
+			err = parseBoolOption(key, value, &d.ErrorOnMissingItem)
+		case "unprojected_index_fallback":
+			err = parseBoolOption(key, value, &d.UnprojectedIndexFallback)
+		default:
+			return nil, fmt.Errorf("unknown connection string parameter %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if (d.AccessKey == "") != (d.SecretKey == "") {
+		return nil, fmt.Errorf("access_key and secret_key must be provided together")
+	}
+	if d.Local {
+		if d.Endpoint == "" {
+			d.Endpoint = localEndpoint
+		}
+		if d.AccessKey == "" {
+			d.AccessKey, d.SecretKey = localAccessKey, localSecretKey
+		}
+	}
+	return d, nil
+}
+
+// parseBoolOption sets field to the boolean value of the parameter key.
+func parseBoolOption(key, value string, field *bool) error {
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q, expected true or false", key, value)
+	}
+	*field = enabled
+	return nil
+}
+
+// AWSConfig returns the aws.Config for the settings in the connection string. Settings that were not provided are
+// left unset so the SDK defaults apply.
+func (d *dsn) AWSConfig() *aws.Config {
+	cfg := aws.NewConfig()
+	if d.Region != "" {
+		cfg = cfg.WithRegion(d.Region)
+	}
+	if d.Endpoint != "" {
+		cfg = cfg.WithEndpoint(d.Endpoint)
+	}
+	if d.AccessKey != "" {
+		cfg = cfg.WithCredentials(credentials.NewStaticCredentials(d.AccessKey, d.SecretKey, ""))
+	}
+	return cfg
+}
@@ -0,0 +1,149 @@
+package dynamosql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		connStr string
+		dsn     *dsn
+		err     string
+	}{
+		{
+			name:    "empty",
+			connStr: "",
+			dsn:     &dsn{},
+		},
+		{
+			name:    "all keys",
+			connStr: "region=us-west-2;endpoint=http://localhost:8000;access_key=fake;secret_key=secret",
+			dsn: &dsn{
+				Region:    "us-west-2",
+				Endpoint:  "http://localhost:8000",
+				AccessKey: "fake",
+				SecretKey: "secret",
+			},
+		},
+		{
+			name:    "whitespace and trailing separator",
+			connStr: " region = us-east-1 ; ",
+			dsn:     &dsn{Region: "us-east-1"},
+		},
+		{
+			name:    "consumed capacity",
+			connStr: "region=us-west-2;consumed_capacity=true",
+			dsn:     &dsn{Region: "us-west-2", ConsumedCapacity: true},
+		},
+		{
+			name:    "invalid consumed capacity",
+			connStr: "consumed_capacity=yes",
+			err:     `invalid consumed_capacity "yes", expected true or false`,
+		},
+		{
+			name:    "wait for active",
+			connStr: "wait_for_active=1",
+			dsn:     &dsn{WaitForActive: true},
+		},
+		{
+			name:    "disallow scan",
+			connStr: "disallow_scan=true",
+			dsn:     &dsn{DisallowScan: true},
+		},
+		{
+			name:    "invalid disallow scan",
+			connStr: "disallow_scan=on",
+			err:     `invalid disallow_scan "on", expected true or false`,
+		},
+		{
+			name:    "number as string",
+			connStr: "number_as_string=true",
+			dsn:     &dsn{NumberAsString: true},
+		},
+		{
+			name:    "table prefix",
+			connStr: "table_prefix=prod_",
+			dsn:     &dsn{TablePrefix: "prod_"},
+		},
+		{
+			name:    "return item on condition failure",
+			connStr: "return_item_on_condition_failure=true",
+			dsn:     &dsn{ReturnItemOnConditionFailure: true},
+		},
+		{
+			name:    "error on condition failure",
+			connStr: "error_on_missing_item=true",
+			dsn:     &dsn{ErrorOnMissingItem: true},
+		},
+		{
+			name:    "unprojected index fallback",
+			connStr: "unprojected_index_fallback=true",
+			dsn:     &dsn{UnprojectedIndexFallback: true},
+		},
+		{
+			name:    "local",
+			connStr: "local=true;region=us-east-1",
+			dsn: &dsn{
+				Local:     true,
+				Region:    "us-east-1",
+				Endpoint:  "http://localhost:8000",
+				AccessKey: "fake",
+				SecretKey: "secret",
+			},
+		},
+		{
+			name:    "local with explicit endpoint and credentials",
+			connStr: "endpoint=http://localhost:4566;local=true;access_key=AKID;secret_key=SECRET",
+			dsn: &dsn{
+				Local:     true,
+				Endpoint:  "http://localhost:4566",
+				AccessKey: "AKID",
+				SecretKey: "SECRET",
+			},
+		},
+		{
+			name:    "local disabled",
+			connStr: "local=false",
+			dsn:     &dsn{},
+		},
+		{
+			name:    "invalid local",
+			connStr: "local=maybe",
+			err:     `invalid local "maybe", expected true or false`,
+		},
+		{
+			name:    "unknown key",
+			connStr: "region=us-west-2;color=blue",
+			err:     `unknown connection string parameter "color"`,
+		},
+		{
+			name:    "missing value",
+			connStr: "region",
+			err:     `invalid connection string parameter "region", expected key=value`,
+		},
+		{
+			name:    "partial credentials",
+			connStr: "access_key=fake",
+			err:     "access_key and secret_key must be provided together",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, err := parseDSN(test.connStr)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.dsn, d)
+		})
+	}
+}
+
+func TestOpenConnectorRejectsInvalidDSN(t *testing.T) {
+	_, err := (&Driver{}).OpenConnector("bogus=1")
+	require.EqualError(t, err, `unknown connection string parameter "bogus"`)
+}
@@ -0,0 +1,153 @@
+package dynamosql
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/querybuilder"
+)
+
+// ErrConditionFailed is returned when a conditional write is rejected by DynamoDB, such as an INSERT of an item
+// whose key already exists. Use errors.Is to detect it. The original ConditionalCheckFailedException can still be
+// retrieved with errors.As.
+//
+// A transaction, or an INSERT of several items, that DynamoDB cancels because a condition failed returns it too. The
+// error used to be the *dynamodb.TransactionCanceledException itself, so callers that type-assert it must now use
+// errors.As to retrieve it and its CancellationReasons.
+var ErrConditionFailed = errors.New("condition failed")
+
+// ErrNoLastInsertID is returned by the LastInsertId of every result, as DynamoDB has no auto-increment keys to report.
+// Use RowsAffected to find how many items a statement wrote.
+var ErrNoLastInsertID = querybuilder.ErrNoLastInsertID
+
+// ErrUnprocessedItems is returned by a REPLACE of several items, or a REPLACE ... SELECT, which write in batches with
+// BatchWriteItem, if DynamoDB still leaves some of the items unprocessed after they have been retried, or the context
+// is done first. The items that were written are kept. Use UnprocessedKeys to find which ones were not. An INSERT
+// never returns it, as BatchWriteItem can't check that its items do not exist.
+var ErrUnprocessedItems = querybuilder.ErrUnprocessedItems
+
+// ErrScanDisallowed is returned by a SELECT that would Scan a table or index when Config.DisallowScan is set. The
+// error explains the conditions the WHERE clause needs to Query instead. Use errors.Is to detect it.
+var ErrScanDisallowed = errors.New("scan disallowed")
+
+type conditionFailedError struct {
+	err error
+	// item is the item whose condition failed, as it was when the write was rejected.
+	item map[string]*dynamodb.AttributeValue
+}
+
+func (c *conditionFailedError) Error() string {
+	return ErrConditionFailed.Error() + ": " + c.err.Error()
+}
+func (c *conditionFailedError) Unwrap() error { return c.err }
+
+func (c *conditionFailedError) Is(target error) bool {
+	return target == ErrConditionFailed
+}
+
+// ConditionFailedItem returns the item whose condition failed, if err is an ErrConditionFailed that DynamoDB returned
+// the item with, so that the conflicting state can be inspected without reading it again. DynamoDB only returns it for
+// writes made with Config.ReturnItemOnConditionFailure, and only if the item exists.
+func ConditionFailedItem(err error) (map[string]*dynamodb.AttributeValue, bool) {
+	var conditionErr *conditionFailedError
+	if !errors.As(err, &conditionErr) || conditionErr.item == nil {
+		return nil, false
+	}
+	return conditionErr.item, true
+}
+
+// UnprocessedKeys returns the number of items that were written and the keys of those that were not, if err is an
+// ErrUnprocessedItems.
+func UnprocessedKeys(err error) (written int, keys []map[string]*dynamodb.AttributeValue, ok bool) {
+	var unprocessed *querybuilder.UnprocessedItemsError
+	if !errors.As(err, &unprocessed) {
+		return 0, nil, false
+	}
+	return unprocessed.Written, unprocessed.Keys, true
+}
+
+// translateError maps DynamoDB errors that callers are expected to handle onto the errors exported by this package. A
+// transaction cancelled by a failed condition is mapped too, wrapping the TransactionCanceledException rather than
+// returning it as is. Either way the item is kept if DynamoDB returned it.
+func translateError(err error) error {
+	if conditionErr, ok := err.(*dynamodb.ConditionalCheckFailedException); ok {
+		return &conditionFailedError{err: err, item: conditionErr.Item}
+	}
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return &conditionFailedError{err: err}
+	}
+	if cancelled, ok := err.(*dynamodb.TransactionCanceledException); ok {
+		for _, reason := range cancelled.CancellationReasons {
+			if aws.StringValue(reason.Code) == "ConditionalCheckFailed" {
+				return &conditionFailedError{err: err, item: reason.Item}
+			}
+		}
+	}
+	return err
+}
+
+// withConditionFailedItems returns a client that asks DynamoDB to return the existing item of a write whose condition
+// fails, for Config.ReturnItemOnConditionFailure.
+func withConditionFailedItems(dynamo dynamodbiface.DynamoDBAPI, enabled bool) dynamodbiface.DynamoDBAPI {
+	if !enabled {
+		return dynamo
+	}
+	return &conditionFailedItemsClient{DynamoDBAPI: dynamo}
+}
+
+type conditionFailedItemsClient struct {
+	dynamodbiface.DynamoDBAPI
+}
+
+var returnValuesAllOld = aws.String(dynamodb.ReturnValuesOnConditionCheckFailureAllOld)
+
+func (c *conditionFailedItemsClient) PutItemWithContext(ctx aws.Context, in *dynamodb.PutItemInput, opts ...request.Option) (*dynamodb.PutItemOutput, error) {
+	req := *in
+	req.ReturnValuesOnConditionCheckFailure = returnValuesAllOld
+	return c.DynamoDBAPI.PutItemWithContext(ctx, &req, opts...)
+}
+
+func (c *conditionFailedItemsClient) UpdateItemWithContext(ctx aws.Context, in *dynamodb.UpdateItemInput, opts ...request.Option) (*dynamodb.UpdateItemOutput, error) {
+	req := *in
+	req.ReturnValuesOnConditionCheckFailure = returnValuesAllOld
+	return c.DynamoDBAPI.UpdateItemWithContext(ctx, &req, opts...)
+}
+
+func (c *conditionFailedItemsClient) DeleteItemWithContext(ctx aws.Context, in *dynamodb.DeleteItemInput, opts ...request.Option) (*dynamodb.DeleteItemOutput, error) {
+	req := *in
+	req.ReturnValuesOnConditionCheckFailure = returnValuesAllOld
+	return c.DynamoDBAPI.DeleteItemWithContext(ctx, &req, opts...)
+}
+
+func (c *conditionFailedItemsClient) TransactWriteItemsWithContext(ctx aws.Context, in *dynamodb.TransactWriteItemsInput, opts ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+	req := *in
+	req.TransactItems = make([]*dynamodb.TransactWriteItem, len(in.TransactItems))
+	for i, item := range in.TransactItems {
+		item := *item
+		switch {
+		case item.Put != nil:
+			put := *item.Put
+			put.ReturnValuesOnConditionCheckFailure = returnValuesAllOld
+			item.Put = &put
+		case item.Update != nil:
+			update := *item.Update
+			update.ReturnValuesOnConditionCheckFailure = returnValuesAllOld
+			item.Update = &update
+		case item.Delete != nil:
+			del := *item.Delete
+			del.ReturnValuesOnConditionCheckFailure = returnValuesAllOld
+			item.Delete = &del
+		case item.ConditionCheck != nil:
+			check := *item.ConditionCheck
+			check.ReturnValuesOnConditionCheckFailure = returnValuesAllOld
+			item.ConditionCheck = &check
+		}
+		req.TransactItems[i] = &item
+	}
+	return c.DynamoDBAPI.TransactWriteItemsWithContext(ctx, &req, opts...)
+}
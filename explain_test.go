@@ -0,0 +1,76 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+func TestExplain(t *testing.T) {
+	ctx := context.Background()
+	// The mock has no handlers for reads or writes, so any request sent to DynamoDB panics.
+	c := newMockConn(&mockDynamoDB{tables: map[string]*dynamodb.CreateTableInput{
+		"gamescores": fixtures.GameScores.Create,
+		"movies":     fixtures.Movies.Create,
+	}})
+	explain := func(t *testing.T, query string) []driver.Value {
+		t.Helper()
+		rows, err := c.QueryContext(ctx, query, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"operation", "table", "index", "key_condition", "filter", "projection", "update", "attribute_names",
+			"attribute_values", "plan",
+		}, rows.Columns())
+		dest := make([]driver.Value, len(rows.Columns()))
+		require.NoError(t, rows.Next(dest))
+		require.Equal(t, io.EOF, rows.Next(make([]driver.Value, len(dest))))
+		return dest
+	}
+
+	for _, test := range []struct {
+		name  string
+		query string
+		row   []driver.Value
+	}{
+		{"query", `EXPLAIN SELECT GameTitle, TopScore FROM gamescores WHERE UserId = :user AND TopScore > 100`,
+			[]driver.Value{"Query", "gamescores", "", "UserId = :user", "TopScore > :_gen1", "GameTitle, TopScore", "", "",
+				":_gen1 = 100, :user = ?", `Query table "gamescores"`}},
+		{"automatically selected index", `EXPLAIN SELECT UserId, TopScore FROM gamescores WHERE GameTitle = "Galaxy Invaders" AND TopScore > 100`,
+			[]driver.Value{"Query", "gamescores", "GameTitleIndex", "GameTitle = :_gen1 AND TopScore > :_gen2", "", "UserId, TopScore", "", "",
+				`:_gen1 = "Galaxy Invaders", :_gen2 = 100`, `Query index "GameTitleIndex" of table "gamescores" (index selected automatically)`}},
+		{"scan", `EXPLAIN SELECT * FROM gamescores WHERE Wins > 3`,
+			[]driver.Value{"Scan", "gamescores", "", "", "Wins > :_gen1", "", "", "", ":_gen1 = 3", `Scan table "gamescores"`}},
+		{"get item", `EXPLAIN SELECT * FROM gamescores WHERE UserId = ? AND GameTitle = ?`,
+			[]driver.Value{"GetItem", "gamescores", "", "UserId = :_pos1 AND GameTitle = :_pos2", "", "", "", "",
+				":_pos1 = ?, :_pos2 = ?", `GetItem table "gamescores"`}},
+		{"batch get item", `EXPLAIN SELECT title, year FROM movies WHERE title IN ("Heat", "Ronin") AND year = 1995`,
+			[]driver.Value{"BatchGetItem", "movies", "", "title IN (:_gen1, :_gen2) AND year = :_gen3", "", "title, #year", "", "#year = year",
+				`:_gen1 = "Heat", :_gen2 = "Ronin", :_gen3 = 1995`, `BatchGetItem table "movies"`}},
+		{"update", `EXPLAIN UPDATE gamescores SET TopScore = 5 WHERE UserId = :user AND GameTitle = "Meteor Blasters" AND Wins > 2`,
+			[]driver.Value{"UpdateItem", "gamescores", "", "UserId = :user AND GameTitle = :_gen2", "attribute_exists(UserId) AND Wins > :_gen3", "",
+				"SET TopScore = :_gen1", "", `:_gen1 = 5, :_gen2 = "Meteor Blasters", :_gen3 = 2, :user = ?`, `UpdateItem table "gamescores"`}},
+		{"delete", `EXPLAIN DELETE FROM gamescores WHERE UserId = :user AND GameTitle = "Meteor Blasters"`,
+			[]driver.Value{"DeleteItem", "gamescores", "", "UserId = :user AND GameTitle = :_gen1", "attribute_exists(UserId)", "", "", "",
+				`:_gen1 = "Meteor Blasters", :user = ?`, `DeleteItem table "gamescores"`}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.row, explain(t, test.query))
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := c.QueryContext(ctx, `EXPLAIN INSERT INTO movies VALUES (:item)`, nil)
+		require.EqualError(t, err, "EXPLAIN only supports SELECT, UPDATE and DELETE, got: INSERT INTO movies VALUES (:item)")
+		// The statement is compiled as it would be to run it.
+		_, err = c.QueryContext(ctx, `EXPLAIN SELECT * FROM missing`, nil)
+		require.Error(t, err)
+		_, err = c.ExecContext(ctx, `EXPLAIN SELECT * FROM movies`, nil)
+		require.EqualError(t, err, "EXPLAIN returns rows, use Query() instead of Exec()")
+	})
+}
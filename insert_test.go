@@ -0,0 +1,622 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertExec(t *testing.T) {
+	tables := map[string]*dynamodb.CreateTableInput{
+		"movies": {
+			TableName: aws.String("movies"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("title"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		},
+	}
+	ctx := context.Background()
+	docs := func(n int) []map[string]interface{} {
+		out := make([]map[string]interface{}, n)
+		for i := range out {
+			out[i] = map[string]interface{}{"title": fmt.Sprint(i)}
+		}
+		return out
+	}
+
+	t.Run("single row uses PutItem", func(t *testing.T) {
+		var puts []*dynamodb.PutItemInput
+		c := newMockConn(&mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			puts = append(puts, in)
+			return &dynamodb.PutItemOutput{}, nil
+		}})
+		res, err := c.ExecContext(ctx, `INSERT INTO movies VALUES ('{"title":"Rush Hour"}')`, nil)
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(1), n)
+		_, err = res.LastInsertId()
+		require.Equal(t, ErrNoLastInsertID, err)
+		require.Len(t, puts, 1)
+		require.Equal(t, "Rush Hour", *puts[0].Item["title"].S)
+		require.Equal(t, "attribute_not_exists(title)", *puts[0].ConditionExpression)
+	})
+
+	t.Run("REPLACE does not condition on existence", func(t *testing.T) {
+		var puts []*dynamodb.PutItemInput
+		c := newMockConn(&mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			puts = append(puts, in)
+			return &dynamodb.PutItemOutput{}, nil
+		}})
+		_, err := c.ExecContext(ctx, `REPLACE INTO movies VALUES ('{"title":"Rush Hour"}')`, nil)
+		require.NoError(t, err)
+		require.Nil(t, puts[0].ConditionExpression)
+	})
+
+	t.Run("INSERT fails on an existing key while REPLACE overwrites it", func(t *testing.T) {
+		stored := map[string]map[string]*dynamodb.AttributeValue{}
+		c := newMockConn(&mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			title := *in.Item["title"].S
+			if _, exists := stored[title]; exists && in.ConditionExpression != nil {
+				return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+			}
+			stored[title] = in.Item
+			return &dynamodb.PutItemOutput{}, nil
+		}})
+		_, err := c.ExecContext(ctx, `INSERT INTO movies VALUES ('{"title":"Heat","year":1995}')`, nil)
+		require.NoError(t, err)
+		_, err = c.ExecContext(ctx, `INSERT INTO movies VALUES ('{"title":"Heat","year":2022}')`, nil)
+		require.True(t, errors.Is(err, ErrConditionFailed), "%v", err)
+		require.Equal(t, "1995", *stored["Heat"]["year"].N)
+		_, err = c.ExecContext(ctx, `REPLACE INTO movies VALUES ('{"title":"Heat","year":2022}')`, nil)
+		require.NoError(t, err)
+		require.Equal(t, "2022", *stored["Heat"]["year"].N)
+	})
+
+	t.Run("RETURNING ALL_OLD returns the replaced item", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			require.Equal(t, dynamodb.ReturnValueAllOld, *in.ReturnValues)
+			return &dynamodb.PutItemOutput{Attributes: map[string]*dynamodb.AttributeValue{"title": {S: aws.String("old")}}}, nil
+		}})
+		rows, err := c.QueryContext(ctx, `REPLACE INTO movies VALUES ('{"title":"Rush Hour"}') RETURNING ALL_OLD`, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"title"}, rows.Columns())
+		dest := make([]driver.Value, 1)
+		require.NoError(t, rows.Next(dest))
+		require.Equal(t, "old", dest[0])
+	})
+
+	t.Run("named placeholder", func(t *testing.T) {
+		var puts []*dynamodb.PutItemInput
+		c := newMockConn(&mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			puts = append(puts, in)
+			return &dynamodb.PutItemOutput{}, nil
+		}})
+		_, err := c.ExecContext(ctx, `INSERT INTO movies VALUES (:item)`, []driver.NamedValue{{Name: "item", Value: docs(1)}})
+		require.NoError(t, err)
+		require.Len(t, puts, 1)
+		_, err = c.ExecContext(ctx, `INSERT INTO movies VALUES (:item)`, []driver.NamedValue{{Name: "doc", Value: docs(1)}})
+		require.EqualError(t, err, `unexpected named argument "doc"`)
+	})
+
+	t.Run("multiple INSERT rows are written in a transaction", func(t *testing.T) {
+		var calls []*dynamodb.TransactWriteItemsInput
+		c := newMockConn(&mockDynamoDB{tables: tables, transact: func(ctx aws.Context, in *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+			calls = append(calls, in)
+			return &dynamodb.TransactWriteItemsOutput{}, nil
+		}})
+		res, err := c.ExecContext(ctx, `INSERT INTO movies VALUES (?)`, []driver.NamedValue{{Ordinal: 1, Value: docs(3)}})
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(3), n)
+		require.Len(t, calls, 1)
+		require.Len(t, calls[0].TransactItems, 3)
+		for _, item := range calls[0].TransactItems {
+			require.Equal(t, "attribute_not_exists(title)", *item.Put.ConditionExpression)
+		}
+
+		_, err = c.ExecContext(ctx, `INSERT INTO movies VALUES (?)`, []driver.NamedValue{{Ordinal: 1, Value: docs(26)}})
+		require.EqualError(t, err, "INSERT of more than 25 items is not supported, use REPLACE to write them in batches")
+	})
+
+	t.Run("multiple REPLACE rows are batched", func(t *testing.T) {
+		var batches []int
+		retried := false
+		c := newMockConn(&mockDynamoDB{tables: tables, batchWrite: func(ctx aws.Context, in *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+			requests := in.RequestItems["movies"]
+			batches = append(batches, len(requests))
+			out := &dynamodb.BatchWriteItemOutput{}
+			if !retried {
+				// Leave the last item unprocessed once to check that it is retried.
+				retried = true
+				out.UnprocessedItems = map[string][]*dynamodb.WriteRequest{"movies": requests[len(requests)-1:]}
+			}
+			return out, nil
+		}})
+		res, err := c.ExecContext(ctx, `REPLACE INTO movies VALUES (?)`, []driver.NamedValue{{Ordinal: 1, Value: docs(60)}})
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(60), n)
+		require.Equal(t, []int{25, 1, 25, 10}, batches)
+	})
+
+	t.Run("REPLACE reports the keys of items it could not write", func(t *testing.T) {
+		unprocessed := func(ctx aws.Context, in *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+			// Always leave the last two items unprocessed.
+			requests := in.RequestItems["movies"]
+			return &dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]*dynamodb.WriteRequest{
+				"movies": requests[len(requests)-2:],
+			}}, nil
+		}
+		c := newMockConn(&mockDynamoDB{tables: tables, batchWrite: unprocessed})
+		// The retries after 50ms and 100ms fit in the deadline, but the next one would not.
+		deadlineCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+		defer cancel()
+		_, err := c.ExecContext(deadlineCtx, `REPLACE INTO movies VALUES (?)`, []driver.NamedValue{{Ordinal: 1, Value: docs(60)}})
+		require.True(t, errors.Is(err, ErrUnprocessedItems))
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		written, keys, ok := UnprocessedKeys(err)
+		require.True(t, ok)
+		require.Equal(t, 23, written)
+		require.Len(t, keys, 37)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{"title": {S: aws.String("23")}}, keys[0])
+		require.Equal(t, map[string]*dynamodb.AttributeValue{"title": {S: aws.String("59")}}, keys[36])
+		require.EqualError(t, err, `items were left unprocessed: 23 items were written, 37 were not: {title: "23"}, {title: "24"}, {title: "25"}, `+
+			`{title: "26"}, {title: "27"}, {title: "28"}, {title: "29"}, {title: "30"}, {title: "31"}, {title: "32"}, and 27 more: context deadline exceeded`)
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		c = newMockConn(&mockDynamoDB{tables: tables, batchWrite: func(ctx aws.Context, in *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+			cancel()
+			return unprocessed(ctx, in)
+		}})
+		_, err = c.ExecContext(cancelCtx, `REPLACE INTO movies VALUES (?)`, []driver.NamedValue{{Ordinal: 1, Value: docs(3)}})
+		require.True(t, errors.Is(err, context.Canceled))
+		written, keys, ok = UnprocessedKeys(err)
+		require.True(t, ok)
+		require.Equal(t, 1, written)
+		require.Len(t, keys, 2)
+	})
+	t.Run("IF NOT EXISTS reports existing items with ErrConditionFailed", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			require.Equal(t, "attribute_not_exists(title)", *in.ConditionExpression)
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+		}})
+		_, err := c.ExecContext(ctx, `INSERT INTO movies VALUES ('{"title":"Rush Hour"}') IF NOT EXISTS`, nil)
+		require.True(t, errors.Is(err, ErrConditionFailed))
+		var awsErr awserr.Error
+		require.True(t, errors.As(err, &awsErr))
+		require.Equal(t, dynamodb.ErrCodeConditionalCheckFailedException, awsErr.Code())
+		require.EqualError(t, err, "condition failed: ConditionalCheckFailedException: The conditional request failed")
+	})
+
+	t.Run("null is written as NULL and an absent key is not written", func(t *testing.T) {
+		var items []map[string]*dynamodb.AttributeValue
+		c := newMockConn(&mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			items = append(items, in.Item)
+			return &dynamodb.PutItemOutput{}, nil
+		}})
+		type movie struct {
+			Title    string  `dynamodbav:"title"`
+			Rating   *int    `dynamodbav:"rating"`
+			Director *string `dynamodbav:"director,omitempty"`
+		}
+		null := &dynamodb.AttributeValue{NULL: aws.Bool(true)}
+		for _, exec := range []struct {
+			query string
+			args  []driver.NamedValue
+		}{
+			{`REPLACE INTO movies VALUES ({"title": "Heat", "rating": null, "info": {"plot": null}})`, nil},
+			{`REPLACE INTO movies VALUES ('{"title": "Heat", "rating": null, "info": {"plot": null}}')`, nil},
+			{`REPLACE INTO movies VALUES ({"title": "Heat", "rating": :rating, "info": {"plot": :plot}})`, []driver.NamedValue{{Name: "rating"}, {Name: "plot"}}},
+			{`REPLACE INTO movies VALUES (?)`, []driver.NamedValue{{Ordinal: 1, Value: map[string]interface{}{"title": "Heat", "rating": nil, "info": map[string]interface{}{"plot": nil}}}}},
+		} {
+			_, err := c.ExecContext(ctx, exec.query, exec.args)
+			require.NoError(t, err, exec.query)
+		}
+		_, err := c.ExecContext(ctx, `REPLACE INTO movies VALUES (?)`, []driver.NamedValue{{Ordinal: 1, Value: movie{Title: "Heat"}}})
+		require.NoError(t, err)
+
+		for _, item := range items[:4] {
+			require.Equal(t, map[string]*dynamodb.AttributeValue{
+				"title":  {S: aws.String("Heat")},
+				"rating": null,
+				"info":   {M: map[string]*dynamodb.AttributeValue{"plot": null}},
+			}, item)
+		}
+		// director is tagged omitempty, so it is left out rather than written as NULL.
+		require.Equal(t, map[string]*dynamodb.AttributeValue{"title": {S: aws.String("Heat")}, "rating": null}, items[4])
+	})
+
+	t.Run("IF NOT EXISTS is rejected on REPLACE", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables})
+		_, err := c.ExecContext(ctx, `REPLACE INTO movies VALUES ('{"title":"Rush Hour"}') IF NOT EXISTS`, nil)
+		require.EqualError(t, err, "IF NOT EXISTS cannot be used with REPLACE, use INSERT instead")
+	})
+
+	t.Run("document literals with sets", func(t *testing.T) {
+		var puts []*dynamodb.PutItemInput
+		c := newMockConn(&mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			puts = append(puts, in)
+			return &dynamodb.PutItemOutput{}, nil
+		}})
+		_, err := c.ExecContext(ctx, `REPLACE INTO movies VALUES ({"title": "Heat", "tags": string_set('crime', 'drama'), "info": {"ratings": number_set(8.5, 9), "posters": binary_set('aGVhdA=='), "cast": ["De Niro", null]}})`, nil)
+		require.NoError(t, err)
+		require.Len(t, puts, 1)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			"title": {S: aws.String("Heat")},
+			"tags":  {SS: aws.StringSlice([]string{"crime", "drama"})},
+			"info": {M: map[string]*dynamodb.AttributeValue{
+				"ratings": {NS: aws.StringSlice([]string{"8.5", "9"})},
+				"posters": {BS: [][]byte{[]byte("heat")}},
+				"cast":    {L: []*dynamodb.AttributeValue{{S: aws.String("De Niro")}, {NULL: aws.Bool(true)}}},
+			}},
+		}, puts[0].Item)
+	})
+
+	t.Run("numbers keep their precision", func(t *testing.T) {
+		var puts []*dynamodb.PutItemInput
+		c := newMockConn(&mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			puts = append(puts, in)
+			return &dynamodb.PutItemOutput{}, nil
+		}})
+		want := map[string]*dynamodb.AttributeValue{
+			"title": {S: aws.String("Heat")},
+			"id":    {N: aws.String("12345678901234567890")},
+			"info":  {M: map[string]*dynamodb.AttributeValue{"ratings": {L: []*dynamodb.AttributeValue{{N: aws.String("0.1")}}}}},
+		}
+		_, err := c.ExecContext(ctx, `REPLACE INTO movies VALUES ({"title": "Heat", "id": 12345678901234567890, "info": {"ratings": [0.1]}})`, nil)
+		require.NoError(t, err)
+		_, err = c.ExecContext(ctx, `REPLACE INTO movies VALUES (?)`, []driver.NamedValue{
+			{Ordinal: 1, Value: `{"title": "Heat", "id": 12345678901234567890, "info": {"ratings": [0.1]}}`},
+		})
+		require.NoError(t, err)
+		require.Len(t, puts, 2)
+		require.Equal(t, want, puts[0].Item)
+		require.Equal(t, want, puts[1].Item)
+	})
+
+	t.Run("document literals keep empty and nested values", func(t *testing.T) {
+		var puts []*dynamodb.PutItemInput
+		c := newMockConn(&mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			puts = append(puts, in)
+			return &dynamodb.PutItemOutput{}, nil
+		}})
+		_, err := c.ExecContext(ctx, `REPLACE INTO movies VALUES ({"title": "Heat", "plot": "", "info.year": 1995, "seen": false, "extra": {}, "awards": [], "crew": {"director": {"name": "Michael Mann", "films": [["Thief", 1981], {}]}}, "sequel": null})`, nil)
+		require.NoError(t, err)
+		require.Len(t, puts, 1)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			"title":     {S: aws.String("Heat")},
+			"plot":      {S: aws.String("")},
+			"info.year": {N: aws.String("1995")},
+			"seen":      {BOOL: aws.Bool(false)},
+			"extra":     {M: map[string]*dynamodb.AttributeValue{}},
+			"awards":    {L: []*dynamodb.AttributeValue{}},
+			"crew": {M: map[string]*dynamodb.AttributeValue{
+				"director": {M: map[string]*dynamodb.AttributeValue{
+					"name": {S: aws.String("Michael Mann")},
+					"films": {L: []*dynamodb.AttributeValue{
+						{L: []*dynamodb.AttributeValue{{S: aws.String("Thief")}, {N: aws.String("1981")}}},
+						{M: map[string]*dynamodb.AttributeValue{}},
+					}},
+				}},
+			}},
+			"sequel": {NULL: aws.Bool(true)},
+		}, puts[0].Item)
+	})
+
+	t.Run("document literals with quotes in strings", func(t *testing.T) {
+		var puts []*dynamodb.PutItemInput
+		c := newMockConn(&mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			puts = append(puts, in)
+			return &dynamodb.PutItemOutput{}, nil
+		}})
+		_, err := c.ExecContext(ctx, `REPLACE INTO movies VALUES ({"title": 'O''Brien', "year": 1995, "plot": "a ""heist"" movie", "tagline": 'it\'s on'})`, nil)
+		require.NoError(t, err)
+		require.Len(t, puts, 1)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			"title":   {S: aws.String("O'Brien")},
+			"year":    {N: aws.String("1995")},
+			"plot":    {S: aws.String(`a "heist" movie`)},
+			"tagline": {S: aws.String("it's on")},
+		}, puts[0].Item)
+	})
+
+	t.Run("placeholders in document literals", func(t *testing.T) {
+		var puts []*dynamodb.PutItemInput
+		c := newMockConn(&mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			puts = append(puts, in)
+			return &dynamodb.PutItemOutput{}, nil
+		}})
+		stmt, err := c.PrepareContext(ctx, `REPLACE INTO movies VALUES ({"title": :title, "year": 1995, "info": {"cast": [:star, "Val Kilmer"], "rating": :rating, "director": :director}})`)
+		require.NoError(t, err)
+		require.Equal(t, 4, stmt.NumInput())
+		for _, title := range []string{"Heat", "Heat 2"} {
+			_, err = stmt.(driver.StmtExecContext).ExecContext(ctx, []driver.NamedValue{
+				{Name: "title", Value: title},
+				{Name: "rating", Value: 8.3},
+				{Name: "star", Value: "Al Pacino"},
+				{Name: "director", Value: map[string]interface{}{"name": "Michael Mann"}},
+			})
+			require.NoError(t, err)
+		}
+		require.Len(t, puts, 2)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			"title": {S: aws.String("Heat 2")},
+			"year":  {N: aws.String("1995")},
+			"info": {M: map[string]*dynamodb.AttributeValue{
+				"cast":     {L: []*dynamodb.AttributeValue{{S: aws.String("Al Pacino")}, {S: aws.String("Val Kilmer")}}},
+				"rating":   {N: aws.String("8.3")},
+				"director": {M: map[string]*dynamodb.AttributeValue{"name": {S: aws.String("Michael Mann")}}},
+			}},
+		}, puts[1].Item)
+		require.Equal(t, "Heat", *puts[0].Item["title"].S)
+
+		// Positional placeholders are bound in the order they appear, across all the documents.
+		var calls []*dynamodb.TransactWriteItemsInput
+		c = newMockConn(&mockDynamoDB{tables: tables, transact: func(ctx aws.Context, in *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+			calls = append(calls, in)
+			return &dynamodb.TransactWriteItemsOutput{}, nil
+		}})
+		res, err := c.ExecContext(ctx, `INSERT INTO movies VALUES ({"title": ?, "tags": [?]}), ({"title": ?}), ('{"title": "Ronin"}')`, []driver.NamedValue{
+			{Ordinal: 1, Value: "Heat"}, {Ordinal: 2, Value: "crime"}, {Ordinal: 3, Value: "Thief"},
+		})
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(3), n)
+		var items []map[string]*dynamodb.AttributeValue
+		for _, item := range calls[0].TransactItems {
+			items = append(items, item.Put.Item)
+		}
+		require.ElementsMatch(t, []map[string]*dynamodb.AttributeValue{
+			{"title": {S: aws.String("Heat")}, "tags": {L: []*dynamodb.AttributeValue{{S: aws.String("crime")}}}},
+			{"title": {S: aws.String("Thief")}},
+			{"title": {S: aws.String("Ronin")}},
+		}, items)
+
+		_, err = c.ExecContext(ctx, `INSERT INTO movies VALUES ({"title": :title})`, nil)
+		require.EqualError(t, err, `missing argument for binding ":title"`)
+		_, err = c.ExecContext(ctx, `INSERT INTO movies VALUES ({"title": :title, "year": ?})`, nil)
+		require.EqualError(t, err, "cannot mix positional params (?) with named params (:param)")
+		_, err = c.ExecContext(ctx, `INSERT INTO movies VALUES ({"title": :title})`, []driver.NamedValue{{Name: "title", Value: []string{"Heat"}}})
+		require.EqualError(t, err, `binding ":title": invalid value type []string`)
+	})
+}
+
+func TestInsertSelect(t *testing.T) {
+	tables := map[string]*dynamodb.CreateTableInput{}
+	for _, name := range []string{"movies", "archive"} {
+		tables[name] = &dynamodb.CreateTableInput{
+			TableName: aws.String(name),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("title"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		}
+	}
+	ctx := context.Background()
+	movie := func(i int) map[string]*dynamodb.AttributeValue {
+		item := map[string]*dynamodb.AttributeValue{"title": {S: aws.String(fmt.Sprint(i))}}
+		if i%2 == 0 {
+			item["info"] = &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{"rating": {N: aws.String(fmt.Sprint(i))}}}
+		}
+		return item
+	}
+	// scan returns 30 movies in pages of 20.
+	scan := func(ctx aws.Context, in *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+		start := 0
+		if in.ExclusiveStartKey != nil {
+			start = 20
+		}
+		out := &dynamodb.ScanOutput{}
+		for i := start; i < 30 && i < start+20; i++ {
+			out.Items = append(out.Items, movie(i))
+		}
+		if start == 0 {
+			out.LastEvaluatedKey = out.Items[len(out.Items)-1]
+		}
+		return out, nil
+	}
+
+	t.Run("REPLACE copies the items in batches", func(t *testing.T) {
+		var batches [][]*dynamodb.WriteRequest
+		retried := false
+		c := newMockConn(&mockDynamoDB{tables: tables, scan: scan, batchWrite: func(ctx aws.Context, in *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+			requests := in.RequestItems["archive"]
+			batches = append(batches, requests)
+			if !retried {
+				// Leave the last item of the first batch unprocessed.
+				retried = true
+				return &dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]*dynamodb.WriteRequest{
+					"archive": requests[len(requests)-1:],
+				}}, nil
+			}
+			return &dynamodb.BatchWriteItemOutput{}, nil
+		}})
+		res, err := c.ExecContext(ctx, `REPLACE INTO archive SELECT * FROM movies`, nil)
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(30), n)
+		require.Len(t, batches, 3)
+		require.Len(t, batches[0], 25)
+		require.Len(t, batches[1], 1)
+		require.Len(t, batches[2], 5)
+		require.Equal(t, movie(0), batches[0][0].PutRequest.Item)
+		require.Equal(t, movie(24), batches[1][0].PutRequest.Item)
+	})
+
+	t.Run("REPLACE counts the items of earlier batches as written", func(t *testing.T) {
+		cancelCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		c := newMockConn(&mockDynamoDB{tables: tables, scan: scan, batchWrite: func(ctx aws.Context, in *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+			requests := in.RequestItems["archive"]
+			if len(requests) == 25 {
+				return &dynamodb.BatchWriteItemOutput{}, nil
+			}
+			cancel()
+			return &dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]*dynamodb.WriteRequest{
+				"archive": requests[len(requests)-2:],
+			}}, nil
+		}})
+		_, err := c.ExecContext(cancelCtx, `REPLACE INTO archive SELECT * FROM movies`, nil)
+		written, keys, ok := UnprocessedKeys(err)
+		require.True(t, ok)
+		require.Equal(t, 28, written)
+		require.Len(t, keys, 2)
+	})
+
+	t.Run("INSERT renames the selected attributes", func(t *testing.T) {
+		var calls []*dynamodb.TransactWriteItemsInput
+		var scans []*dynamodb.ScanInput
+		c := newMockConn(&mockDynamoDB{
+			tables: tables,
+			scan: func(ctx aws.Context, in *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+				scans = append(scans, in)
+				return &dynamodb.ScanOutput{Items: []map[string]*dynamodb.AttributeValue{movie(1), movie(2)}}, nil
+			},
+			transact: func(ctx aws.Context, in *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+				calls = append(calls, in)
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			},
+		})
+		res, err := c.ExecContext(ctx, `INSERT INTO archive SELECT title, info.rating AS rating FROM movies WHERE title <> ? WITH (SCAN)`, []driver.NamedValue{{Ordinal: 1, Value: "0"}})
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(2), n)
+		require.Equal(t, "0", *scans[0].ExpressionAttributeValues[":_pos1"].S)
+		require.Len(t, calls, 1)
+		puts := calls[0].TransactItems
+		require.Equal(t, "archive", *puts[0].Put.TableName)
+		require.Equal(t, "attribute_not_exists(title)", *puts[0].Put.ConditionExpression)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{"title": {S: aws.String("1")}}, puts[0].Put.Item)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			"title":  {S: aws.String("2")},
+			"rating": {N: aws.String("2")},
+		}, puts[1].Put.Item)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables})
+		for query, expected := range map[string]string{
+			`INSERT INTO archive SELECT info.rating FROM movies`:                  "column info.rating must be named with AS to be inserted",
+			`INSERT INTO archive SELECT info.rating AS rating FROM movies`:        `key attribute "title" of table "archive" must be selected`,
+			`INSERT INTO archive SELECT title, info.rating AS title FROM movies`:  `attribute "title" is inserted more than once`,
+			`INSERT INTO archive SELECT COUNT(*) FROM movies`:                     "cannot insert the result of SELECT COUNT(*)",
+			`INSERT INTO archive SELECT *, year + 1 AS next FROM movies`:          "cannot insert the result of SELECT * with other columns, select either * or the columns to insert",
+			`INSERT INTO archive SELECT title, document(a, b) AS doc FROM movies`: "column document(a, b) AS doc must select a single path to be inserted",
+			`REPLACE INTO archive SELECT * FROM movies RETURNING ALL_OLD`:         "cannot use RETURNING with INSERT ... SELECT",
+		} {
+			_, err := c.ExecContext(ctx, query, nil)
+			require.EqualError(t, err, expected, query)
+		}
+	})
+}
+
+func TestInsertOnDuplicateKeyUpdate(t *testing.T) {
+	tables := map[string]*dynamodb.CreateTableInput{
+		"movies": {
+			TableName: aws.String("movies"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("title"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		},
+	}
+	ctx := context.Background()
+	newConn := func(exists bool, puts *[]*dynamodb.PutItemInput, updates *[]*dynamodb.UpdateItemInput) conn {
+		return newMockConn(&mockDynamoDB{
+			tables: tables,
+			putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+				*puts = append(*puts, in)
+				if exists {
+					return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+				}
+				return &dynamodb.PutItemOutput{}, nil
+			},
+			updateItem: func(ctx aws.Context, in *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+				*updates = append(*updates, in)
+				return &dynamodb.UpdateItemOutput{}, nil
+			},
+		})
+	}
+	const query = `INSERT INTO movies VALUES ({"title": :title, "views": 1, "tags": ["new"]}) ON DUPLICATE KEY UPDATE views = views + 1, seen = :seen`
+	args := []driver.NamedValue{{Name: "title", Value: "Heat"}, {Name: "seen", Value: true}}
+
+	t.Run("a new item is inserted with PutItem", func(t *testing.T) {
+		var (
+			puts    []*dynamodb.PutItemInput
+			updates []*dynamodb.UpdateItemInput
+		)
+		res, err := newConn(false, &puts, &updates).ExecContext(ctx, query, args)
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(1), n)
+		require.Len(t, puts, 1)
+		require.Empty(t, updates)
+		require.Equal(t, "attribute_not_exists(title)", *puts[0].ConditionExpression)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			"title": {S: aws.String("Heat")},
+			"views": {N: aws.String("1")},
+			"tags":  {L: []*dynamodb.AttributeValue{{S: aws.String("new")}}},
+		}, puts[0].Item)
+	})
+
+	t.Run("an existing item is updated with UpdateItem", func(t *testing.T) {
+		var (
+			puts    []*dynamodb.PutItemInput
+			updates []*dynamodb.UpdateItemInput
+		)
+		res, err := newConn(true, &puts, &updates).ExecContext(ctx, query, args)
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(1), n)
+		require.Len(t, puts, 1)
+		require.Len(t, updates, 1)
+		upd := updates[0]
+		require.Equal(t, map[string]*dynamodb.AttributeValue{"title": {S: aws.String("Heat")}}, upd.Key)
+		require.Equal(t, "SET #views = #views + :_gen1, seen = :seen", *upd.UpdateExpression)
+		require.Equal(t, map[string]*string{"#views": aws.String("views")}, upd.ExpressionAttributeNames)
+		require.Equal(t, "attribute_exists(title)", *upd.ConditionExpression)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			":_gen1": {N: aws.String("1")},
+			":seen":  {BOOL: aws.Bool(true)},
+		}, upd.ExpressionAttributeValues)
+	})
+
+	t.Run("positional placeholders of the update follow those of the insert", func(t *testing.T) {
+		var (
+			puts    []*dynamodb.PutItemInput
+			updates []*dynamodb.UpdateItemInput
+		)
+		c := newConn(true, &puts, &updates)
+		stmt, err := c.PrepareContext(ctx, `INSERT INTO movies VALUES (?) ON DUPLICATE KEY UPDATE rating = ?`)
+		require.NoError(t, err)
+		require.Equal(t, 2, stmt.NumInput())
+		_, err = c.ExecContext(ctx, `INSERT INTO movies VALUES (?) ON DUPLICATE KEY UPDATE rating = ?`, []driver.NamedValue{
+			{Ordinal: 1, Value: map[string]interface{}{"title": "Heat", "rating": 8}},
+			{Ordinal: 2, Value: 9},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "8", *puts[0].Item["rating"].N)
+		require.Equal(t, "9", *updates[0].ExpressionAttributeValues[":_pos2"].N)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables})
+		for query, expected := range map[string]string{
+			`REPLACE INTO movies VALUES ('{"title": "Heat"}') ON DUPLICATE KEY UPDATE views = 1`:                        "ON DUPLICATE KEY UPDATE cannot be used with REPLACE, which overwrites existing items",
+			`INSERT INTO movies VALUES ('{"title": "Heat"}'), ('{"title": "Ronin"}') ON DUPLICATE KEY UPDATE views = 1`: "ON DUPLICATE KEY UPDATE can only insert a single item",
+			`INSERT INTO movies VALUES ('{"title": "Heat"}') ON DUPLICATE KEY UPDATE title = 'Ronin'`:                   `key attribute "title" cannot be updated`,
+			`INSERT INTO movies VALUES (:item) ON DUPLICATE KEY UPDATE views = ?`:                                       "cannot mix positional params (?) with named params (:param)",
+			`INSERT INTO movies SELECT * FROM archive ON DUPLICATE KEY UPDATE views = 1`:                                "ON DUPLICATE KEY UPDATE cannot be used with INSERT ... SELECT",
+			`INSERT INTO movies VALUES ('{"title": "Heat"}') ON DUPLICATE KEY UPDATE views = 1 IF NOT EXISTS`:           "ON DUPLICATE KEY UPDATE cannot be used with IF NOT EXISTS",
+		} {
+			_, err := c.ExecContext(ctx, query, nil)
+			require.EqualError(t, err, expected, query)
+		}
+	})
+}
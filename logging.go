@@ -0,0 +1,237 @@
+package dynamosql
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// Logger is called with every request the driver makes to DynamoDB once it completes, for Config.Logger. Each attempt
+// of a request retried by Config.Retry is logged separately. LogRequest may be called concurrently.
+type Logger interface {
+	LogRequest(ctx context.Context, req *LoggedRequest)
+}
+
+// LoggerFunc adapts a function to a Logger.
+type LoggerFunc func(ctx context.Context, req *LoggedRequest)
+
+func (f LoggerFunc) LogRequest(ctx context.Context, req *LoggedRequest) {
+	f(ctx, req)
+}
+
+// LoggedRequest describes a request made to DynamoDB. The expressions are those compiled from the statement, with
+// attribute names and values replaced by the placeholders in AttributeNames and AttributeValues. Fields that do not
+// apply to the operation are empty.
+type LoggedRequest struct {
+	// Operation is the name of the DynamoDB API called, such as Query or PutItem.
+	Operation string
+	// Table is the table the request is made to. It is empty for the batch and transaction operations, which can span
+	// tables, and for ListTables.
+	Table string
+	// Index is the secondary index that a Query or Scan reads.
+	Index        string
+	KeyCondition string
+	Filter       string
+	Projection   string
+	Update       string
+	Condition    string
+	// Items is the number of items a batch or transaction operation reads or writes.
+	Items           int
+	AttributeNames  map[string]*string
+	AttributeValues map[string]*dynamodb.AttributeValue
+	// Key is the key of the item that GetItem, UpdateItem or DeleteItem addresses. The items that PutItem and the
+	// batch and transaction operations write are not logged.
+	Key map[string]*dynamodb.AttributeValue
+	// Redacted is set if AttributeValues and Key were left out, for Config.RedactLoggedValues.
+	Redacted bool
+	// Duration is how long the request took, and Err the error it failed with.
+	Duration time.Duration
+	Err      error
+}
+
+// withLogger returns a client that calls the logger with each request made with it. If logger is nil, the client is
+// returned as is.
+func withLogger(dynamo dynamodbiface.DynamoDBAPI, logger Logger, redact bool) dynamodbiface.DynamoDBAPI {
+	if logger == nil {
+		return dynamo
+	}
+	return &loggingClient{DynamoDBAPI: dynamo, logger: logger, redact: redact}
+}
+
+type loggingClient struct {
+	dynamodbiface.DynamoDBAPI
+	logger Logger
+	redact bool
+}
+
+func (c *loggingClient) log(ctx context.Context, req *LoggedRequest, start time.Time, err error) {
+	req.Duration = time.Since(start)
+	req.Err = err
+	if c.redact {
+		req.AttributeValues = nil
+		req.Key = nil
+		req.Redacted = true
+	}
+	c.logger.LogRequest(ctx, req)
+}
+
+func (c *loggingClient) QueryWithContext(ctx aws.Context, in *dynamodb.QueryInput, opts ...request.Option) (*dynamodb.QueryOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.QueryWithContext(ctx, in, opts...)
+	c.log(ctx, &LoggedRequest{
+		Operation:       "Query",
+		Table:           aws.StringValue(in.TableName),
+		Index:           aws.StringValue(in.IndexName),
+		KeyCondition:    aws.StringValue(in.KeyConditionExpression),
+		Filter:          aws.StringValue(in.FilterExpression),
+		Projection:      aws.StringValue(in.ProjectionExpression),
+		AttributeNames:  in.ExpressionAttributeNames,
+		AttributeValues: in.ExpressionAttributeValues,
+	}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) ScanWithContext(ctx aws.Context, in *dynamodb.ScanInput, opts ...request.Option) (*dynamodb.ScanOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.ScanWithContext(ctx, in, opts...)
+	c.log(ctx, &LoggedRequest{
+		Operation:       "Scan",
+		Table:           aws.StringValue(in.TableName),
+		Index:           aws.StringValue(in.IndexName),
+		Filter:          aws.StringValue(in.FilterExpression),
+		Projection:      aws.StringValue(in.ProjectionExpression),
+		AttributeNames:  in.ExpressionAttributeNames,
+		AttributeValues: in.ExpressionAttributeValues,
+	}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) GetItemWithContext(ctx aws.Context, in *dynamodb.GetItemInput, opts ...request.Option) (*dynamodb.GetItemOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.GetItemWithContext(ctx, in, opts...)
+	c.log(ctx, &LoggedRequest{
+		Operation:      "GetItem",
+		Table:          aws.StringValue(in.TableName),
+		Projection:     aws.StringValue(in.ProjectionExpression),
+		AttributeNames: in.ExpressionAttributeNames,
+		Key:            in.Key,
+	}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) BatchGetItemWithContext(ctx aws.Context, in *dynamodb.BatchGetItemInput, opts ...request.Option) (*dynamodb.BatchGetItemOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.BatchGetItemWithContext(ctx, in, opts...)
+	items := 0
+	for _, keys := range in.RequestItems {
+		items += len(keys.Keys)
+	}
+	c.log(ctx, &LoggedRequest{Operation: "BatchGetItem", Items: items}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) PutItemWithContext(ctx aws.Context, in *dynamodb.PutItemInput, opts ...request.Option) (*dynamodb.PutItemOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.PutItemWithContext(ctx, in, opts...)
+	c.log(ctx, &LoggedRequest{
+		Operation:       "PutItem",
+		Table:           aws.StringValue(in.TableName),
+		Condition:       aws.StringValue(in.ConditionExpression),
+		AttributeNames:  in.ExpressionAttributeNames,
+		AttributeValues: in.ExpressionAttributeValues,
+	}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) UpdateItemWithContext(ctx aws.Context, in *dynamodb.UpdateItemInput, opts ...request.Option) (*dynamodb.UpdateItemOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.UpdateItemWithContext(ctx, in, opts...)
+	c.log(ctx, &LoggedRequest{
+		Operation:       "UpdateItem",
+		Table:           aws.StringValue(in.TableName),
+		Update:          aws.StringValue(in.UpdateExpression),
+		Condition:       aws.StringValue(in.ConditionExpression),
+		AttributeNames:  in.ExpressionAttributeNames,
+		AttributeValues: in.ExpressionAttributeValues,
+		Key:             in.Key,
+	}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) DeleteItemWithContext(ctx aws.Context, in *dynamodb.DeleteItemInput, opts ...request.Option) (*dynamodb.DeleteItemOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.DeleteItemWithContext(ctx, in, opts...)
+	c.log(ctx, &LoggedRequest{
+		Operation:       "DeleteItem",
+		Table:           aws.StringValue(in.TableName),
+		Condition:       aws.StringValue(in.ConditionExpression),
+		AttributeNames:  in.ExpressionAttributeNames,
+		AttributeValues: in.ExpressionAttributeValues,
+		Key:             in.Key,
+	}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) BatchWriteItemWithContext(ctx aws.Context, in *dynamodb.BatchWriteItemInput, opts ...request.Option) (*dynamodb.BatchWriteItemOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.BatchWriteItemWithContext(ctx, in, opts...)
+	items := 0
+	for _, writes := range in.RequestItems {
+		items += len(writes)
+	}
+	c.log(ctx, &LoggedRequest{Operation: "BatchWriteItem", Items: items}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) TransactWriteItemsWithContext(ctx aws.Context, in *dynamodb.TransactWriteItemsInput, opts ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.TransactWriteItemsWithContext(ctx, in, opts...)
+	c.log(ctx, &LoggedRequest{Operation: "TransactWriteItems", Items: len(in.TransactItems)}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) DescribeTableWithContext(ctx aws.Context, in *dynamodb.DescribeTableInput, opts ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.DescribeTableWithContext(ctx, in, opts...)
+	c.log(ctx, &LoggedRequest{Operation: "DescribeTable", Table: aws.StringValue(in.TableName)}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) ListTablesWithContext(ctx aws.Context, in *dynamodb.ListTablesInput, opts ...request.Option) (*dynamodb.ListTablesOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.ListTablesWithContext(ctx, in, opts...)
+	c.log(ctx, &LoggedRequest{Operation: "ListTables"}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) CreateTableWithContext(ctx aws.Context, in *dynamodb.CreateTableInput, opts ...request.Option) (*dynamodb.CreateTableOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.CreateTableWithContext(ctx, in, opts...)
+	c.log(ctx, &LoggedRequest{Operation: "CreateTable", Table: aws.StringValue(in.TableName)}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) DeleteTableWithContext(ctx aws.Context, in *dynamodb.DeleteTableInput, opts ...request.Option) (*dynamodb.DeleteTableOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.DeleteTableWithContext(ctx, in, opts...)
+	c.log(ctx, &LoggedRequest{Operation: "DeleteTable", Table: aws.StringValue(in.TableName)}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) UpdateTableWithContext(ctx aws.Context, in *dynamodb.UpdateTableInput, opts ...request.Option) (*dynamodb.UpdateTableOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.UpdateTableWithContext(ctx, in, opts...)
+	c.log(ctx, &LoggedRequest{Operation: "UpdateTable", Table: aws.StringValue(in.TableName)}, start, err)
+	return out, err
+}
+
+func (c *loggingClient) UpdateTimeToLiveWithContext(ctx aws.Context, in *dynamodb.UpdateTimeToLiveInput, opts ...request.Option) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	start := time.Now()
+	out, err := c.DynamoDBAPI.UpdateTimeToLiveWithContext(ctx, in, opts...)
+	c.log(ctx, &LoggedRequest{Operation: "UpdateTimeToLive", Table: aws.StringValue(in.TableName)}, start, err)
+	return out, err
+}
@@ -0,0 +1,88 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	ctx := context.Background()
+	newDB := func(t *testing.T, redact bool) (*sql.DB, func() []*LoggedRequest) {
+		t.Helper()
+		m := &mockDynamoDB{
+			tables: map[string]*dynamodb.CreateTableInput{
+				"movies": {
+					TableName: aws.String("movies"),
+					KeySchema: []*dynamodb.KeySchemaElement{
+						{AttributeName: aws.String("title"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+						{AttributeName: aws.String("year"), KeyType: aws.String(dynamodb.KeyTypeRange)},
+					},
+				},
+			},
+			query: func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+				return &dynamodb.QueryOutput{}, nil
+			},
+		}
+		var (
+			lock   sync.Mutex
+			logged []*LoggedRequest
+		)
+		logger := LoggerFunc(func(ctx context.Context, req *LoggedRequest) {
+			lock.Lock()
+			defer lock.Unlock()
+			logged = append(logged, req)
+		})
+		connector, err := New(Config{DynamoDB: m, Logger: logger, RedactLoggedValues: redact}).OpenConnector("")
+		require.NoError(t, err)
+		db := sql.OpenDB(connector)
+		t.Cleanup(func() { db.Close() })
+		return db, func() []*LoggedRequest {
+			lock.Lock()
+			defer lock.Unlock()
+			return logged
+		}
+	}
+	const query = `SELECT title FROM movies WHERE title = :title AND year > 2000`
+
+	t.Run("logs each request with its expressions", func(t *testing.T) {
+		db, logged := newDB(t, false)
+		rows, err := db.QueryContext(ctx, query, sql.Named("title", "Heat"))
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+
+		requests := logged()
+		require.Len(t, requests, 2)
+		require.Equal(t, "DescribeTable", requests[0].Operation)
+		require.Equal(t, "movies", requests[0].Table)
+		req := requests[1]
+		require.Equal(t, "Query", req.Operation)
+		require.Equal(t, "movies", req.Table)
+		require.Equal(t, "title = :title AND #year > :_gen1", req.KeyCondition)
+		require.Equal(t, "title", req.Projection)
+		require.Equal(t, map[string]*string{"#year": aws.String("year")}, req.AttributeNames)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			":title": {S: aws.String("Heat")},
+			":_gen1": {N: aws.String("2000")},
+		}, req.AttributeValues)
+		require.False(t, req.Redacted)
+		require.NoError(t, req.Err)
+	})
+
+	t.Run("values can be redacted", func(t *testing.T) {
+		db, logged := newDB(t, true)
+		rows, err := db.QueryContext(ctx, query, sql.Named("title", "Heat"))
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+
+		req := logged()[1]
+		require.Equal(t, "title = :title AND #year > :_gen1", req.KeyCondition)
+		require.Nil(t, req.AttributeValues)
+		require.True(t, req.Redacted)
+	})
+}
@@ -0,0 +1,162 @@
+package dynamosql
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// mockDynamoDB is a DynamoDB client for unit tests. Only the methods with a handler set may be called, any other
+// call panics on the nil embedded interface.
+type mockDynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+
+	tables      map[string]*dynamodb.CreateTableInput
+	listTables  func(*dynamodb.ListTablesInput) (*dynamodb.ListTablesOutput, error)
+	query       func(aws.Context, *dynamodb.QueryInput) (*dynamodb.QueryOutput, error)
+	getItem     func(aws.Context, *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error)
+	batchGet    func(aws.Context, *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error)
+	scan        func(aws.Context, *dynamodb.ScanInput) (*dynamodb.ScanOutput, error)
+	transact    func(aws.Context, *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error)
+	updateTTL   func(aws.Context, *dynamodb.UpdateTimeToLiveInput) (*dynamodb.UpdateTimeToLiveOutput, error)
+	putItem     func(aws.Context, *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error)
+	batchWrite  func(aws.Context, *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error)
+	updateItem  func(aws.Context, *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error)
+	deleteItem  func(aws.Context, *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error)
+	updateTable func(aws.Context, *dynamodb.UpdateTableInput) (*dynamodb.UpdateTableOutput, error)
+	// waited are the tables that were waited for with WaitUntilTableExists.
+	waited []string
+}
+
+func (m *mockDynamoDB) CreateTableWithContext(ctx aws.Context, in *dynamodb.CreateTableInput, opts ...request.Option) (*dynamodb.CreateTableOutput, error) {
+	if _, ok := m.tables[*in.TableName]; ok {
+		return nil, awserr.New(dynamodb.ErrCodeResourceInUseException, "table already exists: "+*in.TableName, nil)
+	}
+	m.tables[*in.TableName] = in
+	return &dynamodb.CreateTableOutput{}, nil
+}
+
+// WaitUntilTableExistsWithContext returns immediately, tables in the mock are active as soon as they are created.
+func (m *mockDynamoDB) WaitUntilTableExistsWithContext(ctx aws.Context, in *dynamodb.DescribeTableInput, opts ...request.WaiterOption) error {
+	m.waited = append(m.waited, *in.TableName)
+	if _, ok := m.tables[*in.TableName]; !ok {
+		return awserr.New(request.WaiterResourceNotReadyErrorCode, "table not found: "+*in.TableName, nil)
+	}
+	return nil
+}
+
+func (m *mockDynamoDB) UpdateTimeToLiveWithContext(ctx aws.Context, in *dynamodb.UpdateTimeToLiveInput, opts ...request.Option) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	return m.updateTTL(ctx, in)
+}
+
+func (m *mockDynamoDB) DeleteTableWithContext(ctx aws.Context, in *dynamodb.DeleteTableInput, opts ...request.Option) (*dynamodb.DeleteTableOutput, error) {
+	if _, ok := m.tables[*in.TableName]; !ok {
+		return nil, awserr.New(dynamodb.ErrCodeResourceNotFoundException, "table not found: "+*in.TableName, nil)
+	}
+	delete(m.tables, *in.TableName)
+	return &dynamodb.DeleteTableOutput{}, nil
+}
+
+func (m *mockDynamoDB) UpdateTableWithContext(ctx aws.Context, in *dynamodb.UpdateTableInput, opts ...request.Option) (*dynamodb.UpdateTableOutput, error) {
+	return m.updateTable(ctx, in)
+}
+
+// newMockConn returns a conn backed by the mock, with a schema loader that serves the mock's tables.
+func newMockConn(m *mockDynamoDB) conn {
+	return conn{dynamo: m, tables: schema.NewTableLoader(m)}
+}
+
+func (m *mockDynamoDB) DescribeTableWithContext(ctx aws.Context, in *dynamodb.DescribeTableInput, opts ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	create, ok := m.tables[*in.TableName]
+	if !ok {
+		return nil, &dynamodb.ResourceNotFoundException{Message_: aws.String("table not found: " + *in.TableName)}
+	}
+	desc := &dynamodb.TableDescription{
+		TableName:            create.TableName,
+		KeySchema:            create.KeySchema,
+		AttributeDefinitions: create.AttributeDefinitions,
+	}
+	for _, lsi := range create.LocalSecondaryIndexes {
+		desc.LocalSecondaryIndexes = append(desc.LocalSecondaryIndexes, &dynamodb.LocalSecondaryIndexDescription{
+			IndexName:  lsi.IndexName,
+			KeySchema:  lsi.KeySchema,
+			Projection: lsi.Projection,
+		})
+	}
+	for _, gsi := range create.GlobalSecondaryIndexes {
+		desc.GlobalSecondaryIndexes = append(desc.GlobalSecondaryIndexes, &dynamodb.GlobalSecondaryIndexDescription{
+			IndexName:  gsi.IndexName,
+			KeySchema:  gsi.KeySchema,
+			Projection: gsi.Projection,
+		})
+	}
+	return &dynamodb.DescribeTableOutput{Table: desc}, nil
+}
+
+func (m *mockDynamoDB) ListTablesWithContext(ctx aws.Context, in *dynamodb.ListTablesInput, opts ...request.Option) (*dynamodb.ListTablesOutput, error) {
+	return m.listTables(in)
+}
+
+func (m *mockDynamoDB) QueryWithContext(ctx aws.Context, in *dynamodb.QueryInput, opts ...request.Option) (*dynamodb.QueryOutput, error) {
+	return m.query(ctx, in)
+}
+
+func (m *mockDynamoDB) GetItemWithContext(ctx aws.Context, in *dynamodb.GetItemInput, opts ...request.Option) (*dynamodb.GetItemOutput, error) {
+	return m.getItem(ctx, in)
+}
+
+func (m *mockDynamoDB) BatchGetItemWithContext(ctx aws.Context, in *dynamodb.BatchGetItemInput, opts ...request.Option) (*dynamodb.BatchGetItemOutput, error) {
+	return m.batchGet(ctx, in)
+}
+
+func (m *mockDynamoDB) ScanWithContext(ctx aws.Context, in *dynamodb.ScanInput, opts ...request.Option) (*dynamodb.ScanOutput, error) {
+	return m.scan(ctx, in)
+}
+
+func (m *mockDynamoDB) TransactWriteItemsWithContext(ctx aws.Context, in *dynamodb.TransactWriteItemsInput, opts ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+	return m.transact(ctx, in)
+}
+
+func (m *mockDynamoDB) PutItemWithContext(ctx aws.Context, in *dynamodb.PutItemInput, opts ...request.Option) (*dynamodb.PutItemOutput, error) {
+	return m.putItem(ctx, in)
+}
+
+func (m *mockDynamoDB) UpdateItemWithContext(ctx aws.Context, in *dynamodb.UpdateItemInput, opts ...request.Option) (*dynamodb.UpdateItemOutput, error) {
+	return m.updateItem(ctx, in)
+}
+
+func (m *mockDynamoDB) BatchWriteItemWithContext(ctx aws.Context, in *dynamodb.BatchWriteItemInput, opts ...request.Option) (*dynamodb.BatchWriteItemOutput, error) {
+	return m.batchWrite(ctx, in)
+}
+
+// pagedItems splits items into pages of the given size, keyed by the "id" attribute of the last item on each
+// page, and returns a Query handler that serves them by ExclusiveStartKey.
+func pagedItems(items []map[string]*dynamodb.AttributeValue, pageSize int) func(aws.Context, *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	return func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+		start := 0
+		if in.ExclusiveStartKey != nil {
+			for i, item := range items {
+				if *item["id"].N == *in.ExclusiveStartKey["id"].N {
+					start = i + 1
+				}
+			}
+		}
+		end := start + pageSize
+		if end > len(items) {
+			end = len(items)
+		}
+		out := &dynamodb.QueryOutput{Items: items[start:end], Count: aws.Int64(int64(end - start))}
+		if end < len(items) {
+			out.LastEvaluatedKey = map[string]*dynamodb.AttributeValue{"id": items[end-1]["id"]}
+		}
+		return out, nil
+	}
+}
+
+func (m *mockDynamoDB) DeleteItemWithContext(ctx aws.Context, in *dynamodb.DeleteItemInput, opts ...request.Option) (*dynamodb.DeleteItemOutput, error) {
+	return m.deleteItem(ctx, in)
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"github.com/alecthomas/participle"
+	"github.com/alecthomas/participle/lexer"
+)
+
+// ErrorKind is the stage of parsing that a ParseError was found in.
+type ErrorKind int
+
+const (
+	// LexerError is text that is not a token, such as a character that no token starts with.
+	LexerError ErrorKind = iota + 1
+	// SyntaxError is a statement that does not follow the grammar.
+	SyntaxError
+	// ValidationError is a statement that follows the grammar but is invalid, such as one calling an unknown
+	// function.
+	ValidationError
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case LexerError:
+		return "lexer error"
+	case SyntaxError:
+		return "syntax error"
+	case ValidationError:
+		return "validation error"
+	default:
+		return "unknown error"
+	}
+}
+
+// ParseError is the error returned by Parse, ParseMulti and ParsePath. Pos is the position of the offending token, or
+// of the condition that a validation error is about. It is the zero value for validation errors about a statement as
+// a whole. The error it was made from, such as the one returned by participle, is returned by Unwrap.
+type ParseError struct {
+	Pos  lexer.Position
+	Msg  string
+	Kind ErrorKind
+	err  error
+}
+
+func (p *ParseError) Error() string {
+	return lexer.FormatError(p.Pos, p.Msg)
+}
+
+func (p *ParseError) Unwrap() error {
+	return p.err
+}
+
+// newParseError converts an error returned by participle to a *ParseError.
+func newParseError(err error) *ParseError {
+	switch err := err.(type) {
+	case *ParseError:
+		return err
+	case *lexer.Error:
+		return &ParseError{Pos: err.Tok.Pos, Msg: err.Msg, Kind: LexerError, err: err}
+	case participle.Error:
+		return &ParseError{Pos: err.Token().Pos, Msg: err.Message(), Kind: SyntaxError, err: err}
+	default:
+		return &ParseError{Msg: err.Error(), Kind: SyntaxError, err: err}
+	}
+}
+
+// validationError returns err as a *ParseError of a validation error at pos.
+func validationError(pos lexer.Position, err error) *ParseError {
+	if perr, ok := err.(*ParseError); ok {
+		return perr
+	}
+	return &ParseError{Pos: pos, Msg: err.Error(), Kind: ValidationError, err: err}
+}
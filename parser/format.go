@@ -0,0 +1,639 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Format renders the AST back to SQL. The output is normalised: keywords are upper case, clauses are separated by
+// single spaces, strings are double quoted where possible and identifiers are only quoted when they would otherwise
+// be read as a keyword. Parsing the output gives back an equivalent AST, and formatting that AST gives back the
+// same output, so it is safe to use for logging and golden tests.
+func Format(ast *AST) string {
+	f := &formatter{}
+	f.ast(ast)
+	return f.String()
+}
+
+// String formats the AST with Format.
+func (a *AST) String() string {
+	return Format(a)
+}
+
+var (
+	identRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	keywordSet  = func() map[string]bool {
+		set := make(map[string]bool, len(Keywords))
+		for _, keyword := range Keywords {
+			set[keyword] = true
+		}
+		return set
+	}()
+)
+
+type formatter struct {
+	strings.Builder
+}
+
+func (f *formatter) ast(ast *AST) {
+	switch {
+	case ast.Select != nil:
+		f.selectStmt(ast.Select)
+	case ast.Insert != nil:
+		f.WriteString("INSERT ")
+		f.insert(ast.Insert)
+	case ast.Replace != nil:
+		f.WriteString("REPLACE ")
+		f.insert(ast.Replace)
+	case ast.Update != nil:
+		f.update(ast.Update)
+	case ast.Delete != nil:
+		f.delete(ast.Delete)
+	case ast.CreateTable != nil:
+		f.createTable(ast.CreateTable)
+	case ast.DropTable != nil:
+		f.WriteString("DROP TABLE ")
+		if ast.DropTable.IfExists {
+			f.WriteString("IF EXISTS ")
+		}
+		f.ident(ast.DropTable.Table)
+	case ast.AlterTable != nil:
+		f.alterTable(ast.AlterTable)
+	case ast.Describe != nil:
+		f.WriteString("DESCRIBE ")
+		f.ident(ast.Describe.Table)
+	case ast.ShowTables != nil:
+		f.WriteString("SHOW TABLES")
+		if ast.ShowTables.Like != nil {
+			f.WriteString(" LIKE ")
+			f.WriteString(quoteString(*ast.ShowTables.Like))
+		}
+	case ast.Explain != nil:
+		f.WriteString("EXPLAIN ")
+		f.ast(ast.Explain.Statement)
+	}
+}
+
+func (f *formatter) selectStmt(s *Select) {
+	f.WriteString("SELECT ")
+	f.projection(s.Projection)
+	f.WriteString(" FROM ")
+	f.ident(s.From)
+	if s.Index != nil {
+		f.WriteString(" USE INDEX (")
+		f.WriteString(*s.Index)
+		f.WriteString(")")
+	}
+	f.where(s.Where)
+	if s.OrderBy != nil {
+		f.WriteString(" ORDER BY ")
+		f.path(s.OrderBy.Path)
+		f.direction(s.OrderBy.Descending)
+	}
+	f.direction(s.Descending)
+	if s.Limit != nil {
+		f.WriteString(" LIMIT ")
+		f.value(s.Limit.Value)
+	}
+	if s.Offset != nil {
+		f.WriteString(" OFFSET ")
+		f.WriteString(strconv.Itoa(*s.Offset))
+	}
+	if len(s.Hints) > 0 {
+		f.WriteString(" WITH (")
+		for i, hint := range s.Hints {
+			if i > 0 {
+				f.WriteString(", ")
+			}
+			switch {
+			case hint.Consistent:
+				f.WriteString("CONSISTENT")
+			case hint.Scan:
+				f.WriteString("SCAN")
+			default:
+				f.WriteString("SEGMENTS = ")
+				f.WriteString(strconv.Itoa(*hint.Segments))
+			}
+		}
+		f.WriteString(")")
+	}
+}
+
+func (f *formatter) direction(d *ScanDescending) {
+	switch {
+	case d == nil:
+	case bool(*d):
+		f.WriteString(" DESC")
+	default:
+		f.WriteString(" ASC")
+	}
+}
+
+func (f *formatter) projection(p *ProjectionExpression) {
+	switch {
+	case p.Count:
+		f.WriteString("COUNT(*)")
+	default:
+		if p.All {
+			f.WriteString("*")
+		}
+		for i, col := range p.Columns {
+			if i > 0 || p.All {
+				f.WriteString(", ")
+			}
+			switch {
+			case col.Case != nil:
+				f.caseExpression(col.Case)
+			case col.Function != nil:
+				f.function(col.Function)
+			case col.Arithmetic != nil:
+				f.arithmetic(col.Arithmetic)
+			default:
+				f.path(col.DocumentPath)
+			}
+			if col.Alias != nil {
+				f.WriteString(" AS ")
+				f.ident(*col.Alias)
+			}
+		}
+	}
+}
+
+func (f *formatter) caseExpression(c *CaseExpression) {
+	f.WriteString("CASE")
+	for _, when := range c.Whens {
+		f.WriteString(" WHEN ")
+		f.or(when.Condition)
+		f.WriteString(" THEN ")
+		f.operand(when.Result)
+	}
+	if c.Else != nil {
+		f.WriteString(" ELSE ")
+		f.operand(c.Else)
+	}
+	f.WriteString(" END")
+}
+
+func (f *formatter) arithmetic(a *Arithmetic) {
+	f.arithmeticOperand(a.Operand)
+	for _, op := range a.Ops {
+		f.WriteString(" " + op.Operator + " ")
+		f.arithmeticOperand(op.Operand)
+	}
+}
+
+func (f *formatter) arithmeticOperand(o *ArithmeticOperand) {
+	switch {
+	case o.Number != nil:
+		f.WriteString(o.NumberLiteral())
+	case o.DocumentPath != nil:
+		f.path(o.DocumentPath)
+	default:
+		f.WriteString("(")
+		f.arithmetic(o.Parenthesized)
+		f.WriteString(")")
+	}
+}
+
+func (f *formatter) insert(ins *Insert) {
+	f.WriteString("INTO ")
+	f.ident(ins.Into)
+	if ins.Select != nil {
+		f.WriteString(" ")
+		f.selectStmt(ins.Select)
+	} else {
+		f.WriteString(" VALUES ")
+	}
+	for i, v := range ins.Values {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		f.WriteString("(")
+		if v.Object != nil {
+			f.jsonObject(v.Object)
+		} else {
+			f.value(&v.Value)
+		}
+		f.WriteString(")")
+	}
+	if ins.OnDuplicate != nil {
+		f.WriteString(" ON DUPLICATE KEY UPDATE ")
+		f.setExpressions(ins.OnDuplicate)
+	}
+	if ins.IfNotExists {
+		f.WriteString(" IF NOT EXISTS")
+	}
+	f.returning(ins.Returning)
+}
+
+func (f *formatter) update(u *Update) {
+	f.WriteString("UPDATE ")
+	f.ident(u.Table)
+	for _, action := range u.Actions {
+		f.WriteString(" ")
+		switch {
+		case action.Set != nil:
+			f.WriteString("SET ")
+			f.setExpressions(action.Set)
+		case action.Add != nil:
+			f.WriteString("ADD ")
+			for i, add := range action.Add {
+				if i > 0 {
+					f.WriteString(", ")
+				}
+				f.path(add.Path)
+				f.WriteString(" ")
+				f.value(add.Value)
+			}
+		case action.Remove != nil:
+			f.WriteString("REMOVE ")
+			for i, path := range action.Remove {
+				if i > 0 {
+					f.WriteString(", ")
+				}
+				f.path(path)
+			}
+		case action.Delete != nil:
+			f.WriteString("DELETE ")
+			for i, del := range action.Delete {
+				if i > 0 {
+					f.WriteString(", ")
+				}
+				f.path(del.Path)
+				f.WriteString(" ")
+				f.value(del.Value)
+			}
+		}
+	}
+	f.where(u.Where)
+	f.returning(u.Returning)
+}
+
+func (f *formatter) setExpressions(sets []*SetExpression) {
+	for i, set := range sets {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		f.path(set.Path)
+		f.WriteString(" = ")
+		f.setOperand(&set.SetOperand)
+		if set.Arithmetic != nil {
+			f.WriteString(" " + set.Arithmetic.Operator + " ")
+			f.setOperand(set.Arithmetic.Operand)
+		}
+	}
+}
+
+func (f *formatter) setOperand(o *SetOperand) {
+	if o.Function != nil {
+		f.function(o.Function)
+	} else {
+		f.operand(o.Value)
+	}
+}
+
+func (f *formatter) delete(d *Delete) {
+	f.WriteString("DELETE FROM ")
+	f.ident(d.From)
+	f.where(d.Where)
+	f.returning(d.Returning)
+}
+
+func (f *formatter) returning(r *string) {
+	if r != nil {
+		f.WriteString(" RETURNING ")
+		f.WriteString(strings.ToUpper(*r))
+	}
+}
+
+func (f *formatter) createTable(c *CreateTable) {
+	f.WriteString("CREATE TABLE ")
+	f.ident(c.Table)
+	f.WriteString(" (")
+	for i, entry := range c.Entries {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		switch {
+		case entry.Attr != nil:
+			f.tableAttr(entry.Attr)
+		case entry.GlobalSecondaryIndex != nil:
+			f.globalIndex(entry.GlobalSecondaryIndex)
+		case entry.LocalSecondaryIndex != nil:
+			lsi := entry.LocalSecondaryIndex
+			f.WriteString("LOCAL SECONDARY INDEX ")
+			f.ident(lsi.Name)
+			f.WriteString(" RANGE(")
+			f.ident(lsi.SortKey)
+			f.WriteString(") PROJECTION ")
+			f.tableProjection(lsi.Projection)
+		case entry.ProvisionedThroughput != nil:
+			f.throughput(entry.ProvisionedThroughput)
+		case entry.BillingMode != nil:
+			if entry.BillingMode.PayPerRequest {
+				f.WriteString("BILLING MODE PAY_PER_REQUEST")
+			} else {
+				f.WriteString("BILLING MODE PROVISIONED")
+			}
+		}
+	}
+	f.WriteString(")")
+	if c.TTL != nil {
+		f.WriteString(" TTL (")
+		f.ident(*c.TTL)
+		f.WriteString(")")
+	}
+}
+
+func (f *formatter) alterTable(a *AlterTable) {
+	f.WriteString("ALTER TABLE ")
+	f.ident(a.Table)
+	for i, action := range a.Actions {
+		if i > 0 {
+			f.WriteString(",")
+		}
+		switch {
+		case action.AddIndex != nil:
+			f.WriteString(" ADD ")
+			f.globalIndex(action.AddIndex)
+		case action.AddAttr != nil:
+			f.WriteString(" ADD ")
+			f.tableAttr(action.AddAttr)
+		case action.DropIndex != nil:
+			f.WriteString(" DROP GLOBAL SECONDARY INDEX ")
+			f.ident(*action.DropIndex)
+		case action.SetThroughput != nil:
+			f.WriteString(" SET ")
+			f.throughput(action.SetThroughput)
+		}
+	}
+}
+
+func (f *formatter) tableAttr(attr *TableAttr) {
+	f.ident(attr.Name)
+	f.WriteString(" ")
+	f.WriteString(strings.ToUpper(attr.Type))
+	if attr.Key != "" {
+		f.WriteString(" ")
+		f.WriteString(strings.ToUpper(attr.Key))
+		f.WriteString(" KEY")
+	}
+}
+
+func (f *formatter) globalIndex(gsi *GlobalSecondaryIndex) {
+	f.WriteString("GLOBAL SECONDARY INDEX ")
+	f.ident(gsi.Name)
+	f.WriteString(" HASH(")
+	f.ident(gsi.PartitionKey)
+	f.WriteString(") RANGE(")
+	f.ident(gsi.SortKey)
+	f.WriteString(") PROJECTION ")
+	f.tableProjection(gsi.Projection)
+	if gsi.ProvisionedThroughput != nil {
+		f.WriteString(" ")
+		f.throughput(gsi.ProvisionedThroughput)
+	}
+}
+
+func (f *formatter) tableProjection(p *Projection) {
+	switch {
+	case p.KeysOnly:
+		f.WriteString("KEYS ONLY")
+	case p.All:
+		f.WriteString("ALL")
+	default:
+		f.WriteString("INCLUDE ")
+		for i, attr := range p.Include {
+			if i > 0 {
+				f.WriteString(", ")
+			}
+			f.ident(attr)
+		}
+	}
+}
+
+func (f *formatter) throughput(p *ProvisionedThroughput) {
+	f.WriteString("PROVISIONED THROUGHPUT READ ")
+	f.WriteString(strconv.FormatInt(p.ReadCapacityUnits, 10))
+	f.WriteString(" WRITE ")
+	f.WriteString(strconv.FormatInt(p.WriteCapacityUnits, 10))
+}
+
+func (f *formatter) where(where *ConditionExpression) {
+	if where != nil {
+		f.WriteString(" WHERE ")
+		f.or(where)
+	}
+}
+
+func (f *formatter) or(expr *ConditionExpression) {
+	for i, and := range expr.Or {
+		if i > 0 {
+			f.WriteString(" OR ")
+		}
+		f.and(and)
+	}
+}
+
+func (f *formatter) and(expr *AndExpression) {
+	for i, cond := range expr.And {
+		if i > 0 {
+			f.WriteString(" AND ")
+		}
+		f.condition(cond)
+	}
+}
+
+func (f *formatter) condition(cond *Condition) {
+	switch {
+	case cond.Parenthesized != nil:
+		f.WriteString("(")
+		f.or(cond.Parenthesized.ConditionExpression)
+		f.WriteString(")")
+	case cond.Not != nil:
+		f.WriteString("NOT ")
+		f.condition(cond.Not.Condition)
+	case cond.Operand != nil:
+		if cond.Operand.Size != nil {
+			f.function(cond.Operand.Size)
+		} else {
+			f.path(cond.Operand.Operand)
+		}
+		f.conditionRHS(cond.Operand.ConditionRHS)
+	case cond.Function != nil:
+		f.function(cond.Function)
+	}
+}
+
+func (f *formatter) conditionRHS(rhs *ConditionRHS) {
+	switch {
+	case rhs.Compare != nil:
+		f.WriteString(" ")
+		f.WriteString(rhs.Compare.Operator)
+		f.WriteString(" ")
+		f.operand(rhs.Compare.Operand)
+	case rhs.Between != nil:
+		f.WriteString(" BETWEEN ")
+		f.between(rhs.Between)
+	case rhs.NotBetween != nil:
+		f.WriteString(" NOT BETWEEN ")
+		f.between(rhs.NotBetween)
+	case rhs.In != nil:
+		f.WriteString(" IN (")
+		f.values(rhs.In.Values)
+		f.WriteString(")")
+	case rhs.NotIn != nil:
+		f.WriteString(" NOT IN (")
+		f.values(rhs.NotIn.Values)
+		f.WriteString(")")
+	case rhs.Is != nil:
+		if rhs.Is.Not {
+			f.WriteString(" IS NOT NULL")
+		} else {
+			f.WriteString(" IS NULL")
+		}
+	case rhs.Like != nil:
+		f.WriteString(" LIKE ")
+		f.value(rhs.Like.Pattern)
+	}
+}
+
+func (f *formatter) between(b *Between) {
+	f.operand(b.Start)
+	f.WriteString(" AND ")
+	f.operand(b.End)
+}
+
+func (f *formatter) function(fn *FunctionExpression) {
+	f.WriteString(fn.Function)
+	f.WriteString("(")
+	for i, arg := range fn.Args {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		if arg.DocumentPath != nil {
+			f.path(arg.DocumentPath)
+		} else {
+			f.value(arg.Value)
+		}
+	}
+	f.WriteString(")")
+}
+
+func (f *formatter) operand(o *Operand) {
+	if o.SymbolRef != nil {
+		f.path(o.SymbolRef)
+	} else {
+		f.value(o.Value)
+	}
+}
+
+func (f *formatter) values(values []*Value) {
+	for i, v := range values {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		f.value(v)
+	}
+}
+
+func (f *formatter) value(v *Value) {
+	switch {
+	case v.PlaceHolder != nil:
+		f.WriteString(*v.PlaceHolder)
+	case v.PositionalPlaceholder:
+		f.WriteString("?")
+	default:
+		f.scalar(&v.Scalar)
+	}
+}
+
+func (f *formatter) scalar(s *Scalar) {
+	switch {
+	case s.Number != nil:
+		f.WriteString(s.NumberLiteral())
+	case s.Str != nil:
+		f.WriteString(quoteString(*s.Str))
+	case s.Boolean != nil && bool(*s.Boolean):
+		f.WriteString("TRUE")
+	case s.Boolean != nil:
+		f.WriteString("FALSE")
+	case s.Null:
+		f.WriteString("NULL")
+	}
+}
+
+func (f *formatter) path(p *DocumentPath) {
+	for i, frag := range p.Fragment {
+		if i > 0 {
+			f.WriteString(".")
+		}
+		f.ident(frag.Symbol)
+		for _, accessor := range frag.Accessors {
+			f.WriteString("[")
+			f.WriteString(accessor.String())
+			f.WriteString("]")
+		}
+	}
+}
+
+func (f *formatter) jsonObject(o *JSONObject) {
+	f.WriteString("{")
+	for i, entry := range o.Entries {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		f.WriteString(quoteString(entry.Key))
+		f.WriteString(": ")
+		f.jsonValue(entry.Value)
+	}
+	f.WriteString("}")
+}
+
+func (f *formatter) jsonValue(v *JSONValue) {
+	switch {
+	case v.Object != nil:
+		f.jsonObject(v.Object)
+	case v.Array != nil:
+		f.WriteString("[")
+		for i, entry := range v.Array.Entries {
+			if i > 0 {
+				f.WriteString(", ")
+			}
+			f.jsonValue(entry)
+		}
+		f.WriteString("]")
+	case v.Set != nil:
+		f.WriteString(v.Set.Type)
+		f.WriteString("(")
+		for i, value := range v.Set.Values {
+			if i > 0 {
+				f.WriteString(", ")
+			}
+			f.scalar(value)
+		}
+		f.WriteString(")")
+	default:
+		f.value(&v.Value)
+	}
+}
+
+// ident writes an identifier, quoting it with backticks if it is not a plain identifier or collides with a keyword.
+func (f *formatter) ident(name string) {
+	if identRegexp.MatchString(name) && !keywordSet[strings.ToUpper(name)] {
+		f.WriteString(name)
+		return
+	}
+	f.WriteString("`" + name + "`")
+}
+
+// quoteString quotes a string so that the lexer reads it back unchanged. Strings containing double quotes but no
+// single quotes are single quoted, so that their quotes need no escaping.
+func quoteString(s string) string {
+	quoted := strconv.Quote(s)
+	if strings.Contains(s, `"`) && !strings.Contains(s, `'`) {
+		return "'" + strings.ReplaceAll(quoted[1:len(quoted)-1], `\"`, `"`) + "'"
+	}
+	return quoted
+}
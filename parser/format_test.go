@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatRoundTrip(t *testing.T) {
+	queries, err := os.Open("testdata/queries.sql")
+	require.NoError(t, err)
+	defer queries.Close()
+	scanner := bufio.NewScanner(queries)
+	var formatted []string
+	for scanner.Scan() {
+		query := scanner.Text()
+		if strings.HasPrefix(query, "--") {
+			continue
+		}
+		ast, err := Parse(query)
+		require.NoError(t, err, query)
+		out := Format(ast)
+		reparsed, err := Parse(out)
+		require.NoError(t, err, "Parse(Format(%s)): %s", query, out)
+		require.Equal(t, clearPositions(t, ast), clearPositions(t, reparsed), "Query: %s\nFormatted: %s", query, out)
+		require.Equal(t, out, Format(reparsed), "formatting is not stable for %s", query)
+		formatted = append(formatted, out)
+	}
+	g := goldie.New(t,
+		goldie.WithFixtureDir("testdata/golden"),
+		goldie.WithNameSuffix(".golden.sql"))
+	g.Assert(t, "format", []byte(strings.Join(formatted, "\n")+"\n"))
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "normalises keywords and whitespace",
+			query:    "select  title from movies\nwhere title = ? and year between 1990 and 2000 order by year desc limit 10",
+			expected: "SELECT title FROM movies WHERE title = ? AND year BETWEEN 1990 AND 2000 ORDER BY year DESC LIMIT 10",
+		},
+		{
+			name:     "quotes identifiers that are keywords",
+			query:    "SELECT `order`, `date time` AS `stamp`, `count` FROM `select` WHERE `order` > 1",
+			expected: "SELECT `order`, `date time` AS stamp, count FROM `select` WHERE `order` > 1",
+		},
+		{
+			name:     "drops unnecessary quotes",
+			query:    "SELECT * FROM `movies` WHERE `title` = 'Heat'",
+			expected: `SELECT * FROM movies WHERE title = "Heat"`,
+		},
+		{
+			name:     "single quotes strings containing double quotes",
+			query:    `INSERT INTO movies VALUES ('{"title": "Heat"}')`,
+			expected: `INSERT INTO movies VALUES ('{"title": "Heat"}')`,
+		},
+		{
+			name:     "escapes strings containing both quotes",
+			query:    `SELECT * FROM movies WHERE title = "it's \x22quoted\x22"`,
+			expected: `SELECT * FROM movies WHERE title = "it's \"quoted\""`,
+		},
+		{
+			name:     "doubled and escaped quotes",
+			query:    `SELECT * FROM movies WHERE title = 'O''Brien' AND plot = "say ""hi""" AND tagline = 'it\'s'`,
+			expected: `SELECT * FROM movies WHERE title = "O'Brien" AND plot = 'say "hi"' AND tagline = "it's"`,
+		},
+		{
+			name:     "JSON object values",
+			query:    `REPLACE INTO movies VALUES ({title: 'Heat', "info": {"rating": 8.2, "tags": ["crime", true, null,],},})`,
+			expected: `REPLACE INTO movies VALUES ({"title": "Heat", "info": {"rating": 8.2, "tags": ["crime", TRUE, NULL]}})`,
+		},
+		{
+			name:     "comments are dropped",
+			query:    "SELECT * /* all */ FROM movies -- everything",
+			expected: "SELECT * FROM movies",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ast, err := Parse(test.query)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, ast.String())
+			reparsed, err := Parse(test.expected)
+			require.NoError(t, err)
+			require.Equal(t, clearPositions(t, ast), clearPositions(t, reparsed))
+		})
+	}
+}
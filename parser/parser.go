@@ -3,6 +3,7 @@ package parser
 
 import (
 	"bytes"
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -11,29 +12,150 @@ import (
 )
 
 var (
+	// Keywords are matched case insensitively, and must be quoted with backticks to be used as identifiers. Other
+	// words of the grammar, such as the ON DUPLICATE KEY of an INSERT or the COUNT of SELECT COUNT(*), are lexed as
+	// identifiers, and are matched case insensitively too, so they can still name tables and attributes.
+	Keywords = []string{
+		"SELECT", "FROM", "WHERE", "LIMIT", "OFFSET", "INSERT", "INTO", "VALUES", "TRUE", "FALSE", "NULL", "NOT",
+		"BETWEEN", "AND", "OR", "USE", "INDEX", "ASC", "DESC", "CREATE", "TABLE", "HASH", "RANGE", "PROJECTION",
+		"PROVISIONED", "THROUGHPUT", "READ", "WRITE", "GLOBAL", "LOCAL", "INDEX", "SECONDARY", "STRING", "NUMBER",
+		"BINARY", "RETURNING", "NONE", "ALL_OLD", "UPDATED_OLD", "ALL_NEW", "UPDATED_NEW", "DELETE", "CHECK",
+		"UPDATE", "SET", "ADD", "REMOVE", "ORDER", "BY", "IS", "LIKE", "WITH", "AS", "DROP", "IF", "EXISTS", "CASE",
+		"WHEN", "THEN", "ELSE",
+	}
 	Lexer = lexer.Must(lexer.Regexp(`(\s+)` +
-		`|\b(?P<Keyword>(?i)SELECT|FROM|WHERE|LIMIT|OFFSET|INSERT|INTO|VALUES|TRUE|FALSE|NULL|NOT|BETWEEN|AND|OR|USE|INDEX|ASC|DESC|CREATE|TABLE|HASH|RANGE|PROJECTION|PROVISIONED|THROUGHPUT|READ|WRITE|GLOBAL|LOCAL|INDEX|SECONDARY|STRING|NUMBER|BINARY|RETURNING|NONE|ALL_OLD|UPDATED_OLD|ALL_NEW|UPDATED_NEW|DELETE|CHECK)\b` +
+		`|(?P<Comment>--[^\n]*|/\*(?s:.)*?\*/)` +
+		`|\b(?P<Keyword>(?i)` + strings.Join(Keywords, "|") + `)\b` +
 		"|(?P<QuotedIdent>`[^`]+`)" +
 		`|(?P<Ident>[a-zA-Z_][a-zA-Z0-9_]*)` +
 		`|(?P<Number>[-+]?\d*\.?\d+([eE][-+]?\d+)?)` +
-		`|(?P<String>'[^']*'|"[^"]*")` +
+		`|(?P<String>'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*")` +
 		`|(?P<Operators><>|!=|<=|>=|[-+*/%:?,.()=<>\[\]{}])` +
 		`|;`,
 	))
 	parser = participle.MustBuild(
 		&AST{},
 		participle.Lexer(Lexer),
-		participle.Unquote("String"),
+		participle.Elide("Comment"),
+		UnquoteString(),
 		UnquoteIdent(),
-		participle.CaseInsensitive("Keyword"),
+		participle.CaseInsensitive("Keyword", "Ident"),
 		participle.UseLookahead(2),
 	)
+	pathParser = participle.MustBuild(
+		&DocumentPath{},
+		participle.Lexer(Lexer),
+		participle.Elide("Comment"),
+		UnquoteString(),
+		UnquoteIdent(),
+		participle.CaseInsensitive("Keyword", "Ident"),
+	)
 )
 
+// Parse parses a single statement. Errors are returned as a *ParseError.
 func Parse(s string) (*AST, error) {
 	var ast AST
-	err := parser.ParseString(s, &ast)
-	return &ast, err
+	if err := parser.ParseString(s, &ast); err != nil {
+		return &ast, newParseError(err)
+	}
+	return &ast, Validate(&ast)
+}
+
+// ParseMulti parses a script of statements separated by semicolons, such as a migration, and returns each statement
+// in order. Empty statements and comments between statements are ignored. The positions of errors are relative to
+// the start of the script.
+func ParseMulti(s string) ([]*AST, error) {
+	lex, err := Lexer.Lex(strings.NewReader(s))
+	if err != nil {
+		return nil, newParseError(err)
+	}
+	tokens, err := lexer.ConsumeAll(lex)
+	if err != nil {
+		return nil, newParseError(err)
+	}
+	var asts []*AST
+	start, empty := 0, true
+	comment := Lexer.Symbols()["Comment"]
+	for _, token := range tokens {
+		if token.Type == comment {
+			continue
+		}
+		if !token.EOF() && token.Value != ";" {
+			empty = false
+			continue
+		}
+		if !empty {
+			// The statements before this one are blanked out rather than cut off, so that positions stay the same.
+			blank := strings.Map(func(r rune) rune {
+				if r == '\n' {
+					return r
+				}
+				return ' '
+			}, s[:start])
+			ast, err := Parse(blank + s[start:token.Pos.Offset])
+			if err != nil {
+				return nil, err
+			}
+			asts = append(asts, ast)
+		}
+		start, empty = token.Pos.Offset+len(token.Value), true
+	}
+	return asts, nil
+}
+
+// ParsePath parses a document path on its own, such as info.actors[0] or info['release date'].
+func ParsePath(s string) (*DocumentPath, error) {
+	var path DocumentPath
+	if err := pathParser.ParseString(s, &path); err != nil {
+		return nil, newParseError(err)
+	}
+	if err := validatePaths(&path); err != nil {
+		return nil, validationError(lexer.Position{}, err)
+	}
+	return &path, nil
+}
+
+// Validate checks an AST that was not built by Parse, such as one built in code, the same way Parse checks the
+// statements it parses. Errors are returned as a *ParseError.
+func Validate(ast *AST) error {
+	if err := validate(ast); err != nil {
+		return validationError(lexer.Position{}, err)
+	}
+	return nil
+}
+
+// UnquoteString removes the quotes surrounding strings and unescapes them. Strings have the escapes of Go, and the
+// quote character can also be escaped by doubling it, as in SQL.
+func UnquoteString() participle.Option {
+	return participle.Map(func(t lexer.Token) (lexer.Token, error) {
+		value, err := unquoteString(t.Value)
+		if err != nil {
+			return t, lexer.ErrorWithTokenf(t, "invalid quoted string %q: %s", t.Value, err.Error())
+		}
+		t.Value = value
+		return t, nil
+	}, "String")
+}
+
+func unquoteString(s string) (string, error) {
+	quote := s[0]
+	s = s[1 : len(s)-1]
+	var out strings.Builder
+	for s != "" {
+		// The lexer only allows the quote character in a string if it is escaped or doubled.
+		if s[0] == quote {
+			out.WriteByte(quote)
+			s = s[2:]
+			continue
+		}
+		value, _, tail, err := strconv.UnquoteChar(s, quote)
+		if err != nil {
+			return "", err
+		}
+		out.WriteRune(value)
+		s = tail
+	}
+	return out.String(), nil
 }
 
 // UnquoteIdent removes surrounding backticks (`) from quoted identifiers
@@ -51,6 +173,25 @@ func (b *Boolean) Capture(values []string) error {
 	return nil
 }
 
+// SignedNumber is a number that must have an explicit sign. NumberText is the number as written, without its sign.
+type SignedNumber struct {
+	Operator   string
+	Number     float64
+	NumberText string
+}
+
+func (n *SignedNumber) Capture(values []string) error {
+	if !strings.HasPrefix(values[0], "-") && !strings.HasPrefix(values[0], "+") {
+		return fmt.Errorf("expected + or - before %s", values[0])
+	}
+	number, err := strconv.ParseFloat(values[0][1:], 64)
+	if err != nil {
+		return err
+	}
+	n.Operator, n.Number, n.NumberText = values[0][:1], number, values[0][1:]
+	return nil
+}
+
 type ScanDescending bool
 
 func (b *ScanDescending) Capture(values []string) error {
@@ -63,24 +204,85 @@ type Node interface {
 	node()
 }
 
+// AST is a statement. INSERT and REPLACE share the Insert node, and whether Insert or Replace is set records which
+// verb was used: an INSERT fails if an item with the same key exists, while a REPLACE overwrites it.
 type AST struct {
 	Select      *Select      `(   "SELECT"         @@`
 	Insert      *Insert      `  | "INSERT"         @@`
 	Replace     *Insert      `  | "REPLACE"        @@`
-	CreateTable *CreateTable `  | "CREATE" "TABLE" @@ ) ";"?`
+	Update      *Update      `  | "UPDATE"         @@`
+	Delete      *Delete      `  | "DELETE"         @@`
+	CreateTable *CreateTable `  | "CREATE" "TABLE" @@`
+	DropTable   *DropTable   `  | "DROP" "TABLE" @@`
+	AlterTable  *AlterTable  `  | "ALTER" "TABLE" @@`
+	Describe    *Describe    `  | ("DESCRIBE" | "DESC") @@`
+	ShowTables  *ShowTables  `  | "SHOW" "TABLES" @@`
+	Explain     *Explain     `  | "EXPLAIN" @@ ) ";"?`
 }
 
+func (a *AST) node() {}
+
 type CreateTable struct {
-	Table   string              `@(Ident | QuotedIdent) "("`
+	Table   string              `( @Ident ( @"." @Ident )* | @QuotedIdent ) "("`
 	Entries []*CreateTableEntry `@@ ("," @@)* ")"`
+	// TTL is the attribute to enable Time to Live on, once the table has been created.
+	TTL *string `( "TTL" "(" @(Ident | QuotedIdent) ")" )?`
 }
 
 func (c *CreateTable) node() {}
 
+type DropTable struct {
+	IfExists bool   `@( "IF" "EXISTS" )?`
+	Table    string `( @Ident ( @"." @Ident )* | @QuotedIdent )`
+}
+
+func (d *DropTable) node() {}
+
+// AlterTable changes the global secondary indexes or the provisioned throughput of a table, with UpdateTable.
+type AlterTable struct {
+	Table   string              `( @Ident ( @"." @Ident )* | @QuotedIdent )`
+	Actions []*AlterTableAction `@@ ("," @@)*`
+}
+
+func (a *AlterTable) node() {}
+
+// AlterTableAction is one of the comma separated changes in an ALTER TABLE. Attributes are declared with ADD so
+// that the keys of a new index have a type.
+type AlterTableAction struct {
+	AddIndex      *GlobalSecondaryIndex  `  "ADD" ( @@`
+	AddAttr       *TableAttr             `        | @@ )`
+	DropIndex     *string                `| "DROP" "GLOBAL" "SECONDARY" "INDEX" @(Ident | QuotedIdent)`
+	SetThroughput *ProvisionedThroughput `| "SET" @@`
+}
+
+func (a *AlterTableAction) node() {}
+
+// Describe lists the key attributes of a table and its secondary indexes.
+type Describe struct {
+	Table string `( @Ident ( @"." @Ident )* | @QuotedIdent )`
+}
+
+func (d *Describe) node() {}
+
+// ShowTables lists the tables in the account and region, optionally only those whose name matches a LIKE pattern.
+type ShowTables struct {
+	Like *string `( "LIKE" @String )?`
+}
+
+func (s *ShowTables) node() {}
+
+// Explain describes the requests a statement is compiled into, without running it.
+type Explain struct {
+	Statement *AST `@@`
+}
+
+func (e *Explain) node() {}
+
 type CreateTableEntry struct {
 	GlobalSecondaryIndex  *GlobalSecondaryIndex  `  @@`
 	LocalSecondaryIndex   *LocalSecondaryIndex   `| @@`
 	ProvisionedThroughput *ProvisionedThroughput `| @@`
+	BillingMode           *BillingMode           `| @@`
 	Attr                  *TableAttr             `| @@` // Must be last.
 }
 
@@ -93,12 +295,19 @@ type ProvisionedThroughput struct {
 
 func (p *ProvisionedThroughput) node() {}
 
+// BillingMode is either BILLING MODE PROVISIONED, the default, or BILLING MODE PAY_PER_REQUEST for on-demand tables.
+type BillingMode struct {
+	PayPerRequest bool `"BILLING" "MODE" ( @"PAY_PER_REQUEST" | "PROVISIONED" )`
+}
+
+func (b *BillingMode) node() {}
+
 type GlobalSecondaryIndex struct {
 	Name                  string                 `"GLOBAL" "SECONDARY" "INDEX" @(Ident | QuotedIdent)`
 	PartitionKey          string                 `"HASH" "(" @(Ident | QuotedIdent) ")"`
 	SortKey               string                 `"RANGE" "(" @(Ident | QuotedIdent) ")"`
 	Projection            *Projection            `"PROJECTION" @@`
-	ProvisionedThroughput *ProvisionedThroughput `@@`
+	ProvisionedThroughput *ProvisionedThroughput `@@?`
 }
 
 func (c *GlobalSecondaryIndex) node() {}
@@ -130,67 +339,351 @@ type Select struct {
 	Projection *ProjectionExpression `@@`
 	From       string                `"FROM" ( @Ident ( @"." @Ident )* | @QuotedIdent )`
 	Index      *string               `( "USE" "INDEX" "(" @Ident ")" )?`
-	Where      *AndExpression        `( "WHERE" @@ )?`
+	Where      *ConditionExpression  `( "WHERE" @@ )?`
+	OrderBy    *OrderBy              `( "ORDER" "BY" @@ )?`
 	Descending *ScanDescending       `( @"ASC" | @"DESC" )?`
-	Limit      *int                  `( "LIMIT" @Number )?`
+	Limit      *Limit                `( "LIMIT" @@ )?`
+	// OffsetNumber is the OFFSET as written, which Parse checks is a non-negative integer before moving it to Offset.
+	OffsetNumber *string `( "OFFSET" @Number )?`
+	Offset       *int
+	Hints        []*SelectHint `( "WITH" "(" @@ ( "," @@ )* ")" )?`
 }
 
+// Limit is the maximum number of items a SELECT returns, a positive integer or the placeholder it is bound from.
+type Limit struct {
+	Pos lexer.Position
+
+	Value *Value `@@`
+}
+
+func (l *Limit) node() {}
+
+// Consistent returns true if the WITH (CONSISTENT) hint is given.
+func (e *Select) Consistent() bool {
+	for _, hint := range e.Hints {
+		if hint.Consistent {
+			return true
+		}
+	}
+	return false
+}
+
+// Segments returns the number of parallel scan segments given with WITH (SEGMENTS = n), and whether it was given.
+func (e *Select) Segments() (int, bool) {
+	for _, hint := range e.Hints {
+		if hint.Segments != nil {
+			return *hint.Segments, true
+		}
+	}
+	return 0, false
+}
+
+// Scan returns true if the WITH (SCAN) hint is given, to Scan even if the table or an index could be queried.
+func (e *Select) Scan() bool {
+	for _, hint := range e.Hints {
+		if hint.Scan {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectHint is one of the comma separated hints in WITH (...).
+//
+// The number of SEGMENTS is captured as written into SegmentsNumber, and Parse checks that it is a positive integer
+// before moving it to Segments.
+type SelectHint struct {
+	Consistent     bool    `  @"CONSISTENT"`
+	SegmentsNumber *string `| "SEGMENTS" "=" @Number`
+	Segments       *int
+	Scan           bool `| @"SCAN"`
+}
+
+func (h *SelectHint) node() {}
+
+// OrderBy sorts the results by an attribute. DynamoDB can only sort by the sort key of the table or index.
+type OrderBy struct {
+	Path       *DocumentPath   `@@`
+	Descending *ScanDescending `( @"ASC" | @"DESC" )?`
+}
+
+func (o *OrderBy) node() {}
+
 type Insert struct {
-	Into      string            `"INTO" ( @Ident ( @"." @Ident )* | @QuotedIdent )`
-	Values    []*InsertTerminal `"VALUES" "(" @@ ")" ( "," "(" @@ ")" )* `
-	Returning *string           `( "RETURNING" @( "NONE" | "ALL_OLD" ) )?`
+	Into   string            `"INTO" ( @Ident ( @"." @Ident )* | @QuotedIdent )`
+	Values []*InsertTerminal `(   "VALUES" "(" @@ ")" ( "," "(" @@ ")" )* `
+	// Select is the query whose items are written, as in INSERT INTO dest SELECT * FROM src.
+	Select *Select `  | "SELECT" @@ )`
+	// OnDuplicate are the assignments made instead of inserting, if an item with the same key already exists.
+	OnDuplicate []*SetExpression `( "ON" "DUPLICATE" "KEY" "UPDATE" @@ ( "," @@ )* )?`
+	// IfNotExists makes explicit that the write fails if an item with the same key exists, which is the default
+	// for INSERT.
+	IfNotExists bool    `@( "IF" "NOT" "EXISTS" )?`
+	Returning   *string `( "RETURNING" @( "NONE" | "ALL_OLD" ) )?`
 }
 
+func (i *Insert) node() {}
+
+type Update struct {
+	Table     string               `( @Ident ( @"." @Ident )* | @QuotedIdent )`
+	Actions   []*UpdateAction      `@@+`
+	Where     *ConditionExpression `( "WHERE" @@ )?`
+	Returning *string              `( "RETURNING" @( "NONE" | "ALL_OLD" | "UPDATED_OLD" | "ALL_NEW" | "UPDATED_NEW" ) )?`
+}
+
+func (u *Update) node() {}
+
+// UpdateAction is a single clause of an update expression. Clauses are kept in the order they appear in the statement.
+type UpdateAction struct {
+	Set    []*SetExpression    `  "SET" @@ ( "," @@ )*`
+	Add    []*AddExpression    `| "ADD" @@ ( "," @@ )*`
+	Remove []*DocumentPath     `| "REMOVE" @@ ( "," @@ )*`
+	Delete []*DeleteExpression `| "DELETE" @@ ( "," @@ )*`
+}
+
+func (u *UpdateAction) node() {}
+
+// SetExpression assigns a value to a path, optionally adding or subtracting another value as in
+// SET views = views + :n.
+type SetExpression struct {
+	Path *DocumentPath `@@ "="`
+	SetOperand
+	Arithmetic *SetArithmetic `@@?`
+}
+
+func (s *SetExpression) node() {}
+
+// SetOperand is a value on the right hand side of SET. It is either an operand or a function of operands, such as
+// list_append(tags, :tags) or if_not_exists(views, 0).
+type SetOperand struct {
+	Function *FunctionExpression `(  @@`
+	Value    *Operand            ` | @@ )`
+}
+
+func (s *SetOperand) node() {}
+
+// SetArithmetic is the + or - of a SET. DynamoDB only supports them on numbers.
+type SetArithmetic struct {
+	Operator string      `(  @( "+" | "-" )`
+	Operand  *SetOperand `   @@`
+	// Signed is a number written straight after the value, as in views-1, which is lexed as the single number -1.
+	// Parse folds it into Operator and Operand.
+	Signed *SignedNumber `| @Number )`
+}
+
+func (s *SetArithmetic) node() {}
+
+type AddExpression struct {
+	Path  *DocumentPath `@@`
+	Value *Value        `@@`
+}
+
+func (a *AddExpression) node() {}
+
+type DeleteExpression struct {
+	Path  *DocumentPath `@@`
+	Value *Value        `@@`
+}
+
+func (d *DeleteExpression) node() {}
+
+type Delete struct {
+	From      string               `"FROM" ( @Ident ( @"." @Ident )* | @QuotedIdent )`
+	Where     *ConditionExpression `( "WHERE" @@ )?`
+	Returning *string              `( "RETURNING" @( "NONE" | "ALL_OLD" ) )?`
+}
+
+func (d *Delete) node() {}
+
 type InsertTerminal struct {
 	Value
 	Object *JSONObject `| @@`
 }
 
+func (e *InsertTerminal) node() {}
+
 func (e *Select) node() {}
 
+// ProjectionExpression is the list of columns of a SELECT. All is set for *, which returns each item whole, and may
+// be followed by other columns, as in SELECT *, price * qty AS total.
 type ProjectionExpression struct {
-	All     bool                `  ( @"*" | "document" "(" @"*" ")" )`
+	All bool `  ( @"*" | "document" "(" @"*" ")" )`
+	// Extra are the columns that follow *, which Parse folds into Columns.
+	Extra   []*ProjectionColumn `( "," @@ )*`
+	Count   bool                `| "COUNT" "(" @"*" ")"`
 	Columns []*ProjectionColumn `| @@ ( "," @@ )*`
 }
 
 func (e *ProjectionExpression) node() {}
 
 func (e ProjectionExpression) String() string {
-	if e.All {
+	if e.All && len(e.Columns) == 0 {
 		return ""
 	}
+	if e.Count {
+		return "COUNT(*)"
+	}
 	buf := &bytes.Buffer{}
-	buf.WriteString(e.Columns[0].String())
-	for _, p := range e.Columns[1:] {
-		buf.WriteString(", ")
+	if e.All {
+		buf.WriteString("*")
+	}
+	for i, p := range e.Columns {
+		if i > 0 || e.All {
+			buf.WriteString(", ")
+		}
 		buf.WriteString(p.String())
 	}
 	return buf.String()
 }
 
 type ProjectionColumn struct {
-	Function     *FunctionExpression `  @@`
-	DocumentPath *DocumentPath       `| @@`
+	Case     *CaseExpression     `(  @@`
+	Function *FunctionExpression ` | @@`
+	// Arithmetic is a computed column, which Parse folds into DocumentPath if it is a lone attribute.
+	Arithmetic   *Arithmetic `  | @@ )`
+	DocumentPath *DocumentPath
+	Alias        *string `( "AS" @(Ident | QuotedIdent) )?`
 }
 
 func (c *ProjectionColumn) node() {}
 
+// Name returns the name of the column in the rows of the SELECT, which is its alias if given with AS. A CASE without
+// an alias is named "case", a document() "document", and a computed column is named after its expression, such as
+// "price * qty".
+func (c *ProjectionColumn) Name() string {
+	switch {
+	case c.Alias != nil:
+		return *c.Alias
+	case c.Case != nil:
+		return "case"
+	case c.Arithmetic != nil:
+		return c.Arithmetic.String()
+	case c.Function != nil:
+		return "document"
+	default:
+		return c.DocumentPath.String()
+	}
+}
+
 func (c ProjectionColumn) String() string {
+	var col string
 	if c.DocumentPath != nil {
-		return c.DocumentPath.String()
+		col = c.DocumentPath.String()
+	} else if c.Function != nil {
+		col = c.Function.String()
+	} else if c.Case != nil {
+		col = c.Case.String()
+	} else if c.Arithmetic != nil {
+		col = c.Arithmetic.String()
 	}
-	if c.Function != nil {
-		return c.Function.String()
+	if c.Alias != nil {
+		col += " AS " + *c.Alias
 	}
-	return ""
+	return col
+}
+
+// CaseExpression is a CASE WHEN condition THEN result ... ELSE result END column. DynamoDB can't compute it, so it
+// is evaluated on each item once read, and the attributes it refers to are projected along with the other columns.
+type CaseExpression struct {
+	Whens []*CaseWhen `"CASE" @@+`
+	Else  *Operand    `( "ELSE" @@ )? "END"`
+}
+
+func (c *CaseExpression) node() {}
+
+func (c *CaseExpression) String() string {
+	f := &formatter{}
+	f.caseExpression(c)
+	return f.String()
+}
+
+// Arithmetic is a computed column, such as price * qty or (price - discount) / 2. DynamoDB can't compute it, so it
+// is evaluated on each item once read, with * and / binding tighter than + and -.
+type Arithmetic struct {
+	Operand *ArithmeticOperand `@@`
+	Ops     []*ArithmeticOp    `@@*`
+}
+
+func (a *Arithmetic) node() {}
+
+func (a *Arithmetic) String() string {
+	f := &formatter{}
+	f.arithmetic(a)
+	return f.String()
+}
+
+// ArithmeticOp applies an operator to the value of the operations before it and an operand.
+type ArithmeticOp struct {
+	Operator string             `(  @( "+" | "-" | "*" | "/" )`
+	Operand  *ArithmeticOperand `   @@`
+	// Signed is a number written straight after the value, as in price-1, which is lexed as the single number -1.
+	// Parse folds it into Operator and Operand.
+	Signed *SignedNumber `| @Number )`
+}
+
+func (a *ArithmeticOp) node() {}
+
+// ArithmeticOperand is a number, an attribute or a parenthesized computation. A number is captured as written into
+// NumberText, and Parse sets Number to its value.
+type ArithmeticOperand struct {
+	Number        *float64
+	NumberText    *string       `  @Number`
+	DocumentPath  *DocumentPath `| @@`
+	Parenthesized *Arithmetic   `| "(" @@ ")"`
+}
+
+func (a *ArithmeticOperand) node() {}
+
+// NumberLiteral returns the number as written, or formatted from Number for an operand built without NumberText.
+func (a *ArithmeticOperand) NumberLiteral() string {
+	return numberLiteral(a.Number, a.NumberText)
+}
+
+// CaseWhen is a WHEN condition THEN result clause of a CASE.
+type CaseWhen struct {
+	Condition *ConditionExpression `"WHEN" @@`
+	Result    *Operand             `"THEN" @@`
 }
 
+func (c *CaseWhen) node() {}
+
 type ConditionExpression struct {
 	Or []*AndExpression `@@ ( "OR" @@ )*`
 }
 
 func (e *ConditionExpression) node() {}
 
+// Conjunction returns the conditions that must all hold, or nil if the expression has an OR at the top level or is
+// nil. Only these can pin the key of a table or index. The conditions of a parenthesized group without an OR at its
+// top level, as in (a = 1 AND b = 2) AND c = 3, are also conditions of the expression, and are returned in its place.
+func (e *ConditionExpression) Conjunction() *AndExpression {
+	if e == nil || len(e.Or) != 1 {
+		return nil
+	}
+	and := e.Or[0]
+	for _, term := range and.And {
+		if term.Parenthesized != nil && term.Parenthesized.ConditionExpression.Conjunction() != nil {
+			return and.flatten()
+		}
+	}
+	return and
+}
+
+// flatten returns the conditions with each parenthesized group without an OR replaced by its conditions.
+func (e *AndExpression) flatten() *AndExpression {
+	flat := &AndExpression{}
+	for _, term := range e.And {
+		if term.Parenthesized != nil {
+			if group := term.Parenthesized.ConditionExpression.Conjunction(); group != nil {
+				flat.And = append(flat.And, group.And...)
+				continue
+			}
+		}
+		flat.And = append(flat.And, term)
+	}
+	return flat
+}
+
 type AndExpression struct {
 	And []*Condition `@@ ( "AND" @@ )*`
 }
@@ -204,10 +697,15 @@ type ParenthesizedExpression struct {
 func (e *ParenthesizedExpression) node() {}
 
 type Condition struct {
+	Pos lexer.Position
+
 	Parenthesized *ParenthesizedExpression `  "(" @@ ")"`
 	Not           *NotCondition            `| "NOT" @@`
 	Operand       *ConditionOperand        `| @@`
 	Function      *FunctionExpression      `| @@`
+	// FunctionRHS compares the result of size() and is folded into Operand once parsed, as only size() returns a
+	// value that can be compared.
+	FunctionRHS *ConditionRHS `@@?`
 }
 
 func (e *Condition) node() {}
@@ -261,20 +759,48 @@ func (a FunctionArgument) String() string {
 }
 
 type ConditionOperand struct {
-	Operand      *DocumentPath `@@`
+	Operand *DocumentPath `@@`
+	// Size is set instead of Operand when the size() of an attribute is compared.
+	Size         *FunctionExpression
 	ConditionRHS *ConditionRHS `@@`
 }
 
 func (c *ConditionOperand) node() {}
 
+// Path returns the attribute the condition applies to, which is the argument of size() if the size is compared.
+func (c *ConditionOperand) Path() *DocumentPath {
+	if c.Size != nil {
+		return c.Size.Args[0].DocumentPath
+	}
+	return c.Operand
+}
+
 type ConditionRHS struct {
-	Compare *Compare `  @@`
-	Between *Between `| "BETWEEN" @@`
-	In      *In      `| "IN" "(" @@ ")"`
+	Compare    *Compare `  @@`
+	Between    *Between `| "BETWEEN" @@`
+	NotBetween *Between `| "NOT" "BETWEEN" @@`
+	In         *In      `| "IN" "(" @@ ")"`
+	NotIn      *In      `| "NOT" "IN" "(" @@ ")"`
+	Is         *Is      `| "IS" @@`
+	Like       *Like    `| "LIKE" @@`
 }
 
 func (c *ConditionRHS) node() {}
 
+// Is is an IS NULL or IS NOT NULL check, which tests whether the attribute is absent or present.
+type Is struct {
+	Not bool `@"NOT"? "NULL"`
+}
+
+func (i *Is) node() {}
+
+// Like matches the attribute against a SQL LIKE pattern.
+type Like struct {
+	Pattern *Value `@@`
+}
+
+func (l *Like) node() {}
+
 type In struct {
 	Values []*Value `@@ ( "," @@ )*`
 }
@@ -308,6 +834,16 @@ type DocumentPath struct {
 
 func (p *DocumentPath) node() {}
 
+// Attribute returns the name of the top level attribute that the path addresses, or an empty string if it addresses
+// a value inside one. An attribute named a.b must be quoted as `a.b`, as a.b is the entry b of the map a, and the
+// two have the same String().
+func (p *DocumentPath) Attribute() string {
+	if len(p.Fragment) != 1 || len(p.Fragment[0].Accessors) != 0 {
+		return ""
+	}
+	return p.Fragment[0].Symbol
+}
+
 // String marshals the DocumentPath into a human readable format. Do not use this function when marshaling
 // to expressions, because substitutions need to be applied first for reserved words.
 func (p DocumentPath) String() string {
@@ -321,26 +857,50 @@ func (p DocumentPath) String() string {
 }
 
 type PathFragment struct {
-	Symbol  string `( @Ident | @QuotedIdent )`
-	Indexes []int  `( "[" @Number "]" )*`
+	Symbol    string          `( @Ident | @QuotedIdent )`
+	Accessors []*PathAccessor `( "[" @@ "]" )*`
 }
 
 func (p PathFragment) node() {}
 
 func (p PathFragment) String() string {
-	if len(p.Indexes) == 0 {
+	if len(p.Accessors) == 0 {
 		return p.Symbol
 	}
 	buf := &bytes.Buffer{}
 	buf.WriteString(p.Symbol)
-	for _, idx := range p.Indexes {
+	for _, accessor := range p.Accessors {
 		buf.WriteRune('[')
-		buf.WriteString(strconv.Itoa(idx))
+		buf.WriteString(accessor.String())
 		buf.WriteRune(']')
 	}
 	return buf.String()
 }
 
+// PathAccessor is an element of a list or set, as in [0], or an entry of a map, as in ['a key']. A map key in
+// brackets is the same as .key, but can be any string rather than only an identifier.
+//
+// The index is captured as written into Number, as the lexer's numbers can be signed or fractional, and Parse checks
+// that it is a non-negative integer before moving it to Index.
+type PathAccessor struct {
+	Number *string `  @Number`
+	Index  *int
+	Key    *string `| @String`
+}
+
+func (a *PathAccessor) node() {}
+
+func (a PathAccessor) String() string {
+	switch {
+	case a.Key != nil:
+		return quoteString(*a.Key)
+	case a.Index == nil && a.Number != nil:
+		return *a.Number
+	default:
+		return strconv.Itoa(*a.Index)
+	}
+}
+
 type JSONObjectEntry struct {
 	Key   string     `@(Ident | String)`
 	Value *JSONValue `":" @@`
@@ -376,24 +936,52 @@ func (j *JSONArray) String() string {
 	return "[" + strings.Join(out, ",") + "]"
 }
 
+// JSONValue is a value of a document literal. Besides literals, it can be a placeholder, which is bound each time the
+// statement is executed.
 type JSONValue struct {
-	Scalar
+	Value
 	Object *JSONObject `| @@`
 	Array  *JSONArray  `| @@`
+	Set    *SetLiteral `| @@`
+}
+
+// SetLiteral is a string, number or binary set, such as string_set('a', 'b'). The elements of a binary set are
+// base64 encoded strings.
+type SetLiteral struct {
+	Type   string    `@("string_set" | "number_set" | "binary_set") "("`
+	Values []*Scalar `( @@ ( "," @@ )* )? ")"`
 }
 
+func (s *SetLiteral) node() {}
+
+// Scalar is a literal. A number is captured as written into NumberText, which keeps the precision of integers such as
+// 12345678901234567890 that a float64 can't hold, and Parse sets Number to its value.
 type Scalar struct {
-	Number  *float64 `  @Number`
-	Str     *string  `| @String`
-	Boolean *Boolean `| @("TRUE" | "FALSE")`
-	Null    bool     `| @"NULL"`
+	Number     *float64
+	NumberText *string  `  @Number`
+	Str        *string  `| @String`
+	Boolean    *Boolean `| @("TRUE" | "FALSE")`
+	Null       bool     `| @"NULL"`
 }
 
 func (l *Scalar) node() {}
+
+// NumberLiteral returns the number as written, or formatted from Number for a literal built without NumberText.
+func (l *Scalar) NumberLiteral() string {
+	return numberLiteral(l.Number, l.NumberText)
+}
+
+func numberLiteral(number *float64, text *string) string {
+	if text != nil {
+		return *text
+	}
+	return strconv.FormatFloat(*number, 'f', -1, 64)
+}
+
 func (l *Scalar) String() string {
 	switch {
 	case l.Number != nil:
-		return strconv.FormatFloat(*l.Number, 'g', -1, 64)
+		return l.NumberLiteral()
 	case l.Str != nil:
 		return strconv.Quote(*l.Str)
 	case l.Boolean != nil:
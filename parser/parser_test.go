@@ -2,12 +2,15 @@ package parser
 
 import (
 	"bufio"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/alecthomas/participle"
+	"github.com/alecthomas/participle/lexer"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/sebdah/goldie/v2"
 	"github.com/stretchr/testify/assert"
@@ -56,6 +59,7 @@ func TestGoldenBadQueries(t *testing.T) {
 	type row struct {
 		Query string
 		Error string
+		Kind  string
 	}
 
 	queries, err := os.Open("testdata/bad_queries.sql")
@@ -71,9 +75,12 @@ func TestGoldenBadQueries(t *testing.T) {
 		}
 		_, err := Parse(query)
 		require.Errorf(t, err, "Parse %s, expected error but did not", query)
+		var perr *ParseError
+		require.True(t, errors.As(err, &perr), "Parse %s, expected a *ParseError but got %T", query, err)
 		parsed = append(parsed, row{
 			Query: query,
 			Error: err.Error(),
+			Kind:  perr.Kind.String(),
 		})
 	}
 
@@ -150,14 +157,14 @@ VALUES ({title:"hello",year:2009}),
 					Values: []*InsertTerminal{
 						{
 							Object: &JSONObject{[]*JSONObjectEntry{
-								{"title", &JSONValue{Scalar: Scalar{Str: aws.String("hello")}}},
-								{"year", &JSONValue{Scalar: Scalar{Number: aws.Float64(2009)}}},
+								{"title", &JSONValue{Value: Value{Scalar: Scalar{Str: aws.String("hello")}}}},
+								{"year", &JSONValue{Value: Value{Scalar: Scalar{Number: aws.Float64(2009), NumberText: aws.String("2009")}}}},
 							}},
 						},
 						{
 							Object: &JSONObject{[]*JSONObjectEntry{
-								{"title", &JSONValue{Scalar: Scalar{Str: aws.String("foo")}}},
-								{"year", &JSONValue{Scalar: Scalar{Number: aws.Float64(2938)}}},
+								{"title", &JSONValue{Value: Value{Scalar: Scalar{Str: aws.String("foo")}}}},
+								{"year", &JSONValue{Value: Value{Scalar: Scalar{Number: aws.Float64(2938), NumberText: aws.String("2938")}}}},
 							}},
 						},
 					},
@@ -174,3 +181,161 @@ VALUES ({title:"hello",year:2009}),
 		})
 	}
 }
+
+func TestParseComments(t *testing.T) {
+	expected, err := Parse(`SELECT * FROM movies WHERE title = :title AND year > 2000`)
+	require.NoError(t, err)
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{
+			name: "line",
+			query: `
+-- Movies released after 2000
+SELECT * FROM movies -- by title
+WHERE title = :title AND year > 2000 --`,
+		},
+		{
+			name: "block",
+			query: `
+/*
+ * Movies released
+ * after 2000
+ */
+SELECT * FROM /* table */ movies WHERE title = :title AND/**/year > 2000`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ast, err := Parse(test.query)
+			require.NoError(t, err)
+			// Comments shift the position of the conditions, but must not change the AST otherwise.
+			assert.Equal(t, clearPositions(t, expected), clearPositions(t, ast))
+		})
+	}
+	t.Run("comment markers in strings are not comments", func(t *testing.T) {
+		ast, err := Parse(`SELECT * FROM movies WHERE title = "-- /* */"`)
+		require.NoError(t, err)
+		require.Equal(t, "-- /* */", *ast.Select.Where.Or[0].And[0].Operand.ConditionRHS.Compare.Operand.Value.Str)
+	})
+}
+
+func TestParseMulti(t *testing.T) {
+	t.Run("statements", func(t *testing.T) {
+		asts, err := ParseMulti(`
+-- Create the table; then fill it
+CREATE TABLE movies (title STRING HASH KEY, year NUMBER RANGE KEY);
+INSERT INTO movies VALUES ({"title": "Heat; the movie", "year": 1995}) /* ; */ ;;
+DELETE FROM movies WHERE title = 'a;b' AND year = 1995 AND ` + "`odd;name`" + ` = 1
+-- done;
+`)
+		require.NoError(t, err)
+		var formatted []string
+		for _, ast := range asts {
+			formatted = append(formatted, Format(ast))
+		}
+		require.Equal(t, []string{
+			`CREATE TABLE movies (title STRING HASH KEY, year NUMBER RANGE KEY)`,
+			`INSERT INTO movies VALUES ({"title": "Heat; the movie", "year": 1995})`,
+			"DELETE FROM movies WHERE title = \"a;b\" AND year = 1995 AND `odd;name` = 1",
+		}, formatted)
+	})
+
+	t.Run("positions are relative to the script", func(t *testing.T) {
+		asts, err := ParseMulti("SELECT * FROM a;\nSELECT * FROM b WHERE x = 1 AND y = 2")
+		require.NoError(t, err)
+		require.Equal(t, 2, asts[1].Select.Where.Or[0].And[1].Pos.Line)
+		_, err = ParseMulti("SELECT * FROM a;\n  SELECT * FROM")
+		require.EqualError(t, err, `2:16: unexpected token "<EOF>" (expected <ident> | <quotedident>)`)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		asts, err := ParseMulti(" ; -- nothing\n")
+		require.NoError(t, err)
+		require.Empty(t, asts)
+	})
+}
+
+func TestTokenize(t *testing.T) {
+	tokens, err := Tokenize("select `year`, info['a b'] FROM movies -- all\nWHERE size(tags) >= -1.5 /* x */;")
+	require.NoError(t, err)
+	type token struct {
+		Value, Type, Category string
+	}
+	var got []token
+	for _, tok := range tokens {
+		got = append(got, token{tok.Value, TokenType(tok), Category(tok).String()})
+	}
+	require.Equal(t, []token{
+		{"select", "Keyword", "keyword"},
+		{"`year`", "QuotedIdent", "identifier"},
+		{",", "Operators", "operator"},
+		{"info", "Ident", "identifier"},
+		{"[", "Operators", "operator"},
+		{"'a b'", "String", "string"},
+		{"]", "Operators", "operator"},
+		{"FROM", "Keyword", "keyword"},
+		{"movies", "Ident", "identifier"},
+		{"-- all", "Comment", "comment"},
+		{"WHERE", "Keyword", "keyword"},
+		{"size", "Ident", "identifier"},
+		{"(", "Operators", "operator"},
+		{"tags", "Ident", "identifier"},
+		{")", "Operators", "operator"},
+		{">=", "Operators", "operator"},
+		{"-1.5", "Number", "number"},
+		{"/* x */", "Comment", "comment"},
+		{";", "Semicolon", "operator"},
+	}, got)
+	require.Equal(t, lexer.Position{Offset: 46, Line: 2, Column: 1}, tokens[10].Pos)
+
+	_, err = Tokenize("SELECT # FROM movies")
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, LexerError, parseErr.Kind)
+}
+
+func TestParseError(t *testing.T) {
+	_, err := Parse("SELECT * FROM movies WHERE\n  title = :t AND begins_with(title)")
+	var perr *ParseError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, ValidationError, perr.Kind)
+	require.Equal(t, 2, perr.Pos.Line)
+	require.Equal(t, 18, perr.Pos.Column)
+	require.Equal(t, "begins_with() expects 2 argument(s) but got 1 in begins_with(title)", perr.Msg)
+
+	_, err = Parse("SELECT * FROM")
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, SyntaxError, perr.Kind)
+	var unexpected participle.UnexpectedTokenError
+	require.True(t, errors.As(err, &unexpected), "the participle error is unwrapped")
+	require.Equal(t, perr.Pos, unexpected.Unexpected.Pos)
+
+	_, err = ParsePath("a.#")
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, LexerError, perr.Kind)
+	require.Equal(t, "1:3: invalid token '#'", err.Error())
+
+	_, err = ParsePath("a[-1]")
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, ValidationError, perr.Kind)
+	require.Equal(t, "list index -1 in a[-1] must be a non-negative integer", perr.Msg)
+}
+
+// clearPositions zeroes the source positions recorded in the AST, so that ASTs parsed from differently formatted
+// queries can be compared.
+func clearPositions(t *testing.T, ast *AST) *AST {
+	t.Helper()
+	err := Visit(ast, func(node Node, next func() error) error {
+		if cond, ok := node.(*Condition); ok {
+			cond.Pos = lexer.Position{}
+		}
+		if limit, ok := node.(*Limit); ok && limit != nil {
+			limit.Pos = lexer.Position{}
+		}
+		return next()
+	})
+	require.NoError(t, err)
+	return ast
+}
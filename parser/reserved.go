@@ -593,5 +593,5 @@ var reservedWordList = []string{
 	"WRAPPED",
 	"WRITE",
 	"YEAR",
-	"ZONE ",
+	"ZONE",
 }
@@ -1,2 +1,70 @@
-SELECT * FROM movies WHERE title = "hello" OR title = "world"
-SELECT title, year FROM movies WHERE title = "The Dark Knight" AND year BETWEEN 2009 AND 2015 OR actor = "Will Smith"
+-- condition functions are validated
+SELECT * FROM movies WHERE title = :title AND begins_with(sk)
+SELECT * FROM movies WHERE title = :title AND attribute_exists(a, b)
+SELECT * FROM movies WHERE title = :title AND contains("foo", sk)
+SELECT * FROM movies WHERE title = :title AND begins_wiht(sk, "a")
+DELETE FROM movies WHERE title = :title AND NOT (a = 1 OR size(a, b))
+SELECT * FROM movies /* unterminated
+-- update functions are validated
+UPDATE movies SET tags = size(tags) WHERE title = :t
+UPDATE movies SET tags = list_append(tags) WHERE title = :t
+UPDATE movies SET views = if_not_exists(0, views) WHERE title = :t
+UPDATE movies SET views = views 2 WHERE title = :t
+UPDATE movies SET views = views + size(views) WHERE title = :t
+-- set literals are validated
+INSERT INTO movies VALUES ({"title": "Heat", "tags": string_set()})
+INSERT INTO movies VALUES ({"title": "Heat", "tags": string_set('crime', 1)})
+INSERT INTO movies VALUES ({"title": "Heat", "ratings": number_set(1, 2, 1)})
+REPLACE INTO movies VALUES ({"title": "Heat", "info": {"posters": binary_set('not base64!')}})
+-- ALTER TABLE needs an action
+ALTER TABLE movies
+ALTER TABLE movies DROP LOCAL SECONDARY INDEX title_year
+-- only size() can be compared, and it must be compared
+SELECT * FROM movies WHERE title = :title AND size(tags)
+SELECT * FROM movies WHERE title = :title AND contains(tags, "a") = TRUE
+SELECT * FROM movies WHERE title = :title AND size(tags) IS NULL
+SELECT * FROM movies WHERE title = :title AND size(3) > 1
+-- attribute_type() type codes are validated
+SELECT * FROM movies WHERE title = :title AND attribute_type(info, 'MAP')
+SELECT * FROM movies WHERE title = :title AND attribute_type(info, s)
+SELECT * FROM movies WHERE title = :title AND attribute_type(info, 1)
+SELECT * FROM movies WHERE title = :title AND attribute_type(info, a.b)
+-- CASE can not have placeholders, or a WHEN without THEN
+SELECT CASE WHEN rating > :min THEN "good" END FROM movies
+SELECT CASE WHEN rating > 8 END FROM movies
+-- Computed columns need an operator between operands
+SELECT rating 10 FROM movies
+SELECT rating * FROM movies
+-- EXPLAIN can not be nested
+EXPLAIN EXPLAIN SELECT * FROM movies
+-- EXPLAIN needs a statement
+EXPLAIN
+-- characters that start no token are lexer errors
+SELECT * FROM movies WHERE title = #
+-- a quote in a string must be escaped or doubled
+SELECT * FROM movies WHERE title = 'O'Brien'
+-- list indexes must be non-negative integers
+SELECT tags[-1] FROM movies WHERE title = :title
+SELECT * FROM movies WHERE title = :title AND info.ratings[1.5] > 5
+-- table names may only have the characters DynamoDB allows
+SELECT * FROM `my movies` WHERE title = :title
+INSERT INTO movies SELECT * FROM `old/movies`
+-- * returns the item in the document column, which no other column may be named
+SELECT *, price AS document FROM movies WHERE title = :title
+SELECT *, document(info) FROM movies WHERE title = :title
+-- * must come first
+SELECT title, * FROM movies WHERE title = :title
+-- only document() can be used as a column
+SELECT foo(a) FROM movies WHERE title = :title
+SELECT size(tags) AS n FROM movies WHERE title = :title
+SELECT *, DOCUMENT(info) AS info FROM movies WHERE title = :title
+SELECT document(info, 'plot') FROM movies WHERE title = :title
+-- LIMIT must be a positive integer
+SELECT * FROM movies WHERE title = :title LIMIT 1.5
+SELECT * FROM movies WHERE title = :title LIMIT 0
+SELECT * FROM movies LIMIT 'ten'
+-- OFFSET must be a non-negative integer, and SEGMENTS a positive one
+SELECT * FROM movies WHERE title = :title LIMIT 10 OFFSET 2.5
+SELECT * FROM movies WHERE title = :title OFFSET -1
+SELECT * FROM movies WITH (SEGMENTS = 1.5)
+SELECT * FROM movies WITH (CONSISTENT, SEGMENTS = 0)
@@ -1,4 +1,5 @@
 {
-  "Query": "SELECT * FROM movies WHERE title = \"hello\" OR title = \"world\"",
-  "Error": "1:44: unexpected token \"OR\""
+  "Query": "SELECT * FROM movies WHERE title = :title AND begins_with(sk)",
+  "Error": "1:47: begins_with() expects 2 argument(s) but got 1 in begins_with(sk)",
+  "Kind": "validation error"
 }
@@ -1,4 +1,5 @@
 {
-  "Query": "SELECT title, year FROM movies WHERE title = \"The Dark Knight\" AND year BETWEEN 2009 AND 2015 OR actor = \"Will Smith\"",
-  "Error": "1:95: unexpected token \"OR\""
+  "Query": "SELECT * FROM movies WHERE title = :title AND attribute_exists(a, b)",
+  "Error": "1:47: attribute_exists() expects 1 argument(s) but got 2 in attribute_exists(a, b)",
+  "Kind": "validation error"
 }
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title AND contains(\"foo\", sk)",
+  "Error": "1:47: first argument to contains() must be a document path, got \"foo\"",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title AND begins_wiht(sk, \"a\")",
+  "Error": "1:47: unknown condition function begins_wiht()",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "DELETE FROM movies WHERE title = :title AND NOT (a = 1 OR size(a, b))",
+  "Error": "1:59: size() expects 1 argument(s) but got 2 in size(a, b)",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies /* unterminated",
+  "Error": "1:22: unexpected token \"/\"",
+  "Kind": "syntax error"
+}
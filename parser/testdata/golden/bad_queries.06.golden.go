@@ -0,0 +1,5 @@
+{
+  "Query": "UPDATE movies SET tags = size(tags) WHERE title = :t",
+  "Error": "unknown update function size(), only if_not_exists() and list_append() can be used in SET",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "UPDATE movies SET tags = list_append(tags) WHERE title = :t",
+  "Error": "list_append() expects 2 argument(s) but got 1 in list_append(tags)",
+  "Kind": "validation error"
+}
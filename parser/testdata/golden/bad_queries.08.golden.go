@@ -0,0 +1,5 @@
+{
+  "Query": "UPDATE movies SET views = if_not_exists(0, views) WHERE title = :t",
+  "Error": "first argument to if_not_exists() must be a document path, got 0",
+  "Kind": "validation error"
+}
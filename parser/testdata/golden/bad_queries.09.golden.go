@@ -0,0 +1,5 @@
+{
+  "Query": "UPDATE movies SET views = views 2 WHERE title = :t",
+  "Error": "SetArithmetic.Signed: expected + or - before 2",
+  "Kind": "syntax error"
+}
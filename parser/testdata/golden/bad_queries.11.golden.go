@@ -0,0 +1,5 @@
+{
+  "Query": "INSERT INTO movies VALUES ({\"title\": \"Heat\", \"tags\": string_set()})",
+  "Error": "string_set() cannot be empty, DynamoDB does not allow empty sets",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "INSERT INTO movies VALUES ({\"title\": \"Heat\", \"tags\": string_set('crime', 1)})",
+  "Error": "string_set() elements must be strings, got 1",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "INSERT INTO movies VALUES ({\"title\": \"Heat\", \"ratings\": number_set(1, 2, 1)})",
+  "Error": "number_set() contains 1 more than once, DynamoDB does not allow duplicates in sets",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "REPLACE INTO movies VALUES ({\"title\": \"Heat\", \"info\": {\"posters\": binary_set('not base64!')}})",
+  "Error": "binary_set() elements must be base64 encoded strings, got \"not base64!\"",
+  "Kind": "validation error"
+}
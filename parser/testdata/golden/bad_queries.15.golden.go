@@ -0,0 +1,5 @@
+{
+  "Query": "ALTER TABLE movies",
+  "Error": "1:19: unexpected token \"<EOF>\" (expected \"ADD\" | \"DROP\" | \"SET\")",
+  "Kind": "syntax error"
+}
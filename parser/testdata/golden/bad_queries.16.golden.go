@@ -0,0 +1,5 @@
+{
+  "Query": "ALTER TABLE movies DROP LOCAL SECONDARY INDEX title_year",
+  "Error": "1:25: unexpected token \"LOCAL\" (expected \"GLOBAL\")",
+  "Kind": "syntax error"
+}
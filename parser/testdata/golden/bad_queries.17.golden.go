@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title AND size(tags)",
+  "Error": "1:47: size() is not a condition, compare it instead, such as: size(tags) > 0",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title AND contains(tags, \"a\") = TRUE",
+  "Error": "1:47: only size() can be compared, contains(tags, \"a\") is a condition by itself",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title AND size(tags) IS NULL",
+  "Error": "1:47: size() can only be compared with operators, BETWEEN or IN, in size(tags)",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title AND size(3) > 1",
+  "Error": "1:47: first argument to size() must be a document path, got 3",
+  "Kind": "validation error"
+}
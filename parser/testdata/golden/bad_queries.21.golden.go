@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title AND attribute_type(info, 'MAP')",
+  "Error": "1:47: unknown attribute type \"MAP\" in attribute_type(info, \"MAP\"), expected one of S, N, B, BOOL, NULL, L, M, SS, NS or BS",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title AND attribute_type(info, s)",
+  "Error": "1:47: unknown attribute type \"s\" in attribute_type(info, s), expected one of S, N, B, BOOL, NULL, L, M, SS, NS or BS",
+  "Kind": "validation error"
+}
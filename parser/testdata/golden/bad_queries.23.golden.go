@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title AND attribute_type(info, 1)",
+  "Error": "1:47: second argument to attribute_type() must be a type code, got 1",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT CASE WHEN rating > :min THEN \"good\" END FROM movies",
+  "Error": "placeholder :min can not be used in CASE, which is evaluated on each item once read",
+  "Kind": "validation error"
+}
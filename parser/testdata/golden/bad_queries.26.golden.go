@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT CASE WHEN rating > 8 END FROM movies",
+  "Error": "1:29: unexpected token \"END\" (expected \"THEN\")",
+  "Kind": "syntax error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT rating 10 FROM movies",
+  "Error": "1:15: unexpected token \"10\" (expected \"FROM\")",
+  "Kind": "syntax error"
+}
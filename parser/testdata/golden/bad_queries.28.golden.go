@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT rating * FROM movies",
+  "Error": "1:15: unexpected token \"*\" (expected \"FROM\")",
+  "Kind": "syntax error"
+}
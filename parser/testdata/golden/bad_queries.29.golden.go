@@ -0,0 +1,5 @@
+{
+  "Query": "EXPLAIN EXPLAIN SELECT * FROM movies",
+  "Error": "EXPLAIN can not explain another EXPLAIN",
+  "Kind": "validation error"
+}
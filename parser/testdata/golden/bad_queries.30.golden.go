@@ -0,0 +1,5 @@
+{
+  "Query": "EXPLAIN",
+  "Error": "1:8: unexpected token \"<EOF>\" (expected \"SELECT\" | \"INSERT\" | \"REPLACE\" | \"UPDATE\" | \"DELETE\" | \"CREATE\" | \"DROP\" | \"ALTER\" | \"DESCRIBE\" | \"DESC\" | \"SHOW\" | \"EXPLAIN\")",
+  "Kind": "syntax error"
+}
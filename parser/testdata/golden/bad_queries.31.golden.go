@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = #",
+  "Error": "1:36: invalid token '#'",
+  "Kind": "lexer error"
+}
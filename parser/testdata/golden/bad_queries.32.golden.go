@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = 'O'Brien'",
+  "Error": "1:44: invalid token '\\''",
+  "Kind": "lexer error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT tags[-1] FROM movies WHERE title = :title",
+  "Error": "list index -1 in tags[-1] must be a non-negative integer",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title AND info.ratings[1.5] > 5",
+  "Error": "list index 1.5 in info.ratings[1.5] must be a non-negative integer",
+  "Kind": "validation error"
+}
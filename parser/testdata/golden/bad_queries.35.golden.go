@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM `my movies` WHERE title = :title",
+  "Error": "invalid table name \"my movies\", DynamoDB table names may only contain letters, digits, _, - and .",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "INSERT INTO movies SELECT * FROM `old/movies`",
+  "Error": "invalid table name \"old/movies\", DynamoDB table names may only contain letters, digits, _, - and .",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT *, price AS document FROM movies WHERE title = :title",
+  "Error": "column price AS document can not be selected with *, which returns each item in the document column",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT *, document(info) FROM movies WHERE title = :title",
+  "Error": "column document(info) can not be selected with *, which returns each item in the document column",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT title, * FROM movies WHERE title = :title",
+  "Error": "1:13: unexpected token \",\" (expected \"FROM\")",
+  "Kind": "syntax error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT foo(a) FROM movies WHERE title = :title",
+  "Error": "unknown function foo() in projection, only document() can be selected, or a CASE or arithmetic on attributes",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT size(tags) AS n FROM movies WHERE title = :title",
+  "Error": "size() can not be used in a projection, DynamoDB can only project attributes, use it in WHERE instead",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT *, DOCUMENT(info) AS info FROM movies WHERE title = :title",
+  "Error": "unknown function DOCUMENT() in projection, did you mean document()?",
+  "Kind": "validation error"
+}
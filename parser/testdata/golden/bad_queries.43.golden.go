@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT document(info, 'plot') FROM movies WHERE title = :title",
+  "Error": "arguments to document() must be document paths, got \"plot\"",
+  "Kind": "validation error"
+}
@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title LIMIT 1.5",
+  "Error": "1:49: LIMIT must be a positive integer, got 1.5",
+  "Kind": "validation error"
+}
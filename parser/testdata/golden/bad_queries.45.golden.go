@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title LIMIT 0",
+  "Error": "1:49: LIMIT must be a positive integer, got 0",
+  "Kind": "validation error"
+}
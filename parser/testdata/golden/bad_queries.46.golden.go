@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies LIMIT 'ten'",
+  "Error": "1:28: LIMIT must be a positive integer, got \"ten\"",
+  "Kind": "validation error"
+}
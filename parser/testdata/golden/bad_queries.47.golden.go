@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title LIMIT 10 OFFSET 2.5",
+  "Error": "OFFSET must be a non-negative integer, got 2.5",
+  "Kind": "validation error"
+}
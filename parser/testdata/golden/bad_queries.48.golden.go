@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WHERE title = :title OFFSET -1",
+  "Error": "OFFSET must be a non-negative integer, got -1",
+  "Kind": "validation error"
+}
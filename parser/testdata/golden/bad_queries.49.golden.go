@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WITH (SEGMENTS = 1.5)",
+  "Error": "SEGMENTS must be a positive integer, got 1.5",
+  "Kind": "validation error"
+}
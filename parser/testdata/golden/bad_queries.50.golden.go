@@ -0,0 +1,5 @@
+{
+  "Query": "SELECT * FROM movies WITH (CONSISTENT, SEGMENTS = 0)",
+  "Error": "SEGMENTS must be a positive integer, got 0",
+  "Kind": "validation error"
+}
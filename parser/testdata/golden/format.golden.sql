@@ -0,0 +1,111 @@
+SELECT * FROM movies
+SELECT title, year FROM movies
+SELECT title, year FROM movies WHERE title = "The Dark Knight"
+SELECT title, year FROM movies WHERE title = "The Dark Knight" AND year >= 2009
+SELECT title, year FROM movies WHERE title = "The Dark Knight" AND year BETWEEN 2009 AND 2015
+SELECT title, year FROM movies WHERE title = "The Dark Knight" AND year BETWEEN 2009 AND 2015 AND actor = "Will Smith"
+SELECT title, year FROM movies WHERE title = "The Dark Knight" AND (year BETWEEN 2009 AND 2015 OR actor = "Will Smith")
+SELECT * FROM movies WHERE title = :title
+SELECT * FROM movies WHERE title = :title AND attribute_exists(year)
+SELECT * FROM movies WHERE title = :title AND begins_with(actor, "Will")
+SELECT UserId, TopScore FROM gamescores WHERE UserId = :UserId
+SELECT Scores[3], Scores[3][2] FROM gamescores WHERE UserId = :UserId
+SELECT Studio.Name, Studio.Name.FirstName, Studio.Employees[3] FROM gamescores WHERE UserId = :UserId
+SELECT UserId, TopScore, Scores[3], Scores[3][2], Studio.Name, Studio.Location.Country, Studio.Employees[3] FROM gamescores WHERE UserId = :UserId
+SELECT document(UserId, TopScore, Scores[3], Scores[3][2], Studio.Name, Studio.Location.Country, Studio.Employees[3]) FROM gamescores WHERE UserId = :UserId
+SELECT UserId, document(TopScore) FROM gamescores WHERE UserId = :UserId
+SELECT `SELECT`.foo FROM gamescores WHERE UserId = :UserId
+SELECT * FROM gamescores WHERE UserId = :UserId
+SELECT * FROM movies USE INDEX (some_index) WHERE UserId = :UserId
+SELECT * FROM movies WHERE UserId = TRUE
+SELECT * FROM movies WHERE UserId = :UserId DESC
+SELECT * FROM `namespaced.movies` WHERE UserId = :UserId
+SELECT * FROM gamescores WHERE UserId = ? AND TopScore > ?
+CREATE TABLE movies (title STRING, year NUMBER)
+CREATE TABLE movies (title STRING HASH KEY, year NUMBER RANGE KEY)
+CREATE TABLE movies (title STRING, year NUMBER, GLOBAL SECONDARY INDEX year_title HASH(year) RANGE(title) PROJECTION ALL PROVISIONED THROUGHPUT READ 1 WRITE 1)
+CREATE TABLE movies (title STRING, year NUMBER, LOCAL SECONDARY INDEX year_index RANGE(year) PROJECTION ALL)
+DELETE FROM movies
+DELETE FROM movies WHERE title = :title AND year = 2009
+DELETE FROM movies WHERE title = ? RETURNING ALL_OLD
+DELETE FROM `namespaced.movies` WHERE title = "Inception" AND attribute_exists(director)
+UPDATE movies SET director = :director WHERE title = :title AND year = :year
+UPDATE movies SET director = "Nolan", info.rating = 9, info.alt = info.rating WHERE title = ? AND year = ? RETURNING ALL_NEW
+UPDATE movies REMOVE info.actors[0], info.plot ADD views :one DELETE tags :old SET director = :d WHERE title = :title
+UPDATE movies ADD views 1, plays :plays REMOVE director
+SELECT * FROM movies WHERE title = :title ORDER BY year DESC LIMIT 5
+SELECT * FROM movies WHERE title = :title ORDER BY year
+SELECT COUNT(*) FROM movies WHERE title = :title
+SELECT COUNT(*) FROM movies
+SELECT * FROM movies WHERE title = :title
+SELECT * FROM movies WHERE title = :title AND info.rating IS NULL AND director IS NOT NULL
+SELECT * FROM movies WHERE title = :title AND info.rating = NULL
+SELECT * FROM movies WHERE title = :title AND director LIKE "Steven%"
+SELECT * FROM movies WHERE title = :title AND year NOT BETWEEN 1990 AND 2000 AND director NOT IN ("a", "b")
+SELECT * FROM movies WHERE title = :title AND (year NOT IN (1, 2) OR NOT year BETWEEN 3 AND 4)
+SELECT * FROM movies WHERE title = :title LIMIT 1 WITH (CONSISTENT)
+SELECT * FROM movies WHERE title = :title LIMIT :n
+SELECT * FROM movies WHERE title = ? LIMIT ?
+SELECT * FROM movies WHERE title = :title LIMIT 10 OFFSET 20
+SELECT info.rating AS rating, year AS `release year`, document(title) AS doc FROM movies WHERE title = :title
+DROP TABLE movies
+DROP TABLE IF EXISTS movies
+CREATE TABLE movies (title STRING HASH KEY, BILLING MODE PAY_PER_REQUEST)
+CREATE TABLE movies (title STRING HASH KEY, year NUMBER, GLOBAL SECONDARY INDEX year_title HASH(year) RANGE(title) PROJECTION ALL, BILLING MODE PAY_PER_REQUEST)
+CREATE TABLE movies (title STRING HASH KEY, BILLING MODE PROVISIONED, PROVISIONED THROUGHPUT READ 1 WRITE 1)
+CREATE TABLE movies (title STRING HASH KEY) TTL (expires_at)
+CREATE TABLE movies (title STRING HASH KEY, BILLING MODE PAY_PER_REQUEST) TTL (`expires at`)
+INSERT INTO movies VALUES (?) IF NOT EXISTS
+INSERT INTO movies VALUES ('{"title": "Rush Hour"}') IF NOT EXISTS RETURNING NONE
+SELECT * FROM movies WHERE year > 2000 WITH (SEGMENTS = 8)
+SELECT * FROM movies LIMIT 10 WITH (CONSISTENT, SEGMENTS = 4)
+UPDATE movies SET tags = list_append(tags, :new), views = if_not_exists(views, 0) WHERE title = :title AND year = :year
+UPDATE movies SET views = views + :n, stock = stock - 1, total = 10 + total, plays = if_not_exists(plays, 0) + ? WHERE title = :title
+UPDATE movies SET stock = stock - 1, views = views + 2 WHERE title = :title
+DESCRIBE movies
+DESCRIBE movies
+SHOW TABLES
+SHOW TABLES LIKE "movie%"
+INSERT INTO movies VALUES ({"title": "Heat", "tags": string_set("crime", "drama"), "ratings": number_set(8.2, 9), "posters": binary_set("aGVhdA==")})
+REPLACE INTO movies VALUES ({"title": "Heat", "info": {"cast": [string_set("De Niro", "Pacino")]}})
+ALTER TABLE movies ADD GLOBAL SECONDARY INDEX director_year HASH(director) RANGE(year) PROJECTION KEYS ONLY PROVISIONED THROUGHPUT READ 5 WRITE 5
+ALTER TABLE movies ADD director STRING, ADD GLOBAL SECONDARY INDEX director_year HASH(director) RANGE(year) PROJECTION INCLUDE rating, plot
+ALTER TABLE movies DROP GLOBAL SECONDARY INDEX director_year
+ALTER TABLE movies SET PROVISIONED THROUGHPUT READ 10 WRITE 20
+SELECT * FROM movies WHERE title = :title OR year > 2000 AND (rating > 8 OR director = :director)
+UPDATE movies SET views = views + 1 WHERE title = :title AND year = :year OR director = :director
+DELETE FROM movies WHERE (title = :t AND year = :y) OR (title = :t2 AND year = :y2)
+SELECT title, year FROM movies WHERE title = "The Dark Knight" AND year BETWEEN 2009 AND 2015 OR actor = "Will Smith"
+SELECT info["release date"], info["cast"][0]["first name"] FROM movies WHERE title = :title AND info["a.b"] > 1
+UPDATE movies SET info["box office"][1] = :gross REMOVE info["plot"] WHERE title = :title
+SELECT * FROM movies WHERE title = :title AND size(tags) > 3 AND NOT (size(info.cast) BETWEEN 1 AND :max OR size(plot) IN (0, 1))
+DELETE FROM movies WHERE title = :title AND size(info["cast"]) <> :n
+SELECT * FROM movies WHERE title = :title AND attribute_type(info, "M") AND NOT attribute_type(tags, "SS") AND attribute_type(plot, "NULL") AND attribute_type(year, :type)
+SELECT * FROM movies WHERE title = :title AND year > 2000 WITH (SCAN, SEGMENTS = 2)
+SELECT title, CASE WHEN rating >= 8 AND size(info.cast) > 2 THEN "classic" WHEN year BETWEEN 1990 AND 1999 THEN year ELSE NULL END AS label FROM movies WHERE title = :title
+SELECT title, info.rating * 10 - 1 AS score, (year - 1900) / 10, 2 FROM movies WHERE title = :title
+EXPLAIN SELECT title FROM movies WHERE title = :title AND year > 2000
+EXPLAIN DELETE FROM movies WHERE title = "Heat"
+INSERT INTO movies VALUES ({"title": :title, "info": {"cast": [?, "Al Pacino"], "rating": :rating}})
+INSERT INTO movies VALUES ({"title": "O'Brien", "plot": 'a "quoted" "word"', "it's": "it's"})
+INSERT INTO archive SELECT * FROM movies WHERE year < 1990
+REPLACE INTO archive SELECT title, info.rating AS rating FROM movies USE INDEX (by_year) WHERE year = :year
+INSERT INTO movies VALUES ({"title": :title, "views": 1}) ON DUPLICATE KEY UPDATE views = views + 1, info.seen = if_not_exists(info.seen, :now)
+SELECT tags[0], info.actors[10] FROM movies WHERE title = :title AND info.ratings[0] > 5
+SELECT * FROM `prod.movies` WHERE title = :title
+CREATE TABLE `prod.movies` (title STRING HASH KEY)
+DROP TABLE `prod.movies`
+DESCRIBE `prod-movies`
+ALTER TABLE `prod.movies` SET PROVISIONED THROUGHPUT READ 5 WRITE 5
+SELECT *, price * qty AS total, document(info.rating) AS rating FROM movies WHERE title = :title
+SELECT *, CASE WHEN year > 2000 THEN "new" ELSE "old" END AS era FROM movies WHERE title = :title
+SELECT `a.b`, info["c.d"], info["e[0]"].f FROM movies WHERE `user.id` = :id AND info["g.h"] > 1
+INSERT INTO movies VALUES ({"title": :title, "views": 1}) ON DUPLICATE KEY UPDATE views = views + 1
+CREATE TABLE t (pk STRING HASH KEY) TTL (ttl)
+SELECT ttl FROM t WHERE pk = 1
+SELECT count FROM t WHERE pk = 1
+SELECT COUNT(*) FROM t WHERE pk = 1 WITH (SCAN)
+SELECT mode, scan, segments, tables, billing, consistent, explain, ttl FROM t WHERE count = 1 AND end > 2
+SELECT CASE WHEN mode = 1 THEN end ELSE scan END AS status FROM tables WITH (CONSISTENT, SEGMENTS = 2)
+DESCRIBE count
+INSERT INTO t VALUES ({"pk": 12345678901234567890, "ratio": 0.1})
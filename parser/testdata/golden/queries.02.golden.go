@@ -25,24 +25,33 @@ parser.row{
         },
       },
       From: "movies",
-      Where: &parser.AndExpression{
-        And: []*parser.Condition{
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
           {
-            Operand: &parser.ConditionOperand{
-              Operand: &parser.DocumentPath{
-                Fragment: []*parser.PathFragment{
-                  {
-                    Symbol: "title",
-                  },
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 37,
+                  Line: 1,
+                  Column: 38,
                 },
-              },
-              ConditionRHS: &parser.ConditionRHS{
-                Compare: &parser.Compare{
-                  Operator: "=",
-                  Operand: &parser.Operand{
-                    Value: &parser.Value{
-                      Scalar: parser.Scalar{
-                        Str: &"The Dark Knight",
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                            Str: &"The Dark Knight",
+                          },
+                        },
                       },
                     },
                   },
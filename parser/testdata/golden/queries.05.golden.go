@@ -25,76 +25,97 @@ parser.row{
         },
       },
       From: "movies",
-      Where: &parser.AndExpression{
-        And: []*parser.Condition{
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
           {
-            Operand: &parser.ConditionOperand{
-              Operand: &parser.DocumentPath{
-                Fragment: []*parser.PathFragment{
-                  {
-                    Symbol: "title",
-                  },
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 37,
+                  Line: 1,
+                  Column: 38,
                 },
-              },
-              ConditionRHS: &parser.ConditionRHS{
-                Compare: &parser.Compare{
-                  Operator: "=",
-                  Operand: &parser.Operand{
-                    Value: &parser.Value{
-                      Scalar: parser.Scalar{
-                        Str: &"The Dark Knight",
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
                       },
                     },
                   },
-                },
-              },
-            },
-          },
-          {
-            Operand: &parser.ConditionOperand{
-              Operand: &parser.DocumentPath{
-                Fragment: []*parser.PathFragment{
-                  {
-                    Symbol: "year",
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                            Str: &"The Dark Knight",
+                          },
+                        },
+                      },
+                    },
                   },
                 },
               },
-              ConditionRHS: &parser.ConditionRHS{
-                Between: &parser.Between{
-                  Start: &parser.Operand{
-                    Value: &parser.Value{
-                      Scalar: parser.Scalar{
-                        Number: &2009,
+              {
+                Pos: lexer.Position{
+                  Offset: 67,
+                  Line: 1,
+                  Column: 68,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "year",
                       },
                     },
                   },
-                  End: &parser.Operand{
-                    Value: &parser.Value{
-                      Scalar: parser.Scalar{
-                        Number: &2015,
+                  ConditionRHS: &parser.ConditionRHS{
+                    Between: &parser.Between{
+                      Start: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                            Number: &2009,
+                            NumberText: &"2009",
+                          },
+                        },
+                      },
+                      End: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                            Number: &2015,
+                            NumberText: &"2015",
+                          },
+                        },
                       },
                     },
                   },
                 },
               },
-            },
-          },
-          {
-            Operand: &parser.ConditionOperand{
-              Operand: &parser.DocumentPath{
-                Fragment: []*parser.PathFragment{
-                  {
-                    Symbol: "actor",
-                  },
+              {
+                Pos: lexer.Position{
+                  Offset: 98,
+                  Line: 1,
+                  Column: 99,
                 },
-              },
-              ConditionRHS: &parser.ConditionRHS{
-                Compare: &parser.Compare{
-                  Operator: "=",
-                  Operand: &parser.Operand{
-                    Value: &parser.Value{
-                      Scalar: parser.Scalar{
-                        Str: &"Will Smith",
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "actor",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                            Str: &"Will Smith",
+                          },
+                        },
                       },
                     },
                   },
@@ -6,40 +6,54 @@ parser.row{
         All: true,
       },
       From: "movies",
-      Where: &parser.AndExpression{
-        And: []*parser.Condition{
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
           {
-            Operand: &parser.ConditionOperand{
-              Operand: &parser.DocumentPath{
-                Fragment: []*parser.PathFragment{
-                  {
-                    Symbol: "title",
-                  },
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 27,
+                  Line: 1,
+                  Column: 28,
                 },
-              },
-              ConditionRHS: &parser.ConditionRHS{
-                Compare: &parser.Compare{
-                  Operator: "=",
-                  Operand: &parser.Operand{
-                    Value: &parser.Value{
-                      Scalar: parser.Scalar{
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
                       },
-                      PlaceHolder: &":title",
                     },
                   },
                 },
               },
-            },
-          },
-          {
-            Function: &parser.FunctionExpression{
-              Function: "attribute_exists",
-              Args: []*parser.FunctionArgument{
-                {
-                  DocumentPath: &parser.DocumentPath{
-                    Fragment: []*parser.PathFragment{
-                      {
-                        Symbol: "year",
+              {
+                Pos: lexer.Position{
+                  Offset: 46,
+                  Line: 1,
+                  Column: 47,
+                },
+                Function: &parser.FunctionExpression{
+                  Function: "attribute_exists",
+                  Args: []*parser.FunctionArgument{
+                    {
+                      DocumentPath: &parser.DocumentPath{
+                        Fragment: []*parser.PathFragment{
+                          {
+                            Symbol: "year",
+                          },
+                        },
                       },
                     },
                   },
@@ -6,48 +6,62 @@ parser.row{
         All: true,
       },
       From: "movies",
-      Where: &parser.AndExpression{
-        And: []*parser.Condition{
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
           {
-            Operand: &parser.ConditionOperand{
-              Operand: &parser.DocumentPath{
-                Fragment: []*parser.PathFragment{
-                  {
-                    Symbol: "title",
-                  },
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 27,
+                  Line: 1,
+                  Column: 28,
                 },
-              },
-              ConditionRHS: &parser.ConditionRHS{
-                Compare: &parser.Compare{
-                  Operator: "=",
-                  Operand: &parser.Operand{
-                    Value: &parser.Value{
-                      Scalar: parser.Scalar{
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
                       },
-                      PlaceHolder: &":title",
                     },
                   },
-                },
-              },
-            },
-          },
-          {
-            Function: &parser.FunctionExpression{
-              Function: "begins_with",
-              Args: []*parser.FunctionArgument{
-                {
-                  DocumentPath: &parser.DocumentPath{
-                    Fragment: []*parser.PathFragment{
-                      {
-                        Symbol: "actor",
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
                       },
                     },
                   },
                 },
-                {
-                  Value: &parser.Value{
-                    Scalar: parser.Scalar{
-                      Str: &"Will",
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 46,
+                  Line: 1,
+                  Column: 47,
+                },
+                Function: &parser.FunctionExpression{
+                  Function: "begins_with",
+                  Args: []*parser.FunctionArgument{
+                    {
+                      DocumentPath: &parser.DocumentPath{
+                        Fragment: []*parser.PathFragment{
+                          {
+                            Symbol: "actor",
+                          },
+                        },
+                      },
+                    },
+                    {
+                      Value: &parser.Value{
+                        Scalar: parser.Scalar{
+                          Str: &"Will",
+                        },
+                      },
                     },
                   },
                 },
@@ -0,0 +1,109 @@
+parser.row{
+  Query: "SELECT document(*), CASE WHEN year > 2000 THEN 'new' ELSE 'old' END AS era FROM movies WHERE title = :title",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        All: true,
+        Columns: []*parser.ProjectionColumn{
+          {
+            Case: &parser.CaseExpression{
+              Whens: []*parser.CaseWhen{
+                {
+                  Condition: &parser.ConditionExpression{
+                    Or: []*parser.AndExpression{
+                      {
+                        And: []*parser.Condition{
+                          {
+                            Pos: lexer.Position{
+                              Offset: 30,
+                              Line: 1,
+                              Column: 31,
+                            },
+                            Operand: &parser.ConditionOperand{
+                              Operand: &parser.DocumentPath{
+                                Fragment: []*parser.PathFragment{
+                                  {
+                                    Symbol: "year",
+                                  },
+                                },
+                              },
+                              ConditionRHS: &parser.ConditionRHS{
+                                Compare: &parser.Compare{
+                                  Operator: ">",
+                                  Operand: &parser.Operand{
+                                    Value: &parser.Value{
+                                      Scalar: parser.Scalar{
+                                        Number: &2000,
+                                        NumberText: &"2000",
+                                      },
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                  Result: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                        Str: &"new",
+                      },
+                    },
+                  },
+                },
+              },
+              Else: &parser.Operand{
+                Value: &parser.Value{
+                  Scalar: parser.Scalar{
+                    Str: &"old",
+                  },
+                },
+              },
+            },
+            Alias: &"era",
+          },
+        },
+      },
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 93,
+                  Line: 1,
+                  Column: 94,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
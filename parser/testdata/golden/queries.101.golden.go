@@ -0,0 +1,122 @@
+parser.row{
+  Query: "SELECT `a.b`, info['c.d'], info['e[0]'].f FROM movies WHERE `user.id` = :id AND info['g.h'] > 1",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        Columns: []*parser.ProjectionColumn{
+          {
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "a.b",
+                },
+              },
+            },
+          },
+          {
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "info",
+                  Accessors: []*parser.PathAccessor{
+                    {
+                      Key: &"c.d",
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "info",
+                  Accessors: []*parser.PathAccessor{
+                    {
+                      Key: &"e[0]",
+                    },
+                  },
+                },
+                {
+                  Symbol: "f",
+                },
+              },
+            },
+          },
+        },
+      },
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 60,
+                  Line: 1,
+                  Column: 61,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "user.id",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":id",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 80,
+                  Line: 1,
+                  Column: 81,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "info",
+                        Accessors: []*parser.PathAccessor{
+                          {
+                            Key: &"g.h",
+                          },
+                        },
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: ">",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                            Number: &1,
+                            NumberText: &"1",
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
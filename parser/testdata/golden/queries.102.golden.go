@@ -0,0 +1,76 @@
+parser.row{
+  Query: "insert into movies values ({\"title\": :title, \"views\": 1}) on duplicate key update views = views + 1",
+  AST: &parser.AST{
+    Insert: &parser.Insert{
+      Into: "movies",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+          },
+          Object: &parser.JSONObject{
+            Entries: []*parser.JSONObjectEntry{
+              {
+                Key: "title",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                    },
+                    PlaceHolder: &":title",
+                  },
+                },
+              },
+              {
+                Key: "views",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                      Number: &1,
+                      NumberText: &"1",
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      OnDuplicate: []*parser.SetExpression{
+        {
+          Path: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "views",
+              },
+            },
+          },
+          SetOperand: parser.SetOperand{
+            Value: &parser.Operand{
+              SymbolRef: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "views",
+                  },
+                },
+              },
+            },
+          },
+          Arithmetic: &parser.SetArithmetic{
+            Operator: "+",
+            Operand: &parser.SetOperand{
+              Value: &parser.Operand{
+                Value: &parser.Value{
+                  Scalar: parser.Scalar{
+                    Number: &1,
+                    NumberText: &"1",
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
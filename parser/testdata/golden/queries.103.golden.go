@@ -0,0 +1,18 @@
+parser.row{
+  Query: "CREATE TABLE t (pk STRING HASH KEY) TTL (ttl)",
+  AST: &parser.AST{
+    CreateTable: &parser.CreateTable{
+      Table: "t",
+      Entries: []*parser.CreateTableEntry{
+        {
+          Attr: &parser.TableAttr{
+            Name: "pk",
+            Type: "STRING",
+            Key: "HASH",
+          },
+        },
+      },
+      TTL: &"ttl",
+    },
+  },
+}
\ No newline at end of file
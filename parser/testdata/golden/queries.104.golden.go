@@ -0,0 +1,58 @@
+parser.row{
+  Query: "SELECT ttl FROM t WHERE pk = 1",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        Columns: []*parser.ProjectionColumn{
+          {
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "ttl",
+                },
+              },
+            },
+          },
+        },
+      },
+      From: "t",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 24,
+                  Line: 1,
+                  Column: 25,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "pk",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                            Number: &1,
+                            NumberText: &"1",
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
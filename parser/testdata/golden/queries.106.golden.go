@@ -0,0 +1,53 @@
+parser.row{
+  Query: "select count(*) from t where pk = 1 with (scan)",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        Count: true,
+      },
+      From: "t",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 29,
+                  Line: 1,
+                  Column: 30,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "pk",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                            Number: &1,
+                            NumberText: &"1",
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Hints: []*parser.SelectHint{
+        {
+          Scan: true,
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
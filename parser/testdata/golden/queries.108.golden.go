@@ -0,0 +1,84 @@
+parser.row{
+  Query: "SELECT CASE WHEN mode = 1 THEN end ELSE scan END AS status FROM tables WITH (CONSISTENT, SEGMENTS = 2)",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        Columns: []*parser.ProjectionColumn{
+          {
+            Case: &parser.CaseExpression{
+              Whens: []*parser.CaseWhen{
+                {
+                  Condition: &parser.ConditionExpression{
+                    Or: []*parser.AndExpression{
+                      {
+                        And: []*parser.Condition{
+                          {
+                            Pos: lexer.Position{
+                              Offset: 17,
+                              Line: 1,
+                              Column: 18,
+                            },
+                            Operand: &parser.ConditionOperand{
+                              Operand: &parser.DocumentPath{
+                                Fragment: []*parser.PathFragment{
+                                  {
+                                    Symbol: "mode",
+                                  },
+                                },
+                              },
+                              ConditionRHS: &parser.ConditionRHS{
+                                Compare: &parser.Compare{
+                                  Operator: "=",
+                                  Operand: &parser.Operand{
+                                    Value: &parser.Value{
+                                      Scalar: parser.Scalar{
+                                        Number: &1,
+                                        NumberText: &"1",
+                                      },
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                  Result: &parser.Operand{
+                    SymbolRef: &parser.DocumentPath{
+                      Fragment: []*parser.PathFragment{
+                        {
+                          Symbol: "end",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              Else: &parser.Operand{
+                SymbolRef: &parser.DocumentPath{
+                  Fragment: []*parser.PathFragment{
+                    {
+                      Symbol: "scan",
+                    },
+                  },
+                },
+              },
+            },
+            Alias: &"status",
+          },
+        },
+      },
+      From: "tables",
+      Hints: []*parser.SelectHint{
+        {
+          Consistent: true,
+        },
+        {
+          Segments: &2,
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,8 @@
+parser.row{
+  Query: "DESCRIBE count",
+  AST: &parser.AST{
+    Describe: &parser.Describe{
+      Table: "count",
+    },
+  },
+}
\ No newline at end of file
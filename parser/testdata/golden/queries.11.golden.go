@@ -9,8 +9,10 @@ parser.row{
               Fragment: []*parser.PathFragment{
                 {
                   Symbol: "Scores",
-                  Indexes: []int{
-                    3,
+                  Accessors: []*parser.PathAccessor{
+                    {
+                      Index: &3,
+                    },
                   },
                 },
               },
@@ -21,9 +23,13 @@ parser.row{
               Fragment: []*parser.PathFragment{
                 {
                   Symbol: "Scores",
-                  Indexes: []int{
-                    3,
-                    2,
+                  Accessors: []*parser.PathAccessor{
+                    {
+                      Index: &3,
+                    },
+                    {
+                      Index: &2,
+                    },
                   },
                 },
               },
@@ -32,25 +38,34 @@ parser.row{
         },
       },
       From: "gamescores",
-      Where: &parser.AndExpression{
-        And: []*parser.Condition{
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
           {
-            Operand: &parser.ConditionOperand{
-              Operand: &parser.DocumentPath{
-                Fragment: []*parser.PathFragment{
-                  {
-                    Symbol: "UserId",
-                  },
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 53,
+                  Line: 1,
+                  Column: 54,
                 },
-              },
-              ConditionRHS: &parser.ConditionRHS{
-                Compare: &parser.Compare{
-                  Operator: "=",
-                  Operand: &parser.Operand{
-                    Value: &parser.Value{
-                      Scalar: parser.Scalar{
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "UserId",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":UserId",
+                        },
                       },
-                      PlaceHolder: &":UserId",
                     },
                   },
                 },
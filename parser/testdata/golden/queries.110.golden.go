@@ -0,0 +1,42 @@
+parser.row{
+  Query: "INSERT INTO t VALUES ({\"pk\": 12345678901234567890, \"ratio\": 0.1})",
+  AST: &parser.AST{
+    Insert: &parser.Insert{
+      Into: "t",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+          },
+          Object: &parser.JSONObject{
+            Entries: []*parser.JSONObjectEntry{
+              {
+                Key: "pk",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                      Number: &1.2345678901234567e+19,
+                      NumberText: &"12345678901234567890",
+                    },
+                  },
+                },
+              },
+              {
+                Key: "ratio",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                      Number: &0.1,
+                      NumberText: &"0.1",
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
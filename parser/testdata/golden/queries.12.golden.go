@@ -39,8 +39,10 @@ parser.row{
                 },
                 {
                   Symbol: "Employees",
-                  Indexes: []int{
-                    3,
+                  Accessors: []*parser.PathAccessor{
+                    {
+                      Index: &3,
+                    },
                   },
                 },
               },
@@ -49,25 +51,34 @@ parser.row{
         },
       },
       From: "gamescores",
-      Where: &parser.AndExpression{
-        And: []*parser.Condition{
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
           {
-            Operand: &parser.ConditionOperand{
-              Operand: &parser.DocumentPath{
-                Fragment: []*parser.PathFragment{
-                  {
-                    Symbol: "UserId",
-                  },
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 85,
+                  Line: 1,
+                  Column: 86,
                 },
-              },
-              ConditionRHS: &parser.ConditionRHS{
-                Compare: &parser.Compare{
-                  Operator: "=",
-                  Operand: &parser.Operand{
-                    Value: &parser.Value{
-                      Scalar: parser.Scalar{
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "UserId",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":UserId",
+                        },
                       },
-                      PlaceHolder: &":UserId",
                     },
                   },
                 },
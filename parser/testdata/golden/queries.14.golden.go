@@ -31,8 +31,10 @@ parser.row{
                     Fragment: []*parser.PathFragment{
                       {
                         Symbol: "Scores",
-                        Indexes: []int{
-                          3,
+                        Accessors: []*parser.PathAccessor{
+                          {
+                            Index: &3,
+                          },
                         },
                       },
                     },
@@ -43,9 +45,13 @@ parser.row{
                     Fragment: []*parser.PathFragment{
                       {
                         Symbol: "Scores",
-                        Indexes: []int{
-                          3,
-                          2,
+                        Accessors: []*parser.PathAccessor{
+                          {
+                            Index: &3,
+                          },
+                          {
+                            Index: &2,
+                          },
                         },
                       },
                     },
@@ -86,8 +92,10 @@ parser.row{
                       },
                       {
                         Symbol: "Employees",
-                        Indexes: []int{
-                          3,
+                        Accessors: []*parser.PathAccessor{
+                          {
+                            Index: &3,
+                          },
                         },
                       },
                     },
@@ -99,25 +107,34 @@ parser.row{
         },
       },
       From: "gamescores",
-      Where: &parser.AndExpression{
-        And: []*parser.Condition{
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
           {
-            Operand: &parser.ConditionOperand{
-              Operand: &parser.DocumentPath{
-                Fragment: []*parser.PathFragment{
-                  {
-                    Symbol: "UserId",
-                  },
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 140,
+                  Line: 1,
+                  Column: 141,
                 },
-              },
-              ConditionRHS: &parser.ConditionRHS{
-                Compare: &parser.Compare{
-                  Operator: "=",
-                  Operand: &parser.Operand{
-                    Value: &parser.Value{
-                      Scalar: parser.Scalar{
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "UserId",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":UserId",
+                        },
                       },
-                      PlaceHolder: &":UserId",
                     },
                   },
                 },
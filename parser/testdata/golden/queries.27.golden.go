@@ -0,0 +1,8 @@
+parser.row{
+  Query: "DELETE FROM movies",
+  AST: &parser.AST{
+    Delete: &parser.Delete{
+      From: "movies",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,65 @@
+parser.row{
+  Query: "DELETE FROM namespaced.movies WHERE title = \"Inception\" AND attribute_exists(director);",
+  AST: &parser.AST{
+    Delete: &parser.Delete{
+      From: "namespaced.movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 36,
+                  Line: 1,
+                  Column: 37,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                            Str: &"Inception",
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 60,
+                  Line: 1,
+                  Column: 61,
+                },
+                Function: &parser.FunctionExpression{
+                  Function: "attribute_exists",
+                  Args: []*parser.FunctionArgument{
+                    {
+                      DocumentPath: &parser.DocumentPath{
+                        Fragment: []*parser.PathFragment{
+                          {
+                            Symbol: "director",
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
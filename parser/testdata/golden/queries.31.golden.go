@@ -0,0 +1,96 @@
+parser.row{
+  Query: "UPDATE movies SET director = :director WHERE title = :title AND year = :year",
+  AST: &parser.AST{
+    Update: &parser.Update{
+      Table: "movies",
+      Actions: []*parser.UpdateAction{
+        {
+          Set: []*parser.SetExpression{
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "director",
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Value: &parser.Operand{
+                  Value: &parser.Value{
+                    Scalar: parser.Scalar{
+                    },
+                    PlaceHolder: &":director",
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 45,
+                  Line: 1,
+                  Column: 46,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 64,
+                  Line: 1,
+                  Column: 65,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "year",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":year",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,145 @@
+parser.row{
+  Query: "UPDATE movies SET director = \"Nolan\", info.rating = 9, info.alt = info.rating WHERE title = ? AND year = ? RETURNING ALL_NEW",
+  AST: &parser.AST{
+    Update: &parser.Update{
+      Table: "movies",
+      Actions: []*parser.UpdateAction{
+        {
+          Set: []*parser.SetExpression{
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "director",
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Value: &parser.Operand{
+                  Value: &parser.Value{
+                    Scalar: parser.Scalar{
+                      Str: &"Nolan",
+                    },
+                  },
+                },
+              },
+            },
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "info",
+                  },
+                  {
+                    Symbol: "rating",
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Value: &parser.Operand{
+                  Value: &parser.Value{
+                    Scalar: parser.Scalar{
+                      Number: &9,
+                      NumberText: &"9",
+                    },
+                  },
+                },
+              },
+            },
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "info",
+                  },
+                  {
+                    Symbol: "alt",
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Value: &parser.Operand{
+                  SymbolRef: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "info",
+                      },
+                      {
+                        Symbol: "rating",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 84,
+                  Line: 1,
+                  Column: 85,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PositionalPlaceholder: true,
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 98,
+                  Line: 1,
+                  Column: 99,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "year",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PositionalPlaceholder: true,
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Returning: &"ALL_NEW",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,133 @@
+parser.row{
+  Query: "UPDATE movies REMOVE info.actors[0], info.plot ADD views :one DELETE tags :old SET director = :d WHERE title = :title",
+  AST: &parser.AST{
+    Update: &parser.Update{
+      Table: "movies",
+      Actions: []*parser.UpdateAction{
+        {
+          Remove: []*parser.DocumentPath{
+            {
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "info",
+                },
+                {
+                  Symbol: "actors",
+                  Accessors: []*parser.PathAccessor{
+                    {
+                      Index: &0,
+                    },
+                  },
+                },
+              },
+            },
+            {
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "info",
+                },
+                {
+                  Symbol: "plot",
+                },
+              },
+            },
+          },
+        },
+        {
+          Add: []*parser.AddExpression{
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "views",
+                  },
+                },
+              },
+              Value: &parser.Value{
+                Scalar: parser.Scalar{
+                },
+                PlaceHolder: &":one",
+              },
+            },
+          },
+        },
+        {
+          Delete: []*parser.DeleteExpression{
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "tags",
+                  },
+                },
+              },
+              Value: &parser.Value{
+                Scalar: parser.Scalar{
+                },
+                PlaceHolder: &":old",
+              },
+            },
+          },
+        },
+        {
+          Set: []*parser.SetExpression{
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "director",
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Value: &parser.Operand{
+                  Value: &parser.Value{
+                    Scalar: parser.Scalar{
+                    },
+                    PlaceHolder: &":d",
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 103,
+                  Line: 1,
+                  Column: 104,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
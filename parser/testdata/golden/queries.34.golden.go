@@ -0,0 +1,54 @@
+parser.row{
+  Query: "UPDATE movies ADD views 1, plays :plays REMOVE director",
+  AST: &parser.AST{
+    Update: &parser.Update{
+      Table: "movies",
+      Actions: []*parser.UpdateAction{
+        {
+          Add: []*parser.AddExpression{
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "views",
+                  },
+                },
+              },
+              Value: &parser.Value{
+                Scalar: parser.Scalar{
+                  Number: &1,
+                  NumberText: &"1",
+                },
+              },
+            },
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "plays",
+                  },
+                },
+              },
+              Value: &parser.Value{
+                Scalar: parser.Scalar{
+                },
+                PlaceHolder: &":plays",
+              },
+            },
+          },
+        },
+        {
+          Remove: []*parser.DocumentPath{
+            {
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "director",
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,70 @@
+parser.row{
+  Query: "SELECT * FROM movies WHERE title = :title ORDER BY year DESC LIMIT 5",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        All: true,
+      },
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 27,
+                  Line: 1,
+                  Column: 28,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      OrderBy: &parser.OrderBy{
+        Path: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "year",
+            },
+          },
+        },
+        Descending: &parser.ScanDescending(true),
+      },
+      Limit: &parser.Limit{
+        Pos: lexer.Position{
+          Offset: 67,
+          Line: 1,
+          Column: 68,
+        },
+        Value: &parser.Value{
+          Scalar: parser.Scalar{
+            Number: &5,
+            NumberText: &"5",
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
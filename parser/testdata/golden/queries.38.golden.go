@@ -0,0 +1,11 @@
+parser.row{
+  Query: "select count(*) from movies",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        Count: true,
+      },
+      From: "movies",
+    },
+  },
+}
\ No newline at end of file
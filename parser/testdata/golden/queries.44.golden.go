@@ -0,0 +1,147 @@
+parser.row{
+  Query: "SELECT * FROM movies WHERE title = :title AND (year NOT IN (1, 2) OR NOT year BETWEEN 3 AND 4)",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        All: true,
+      },
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 27,
+                  Line: 1,
+                  Column: 28,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 46,
+                  Line: 1,
+                  Column: 47,
+                },
+                Parenthesized: &parser.ParenthesizedExpression{
+                  ConditionExpression: &parser.ConditionExpression{
+                    Or: []*parser.AndExpression{
+                      {
+                        And: []*parser.Condition{
+                          {
+                            Pos: lexer.Position{
+                              Offset: 47,
+                              Line: 1,
+                              Column: 48,
+                            },
+                            Operand: &parser.ConditionOperand{
+                              Operand: &parser.DocumentPath{
+                                Fragment: []*parser.PathFragment{
+                                  {
+                                    Symbol: "year",
+                                  },
+                                },
+                              },
+                              ConditionRHS: &parser.ConditionRHS{
+                                NotIn: &parser.In{
+                                  Values: []*parser.Value{
+                                    {
+                                      Scalar: parser.Scalar{
+                                        Number: &1,
+                                        NumberText: &"1",
+                                      },
+                                    },
+                                    {
+                                      Scalar: parser.Scalar{
+                                        Number: &2,
+                                        NumberText: &"2",
+                                      },
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                      {
+                        And: []*parser.Condition{
+                          {
+                            Pos: lexer.Position{
+                              Offset: 69,
+                              Line: 1,
+                              Column: 70,
+                            },
+                            Not: &parser.NotCondition{
+                              Condition: &parser.Condition{
+                                Pos: lexer.Position{
+                                  Offset: 73,
+                                  Line: 1,
+                                  Column: 74,
+                                },
+                                Operand: &parser.ConditionOperand{
+                                  Operand: &parser.DocumentPath{
+                                    Fragment: []*parser.PathFragment{
+                                      {
+                                        Symbol: "year",
+                                      },
+                                    },
+                                  },
+                                  ConditionRHS: &parser.ConditionRHS{
+                                    Between: &parser.Between{
+                                      Start: &parser.Operand{
+                                        Value: &parser.Value{
+                                          Scalar: parser.Scalar{
+                                            Number: &3,
+                                            NumberText: &"3",
+                                          },
+                                        },
+                                      },
+                                      End: &parser.Operand{
+                                        Value: &parser.Value{
+                                          Scalar: parser.Scalar{
+                                            Number: &4,
+                                            NumberText: &"4",
+                                          },
+                                        },
+                                      },
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,61 @@
+parser.row{
+  Query: "SELECT * FROM movies WHERE title = :title LIMIT 10 OFFSET 20",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        All: true,
+      },
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 27,
+                  Line: 1,
+                  Column: 28,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Limit: &parser.Limit{
+        Pos: lexer.Position{
+          Offset: 48,
+          Line: 1,
+          Column: 49,
+        },
+        Value: &parser.Value{
+          Scalar: parser.Scalar{
+            Number: &10,
+            NumberText: &"10",
+          },
+        },
+      },
+      Offset: &20,
+    },
+  },
+}
\ No newline at end of file
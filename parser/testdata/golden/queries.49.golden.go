@@ -0,0 +1,88 @@
+parser.row{
+  Query: "SELECT info.rating AS rating, year AS `release year`, document(title) AS doc FROM movies WHERE title = :title",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        Columns: []*parser.ProjectionColumn{
+          {
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "info",
+                },
+                {
+                  Symbol: "rating",
+                },
+              },
+            },
+            Alias: &"rating",
+          },
+          {
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "year",
+                },
+              },
+            },
+            Alias: &"release year",
+          },
+          {
+            Function: &parser.FunctionExpression{
+              Function: "document",
+              Args: []*parser.FunctionArgument{
+                {
+                  DocumentPath: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+            Alias: &"doc",
+          },
+        },
+      },
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 95,
+                  Line: 1,
+                  Column: 96,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,8 @@
+parser.row{
+  Query: "DROP TABLE movies",
+  AST: &parser.AST{
+    DropTable: &parser.DropTable{
+      Table: "movies",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,9 @@
+parser.row{
+  Query: "DROP TABLE IF EXISTS `movies`;",
+  AST: &parser.AST{
+    DropTable: &parser.DropTable{
+      IfExists: true,
+      Table: "movies",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,22 @@
+parser.row{
+  Query: "CREATE TABLE movies (title STRING HASH KEY, BILLING MODE PAY_PER_REQUEST);",
+  AST: &parser.AST{
+    CreateTable: &parser.CreateTable{
+      Table: "movies",
+      Entries: []*parser.CreateTableEntry{
+        {
+          Attr: &parser.TableAttr{
+            Name: "title",
+            Type: "STRING",
+            Key: "HASH",
+          },
+        },
+        {
+          BillingMode: &parser.BillingMode{
+            PayPerRequest: true,
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,38 @@
+parser.row{
+  Query: "CREATE TABLE movies (title STRING HASH KEY, year NUMBER, GLOBAL SECONDARY INDEX year_title HASH(year) RANGE(title) PROJECTION ALL, BILLING MODE PAY_PER_REQUEST);",
+  AST: &parser.AST{
+    CreateTable: &parser.CreateTable{
+      Table: "movies",
+      Entries: []*parser.CreateTableEntry{
+        {
+          Attr: &parser.TableAttr{
+            Name: "title",
+            Type: "STRING",
+            Key: "HASH",
+          },
+        },
+        {
+          Attr: &parser.TableAttr{
+            Name: "year",
+            Type: "NUMBER",
+          },
+        },
+        {
+          GlobalSecondaryIndex: &parser.GlobalSecondaryIndex{
+            Name: "year_title",
+            PartitionKey: "year",
+            SortKey: "title",
+            Projection: &parser.Projection{
+              All: true,
+            },
+          },
+        },
+        {
+          BillingMode: &parser.BillingMode{
+            PayPerRequest: true,
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,18 @@
+parser.row{
+  Query: "CREATE TABLE movies (title STRING HASH KEY) TTL (expires_at);",
+  AST: &parser.AST{
+    CreateTable: &parser.CreateTable{
+      Table: "movies",
+      Entries: []*parser.CreateTableEntry{
+        {
+          Attr: &parser.TableAttr{
+            Name: "title",
+            Type: "STRING",
+            Key: "HASH",
+          },
+        },
+      },
+      TTL: &"expires_at",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,23 @@
+parser.row{
+  Query: "CREATE TABLE movies (title STRING HASH KEY, BILLING MODE PAY_PER_REQUEST) TTL (`expires at`)",
+  AST: &parser.AST{
+    CreateTable: &parser.CreateTable{
+      Table: "movies",
+      Entries: []*parser.CreateTableEntry{
+        {
+          Attr: &parser.TableAttr{
+            Name: "title",
+            Type: "STRING",
+            Key: "HASH",
+          },
+        },
+        {
+          BillingMode: &parser.BillingMode{
+            PayPerRequest: true,
+          },
+        },
+      },
+      TTL: &"expires at",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,18 @@
+parser.row{
+  Query: "INSERT INTO movies VALUES (?) IF NOT EXISTS",
+  AST: &parser.AST{
+    Insert: &parser.Insert{
+      Into: "movies",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+            PositionalPlaceholder: true,
+          },
+        },
+      },
+      IfNotExists: true,
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,19 @@
+parser.row{
+  Query: "INSERT INTO movies VALUES ('{\"title\": \"Rush Hour\"}') IF NOT EXISTS RETURNING NONE;",
+  AST: &parser.AST{
+    Insert: &parser.Insert{
+      Into: "movies",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+              Str: &"{\"title\": \"Rush Hour\"}",
+            },
+          },
+        },
+      },
+      IfNotExists: true,
+      Returning: &"NONE",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,32 @@
+parser.row{
+  Query: "SELECT * FROM movies LIMIT 10 WITH (CONSISTENT, SEGMENTS = 4)",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        All: true,
+      },
+      From: "movies",
+      Limit: &parser.Limit{
+        Pos: lexer.Position{
+          Offset: 27,
+          Line: 1,
+          Column: 28,
+        },
+        Value: &parser.Value{
+          Scalar: parser.Scalar{
+            Number: &10,
+            NumberText: &"10",
+          },
+        },
+      },
+      Hints: []*parser.SelectHint{
+        {
+          Consistent: true,
+        },
+        {
+          Segments: &4,
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
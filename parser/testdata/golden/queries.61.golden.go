@@ -0,0 +1,143 @@
+parser.row{
+  Query: "UPDATE movies SET tags = list_append(tags, :new), views = if_not_exists(views, 0) WHERE title = :title AND year = :year",
+  AST: &parser.AST{
+    Update: &parser.Update{
+      Table: "movies",
+      Actions: []*parser.UpdateAction{
+        {
+          Set: []*parser.SetExpression{
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "tags",
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Function: &parser.FunctionExpression{
+                  Function: "list_append",
+                  Args: []*parser.FunctionArgument{
+                    {
+                      DocumentPath: &parser.DocumentPath{
+                        Fragment: []*parser.PathFragment{
+                          {
+                            Symbol: "tags",
+                          },
+                        },
+                      },
+                    },
+                    {
+                      Value: &parser.Value{
+                        Scalar: parser.Scalar{
+                        },
+                        PlaceHolder: &":new",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "views",
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Function: &parser.FunctionExpression{
+                  Function: "if_not_exists",
+                  Args: []*parser.FunctionArgument{
+                    {
+                      DocumentPath: &parser.DocumentPath{
+                        Fragment: []*parser.PathFragment{
+                          {
+                            Symbol: "views",
+                          },
+                        },
+                      },
+                    },
+                    {
+                      Value: &parser.Value{
+                        Scalar: parser.Scalar{
+                          Number: &0,
+                          NumberText: &"0",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 88,
+                  Line: 1,
+                  Column: 89,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 107,
+                  Line: 1,
+                  Column: 108,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "year",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":year",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
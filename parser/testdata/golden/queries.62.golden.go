@@ -0,0 +1,193 @@
+parser.row{
+  Query: "UPDATE movies SET views = views + :n, stock = stock - 1, total = 10 + total, plays = if_not_exists(plays, 0) + ? WHERE title = :title",
+  AST: &parser.AST{
+    Update: &parser.Update{
+      Table: "movies",
+      Actions: []*parser.UpdateAction{
+        {
+          Set: []*parser.SetExpression{
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "views",
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Value: &parser.Operand{
+                  SymbolRef: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "views",
+                      },
+                    },
+                  },
+                },
+              },
+              Arithmetic: &parser.SetArithmetic{
+                Operator: "+",
+                Operand: &parser.SetOperand{
+                  Value: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PlaceHolder: &":n",
+                    },
+                  },
+                },
+              },
+            },
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "stock",
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Value: &parser.Operand{
+                  SymbolRef: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "stock",
+                      },
+                    },
+                  },
+                },
+              },
+              Arithmetic: &parser.SetArithmetic{
+                Operator: "-",
+                Operand: &parser.SetOperand{
+                  Value: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                        Number: &1,
+                        NumberText: &"1",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "total",
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Value: &parser.Operand{
+                  Value: &parser.Value{
+                    Scalar: parser.Scalar{
+                      Number: &10,
+                      NumberText: &"10",
+                    },
+                  },
+                },
+              },
+              Arithmetic: &parser.SetArithmetic{
+                Operator: "+",
+                Operand: &parser.SetOperand{
+                  Value: &parser.Operand{
+                    SymbolRef: &parser.DocumentPath{
+                      Fragment: []*parser.PathFragment{
+                        {
+                          Symbol: "total",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "plays",
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Function: &parser.FunctionExpression{
+                  Function: "if_not_exists",
+                  Args: []*parser.FunctionArgument{
+                    {
+                      DocumentPath: &parser.DocumentPath{
+                        Fragment: []*parser.PathFragment{
+                          {
+                            Symbol: "plays",
+                          },
+                        },
+                      },
+                    },
+                    {
+                      Value: &parser.Value{
+                        Scalar: parser.Scalar{
+                          Number: &0,
+                          NumberText: &"0",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              Arithmetic: &parser.SetArithmetic{
+                Operator: "+",
+                Operand: &parser.SetOperand{
+                  Value: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PositionalPlaceholder: true,
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 119,
+                  Line: 1,
+                  Column: 120,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
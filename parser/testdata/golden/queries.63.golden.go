@@ -0,0 +1,116 @@
+parser.row{
+  Query: "UPDATE movies SET stock = stock-1, views = views +2 WHERE title = :title",
+  AST: &parser.AST{
+    Update: &parser.Update{
+      Table: "movies",
+      Actions: []*parser.UpdateAction{
+        {
+          Set: []*parser.SetExpression{
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "stock",
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Value: &parser.Operand{
+                  SymbolRef: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "stock",
+                      },
+                    },
+                  },
+                },
+              },
+              Arithmetic: &parser.SetArithmetic{
+                Operator: "-",
+                Operand: &parser.SetOperand{
+                  Value: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                        Number: &1,
+                        NumberText: &"1",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "views",
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Value: &parser.Operand{
+                  SymbolRef: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "views",
+                      },
+                    },
+                  },
+                },
+              },
+              Arithmetic: &parser.SetArithmetic{
+                Operator: "+",
+                Operand: &parser.SetOperand{
+                  Value: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                        Number: &2,
+                        NumberText: &"2",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 58,
+                  Line: 1,
+                  Column: 59,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
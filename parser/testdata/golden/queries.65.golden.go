@@ -0,0 +1,8 @@
+parser.row{
+  Query: "desc `movies`;",
+  AST: &parser.AST{
+    Describe: &parser.Describe{
+      Table: "movies",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,7 @@
+parser.row{
+  Query: "SHOW TABLES",
+  AST: &parser.AST{
+    ShowTables: &parser.ShowTables{
+    },
+  },
+}
\ No newline at end of file
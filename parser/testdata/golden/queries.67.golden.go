@@ -0,0 +1,8 @@
+parser.row{
+  Query: "show tables like 'movie%';",
+  AST: &parser.AST{
+    ShowTables: &parser.ShowTables{
+      Like: &"movie%",
+    },
+  },
+}
\ No newline at end of file
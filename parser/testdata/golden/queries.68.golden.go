@@ -0,0 +1,89 @@
+parser.row{
+  Query: "INSERT INTO movies VALUES ({\"title\": \"Heat\", \"tags\": string_set('crime', \"drama\"), \"ratings\": number_set(8.2, 9), \"posters\": binary_set('aGVhdA==')})",
+  AST: &parser.AST{
+    Insert: &parser.Insert{
+      Into: "movies",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+          },
+          Object: &parser.JSONObject{
+            Entries: []*parser.JSONObjectEntry{
+              {
+                Key: "title",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                      Str: &"Heat",
+                    },
+                  },
+                },
+              },
+              {
+                Key: "tags",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                    },
+                  },
+                  Set: &parser.SetLiteral{
+                    Type: "string_set",
+                    Values: []*parser.Scalar{
+                      {
+                        Str: &"crime",
+                      },
+                      {
+                        Str: &"drama",
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Key: "ratings",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                    },
+                  },
+                  Set: &parser.SetLiteral{
+                    Type: "number_set",
+                    Values: []*parser.Scalar{
+                      {
+                        Number: &8.2,
+                        NumberText: &"8.2",
+                      },
+                      {
+                        Number: &9,
+                        NumberText: &"9",
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Key: "posters",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                    },
+                  },
+                  Set: &parser.SetLiteral{
+                    Type: "binary_set",
+                    Values: []*parser.Scalar{
+                      {
+                        Str: &"aGVhdA==",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
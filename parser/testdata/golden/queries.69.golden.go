@@ -0,0 +1,73 @@
+parser.row{
+  Query: "REPLACE INTO movies VALUES ({\"title\": \"Heat\", \"info\": {\"cast\": [string_set(\"De Niro\", \"Pacino\")]}})",
+  AST: &parser.AST{
+    Replace: &parser.Insert{
+      Into: "movies",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+          },
+          Object: &parser.JSONObject{
+            Entries: []*parser.JSONObjectEntry{
+              {
+                Key: "title",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                      Str: &"Heat",
+                    },
+                  },
+                },
+              },
+              {
+                Key: "info",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                    },
+                  },
+                  Object: &parser.JSONObject{
+                    Entries: []*parser.JSONObjectEntry{
+                      {
+                        Key: "cast",
+                        Value: &parser.JSONValue{
+                          Value: parser.Value{
+                            Scalar: parser.Scalar{
+                            },
+                          },
+                          Array: &parser.JSONArray{
+                            Entries: []*parser.JSONValue{
+                              {
+                                Value: parser.Value{
+                                  Scalar: parser.Scalar{
+                                  },
+                                },
+                                Set: &parser.SetLiteral{
+                                  Type: "string_set",
+                                  Values: []*parser.Scalar{
+                                    {
+                                      Str: &"De Niro",
+                                    },
+                                    {
+                                      Str: &"Pacino",
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
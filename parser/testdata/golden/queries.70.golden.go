@@ -0,0 +1,24 @@
+parser.row{
+  Query: "ALTER TABLE movies ADD GLOBAL SECONDARY INDEX director_year HASH(director) RANGE(year) PROJECTION KEYS ONLY PROVISIONED THROUGHPUT READ 5 WRITE 5",
+  AST: &parser.AST{
+    AlterTable: &parser.AlterTable{
+      Table: "movies",
+      Actions: []*parser.AlterTableAction{
+        {
+          AddIndex: &parser.GlobalSecondaryIndex{
+            Name: "director_year",
+            PartitionKey: "director",
+            SortKey: "year",
+            Projection: &parser.Projection{
+              KeysOnly: true,
+            },
+            ProvisionedThroughput: &parser.ProvisionedThroughput{
+              ReadCapacityUnits: 5,
+              WriteCapacityUnits: 5,
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
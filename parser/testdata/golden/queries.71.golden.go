@@ -0,0 +1,29 @@
+parser.row{
+  Query: "ALTER TABLE movies ADD director STRING, ADD GLOBAL SECONDARY INDEX director_year HASH(director) RANGE(year) PROJECTION INCLUDE rating, plot;",
+  AST: &parser.AST{
+    AlterTable: &parser.AlterTable{
+      Table: "movies",
+      Actions: []*parser.AlterTableAction{
+        {
+          AddAttr: &parser.TableAttr{
+            Name: "director",
+            Type: "STRING",
+          },
+        },
+        {
+          AddIndex: &parser.GlobalSecondaryIndex{
+            Name: "director_year",
+            PartitionKey: "director",
+            SortKey: "year",
+            Projection: &parser.Projection{
+              Include: []string{
+                "rating",
+                "plot",
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,13 @@
+parser.row{
+  Query: "ALTER TABLE `movies` DROP GLOBAL SECONDARY INDEX director_year",
+  AST: &parser.AST{
+    AlterTable: &parser.AlterTable{
+      Table: "movies",
+      Actions: []*parser.AlterTableAction{
+        {
+          DropIndex: &"director_year",
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,16 @@
+parser.row{
+  Query: "alter table movies set provisioned throughput read 10 write 20",
+  AST: &parser.AST{
+    AlterTable: &parser.AlterTable{
+      Table: "movies",
+      Actions: []*parser.AlterTableAction{
+        {
+          SetThroughput: &parser.ProvisionedThroughput{
+            ReadCapacityUnits: 10,
+            WriteCapacityUnits: 20,
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
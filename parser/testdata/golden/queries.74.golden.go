@@ -0,0 +1,158 @@
+parser.row{
+  Query: "SELECT * FROM movies WHERE title = :title OR year > 2000 AND (rating > 8 OR director = :director)",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        All: true,
+      },
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 27,
+                  Line: 1,
+                  Column: 28,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 45,
+                  Line: 1,
+                  Column: 46,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "year",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: ">",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                            Number: &2000,
+                            NumberText: &"2000",
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 61,
+                  Line: 1,
+                  Column: 62,
+                },
+                Parenthesized: &parser.ParenthesizedExpression{
+                  ConditionExpression: &parser.ConditionExpression{
+                    Or: []*parser.AndExpression{
+                      {
+                        And: []*parser.Condition{
+                          {
+                            Pos: lexer.Position{
+                              Offset: 62,
+                              Line: 1,
+                              Column: 63,
+                            },
+                            Operand: &parser.ConditionOperand{
+                              Operand: &parser.DocumentPath{
+                                Fragment: []*parser.PathFragment{
+                                  {
+                                    Symbol: "rating",
+                                  },
+                                },
+                              },
+                              ConditionRHS: &parser.ConditionRHS{
+                                Compare: &parser.Compare{
+                                  Operator: ">",
+                                  Operand: &parser.Operand{
+                                    Value: &parser.Value{
+                                      Scalar: parser.Scalar{
+                                        Number: &8,
+                                        NumberText: &"8",
+                                      },
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                      {
+                        And: []*parser.Condition{
+                          {
+                            Pos: lexer.Position{
+                              Offset: 76,
+                              Line: 1,
+                              Column: 77,
+                            },
+                            Operand: &parser.ConditionOperand{
+                              Operand: &parser.DocumentPath{
+                                Fragment: []*parser.PathFragment{
+                                  {
+                                    Symbol: "director",
+                                  },
+                                },
+                              },
+                              ConditionRHS: &parser.ConditionRHS{
+                                Compare: &parser.Compare{
+                                  Operator: "=",
+                                  Operand: &parser.Operand{
+                                    Value: &parser.Value{
+                                      Scalar: parser.Scalar{
+                                      },
+                                      PlaceHolder: &":director",
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,166 @@
+parser.row{
+  Query: "DELETE FROM movies WHERE (title = :t AND year = :y) OR (title = :t2 AND year = :y2);",
+  AST: &parser.AST{
+    Delete: &parser.Delete{
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 25,
+                  Line: 1,
+                  Column: 26,
+                },
+                Parenthesized: &parser.ParenthesizedExpression{
+                  ConditionExpression: &parser.ConditionExpression{
+                    Or: []*parser.AndExpression{
+                      {
+                        And: []*parser.Condition{
+                          {
+                            Pos: lexer.Position{
+                              Offset: 26,
+                              Line: 1,
+                              Column: 27,
+                            },
+                            Operand: &parser.ConditionOperand{
+                              Operand: &parser.DocumentPath{
+                                Fragment: []*parser.PathFragment{
+                                  {
+                                    Symbol: "title",
+                                  },
+                                },
+                              },
+                              ConditionRHS: &parser.ConditionRHS{
+                                Compare: &parser.Compare{
+                                  Operator: "=",
+                                  Operand: &parser.Operand{
+                                    Value: &parser.Value{
+                                      Scalar: parser.Scalar{
+                                      },
+                                      PlaceHolder: &":t",
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                          {
+                            Pos: lexer.Position{
+                              Offset: 41,
+                              Line: 1,
+                              Column: 42,
+                            },
+                            Operand: &parser.ConditionOperand{
+                              Operand: &parser.DocumentPath{
+                                Fragment: []*parser.PathFragment{
+                                  {
+                                    Symbol: "year",
+                                  },
+                                },
+                              },
+                              ConditionRHS: &parser.ConditionRHS{
+                                Compare: &parser.Compare{
+                                  Operator: "=",
+                                  Operand: &parser.Operand{
+                                    Value: &parser.Value{
+                                      Scalar: parser.Scalar{
+                                      },
+                                      PlaceHolder: &":y",
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 55,
+                  Line: 1,
+                  Column: 56,
+                },
+                Parenthesized: &parser.ParenthesizedExpression{
+                  ConditionExpression: &parser.ConditionExpression{
+                    Or: []*parser.AndExpression{
+                      {
+                        And: []*parser.Condition{
+                          {
+                            Pos: lexer.Position{
+                              Offset: 56,
+                              Line: 1,
+                              Column: 57,
+                            },
+                            Operand: &parser.ConditionOperand{
+                              Operand: &parser.DocumentPath{
+                                Fragment: []*parser.PathFragment{
+                                  {
+                                    Symbol: "title",
+                                  },
+                                },
+                              },
+                              ConditionRHS: &parser.ConditionRHS{
+                                Compare: &parser.Compare{
+                                  Operator: "=",
+                                  Operand: &parser.Operand{
+                                    Value: &parser.Value{
+                                      Scalar: parser.Scalar{
+                                      },
+                                      PlaceHolder: &":t2",
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                          {
+                            Pos: lexer.Position{
+                              Offset: 72,
+                              Line: 1,
+                              Column: 73,
+                            },
+                            Operand: &parser.ConditionOperand{
+                              Operand: &parser.DocumentPath{
+                                Fragment: []*parser.PathFragment{
+                                  {
+                                    Symbol: "year",
+                                  },
+                                },
+                              },
+                              ConditionRHS: &parser.ConditionRHS{
+                                Compare: &parser.Compare{
+                                  Operator: "=",
+                                  Operand: &parser.Operand{
+                                    Value: &parser.Value{
+                                      Scalar: parser.Scalar{
+                                      },
+                                      PlaceHolder: &":y2",
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
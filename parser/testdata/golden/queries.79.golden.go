@@ -0,0 +1,92 @@
+parser.row{
+  Query: "UPDATE movies SET info['box office'][1] = :gross REMOVE info[\"plot\"] WHERE title = :title",
+  AST: &parser.AST{
+    Update: &parser.Update{
+      Table: "movies",
+      Actions: []*parser.UpdateAction{
+        {
+          Set: []*parser.SetExpression{
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "info",
+                    Accessors: []*parser.PathAccessor{
+                      {
+                        Key: &"box office",
+                      },
+                      {
+                        Index: &1,
+                      },
+                    },
+                  },
+                },
+              },
+              SetOperand: parser.SetOperand{
+                Value: &parser.Operand{
+                  Value: &parser.Value{
+                    Scalar: parser.Scalar{
+                    },
+                    PlaceHolder: &":gross",
+                  },
+                },
+              },
+            },
+          },
+        },
+        {
+          Remove: []*parser.DocumentPath{
+            {
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "info",
+                  Accessors: []*parser.PathAccessor{
+                    {
+                      Key: &"plot",
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 75,
+                  Line: 1,
+                  Column: 76,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
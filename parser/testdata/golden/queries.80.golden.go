@@ -0,0 +1,199 @@
+parser.row{
+  Query: "SELECT * FROM movies WHERE title = :title AND size(tags) > 3 AND NOT (size(info.cast) BETWEEN 1 AND :max OR size(`plot`) IN (0, 1))",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        All: true,
+      },
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 27,
+                  Line: 1,
+                  Column: 28,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 46,
+                  Line: 1,
+                  Column: 47,
+                },
+                Operand: &parser.ConditionOperand{
+                  Size: &parser.FunctionExpression{
+                    Function: "size",
+                    Args: []*parser.FunctionArgument{
+                      {
+                        DocumentPath: &parser.DocumentPath{
+                          Fragment: []*parser.PathFragment{
+                            {
+                              Symbol: "tags",
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: ">",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                            Number: &3,
+                            NumberText: &"3",
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 65,
+                  Line: 1,
+                  Column: 66,
+                },
+                Not: &parser.NotCondition{
+                  Condition: &parser.Condition{
+                    Pos: lexer.Position{
+                      Offset: 69,
+                      Line: 1,
+                      Column: 70,
+                    },
+                    Parenthesized: &parser.ParenthesizedExpression{
+                      ConditionExpression: &parser.ConditionExpression{
+                        Or: []*parser.AndExpression{
+                          {
+                            And: []*parser.Condition{
+                              {
+                                Pos: lexer.Position{
+                                  Offset: 70,
+                                  Line: 1,
+                                  Column: 71,
+                                },
+                                Operand: &parser.ConditionOperand{
+                                  Size: &parser.FunctionExpression{
+                                    Function: "size",
+                                    Args: []*parser.FunctionArgument{
+                                      {
+                                        DocumentPath: &parser.DocumentPath{
+                                          Fragment: []*parser.PathFragment{
+                                            {
+                                              Symbol: "info",
+                                            },
+                                            {
+                                              Symbol: "cast",
+                                            },
+                                          },
+                                        },
+                                      },
+                                    },
+                                  },
+                                  ConditionRHS: &parser.ConditionRHS{
+                                    Between: &parser.Between{
+                                      Start: &parser.Operand{
+                                        Value: &parser.Value{
+                                          Scalar: parser.Scalar{
+                                            Number: &1,
+                                            NumberText: &"1",
+                                          },
+                                        },
+                                      },
+                                      End: &parser.Operand{
+                                        Value: &parser.Value{
+                                          Scalar: parser.Scalar{
+                                          },
+                                          PlaceHolder: &":max",
+                                        },
+                                      },
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                          {
+                            And: []*parser.Condition{
+                              {
+                                Pos: lexer.Position{
+                                  Offset: 108,
+                                  Line: 1,
+                                  Column: 109,
+                                },
+                                Operand: &parser.ConditionOperand{
+                                  Size: &parser.FunctionExpression{
+                                    Function: "size",
+                                    Args: []*parser.FunctionArgument{
+                                      {
+                                        DocumentPath: &parser.DocumentPath{
+                                          Fragment: []*parser.PathFragment{
+                                            {
+                                              Symbol: "plot",
+                                            },
+                                          },
+                                        },
+                                      },
+                                    },
+                                  },
+                                  ConditionRHS: &parser.ConditionRHS{
+                                    In: &parser.In{
+                                      Values: []*parser.Value{
+                                        {
+                                          Scalar: parser.Scalar{
+                                            Number: &0,
+                                            NumberText: &"0",
+                                          },
+                                        },
+                                        {
+                                          Scalar: parser.Scalar{
+                                            Number: &1,
+                                            NumberText: &"1",
+                                          },
+                                        },
+                                      },
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,84 @@
+parser.row{
+  Query: "DELETE FROM movies WHERE title = :title AND size(info['cast']) <> :n",
+  AST: &parser.AST{
+    Delete: &parser.Delete{
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 25,
+                  Line: 1,
+                  Column: 26,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 44,
+                  Line: 1,
+                  Column: 45,
+                },
+                Operand: &parser.ConditionOperand{
+                  Size: &parser.FunctionExpression{
+                    Function: "size",
+                    Args: []*parser.FunctionArgument{
+                      {
+                        DocumentPath: &parser.DocumentPath{
+                          Fragment: []*parser.PathFragment{
+                            {
+                              Symbol: "info",
+                              Accessors: []*parser.PathAccessor{
+                                {
+                                  Key: &"cast",
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "<>",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":n",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
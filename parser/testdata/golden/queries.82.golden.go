@@ -0,0 +1,168 @@
+parser.row{
+  Query: "SELECT * FROM movies WHERE title = :title AND attribute_type(info, 'M') AND NOT attribute_type(tags, SS) AND attribute_type(plot, NULL) AND attribute_type(year, :type)",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        All: true,
+      },
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 27,
+                  Line: 1,
+                  Column: 28,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 46,
+                  Line: 1,
+                  Column: 47,
+                },
+                Function: &parser.FunctionExpression{
+                  Function: "attribute_type",
+                  Args: []*parser.FunctionArgument{
+                    {
+                      DocumentPath: &parser.DocumentPath{
+                        Fragment: []*parser.PathFragment{
+                          {
+                            Symbol: "info",
+                          },
+                        },
+                      },
+                    },
+                    {
+                      Value: &parser.Value{
+                        Scalar: parser.Scalar{
+                          Str: &"M",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 76,
+                  Line: 1,
+                  Column: 77,
+                },
+                Not: &parser.NotCondition{
+                  Condition: &parser.Condition{
+                    Pos: lexer.Position{
+                      Offset: 80,
+                      Line: 1,
+                      Column: 81,
+                    },
+                    Function: &parser.FunctionExpression{
+                      Function: "attribute_type",
+                      Args: []*parser.FunctionArgument{
+                        {
+                          DocumentPath: &parser.DocumentPath{
+                            Fragment: []*parser.PathFragment{
+                              {
+                                Symbol: "tags",
+                              },
+                            },
+                          },
+                        },
+                        {
+                          Value: &parser.Value{
+                            Scalar: parser.Scalar{
+                              Str: &"SS",
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 109,
+                  Line: 1,
+                  Column: 110,
+                },
+                Function: &parser.FunctionExpression{
+                  Function: "attribute_type",
+                  Args: []*parser.FunctionArgument{
+                    {
+                      DocumentPath: &parser.DocumentPath{
+                        Fragment: []*parser.PathFragment{
+                          {
+                            Symbol: "plot",
+                          },
+                        },
+                      },
+                    },
+                    {
+                      Value: &parser.Value{
+                        Scalar: parser.Scalar{
+                          Str: &"NULL",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Pos: lexer.Position{
+                  Offset: 140,
+                  Line: 1,
+                  Column: 141,
+                },
+                Function: &parser.FunctionExpression{
+                  Function: "attribute_type",
+                  Args: []*parser.FunctionArgument{
+                    {
+                      DocumentPath: &parser.DocumentPath{
+                        Fragment: []*parser.PathFragment{
+                          {
+                            Symbol: "year",
+                          },
+                        },
+                      },
+                    },
+                    {
+                      Value: &parser.Value{
+                        Scalar: parser.Scalar{
+                        },
+                        PlaceHolder: &":type",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
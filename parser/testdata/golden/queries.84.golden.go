@@ -0,0 +1,211 @@
+parser.row{
+  Query: "SELECT title, CASE WHEN rating >= 8 AND size(info.cast) > 2 THEN \"classic\" WHEN `year` BETWEEN 1990 AND 1999 THEN `year` ELSE NULL END AS label FROM movies WHERE title = :title",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        Columns: []*parser.ProjectionColumn{
+          {
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "title",
+                },
+              },
+            },
+          },
+          {
+            Case: &parser.CaseExpression{
+              Whens: []*parser.CaseWhen{
+                {
+                  Condition: &parser.ConditionExpression{
+                    Or: []*parser.AndExpression{
+                      {
+                        And: []*parser.Condition{
+                          {
+                            Pos: lexer.Position{
+                              Offset: 24,
+                              Line: 1,
+                              Column: 25,
+                            },
+                            Operand: &parser.ConditionOperand{
+                              Operand: &parser.DocumentPath{
+                                Fragment: []*parser.PathFragment{
+                                  {
+                                    Symbol: "rating",
+                                  },
+                                },
+                              },
+                              ConditionRHS: &parser.ConditionRHS{
+                                Compare: &parser.Compare{
+                                  Operator: ">=",
+                                  Operand: &parser.Operand{
+                                    Value: &parser.Value{
+                                      Scalar: parser.Scalar{
+                                        Number: &8,
+                                        NumberText: &"8",
+                                      },
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                          {
+                            Pos: lexer.Position{
+                              Offset: 40,
+                              Line: 1,
+                              Column: 41,
+                            },
+                            Operand: &parser.ConditionOperand{
+                              Size: &parser.FunctionExpression{
+                                Function: "size",
+                                Args: []*parser.FunctionArgument{
+                                  {
+                                    DocumentPath: &parser.DocumentPath{
+                                      Fragment: []*parser.PathFragment{
+                                        {
+                                          Symbol: "info",
+                                        },
+                                        {
+                                          Symbol: "cast",
+                                        },
+                                      },
+                                    },
+                                  },
+                                },
+                              },
+                              ConditionRHS: &parser.ConditionRHS{
+                                Compare: &parser.Compare{
+                                  Operator: ">",
+                                  Operand: &parser.Operand{
+                                    Value: &parser.Value{
+                                      Scalar: parser.Scalar{
+                                        Number: &2,
+                                        NumberText: &"2",
+                                      },
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                  Result: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                        Str: &"classic",
+                      },
+                    },
+                  },
+                },
+                {
+                  Condition: &parser.ConditionExpression{
+                    Or: []*parser.AndExpression{
+                      {
+                        And: []*parser.Condition{
+                          {
+                            Pos: lexer.Position{
+                              Offset: 80,
+                              Line: 1,
+                              Column: 81,
+                            },
+                            Operand: &parser.ConditionOperand{
+                              Operand: &parser.DocumentPath{
+                                Fragment: []*parser.PathFragment{
+                                  {
+                                    Symbol: "year",
+                                  },
+                                },
+                              },
+                              ConditionRHS: &parser.ConditionRHS{
+                                Between: &parser.Between{
+                                  Start: &parser.Operand{
+                                    Value: &parser.Value{
+                                      Scalar: parser.Scalar{
+                                        Number: &1990,
+                                        NumberText: &"1990",
+                                      },
+                                    },
+                                  },
+                                  End: &parser.Operand{
+                                    Value: &parser.Value{
+                                      Scalar: parser.Scalar{
+                                        Number: &1999,
+                                        NumberText: &"1999",
+                                      },
+                                    },
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                  Result: &parser.Operand{
+                    SymbolRef: &parser.DocumentPath{
+                      Fragment: []*parser.PathFragment{
+                        {
+                          Symbol: "year",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              Else: &parser.Operand{
+                Value: &parser.Value{
+                  Scalar: parser.Scalar{
+                    Null: true,
+                  },
+                },
+              },
+            },
+            Alias: &"label",
+          },
+        },
+      },
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 162,
+                  Line: 1,
+                  Column: 163,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,133 @@
+parser.row{
+  Query: "SELECT title, info.rating * 10 - 1 AS score, (year-1900) / 10, 2 FROM movies WHERE title = :title",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        Columns: []*parser.ProjectionColumn{
+          {
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "title",
+                },
+              },
+            },
+          },
+          {
+            Arithmetic: &parser.Arithmetic{
+              Operand: &parser.ArithmeticOperand{
+                DocumentPath: &parser.DocumentPath{
+                  Fragment: []*parser.PathFragment{
+                    {
+                      Symbol: "info",
+                    },
+                    {
+                      Symbol: "rating",
+                    },
+                  },
+                },
+              },
+              Ops: []*parser.ArithmeticOp{
+                {
+                  Operator: "*",
+                  Operand: &parser.ArithmeticOperand{
+                    Number: &10,
+                    NumberText: &"10",
+                  },
+                },
+                {
+                  Operator: "-",
+                  Operand: &parser.ArithmeticOperand{
+                    Number: &1,
+                    NumberText: &"1",
+                  },
+                },
+              },
+            },
+            Alias: &"score",
+          },
+          {
+            Arithmetic: &parser.Arithmetic{
+              Operand: &parser.ArithmeticOperand{
+                Parenthesized: &parser.Arithmetic{
+                  Operand: &parser.ArithmeticOperand{
+                    DocumentPath: &parser.DocumentPath{
+                      Fragment: []*parser.PathFragment{
+                        {
+                          Symbol: "year",
+                        },
+                      },
+                    },
+                  },
+                  Ops: []*parser.ArithmeticOp{
+                    {
+                      Operator: "-",
+                      Operand: &parser.ArithmeticOperand{
+                        Number: &1900,
+                        NumberText: &"1900",
+                      },
+                    },
+                  },
+                },
+              },
+              Ops: []*parser.ArithmeticOp{
+                {
+                  Operator: "/",
+                  Operand: &parser.ArithmeticOperand{
+                    Number: &10,
+                    NumberText: &"10",
+                  },
+                },
+              },
+            },
+          },
+          {
+            Arithmetic: &parser.Arithmetic{
+              Operand: &parser.ArithmeticOperand{
+                Number: &2,
+                NumberText: &"2",
+              },
+            },
+          },
+        },
+      },
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 83,
+                  Line: 1,
+                  Column: 84,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
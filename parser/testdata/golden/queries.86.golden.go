@@ -0,0 +1,90 @@
+parser.row{
+  Query: "EXPLAIN SELECT title FROM movies WHERE title = :title AND year > 2000;",
+  AST: &parser.AST{
+    Explain: &parser.Explain{
+      Statement: &parser.AST{
+        Select: &parser.Select{
+          Projection: &parser.ProjectionExpression{
+            Columns: []*parser.ProjectionColumn{
+              {
+                DocumentPath: &parser.DocumentPath{
+                  Fragment: []*parser.PathFragment{
+                    {
+                      Symbol: "title",
+                    },
+                  },
+                },
+              },
+            },
+          },
+          From: "movies",
+          Where: &parser.ConditionExpression{
+            Or: []*parser.AndExpression{
+              {
+                And: []*parser.Condition{
+                  {
+                    Pos: lexer.Position{
+                      Offset: 39,
+                      Line: 1,
+                      Column: 40,
+                    },
+                    Operand: &parser.ConditionOperand{
+                      Operand: &parser.DocumentPath{
+                        Fragment: []*parser.PathFragment{
+                          {
+                            Symbol: "title",
+                          },
+                        },
+                      },
+                      ConditionRHS: &parser.ConditionRHS{
+                        Compare: &parser.Compare{
+                          Operator: "=",
+                          Operand: &parser.Operand{
+                            Value: &parser.Value{
+                              Scalar: parser.Scalar{
+                              },
+                              PlaceHolder: &":title",
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                  {
+                    Pos: lexer.Position{
+                      Offset: 58,
+                      Line: 1,
+                      Column: 59,
+                    },
+                    Operand: &parser.ConditionOperand{
+                      Operand: &parser.DocumentPath{
+                        Fragment: []*parser.PathFragment{
+                          {
+                            Symbol: "year",
+                          },
+                        },
+                      },
+                      ConditionRHS: &parser.ConditionRHS{
+                        Compare: &parser.Compare{
+                          Operator: ">",
+                          Operand: &parser.Operand{
+                            Value: &parser.Value{
+                              Scalar: parser.Scalar{
+                                Number: &2000,
+                                NumberText: &"2000",
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
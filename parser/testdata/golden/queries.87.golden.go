@@ -0,0 +1,48 @@
+parser.row{
+  Query: "explain DELETE FROM movies WHERE title = \"Heat\"",
+  AST: &parser.AST{
+    Explain: &parser.Explain{
+      Statement: &parser.AST{
+        Delete: &parser.Delete{
+          From: "movies",
+          Where: &parser.ConditionExpression{
+            Or: []*parser.AndExpression{
+              {
+                And: []*parser.Condition{
+                  {
+                    Pos: lexer.Position{
+                      Offset: 33,
+                      Line: 1,
+                      Column: 34,
+                    },
+                    Operand: &parser.ConditionOperand{
+                      Operand: &parser.DocumentPath{
+                        Fragment: []*parser.PathFragment{
+                          {
+                            Symbol: "title",
+                          },
+                        },
+                      },
+                      ConditionRHS: &parser.ConditionRHS{
+                        Compare: &parser.Compare{
+                          Operator: "=",
+                          Operand: &parser.Operand{
+                            Value: &parser.Value{
+                              Scalar: parser.Scalar{
+                                Str: &"Heat",
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
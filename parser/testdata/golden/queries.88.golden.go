@@ -0,0 +1,80 @@
+parser.row{
+  Query: "INSERT INTO movies VALUES ({\"title\": :title, \"info\": {\"cast\": [?, \"Al Pacino\"], \"rating\": :rating}})",
+  AST: &parser.AST{
+    Insert: &parser.Insert{
+      Into: "movies",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+          },
+          Object: &parser.JSONObject{
+            Entries: []*parser.JSONObjectEntry{
+              {
+                Key: "title",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                    },
+                    PlaceHolder: &":title",
+                  },
+                },
+              },
+              {
+                Key: "info",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                    },
+                  },
+                  Object: &parser.JSONObject{
+                    Entries: []*parser.JSONObjectEntry{
+                      {
+                        Key: "cast",
+                        Value: &parser.JSONValue{
+                          Value: parser.Value{
+                            Scalar: parser.Scalar{
+                            },
+                          },
+                          Array: &parser.JSONArray{
+                            Entries: []*parser.JSONValue{
+                              {
+                                Value: parser.Value{
+                                  Scalar: parser.Scalar{
+                                  },
+                                  PositionalPlaceholder: true,
+                                },
+                              },
+                              {
+                                Value: parser.Value{
+                                  Scalar: parser.Scalar{
+                                    Str: &"Al Pacino",
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                      {
+                        Key: "rating",
+                        Value: &parser.JSONValue{
+                          Value: parser.Value{
+                            Scalar: parser.Scalar{
+                            },
+                            PlaceHolder: &":rating",
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,50 @@
+parser.row{
+  Query: "INSERT INTO movies VALUES ({\"title\": 'O''Brien', \"plot\": \"a \"\"quoted\"\" \\\"word\\\"\", 'it''s': 'it\\'s'})",
+  AST: &parser.AST{
+    Insert: &parser.Insert{
+      Into: "movies",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+          },
+          Object: &parser.JSONObject{
+            Entries: []*parser.JSONObjectEntry{
+              {
+                Key: "title",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                      Str: &"O'Brien",
+                    },
+                  },
+                },
+              },
+              {
+                Key: "plot",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                      Str: &"a \"quoted\" \"word\"",
+                    },
+                  },
+                },
+              },
+              {
+                Key: "it's",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                      Str: &"it's",
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,51 @@
+parser.row{
+  Query: "INSERT INTO archive SELECT * FROM movies WHERE year < 1990",
+  AST: &parser.AST{
+    Insert: &parser.Insert{
+      Into: "archive",
+      Select: &parser.Select{
+        Projection: &parser.ProjectionExpression{
+          All: true,
+        },
+        From: "movies",
+        Where: &parser.ConditionExpression{
+          Or: []*parser.AndExpression{
+            {
+              And: []*parser.Condition{
+                {
+                  Pos: lexer.Position{
+                    Offset: 47,
+                    Line: 1,
+                    Column: 48,
+                  },
+                  Operand: &parser.ConditionOperand{
+                    Operand: &parser.DocumentPath{
+                      Fragment: []*parser.PathFragment{
+                        {
+                          Symbol: "year",
+                        },
+                      },
+                    },
+                    ConditionRHS: &parser.ConditionRHS{
+                      Compare: &parser.Compare{
+                        Operator: "<",
+                        Operand: &parser.Operand{
+                          Value: &parser.Value{
+                            Scalar: parser.Scalar{
+                              Number: &1990,
+                              NumberText: &"1990",
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,74 @@
+parser.row{
+  Query: "REPLACE INTO archive SELECT title, info.rating AS rating FROM movies USE INDEX (by_year) WHERE year = :year",
+  AST: &parser.AST{
+    Replace: &parser.Insert{
+      Into: "archive",
+      Select: &parser.Select{
+        Projection: &parser.ProjectionExpression{
+          Columns: []*parser.ProjectionColumn{
+            {
+              DocumentPath: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "title",
+                  },
+                },
+              },
+            },
+            {
+              DocumentPath: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "info",
+                  },
+                  {
+                    Symbol: "rating",
+                  },
+                },
+              },
+              Alias: &"rating",
+            },
+          },
+        },
+        From: "movies",
+        Index: &"by_year",
+        Where: &parser.ConditionExpression{
+          Or: []*parser.AndExpression{
+            {
+              And: []*parser.Condition{
+                {
+                  Pos: lexer.Position{
+                    Offset: 95,
+                    Line: 1,
+                    Column: 96,
+                  },
+                  Operand: &parser.ConditionOperand{
+                    Operand: &parser.DocumentPath{
+                      Fragment: []*parser.PathFragment{
+                        {
+                          Symbol: "year",
+                        },
+                      },
+                    },
+                    ConditionRHS: &parser.ConditionRHS{
+                      Compare: &parser.Compare{
+                        Operator: "=",
+                        Operand: &parser.Operand{
+                          Value: &parser.Value{
+                            Scalar: parser.Scalar{
+                            },
+                            PlaceHolder: &":year",
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
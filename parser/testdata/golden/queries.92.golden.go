@@ -0,0 +1,114 @@
+parser.row{
+  Query: "INSERT INTO movies VALUES ({\"title\": :title, \"views\": 1}) ON DUPLICATE KEY UPDATE views = views + 1, info.seen = if_not_exists(info.seen, :now)",
+  AST: &parser.AST{
+    Insert: &parser.Insert{
+      Into: "movies",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+          },
+          Object: &parser.JSONObject{
+            Entries: []*parser.JSONObjectEntry{
+              {
+                Key: "title",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                    },
+                    PlaceHolder: &":title",
+                  },
+                },
+              },
+              {
+                Key: "views",
+                Value: &parser.JSONValue{
+                  Value: parser.Value{
+                    Scalar: parser.Scalar{
+                      Number: &1,
+                      NumberText: &"1",
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      OnDuplicate: []*parser.SetExpression{
+        {
+          Path: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "views",
+              },
+            },
+          },
+          SetOperand: parser.SetOperand{
+            Value: &parser.Operand{
+              SymbolRef: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "views",
+                  },
+                },
+              },
+            },
+          },
+          Arithmetic: &parser.SetArithmetic{
+            Operator: "+",
+            Operand: &parser.SetOperand{
+              Value: &parser.Operand{
+                Value: &parser.Value{
+                  Scalar: parser.Scalar{
+                    Number: &1,
+                    NumberText: &"1",
+                  },
+                },
+              },
+            },
+          },
+        },
+        {
+          Path: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "info",
+              },
+              {
+                Symbol: "seen",
+              },
+            },
+          },
+          SetOperand: parser.SetOperand{
+            Function: &parser.FunctionExpression{
+              Function: "if_not_exists",
+              Args: []*parser.FunctionArgument{
+                {
+                  DocumentPath: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "info",
+                      },
+                      {
+                        Symbol: "seen",
+                      },
+                    },
+                  },
+                },
+                {
+                  Value: &parser.Value{
+                    Scalar: parser.Scalar{
+                    },
+                    PlaceHolder: &":now",
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
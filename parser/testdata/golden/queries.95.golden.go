@@ -0,0 +1,17 @@
+parser.row{
+  Query: "CREATE TABLE prod.movies (title STRING HASH KEY)",
+  AST: &parser.AST{
+    CreateTable: &parser.CreateTable{
+      Table: "prod.movies",
+      Entries: []*parser.CreateTableEntry{
+        {
+          Attr: &parser.TableAttr{
+            Name: "title",
+            Type: "STRING",
+            Key: "HASH",
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,8 @@
+parser.row{
+  Query: "DROP TABLE prod.movies",
+  AST: &parser.AST{
+    DropTable: &parser.DropTable{
+      Table: "prod.movies",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,8 @@
+parser.row{
+  Query: "DESCRIBE `prod-movies`",
+  AST: &parser.AST{
+    Describe: &parser.Describe{
+      Table: "prod-movies",
+    },
+  },
+}
\ No newline at end of file
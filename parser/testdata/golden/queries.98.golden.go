@@ -0,0 +1,16 @@
+parser.row{
+  Query: "ALTER TABLE prod.movies SET PROVISIONED THROUGHPUT READ 5 WRITE 5",
+  AST: &parser.AST{
+    AlterTable: &parser.AlterTable{
+      Table: "prod.movies",
+      Actions: []*parser.AlterTableAction{
+        {
+          SetThroughput: &parser.ProvisionedThroughput{
+            ReadCapacityUnits: 5,
+            WriteCapacityUnits: 5,
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
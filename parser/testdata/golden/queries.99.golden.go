@@ -0,0 +1,97 @@
+parser.row{
+  Query: "SELECT *, price * qty AS total, document(info.rating) AS rating FROM movies WHERE title = :title",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        All: true,
+        Columns: []*parser.ProjectionColumn{
+          {
+            Arithmetic: &parser.Arithmetic{
+              Operand: &parser.ArithmeticOperand{
+                DocumentPath: &parser.DocumentPath{
+                  Fragment: []*parser.PathFragment{
+                    {
+                      Symbol: "price",
+                    },
+                  },
+                },
+              },
+              Ops: []*parser.ArithmeticOp{
+                {
+                  Operator: "*",
+                  Operand: &parser.ArithmeticOperand{
+                    DocumentPath: &parser.DocumentPath{
+                      Fragment: []*parser.PathFragment{
+                        {
+                          Symbol: "qty",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+            Alias: &"total",
+          },
+          {
+            Function: &parser.FunctionExpression{
+              Function: "document",
+              Args: []*parser.FunctionArgument{
+                {
+                  DocumentPath: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "info",
+                      },
+                      {
+                        Symbol: "rating",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+            Alias: &"rating",
+          },
+        },
+      },
+      From: "movies",
+      Where: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 82,
+                  Line: 1,
+                  Column: 83,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -40,3 +40,116 @@ CREATE TABLE movies (title STRING, year NUMBER);
 CREATE TABLE movies (title STRING HASH KEY, year NUMBER RANGE KEY);
 CREATE TABLE movies (title STRING, year NUMBER, GLOBAL SECONDARY INDEX year_title HASH(year) RANGE(title) PROJECTION ALL PROVISIONED THROUGHPUT READ 1 WRITE 1);
 CREATE TABLE movies (title STRING, year NUMBER, LOCAL SECONDARY INDEX year_index RANGE(year) PROJECTION ALL);
+-- delete
+DELETE FROM movies
+DELETE FROM movies WHERE title = :title AND year = 2009
+DELETE FROM `movies` WHERE title = ? RETURNING ALL_OLD
+DELETE FROM namespaced.movies WHERE title = "Inception" AND attribute_exists(director);
+-- update
+UPDATE movies SET director = :director WHERE title = :title AND year = :year
+UPDATE movies SET director = "Nolan", info.rating = 9, info.alt = info.rating WHERE title = ? AND year = ? RETURNING ALL_NEW
+UPDATE movies REMOVE info.actors[0], info.plot ADD views :one DELETE tags :old SET director = :d WHERE title = :title
+UPDATE movies ADD views 1, plays :plays REMOVE director
+-- order by
+SELECT * FROM movies WHERE title = :title ORDER BY year DESC LIMIT 5
+SELECT * FROM movies WHERE title = :title order by year
+-- count
+SELECT COUNT(*) FROM movies WHERE title = :title
+select count(*) from movies
+SELECT * FROM movies /* inline */ WHERE title = :title -- trailing
+-- IS NULL
+SELECT * FROM movies WHERE title = :title AND info.rating IS NULL AND director IS NOT NULL
+SELECT * FROM movies WHERE title = :title AND info.rating = NULL
+-- LIKE
+SELECT * FROM movies WHERE title = :title AND director LIKE 'Steven%'
+-- NOT IN and NOT BETWEEN
+SELECT * FROM movies WHERE title = :title AND year NOT BETWEEN 1990 AND 2000 AND director NOT IN ("a", "b")
+SELECT * FROM movies WHERE title = :title AND (year NOT IN (1, 2) OR NOT year BETWEEN 3 AND 4)
+-- consistent reads
+SELECT * FROM movies WHERE title = :title LIMIT 1 WITH (CONSISTENT)
+-- LIMIT placeholders
+SELECT * FROM movies WHERE title = :title LIMIT :n
+SELECT * FROM movies WHERE title = ? LIMIT ?
+-- OFFSET
+SELECT * FROM movies WHERE title = :title LIMIT 10 OFFSET 20
+-- column aliases
+SELECT info.rating AS rating, year AS `release year`, document(title) AS doc FROM movies WHERE title = :title
+-- DROP TABLE
+DROP TABLE movies
+DROP TABLE IF EXISTS `movies`;
+-- BILLING MODE
+CREATE TABLE movies (title STRING HASH KEY, BILLING MODE PAY_PER_REQUEST);
+CREATE TABLE movies (title STRING HASH KEY, year NUMBER, GLOBAL SECONDARY INDEX year_title HASH(year) RANGE(title) PROJECTION ALL, BILLING MODE PAY_PER_REQUEST);
+CREATE TABLE movies (title STRING HASH KEY, BILLING MODE PROVISIONED, PROVISIONED THROUGHPUT READ 1 WRITE 1);
+-- TTL
+CREATE TABLE movies (title STRING HASH KEY) TTL (expires_at);
+CREATE TABLE movies (title STRING HASH KEY, BILLING MODE PAY_PER_REQUEST) TTL (`expires at`)
+-- IF NOT EXISTS
+INSERT INTO movies VALUES (?) IF NOT EXISTS
+INSERT INTO movies VALUES ('{"title": "Rush Hour"}') IF NOT EXISTS RETURNING NONE;
+-- WITH hints
+SELECT * FROM movies WHERE year > 2000 WITH (SEGMENTS = 8)
+SELECT * FROM movies LIMIT 10 WITH (CONSISTENT, SEGMENTS = 4)
+UPDATE movies SET tags = list_append(tags, :new), views = if_not_exists(views, 0) WHERE title = :title AND year = :year
+UPDATE movies SET views = views + :n, stock = stock - 1, total = 10 + total, plays = if_not_exists(plays, 0) + ? WHERE title = :title
+UPDATE movies SET stock = stock-1, views = views +2 WHERE title = :title
+-- DESCRIBE
+DESCRIBE movies
+desc `movies`;
+-- SHOW TABLES
+SHOW TABLES
+show tables like 'movie%';
+-- set literals
+INSERT INTO movies VALUES ({"title": "Heat", "tags": string_set('crime', "drama"), "ratings": number_set(8.2, 9), "posters": binary_set('aGVhdA==')})
+REPLACE INTO movies VALUES ({"title": "Heat", "info": {"cast": [string_set("De Niro", "Pacino")]}})
+-- ALTER TABLE
+ALTER TABLE movies ADD GLOBAL SECONDARY INDEX director_year HASH(director) RANGE(year) PROJECTION KEYS ONLY PROVISIONED THROUGHPUT READ 5 WRITE 5
+ALTER TABLE movies ADD director STRING, ADD GLOBAL SECONDARY INDEX director_year HASH(director) RANGE(year) PROJECTION INCLUDE rating, plot;
+ALTER TABLE `movies` DROP GLOBAL SECONDARY INDEX director_year
+alter table movies set provisioned throughput read 10 write 20
+-- OR at the top level of WHERE
+SELECT * FROM movies WHERE title = :title OR year > 2000 AND (rating > 8 OR director = :director)
+UPDATE movies SET views = views + 1 WHERE title = :title AND year = :year OR director = :director
+DELETE FROM movies WHERE (title = :t AND year = :y) OR (title = :t2 AND year = :y2);
+SELECT title, year FROM movies WHERE title = "The Dark Knight" AND year BETWEEN 2009 AND 2015 OR actor = "Will Smith"
+-- map keys in brackets
+SELECT info['release date'], info["cast"][0]['first name'] FROM movies WHERE title = :title AND info['a.b'] > 1
+UPDATE movies SET info['box office'][1] = :gross REMOVE info["plot"] WHERE title = :title
+-- size() compared in conditions
+SELECT * FROM movies WHERE title = :title AND size(tags) > 3 AND NOT (size(info.cast) BETWEEN 1 AND :max OR size(`plot`) IN (0, 1))
+DELETE FROM movies WHERE title = :title AND size(info['cast']) <> :n
+-- attribute_type() takes a type code, as a string or a bare code
+SELECT * FROM movies WHERE title = :title AND attribute_type(info, 'M') AND NOT attribute_type(tags, SS) AND attribute_type(plot, NULL) AND attribute_type(year, :type)
+-- WITH (SCAN) forces a Scan
+SELECT * FROM movies WHERE title = :title AND year > 2000 WITH (scan, SEGMENTS = 2)
+-- CASE is evaluated on each item once read
+SELECT title, CASE WHEN rating >= 8 AND size(info.cast) > 2 THEN "classic" WHEN `year` BETWEEN 1990 AND 1999 THEN `year` ELSE NULL END AS label FROM movies WHERE title = :title
+-- Computed columns are evaluated on each item once read
+SELECT title, info.rating * 10 - 1 AS score, (year-1900) / 10, 2 FROM movies WHERE title = :title
+-- EXPLAIN wraps a statement
+EXPLAIN SELECT title FROM movies WHERE title = :title AND year > 2000;
+explain DELETE FROM movies WHERE title = "Heat"
+-- Placeholders can be values of document literals
+INSERT INTO movies VALUES ({"title": :title, "info": {"cast": [?, "Al Pacino"], "rating": :rating}})
+INSERT INTO movies VALUES ({"title": 'O''Brien', "plot": "a ""quoted"" \"word\"", 'it''s': 'it\'s'})
+INSERT INTO archive SELECT * FROM movies WHERE year < 1990
+REPLACE INTO archive SELECT title, info.rating AS rating FROM movies USE INDEX (by_year) WHERE year = :year
+INSERT INTO movies VALUES ({"title": :title, "views": 1}) ON DUPLICATE KEY UPDATE views = views + 1, info.seen = if_not_exists(info.seen, :now)
+SELECT tags[0], info.actors[10] FROM movies WHERE title = :title AND info.ratings[0] > 5
+SELECT * FROM `prod.movies` WHERE title = :title
+CREATE TABLE prod.movies (title STRING HASH KEY)
+DROP TABLE prod.movies
+DESCRIBE `prod-movies`
+ALTER TABLE prod.movies SET PROVISIONED THROUGHPUT READ 5 WRITE 5
+SELECT *, price * qty AS total, document(info.rating) AS rating FROM movies WHERE title = :title
+SELECT document(*), CASE WHEN year > 2000 THEN 'new' ELSE 'old' END AS era FROM movies WHERE title = :title
+SELECT `a.b`, info['c.d'], info['e[0]'].f FROM movies WHERE `user.id` = :id AND info['g.h'] > 1
+insert into movies values ({"title": :title, "views": 1}) on duplicate key update views = views + 1
+CREATE TABLE t (pk STRING HASH KEY) TTL (ttl)
+SELECT ttl FROM t WHERE pk = 1
+SELECT count FROM t WHERE pk = 1
+select count(*) from t where pk = 1 with (scan)
+SELECT mode, scan, segments, tables, billing, consistent, explain, ttl FROM t WHERE count = 1 AND `end` > 2
+SELECT CASE WHEN mode = 1 THEN end ELSE scan END AS status FROM tables WITH (CONSISTENT, SEGMENTS = 2)
+DESCRIBE count
+INSERT INTO t VALUES ({"pk": 12345678901234567890, "ratio": 0.1})
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+// TokenCategory is the kind of a token, for syntax highlighting.
+type TokenCategory int
+
+const (
+	// KeywordToken is one of Keywords, in any case. Other words of the grammar, such as COUNT, are identifiers.
+	KeywordToken TokenCategory = iota + 1
+	// IdentifierToken is the name of a table, index, attribute or function, quoted with backticks or not.
+	IdentifierToken
+	// StringToken is a string literal, with its quotes.
+	StringToken
+	// NumberToken is a number literal, including its sign.
+	NumberToken
+	// OperatorToken is an operator or punctuation, such as =, [ or ;.
+	OperatorToken
+	// CommentToken is a -- or /* */ comment.
+	CommentToken
+)
+
+func (c TokenCategory) String() string {
+	switch c {
+	case KeywordToken:
+		return "keyword"
+	case IdentifierToken:
+		return "identifier"
+	case StringToken:
+		return "string"
+	case NumberToken:
+		return "number"
+	case OperatorToken:
+		return "operator"
+	case CommentToken:
+		return "comment"
+	default:
+		return "unknown"
+	}
+}
+
+var tokenCategories = map[string]TokenCategory{
+	"Keyword":     KeywordToken,
+	"QuotedIdent": IdentifierToken,
+	"Ident":       IdentifierToken,
+	"String":      StringToken,
+	"Number":      NumberToken,
+	"Operators":   OperatorToken,
+	"Comment":     CommentToken,
+}
+
+// Tokenize splits s into the tokens of Lexer without parsing it, as for syntax highlighting. Whitespace is left out,
+// but comments are kept. Values are as written, so strings and quoted identifiers keep their quotes. The name of the
+// type of a token is returned by TokenType. Errors are returned as a *ParseError.
+func Tokenize(s string) ([]lexer.Token, error) {
+	lex, err := Lexer.Lex(strings.NewReader(s))
+	if err != nil {
+		return nil, newParseError(err)
+	}
+	tokens, err := lexer.ConsumeAll(lex)
+	if err != nil {
+		return nil, newParseError(err)
+	}
+	// Drop the EOF token.
+	return tokens[:len(tokens)-1], nil
+}
+
+var tokenTypes = lexer.SymbolsByRune(Lexer)
+
+// TokenType returns the name of the type of a token returned by Tokenize, such as Keyword, Ident or Comment. A ; has
+// no type of its own, and is named Semicolon.
+func TokenType(token lexer.Token) string {
+	if token.Value == ";" {
+		return "Semicolon"
+	}
+	return tokenTypes[token.Type]
+}
+
+// Category returns the category of a token returned by Tokenize.
+func Category(token lexer.Token) TokenCategory {
+	if token.Value == ";" {
+		return OperatorToken
+	}
+	return tokenCategories[TokenType(token)]
+}
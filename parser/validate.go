@@ -0,0 +1,489 @@
+package parser
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// conditionFunctions maps the DynamoDB condition functions to the number of arguments they accept.
+// https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/Expressions.OperatorsAndFunctions.html
+var conditionFunctions = map[string]int{
+	"attribute_exists":     1,
+	"attribute_not_exists": 1,
+	"attribute_type":       2,
+	"begins_with":          2,
+	"contains":             2,
+	"size":                 1,
+}
+
+// attributeTypes are the type codes that attribute_type() accepts.
+var attributeTypes = map[string]bool{
+	"S": true, "N": true, "B": true, "BOOL": true, "NULL": true, "L": true, "M": true, "SS": true, "NS": true, "BS": true,
+}
+
+// projectionFunctions are the functions that may be used as a column of a SELECT. DynamoDB can only project
+// attributes, so these are evaluated by the driver on the attributes they project, and any other function would be
+// compiled into an invalid ProjectionExpression.
+var projectionFunctions = map[string]bool{
+	"document": true,
+}
+
+// updateFunctions maps the functions that may be used on the right hand side of SET in an UPDATE to the number of
+// arguments they accept.
+var updateFunctions = map[string]int{
+	"if_not_exists": 2,
+	"list_append":   2,
+}
+
+// tableNameRegexp matches the characters DynamoDB allows in the name of a table.
+var tableNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// validate checks the parts of the AST that the grammar alone can't enforce.
+func validate(ast *AST) error {
+	if err := parseNumbers(ast); err != nil {
+		return err
+	}
+	if err := validatePaths(ast); err != nil {
+		return err
+	}
+	if err := validateTableNames(ast); err != nil {
+		return err
+	}
+	var where *ConditionExpression
+	switch {
+	case ast.Select != nil:
+		return validateSelect(ast.Select)
+	case ast.Update != nil:
+		if err := validateUpdate(ast.Update); err != nil {
+			return err
+		}
+		where = ast.Update.Where
+	case ast.Delete != nil:
+		where = ast.Delete.Where
+	case ast.Insert != nil:
+		return validateInsert(ast.Insert)
+	case ast.Replace != nil:
+		return validateInsert(ast.Replace)
+	case ast.Explain != nil:
+		if ast.Explain.Statement.Explain != nil {
+			return errors.New("EXPLAIN can not explain another EXPLAIN")
+		}
+		return validate(ast.Explain.Statement)
+	}
+	if where == nil {
+		return nil
+	}
+	return validateConditions(where)
+}
+
+// validateTableNames checks that the tables the statement names are valid in DynamoDB, which has a single flat
+// namespace of tables. A dotted name such as a.b is a single table named "a.b", the same as `a.b`, as DynamoDB allows
+// dots in table names. Quoting is only needed for names with a -, or that are keywords.
+func validateTableNames(ast *AST) error {
+	var tables []string
+	switch {
+	case ast.Select != nil:
+		tables = append(tables, ast.Select.From)
+	case ast.Insert != nil, ast.Replace != nil:
+		ins := ast.Insert
+		if ins == nil {
+			ins = ast.Replace
+		}
+		tables = append(tables, ins.Into)
+		if ins.Select != nil {
+			tables = append(tables, ins.Select.From)
+		}
+	case ast.Update != nil:
+		tables = append(tables, ast.Update.Table)
+	case ast.Delete != nil:
+		tables = append(tables, ast.Delete.From)
+	case ast.CreateTable != nil:
+		tables = append(tables, ast.CreateTable.Table)
+	case ast.DropTable != nil:
+		tables = append(tables, ast.DropTable.Table)
+	case ast.AlterTable != nil:
+		tables = append(tables, ast.AlterTable.Table)
+	case ast.Describe != nil:
+		tables = append(tables, ast.Describe.Table)
+	}
+	for _, table := range tables {
+		if !tableNameRegexp.MatchString(table) {
+			return fmt.Errorf("invalid table name %q, DynamoDB table names may only contain letters, digits, _, - and .", table)
+		}
+	}
+	return nil
+}
+
+func validateSelect(s *Select) error {
+	if err := validateProjection(s.Projection); err != nil {
+		return err
+	}
+	if err := validateLimit(s.Limit); err != nil {
+		return err
+	}
+	if err := validateOffset(s); err != nil {
+		return err
+	}
+	if err := validateSegments(s.Hints); err != nil {
+		return err
+	}
+	if s.Where == nil {
+		return nil
+	}
+	return validateConditions(s.Where)
+}
+
+// validateLimit checks that a literal LIMIT is a positive integer. A placeholder is checked once it is bound.
+func validateLimit(limit *Limit) error {
+	if limit == nil || limit.Value.PlaceHolder != nil || limit.Value.PositionalPlaceholder {
+		return nil
+	}
+	if n := limit.Value.Number; n != nil && *n > 0 && *n == float64(int(*n)) {
+		return nil
+	}
+	return validationError(limit.Pos, fmt.Errorf("LIMIT must be a positive integer, got %s", limit.Value))
+}
+
+// validateOffset checks that a SELECT's OFFSET is a non-negative integer, and moves it from OffsetNumber to Offset.
+func validateOffset(s *Select) error {
+	if s.OffsetNumber == nil {
+		return nil
+	}
+	offset, err := strconv.Atoi(*s.OffsetNumber)
+	if err != nil || offset < 0 {
+		return fmt.Errorf("OFFSET must be a non-negative integer, got %s", *s.OffsetNumber)
+	}
+	s.Offset = &offset
+	s.OffsetNumber = nil
+	return nil
+}
+
+// validateSegments checks that the number of WITH (SEGMENTS = n) is a positive integer, and moves it from
+// SegmentsNumber to Segments.
+func validateSegments(hints []*SelectHint) error {
+	for _, hint := range hints {
+		if hint.SegmentsNumber == nil {
+			continue
+		}
+		segments, err := strconv.Atoi(*hint.SegmentsNumber)
+		if err != nil || segments < 1 {
+			return fmt.Errorf("SEGMENTS must be a positive integer, got %s", *hint.SegmentsNumber)
+		}
+		hint.Segments = &segments
+		hint.SegmentsNumber = nil
+	}
+	return nil
+}
+
+// validateConditions checks the functions of the conditions in node.
+func validateConditions(node Node) error {
+	return Visit(node, func(node Node, next func() error) error {
+		if cond, ok := node.(*Condition); ok && cond.Function != nil {
+			if err := validateConditionFunction(cond); err != nil {
+				return validationError(cond.Pos, err)
+			}
+		}
+		return next()
+	})
+}
+
+// parseNumbers sets the Number of each number literal to the value of its NumberText, as written in the statement.
+func parseNumbers(node Node) error {
+	parse := func(number **float64, text *string) error {
+		if text == nil || *number != nil {
+			return nil
+		}
+		n, err := strconv.ParseFloat(*text, 64)
+		if err != nil {
+			return fmt.Errorf("number %s is out of range", *text)
+		}
+		*number = &n
+		return nil
+	}
+	var err error
+	Walk(node, func(node Node) bool {
+		switch node := node.(type) {
+		case *Value:
+			err = parse(&node.Number, node.NumberText)
+		case *Scalar:
+			err = parse(&node.Number, node.NumberText)
+		case *ArithmeticOperand:
+			err = parse(&node.Number, node.NumberText)
+		}
+		return err == nil
+	})
+	return err
+}
+
+// validatePaths checks the list indexes of the document paths in node, which DynamoDB only allows to be non-negative
+// integers, and moves them from Number to Index.
+func validatePaths(node Node) error {
+	return Visit(node, func(node Node, next func() error) error {
+		path, ok := node.(*DocumentPath)
+		if !ok {
+			return next()
+		}
+		for _, fragment := range path.Fragment {
+			for _, accessor := range fragment.Accessors {
+				if accessor.Number == nil {
+					continue
+				}
+				index, err := strconv.Atoi(*accessor.Number)
+				if err != nil || index < 0 || strings.HasPrefix(*accessor.Number, "+") {
+					return fmt.Errorf("list index %s in %s must be a non-negative integer", *accessor.Number, path)
+				}
+				accessor.Index = &index
+				accessor.Number = nil
+			}
+		}
+		return nil
+	})
+}
+
+// validateProjection checks the CASE columns of a projection. They are evaluated on each item once it has been read,
+// where there are no arguments to bind, so they can't have placeholders. Computed columns that are a lone attribute
+// are folded into a DocumentPath, and signed numbers into the arithmetic they stand for.
+func validateProjection(p *ProjectionExpression) error {
+	if len(p.Extra) > 0 {
+		p.Columns, p.Extra = p.Extra, nil
+	}
+	for _, col := range p.Columns {
+		if p.All && col.Name() == "document" {
+			return fmt.Errorf("column %s can not be selected with *, which returns each item in the document column", col)
+		}
+		if col.Function != nil {
+			if err := validateProjectionFunction(col.Function); err != nil {
+				return err
+			}
+		}
+		if arith := col.Arithmetic; arith != nil {
+			if len(arith.Ops) == 0 && arith.Operand.DocumentPath != nil {
+				col.DocumentPath = arith.Operand.DocumentPath
+				col.Arithmetic = nil
+				continue
+			}
+			Walk(arith, func(node Node) bool {
+				if op, ok := node.(*ArithmeticOp); ok && op.Signed != nil {
+					number, text := op.Signed.Number, op.Signed.NumberText
+					op.Operator = op.Signed.Operator
+					op.Operand = &ArithmeticOperand{Number: &number, NumberText: &text}
+					op.Signed = nil
+				}
+				return true
+			})
+		}
+		if col.Case == nil {
+			continue
+		}
+		var placeholder *Value
+		Walk(col.Case, func(node Node) bool {
+			if value, ok := node.(*Value); ok && placeholder == nil && (value.PlaceHolder != nil || value.PositionalPlaceholder) {
+				placeholder = value
+			}
+			return true
+		})
+		if placeholder != nil {
+			return fmt.Errorf("placeholder %s can not be used in CASE, which is evaluated on each item once read", placeholder.String())
+		}
+		if err := validateConditions(col.Case); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateProjectionFunction checks a function used as a column, suggesting what to use instead if it is not one of
+// projectionFunctions.
+func validateProjectionFunction(f *FunctionExpression) error {
+	if projectionFunctions[f.Function] {
+		for _, arg := range f.Args {
+			if arg.DocumentPath == nil {
+				return fmt.Errorf("arguments to %s() must be document paths, got %s", f.Function, arg.String())
+			}
+		}
+		return nil
+	}
+	for name := range projectionFunctions {
+		if strings.EqualFold(f.Function, name) {
+			return fmt.Errorf("unknown function %s() in projection, did you mean %s()?", f.Function, name)
+		}
+	}
+	if _, ok := conditionFunctions[f.Function]; ok {
+		return fmt.Errorf("%s() can not be used in a projection, DynamoDB can only project attributes, use it in WHERE instead", f.Function)
+	}
+	return fmt.Errorf("unknown function %s() in projection, only document() can be selected, or a CASE or arithmetic on attributes", f.Function)
+}
+
+// validateConditionFunction checks a function used as a condition, and folds a comparison of size() into
+// a ConditionOperand.
+func validateConditionFunction(cond *Condition) error {
+	f := cond.Function
+	arity, ok := conditionFunctions[f.Function]
+	if !ok {
+		return fmt.Errorf("unknown condition function %s()", f.Function)
+	}
+	if len(f.Args) != arity {
+		return fmt.Errorf("%s() expects %d argument(s) but got %d in %s", f.Function, arity, len(f.Args), f.String())
+	}
+	if !f.FirstArgIsRef() {
+		return fmt.Errorf("first argument to %s() must be a document path, got %s", f.Function, f.Args[0].String())
+	}
+	if f.Function == "attribute_type" {
+		if err := validateAttributeType(f); err != nil {
+			return err
+		}
+	}
+	switch {
+	case f.Function == "size" && cond.FunctionRHS == nil:
+		return fmt.Errorf("size() is not a condition, compare it instead, such as: %s > 0", f.String())
+	case f.Function != "size" && cond.FunctionRHS != nil:
+		return fmt.Errorf("only size() can be compared, %s is a condition by itself", f.String())
+	case f.Function == "size":
+		if rhs := cond.FunctionRHS; rhs.Is != nil || rhs.Like != nil {
+			return fmt.Errorf("size() can only be compared with operators, BETWEEN or IN, in %s", f.String())
+		}
+		cond.Operand = &ConditionOperand{Size: f, ConditionRHS: cond.FunctionRHS}
+		cond.Function = nil
+		cond.FunctionRHS = nil
+	}
+	return nil
+}
+
+// validateAttributeType checks the type code passed to attribute_type(), which may be written as a string or as a
+// bare code such as attribute_type(a, SS). A bare code is replaced by the string it stands for.
+func validateAttributeType(f *FunctionExpression) error {
+	arg := f.Args[1]
+	var code string
+	switch {
+	case arg.DocumentPath != nil && len(arg.DocumentPath.Fragment) == 1 && len(arg.DocumentPath.Fragment[0].Accessors) == 0:
+		code = arg.DocumentPath.Fragment[0].Symbol
+	case arg.Value != nil && arg.Value.Null:
+		code = "NULL"
+	case arg.Value != nil && arg.Value.Str != nil:
+		code = *arg.Value.Str
+	case arg.Value != nil && (arg.Value.PlaceHolder != nil || arg.Value.PositionalPlaceholder):
+		return nil
+	default:
+		return fmt.Errorf("second argument to attribute_type() must be a type code, got %s", arg.String())
+	}
+	if !attributeTypes[code] {
+		return fmt.Errorf("unknown attribute type %q in %s, expected one of S, N, B, BOOL, NULL, L, M, SS, NS or BS", code, f.String())
+	}
+	f.Args[1] = &FunctionArgument{Value: &Value{Scalar: Scalar{Str: &code}}}
+	return nil
+}
+
+// validateUpdate checks the functions in SET, and folds signed numbers into the arithmetic they stand for.
+func validateUpdate(u *Update) error {
+	for _, action := range u.Actions {
+		if err := validateSetExpressions(action.Set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateSetExpressions(sets []*SetExpression) error {
+	for _, set := range sets {
+		operands := []*SetOperand{&set.SetOperand}
+		if arith := set.Arithmetic; arith != nil {
+			if arith.Signed != nil {
+				number, text := arith.Signed.Number, arith.Signed.NumberText
+				arith.Operator = arith.Signed.Operator
+				arith.Operand = &SetOperand{Value: &Operand{Value: &Value{Scalar: Scalar{Number: &number, NumberText: &text}}}}
+				arith.Signed = nil
+			}
+			operands = append(operands, arith.Operand)
+		}
+		for _, operand := range operands {
+			if err := validateUpdateFunction(operand.Function); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateUpdateFunction(f *FunctionExpression) error {
+	if f == nil {
+		return nil
+	}
+	arity, ok := updateFunctions[f.Function]
+	if !ok {
+		return fmt.Errorf("unknown update function %s(), only if_not_exists() and list_append() can be used in SET", f.Function)
+	}
+	if len(f.Args) != arity {
+		return fmt.Errorf("%s() expects %d argument(s) but got %d in %s", f.Function, arity, len(f.Args), f.String())
+	}
+	if f.Function == "if_not_exists" && !f.FirstArgIsRef() {
+		return fmt.Errorf("first argument to %s() must be a document path, got %s", f.Function, f.Args[0].String())
+	}
+	return nil
+}
+
+// validateInsert checks the set literals in the documents of an INSERT or REPLACE.
+func validateInsert(ins *Insert) error {
+	if err := validateSetExpressions(ins.OnDuplicate); err != nil {
+		return err
+	}
+	if ins.Select != nil {
+		return validateSelect(ins.Select)
+	}
+	for _, value := range ins.Values {
+		if value.Object == nil {
+			continue
+		}
+		err := Visit(value.Object, func(node Node, next func() error) error {
+			if set, ok := node.(*SetLiteral); ok {
+				return validateSetLiteral(set)
+			}
+			return next()
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSetLiteral checks that a set is not empty, has elements of its type and has no duplicates, all of which
+// DynamoDB rejects.
+func validateSetLiteral(set *SetLiteral) error {
+	if len(set.Values) == 0 {
+		return fmt.Errorf("%s() cannot be empty, DynamoDB does not allow empty sets", set.Type)
+	}
+	seen := map[string]bool{}
+	for _, value := range set.Values {
+		var key string
+		switch {
+		case set.Type == "number_set" && value.Number != nil:
+			// Compared by value, as numbers are kept as written and 1 and 1.0 are the same element.
+			r, ok := new(big.Rat).SetString(value.NumberLiteral())
+			if !ok {
+				return fmt.Errorf("number_set() element %s is not a number", value)
+			}
+			key = r.RatString()
+		case set.Type == "string_set" && value.Str != nil:
+			key = *value.Str
+		case set.Type == "binary_set" && value.Str != nil:
+			b, err := base64.StdEncoding.DecodeString(*value.Str)
+			if err != nil {
+				return fmt.Errorf("binary_set() elements must be base64 encoded strings, got %s", value)
+			}
+			key = string(b)
+		default:
+			kind := map[string]string{"string_set": "strings", "number_set": "numbers", "binary_set": "base64 encoded strings"}[set.Type]
+			return fmt.Errorf("%s() elements must be %s, got %s", set.Type, kind, value)
+		}
+		if seen[key] {
+			return fmt.Errorf("%s() contains %s more than once, DynamoDB does not allow duplicates in sets", set.Type, value)
+		}
+		seen[key] = true
+	}
+	return nil
+}
@@ -9,13 +9,43 @@ import (
 
 // Visit nodes in the AST
 //
-// The visitor can call "next()" to continue traversal of child nodes.
+// The visitor can call "next()" to continue traversal of child nodes. Nodes are visited in the order they appear in
+// the statement.
 func Visit(node Node, visitor func(node Node, next func() error) error) error {
 	return visitor(node, func() error {
 		if reflect.ValueOf(node).IsNil() { // Workaround for Go's typed nil interfaces.
 			return nil
 		}
 		switch node := node.(type) {
+		case *AST:
+			switch {
+			case node.Select != nil:
+				return Visit(node.Select, visitor)
+			case node.Insert != nil:
+				return Visit(node.Insert, visitor)
+			case node.Replace != nil:
+				return Visit(node.Replace, visitor)
+			case node.Update != nil:
+				return Visit(node.Update, visitor)
+			case node.Delete != nil:
+				return Visit(node.Delete, visitor)
+			case node.CreateTable != nil:
+				return Visit(node.CreateTable, visitor)
+			case node.DropTable != nil:
+				return Visit(node.DropTable, visitor)
+			case node.AlterTable != nil:
+				return Visit(node.AlterTable, visitor)
+			case node.Describe != nil:
+				return Visit(node.Describe, visitor)
+			case node.ShowTables != nil:
+				return Visit(node.ShowTables, visitor)
+			case node.Explain != nil:
+				return Visit(node.Explain, visitor)
+			default:
+				return nil
+			}
+		case *Explain:
+			return Visit(node.Statement, visitor)
 		case *CreateTable:
 			for _, entry := range node.Entries {
 				if err := Visit(entry, visitor); err != nil {
@@ -33,17 +63,127 @@ func Visit(node Node, visitor func(node Node, next func() error) error) error {
 				return Visit(node.GlobalSecondaryIndex, visitor)
 			case node.LocalSecondaryIndex != nil:
 				return Visit(node.LocalSecondaryIndex, visitor)
+			case node.BillingMode != nil:
+				return Visit(node.BillingMode, visitor)
 			default:
 				panic(repr.String(node))
 			}
-		case *TableAttr, *GlobalSecondaryIndex, *LocalSecondaryIndex, *ProvisionedThroughput:
+		case *AlterTable:
+			for _, action := range node.Actions {
+				if err := Visit(action, visitor); err != nil {
+					return err
+				}
+			}
+			return nil
+		case *AlterTableAction:
+			switch {
+			case node.AddIndex != nil:
+				return Visit(node.AddIndex, visitor)
+			case node.AddAttr != nil:
+				return Visit(node.AddAttr, visitor)
+			case node.SetThroughput != nil:
+				return Visit(node.SetThroughput, visitor)
+			default:
+				return nil
+			}
+		case *TableAttr, *GlobalSecondaryIndex, *LocalSecondaryIndex, *ProvisionedThroughput, *DropTable, *Describe, *ShowTables, *BillingMode, *SelectHint:
 			return nil
 		case *Select:
 			if err := Visit(node.Projection, visitor); err != nil {
 				return err
 			}
+			if err := Visit(node.Where, visitor); err != nil {
+				return err
+			}
+			if err := Visit(node.OrderBy, visitor); err != nil {
+				return err
+			}
+			return Visit(node.Limit, visitor)
+		case *Limit:
+			return Visit(node.Value, visitor)
+		case *OrderBy:
+			return Visit(node.Path, visitor)
+		case *Update:
+			for _, action := range node.Actions {
+				if err := Visit(action, visitor); err != nil {
+					return err
+				}
+			}
+			return Visit(node.Where, visitor)
+		case *UpdateAction:
+			for _, entry := range node.Set {
+				if err := Visit(entry, visitor); err != nil {
+					return err
+				}
+			}
+			for _, entry := range node.Add {
+				if err := Visit(entry, visitor); err != nil {
+					return err
+				}
+			}
+			for _, entry := range node.Remove {
+				if err := Visit(entry, visitor); err != nil {
+					return err
+				}
+			}
+			for _, entry := range node.Delete {
+				if err := Visit(entry, visitor); err != nil {
+					return err
+				}
+			}
+			return nil
+		case *SetExpression:
+			if err := Visit(node.Path, visitor); err != nil {
+				return err
+			}
+			if err := Visit(&node.SetOperand, visitor); err != nil {
+				return err
+			}
+			if node.Arithmetic != nil {
+				return Visit(node.Arithmetic.Operand, visitor)
+			}
+			return nil
+		case *SetOperand:
+			if node.Function != nil {
+				return Visit(node.Function, visitor)
+			}
+			return Visit(node.Value, visitor)
+		case *AddExpression:
+			if err := Visit(node.Path, visitor); err != nil {
+				return err
+			}
+			return Visit(node.Value, visitor)
+		case *DeleteExpression:
+			if err := Visit(node.Path, visitor); err != nil {
+				return err
+			}
+			return Visit(node.Value, visitor)
+		case *Delete:
 			return Visit(node.Where, visitor)
+		case *Insert:
+			for _, value := range node.Values {
+				if err := Visit(value, visitor); err != nil {
+					return err
+				}
+			}
+			for _, set := range node.OnDuplicate {
+				if err := Visit(set, visitor); err != nil {
+					return err
+				}
+			}
+			return Visit(node.Select, visitor)
+		case *InsertTerminal:
+			if node.Object != nil {
+				return Visit(node.Object, visitor)
+			}
+			return Visit(&node.Value, visitor)
 		case *ProjectionExpression:
+			// Until Parse folds them into Columns, the columns that follow * are held by Extra.
+			for _, e := range node.Extra {
+				if err := Visit(e, visitor); err != nil {
+					return err
+				}
+			}
 			for _, e := range node.Columns {
 				if err := Visit(e, visitor); err != nil {
 					return err
@@ -51,10 +191,44 @@ func Visit(node Node, visitor func(node Node, next func() error) error) error {
 			}
 			return nil
 		case *ProjectionColumn:
-			if node.DocumentPath != nil {
+			switch {
+			case node.Case != nil:
+				return Visit(node.Case, visitor)
+			case node.Arithmetic != nil:
+				return Visit(node.Arithmetic, visitor)
+			case node.DocumentPath != nil:
 				return Visit(node.DocumentPath, visitor)
 			}
 			return Visit(node.Function, visitor)
+		case *Arithmetic:
+			if err := Visit(node.Operand, visitor); err != nil {
+				return err
+			}
+			for _, op := range node.Ops {
+				if err := Visit(op, visitor); err != nil {
+					return err
+				}
+			}
+			return nil
+		case *ArithmeticOp:
+			return Visit(node.Operand, visitor)
+		case *ArithmeticOperand:
+			if node.DocumentPath != nil {
+				return Visit(node.DocumentPath, visitor)
+			}
+			return Visit(node.Parenthesized, visitor)
+		case *CaseExpression:
+			for _, when := range node.Whens {
+				if err := Visit(when, visitor); err != nil {
+					return err
+				}
+			}
+			return Visit(node.Else, visitor)
+		case *CaseWhen:
+			if err := Visit(node.Condition, visitor); err != nil {
+				return err
+			}
+			return Visit(node.Result, visitor)
 		case *ConditionExpression:
 			for _, entry := range node.Or {
 				if err := Visit(entry, visitor); err != nil {
@@ -78,7 +252,14 @@ func Visit(node Node, visitor func(node Node, next func() error) error) error {
 			case node.Operand != nil:
 				return Visit(node.Operand, visitor)
 			case node.Function != nil:
-				return Visit(node.Function, visitor)
+				if err := Visit(node.Function, visitor); err != nil {
+					return err
+				}
+				// Until Parse folds it into a ConditionOperand, a comparison of size() is held by FunctionRHS.
+				if node.FunctionRHS != nil {
+					return Visit(node.FunctionRHS, visitor)
+				}
+				return nil
 			default:
 				panic(fmt.Sprintf("invalid Condition %v", node))
 			}
@@ -87,7 +268,11 @@ func Visit(node Node, visitor func(node Node, next func() error) error) error {
 		case *NotCondition:
 			return Visit(node.Condition, visitor)
 		case *ConditionOperand:
-			if err := Visit(node.Operand, visitor); err != nil {
+			if node.Size != nil {
+				if err := Visit(node.Size, visitor); err != nil {
+					return err
+				}
+			} else if err := Visit(node.Operand, visitor); err != nil {
 				return err
 			}
 			return Visit(node.ConditionRHS, visitor)
@@ -97,8 +282,16 @@ func Visit(node Node, visitor func(node Node, next func() error) error) error {
 				return Visit(node.Between, visitor)
 			case node.Compare != nil:
 				return Visit(node.Compare, visitor)
+			case node.NotBetween != nil:
+				return Visit(node.NotBetween, visitor)
 			case node.In != nil:
 				return Visit(node.In, visitor)
+			case node.NotIn != nil:
+				return Visit(node.NotIn, visitor)
+			case node.Is != nil:
+				return Visit(node.Is, visitor)
+			case node.Like != nil:
+				return Visit(node.Like, visitor)
 			default:
 				panic(fmt.Sprintf("invalid ConditionRHS %v", node))
 			}
@@ -109,6 +302,10 @@ func Visit(node Node, visitor func(node Node, next func() error) error) error {
 			return Visit(node.End, visitor)
 		case *Compare:
 			return Visit(node.Operand, visitor)
+		case *Is:
+			return nil
+		case *Like:
+			return Visit(node.Pattern, visitor)
 		case *In:
 			for _, entry := range node.Values {
 				if err := Visit(entry, visitor); err != nil {
@@ -124,14 +321,21 @@ func Visit(node Node, visitor func(node Node, next func() error) error) error {
 		case *DocumentPath:
 			for _, frag := range node.Fragment {
 				if err := Visit(frag, visitor); err != nil {
-					return Visit(frag, visitor)
+					return err
+				}
+			}
+			return nil
+		case *PathFragment:
+			for _, accessor := range node.Accessors {
+				if err := Visit(accessor, visitor); err != nil {
+					return err
 				}
 			}
 			return nil
 		case *FunctionExpression:
 			for _, arg := range node.Args {
 				if err := Visit(arg, visitor); err != nil {
-					return Visit(arg, visitor)
+					return err
 				}
 			}
 			return nil
@@ -162,9 +366,18 @@ func Visit(node Node, visitor func(node Node, next func() error) error) error {
 				return Visit(node.Object, visitor)
 			case node.Array != nil:
 				return Visit(node.Array, visitor)
+			case node.Set != nil:
+				return Visit(node.Set, visitor)
 			}
-			return Visit(&node.Scalar, visitor)
-		case *Value, *PathFragment:
+			return Visit(&node.Value, visitor)
+		case *SetLiteral:
+			for _, value := range node.Values {
+				if err := Visit(value, visitor); err != nil {
+					return err
+				}
+			}
+			return nil
+		case *Value, *Scalar, *PathAccessor:
 			// Leaf nodes
 			return nil
 		default:
@@ -172,3 +385,34 @@ func Visit(node Node, visitor func(node Node, next func() error) error) error {
 		}
 	})
 }
+
+// Walk visits node and everything below it in pre-order, in the order they appear in the statement. If fn returns
+// false, the children of the node are skipped, as with ast.Inspect.
+func Walk(node Node, fn func(node Node) bool) {
+	_ = Visit(node, func(node Node, next func() error) error {
+		if reflect.ValueOf(node).IsNil() || !fn(node) {
+			return nil
+		}
+		return next()
+	})
+}
+
+// Placeholders returns the placeholders of a statement in the order they appear, as :name or ?. A named placeholder
+// is only returned the first time it appears, so there is one entry per argument the statement expects.
+func Placeholders(ast *AST) []string {
+	var placeholders []string
+	seen := map[string]bool{}
+	Walk(ast, func(node Node) bool {
+		value, ok := node.(*Value)
+		switch {
+		case !ok:
+		case value.PositionalPlaceholder:
+			placeholders = append(placeholders, "?")
+		case value.PlaceHolder != nil && !seen[*value.PlaceHolder]:
+			seen[*value.PlaceHolder] = true
+			placeholders = append(placeholders, *value.PlaceHolder)
+		}
+		return true
+	})
+	return placeholders
+}
@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalk(t *testing.T) {
+	ast, err := Parse(`UPDATE movies SET info.rating = :r, tags = list_append(tags, :t) REMOVE info.actors[0] WHERE title = :title AND NOT (size(info.plot) > 3 OR attribute_exists(deleted))`)
+	require.NoError(t, err)
+
+	t.Run("collect paths", func(t *testing.T) {
+		var paths []string
+		Walk(ast, func(node Node) bool {
+			if path, ok := node.(*DocumentPath); ok {
+				paths = append(paths, path.String())
+			}
+			return true
+		})
+		require.Equal(t, []string{"info.rating", "tags", "tags", "info.actors[0]", "title", "info.plot", "deleted"}, paths)
+	})
+
+	t.Run("skip children", func(t *testing.T) {
+		var paths []string
+		Walk(ast, func(node Node) bool {
+			if path, ok := node.(*DocumentPath); ok {
+				paths = append(paths, path.String())
+			}
+			_, ok := node.(*NotCondition)
+			return !ok
+		})
+		require.Equal(t, []string{"info.rating", "tags", "tags", "info.actors[0]", "title"}, paths)
+	})
+
+	t.Run("rewrite", func(t *testing.T) {
+		ast, err := Parse(`SELECT info.rating FROM movies WHERE info.rating > 5 ORDER BY rating`)
+		require.NoError(t, err)
+		Walk(ast, func(node Node) bool {
+			if frag, ok := node.(*PathFragment); ok && frag.Symbol == "rating" {
+				frag.Symbol = "score"
+			}
+			return true
+		})
+		require.Equal(t, `SELECT info.score FROM movies WHERE info.score > 5 ORDER BY score`, Format(ast))
+	})
+}
+
+func TestPlaceholders(t *testing.T) {
+	tests := []struct {
+		query        string
+		placeholders []string
+	}{
+		{`SELECT * FROM movies WHERE title = "Heat"`, nil},
+		{`SELECT * FROM movies WHERE title = :title AND year > :year AND rating < :year LIMIT :n`, []string{":title", ":year", ":n"}},
+		{`SELECT * FROM movies WHERE title = ? AND year BETWEEN ? AND ? AND tags IN (?, ?) LIMIT ?`, []string{"?", "?", "?", "?", "?", "?"}},
+		{`UPDATE movies SET views = views + :n, tags = list_append(tags, :tags) ADD seen :n WHERE title = :title`, []string{":n", ":tags", ":title"}},
+		{`DELETE FROM movies WHERE title = ? AND begins_with(plot, ?)`, []string{"?", "?"}},
+		{`INSERT INTO movies VALUES (:movies)`, []string{":movies"}},
+		{`INSERT INTO movies VALUES ({"title": "Heat"})`, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			ast, err := Parse(test.query)
+			require.NoError(t, err)
+			require.Equal(t, test.placeholders, Placeholders(ast))
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/repr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// PrepareAlterTable prepares an ALTER TABLE, which is a single UpdateTable call. The table is removed from the schema
+// cache once it is updated, as its indexes may have changed.
+func PrepareAlterTable(ast *parser.AST, tables *schema.TableLoader) (ExecStmt, error) {
+	if ast.AlterTable == nil {
+		return nil, fmt.Errorf("expected ALTER TABLE but got %s", repr.String(ast))
+	}
+	req, err := buildUpdateTableInput(ast.AlterTable)
+	if err != nil {
+		return nil, err
+	}
+	return execStatementFunc(func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
+		resolved, err := resolveIndexAttributes(ctx, dynamo, req)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := dynamo.UpdateTableWithContext(ctx, resolved); err != nil {
+			return nil, err
+		}
+		tables.Forget(*req.TableName)
+		return &DriverResult{count: 0}, nil
+	}), nil
+}
+
+func buildUpdateTableInput(stmt *parser.AlterTable) (*dynamodb.UpdateTableInput, error) {
+	req := &dynamodb.UpdateTableInput{TableName: aws.String(stmt.Table)}
+	for _, action := range stmt.Actions {
+		switch {
+		case action.AddIndex != nil, action.DropIndex != nil:
+			if len(req.GlobalSecondaryIndexUpdates) > 0 {
+				// DynamoDB only accepts one index to be created or deleted per UpdateTable call.
+				return nil, errors.New("ALTER TABLE can only create or drop one global secondary index at a time, use a separate statement for each index")
+			}
+			update := &dynamodb.GlobalSecondaryIndexUpdate{}
+			if gsi := action.AddIndex; gsi != nil {
+				update.Create = &dynamodb.CreateGlobalSecondaryIndexAction{
+					IndexName: aws.String(gsi.Name),
+					KeySchema: []*dynamodb.KeySchemaElement{
+						{AttributeName: aws.String(gsi.PartitionKey), KeyType: aws.String("HASH")},
+						{AttributeName: aws.String(gsi.SortKey), KeyType: aws.String("RANGE")},
+					},
+					Projection:            mapProjection(gsi.Projection),
+					ProvisionedThroughput: mapProvisionedThroughput(gsi.ProvisionedThroughput),
+				}
+			} else {
+				update.Delete = &dynamodb.DeleteGlobalSecondaryIndexAction{IndexName: action.DropIndex}
+			}
+			req.GlobalSecondaryIndexUpdates = append(req.GlobalSecondaryIndexUpdates, update)
+
+		case action.AddAttr != nil:
+			attr := action.AddAttr
+			if attr.Key != "" {
+				return nil, fmt.Errorf("the key of table %q cannot be changed", stmt.Table)
+			}
+			req.AttributeDefinitions = append(req.AttributeDefinitions, &dynamodb.AttributeDefinition{
+				AttributeName: aws.String(attr.Name),
+				AttributeType: aws.String(strings.ToUpper(attr.Type[0:1])),
+			})
+
+		case action.SetThroughput != nil:
+			if req.ProvisionedThroughput != nil {
+				return nil, errors.New("PROVISIONED THROUGHPUT can only be set once")
+			}
+			req.ProvisionedThroughput = mapProvisionedThroughput(action.SetThroughput)
+
+		default:
+			panic(repr.String(action))
+		}
+	}
+	// DynamoDB rejects attribute definitions that are not used by the key schema of a new index.
+	for _, def := range req.AttributeDefinitions {
+		if !isIndexKey(req, *def.AttributeName) {
+			return nil, fmt.Errorf("attribute %q is not a key of a new global secondary index", *def.AttributeName)
+		}
+	}
+	return req, nil
+}
+
+// resolveIndexAttributes returns the request with a definition for each key attribute of the index it creates. Keys
+// that are not declared with ADD must already be defined by the table.
+func resolveIndexAttributes(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, req *dynamodb.UpdateTableInput) (*dynamodb.UpdateTableInput, error) {
+	if len(req.GlobalSecondaryIndexUpdates) == 0 || req.GlobalSecondaryIndexUpdates[0].Create == nil {
+		return req, nil
+	}
+	create := req.GlobalSecondaryIndexUpdates[0].Create
+	defined := map[string]bool{}
+	for _, def := range req.AttributeDefinitions {
+		defined[*def.AttributeName] = true
+	}
+	var missing []string
+	for _, key := range create.KeySchema {
+		if !defined[*key.AttributeName] {
+			missing = append(missing, *key.AttributeName)
+		}
+	}
+	if len(missing) == 0 {
+		return req, nil
+	}
+	desc, err := dynamo.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{TableName: req.TableName})
+	if err != nil {
+		return nil, err
+	}
+	out := *req
+	out.AttributeDefinitions = append([]*dynamodb.AttributeDefinition(nil), req.AttributeDefinitions...)
+	for _, name := range missing {
+		var found *dynamodb.AttributeDefinition
+		for _, def := range desc.Table.AttributeDefinitions {
+			if *def.AttributeName == name {
+				found = def
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("the type of attribute %q of index %q is unknown, declare it with ADD %s STRING, NUMBER or BINARY", name, *create.IndexName, name)
+		}
+		out.AttributeDefinitions = append(out.AttributeDefinitions, found)
+	}
+	return &out, nil
+}
+
+func isIndexKey(req *dynamodb.UpdateTableInput, attr string) bool {
+	for _, update := range req.GlobalSecondaryIndexUpdates {
+		if update.Create == nil {
+			continue
+		}
+		for _, key := range update.Create.KeySchema {
+			if *key.AttributeName == attr {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package querybuilder
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+func TestAlterTable(t *testing.T) {
+	build := func(t *testing.T, query string) (*dynamodb.UpdateTableInput, error) {
+		t.Helper()
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		return buildUpdateTableInput(ast.AlterTable)
+	}
+
+	t.Run("add index", func(t *testing.T) {
+		req, err := build(t, `ALTER TABLE movies ADD director STRING, `+
+			`ADD GLOBAL SECONDARY INDEX director_year HASH(director) RANGE(year) PROJECTION KEYS ONLY PROVISIONED THROUGHPUT READ 5 WRITE 6`)
+		require.NoError(t, err)
+		require.Len(t, req.GlobalSecondaryIndexUpdates, 1)
+		create := req.GlobalSecondaryIndexUpdates[0].Create
+		require.Equal(t, "director_year", aws.StringValue(create.IndexName))
+		require.Equal(t, "KEYS_ONLY", aws.StringValue(create.Projection.ProjectionType))
+		require.Equal(t, int64(6), aws.Int64Value(create.ProvisionedThroughput.WriteCapacityUnits))
+		require.Equal(t, []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("director"), AttributeType: aws.String("S")},
+		}, req.AttributeDefinitions)
+		require.Nil(t, req.ProvisionedThroughput)
+	})
+
+	t.Run("drop index and set throughput", func(t *testing.T) {
+		req, err := build(t, `ALTER TABLE movies DROP GLOBAL SECONDARY INDEX director_year, SET PROVISIONED THROUGHPUT READ 1 WRITE 2`)
+		require.NoError(t, err)
+		require.Equal(t, "director_year", aws.StringValue(req.GlobalSecondaryIndexUpdates[0].Delete.IndexName))
+		require.Equal(t, int64(1), aws.Int64Value(req.ProvisionedThroughput.ReadCapacityUnits))
+		require.Equal(t, int64(2), aws.Int64Value(req.ProvisionedThroughput.WriteCapacityUnits))
+	})
+
+	for _, test := range []struct {
+		name  string
+		query string
+		err   string
+	}{
+		{"multiple index changes",
+			`ALTER TABLE movies DROP GLOBAL SECONDARY INDEX a, ADD GLOBAL SECONDARY INDEX b HASH(director) RANGE(year) PROJECTION ALL`,
+			"ALTER TABLE can only create or drop one global secondary index at a time, use a separate statement for each index"},
+		{"throughput set twice",
+			`ALTER TABLE movies SET PROVISIONED THROUGHPUT READ 1 WRITE 1, SET PROVISIONED THROUGHPUT READ 2 WRITE 2`,
+			"PROVISIONED THROUGHPUT can only be set once"},
+		{"unused attribute",
+			`ALTER TABLE movies ADD director STRING`,
+			`attribute "director" is not a key of a new global secondary index`},
+		{"key attribute",
+			`ALTER TABLE movies ADD director STRING HASH KEY`,
+			`the key of table "movies" cannot be changed`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := build(t, test.query)
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
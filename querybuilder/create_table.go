@@ -3,6 +3,8 @@ package querybuilder
 import (
 	"context"
 	"database/sql/driver"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/alecthomas/repr"
@@ -11,66 +13,163 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
 	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
 )
 
-func PrepareCreateTable(ast *parser.AST) (ExecStmt, error) {
+// PrepareCreateTable prepares a CREATE TABLE. DynamoDB creates tables asynchronously, so with waitForActive the
+// statement blocks until the table is ACTIVE and can be written to. Tables with a TTL are always waited for, as Time
+// to Live can only be enabled on an ACTIVE table.
+func PrepareCreateTable(ast *parser.AST, tables *schema.TableLoader, waitForActive bool) (ExecStmt, error) {
+	if ast.CreateTable == nil {
+		return nil, fmt.Errorf("expected CREATE TABLE but got %s", repr.String(ast))
+	}
+	req, err := buildCreateTableInput(ast.CreateTable)
+	if err != nil {
+		return nil, err
+	}
+	ttl := ast.CreateTable.TTL
 	return execStatementFunc(func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
-		stmt := ast.CreateTable
-		req := &dynamodb.CreateTableInput{
-			TableName: &stmt.Table,
-			ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
-				ReadCapacityUnits:  nil,
-				WriteCapacityUnits: nil,
+		if _, err := dynamo.CreateTableWithContext(ctx, req); err != nil {
+			return nil, err
+		}
+		// A table of the same name may have been cached before it was dropped outside the driver.
+		tables.Forget(*req.TableName)
+		if ttl == nil && !waitForActive {
+			return &DriverResult{count: 0}, nil
+		}
+		err := dynamo.WaitUntilTableExistsWithContext(ctx, &dynamodb.DescribeTableInput{TableName: req.TableName})
+		if err != nil {
+			return nil, fmt.Errorf("waiting for table %q to become active: %w", *req.TableName, err)
+		}
+		if ttl == nil {
+			return &DriverResult{count: 0}, nil
+		}
+		_, err = dynamo.UpdateTimeToLiveWithContext(ctx, &dynamodb.UpdateTimeToLiveInput{
+			TableName: req.TableName,
+			TimeToLiveSpecification: &dynamodb.TimeToLiveSpecification{
+				AttributeName: ttl,
+				Enabled:       aws.Bool(true),
 			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("table %q was created but enabling TTL on %q failed: %w", *req.TableName, *ttl, err)
 		}
-		for _, entry := range stmt.Entries {
-			switch {
-			case entry.Attr != nil:
-				attr := entry.Attr
-				typ := strings.ToUpper(attr.Type[0:1])
-				req.AttributeDefinitions = append(req.AttributeDefinitions, &dynamodb.AttributeDefinition{
+		return &DriverResult{count: 0}, nil
+	}), nil
+}
+
+func buildCreateTableInput(stmt *parser.CreateTable) (*dynamodb.CreateTableInput, error) {
+	req := &dynamodb.CreateTableInput{
+		TableName: &stmt.Table,
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  nil,
+			WriteCapacityUnits: nil,
+		},
+	}
+	payPerRequest := false
+	hasThroughput := false
+	for _, entry := range stmt.Entries {
+		switch {
+		case entry.Attr != nil:
+			attr := entry.Attr
+			typ := strings.ToUpper(attr.Type[0:1])
+			req.AttributeDefinitions = append(req.AttributeDefinitions, &dynamodb.AttributeDefinition{
+				AttributeName: &attr.Name,
+				AttributeType: &typ,
+			})
+			if attr.Key != "" {
+				req.KeySchema = append(req.KeySchema, &dynamodb.KeySchemaElement{
 					AttributeName: &attr.Name,
-					AttributeType: &typ,
+					KeyType:       aws.String(strings.ToUpper(attr.Key)),
 				})
-				if attr.Key != "" {
-					req.KeySchema = append(req.KeySchema, &dynamodb.KeySchemaElement{
-						AttributeName: &attr.Name,
-						KeyType:       aws.String(strings.ToUpper(attr.Key)),
-					})
-				}
+			}
 
-			case entry.GlobalSecondaryIndex != nil:
-				gsi := entry.GlobalSecondaryIndex
-				req.GlobalSecondaryIndexes = append(req.GlobalSecondaryIndexes, &dynamodb.GlobalSecondaryIndex{
-					IndexName: &gsi.Name,
-					KeySchema: []*dynamodb.KeySchemaElement{
-						{AttributeName: &gsi.PartitionKey, KeyType: aws.String("HASH")},
-						{AttributeName: &gsi.SortKey, KeyType: aws.String("RANGE")},
-					},
-					Projection:            mapProjection(gsi.Projection),
-					ProvisionedThroughput: mapProvisionedThroughput(gsi.ProvisionedThroughput),
-				})
+		case entry.GlobalSecondaryIndex != nil:
+			gsi := entry.GlobalSecondaryIndex
+			if gsi.ProvisionedThroughput != nil {
+				hasThroughput = true
+			}
+			req.GlobalSecondaryIndexes = append(req.GlobalSecondaryIndexes, &dynamodb.GlobalSecondaryIndex{
+				IndexName: &gsi.Name,
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: &gsi.PartitionKey, KeyType: aws.String("HASH")},
+					{AttributeName: &gsi.SortKey, KeyType: aws.String("RANGE")},
+				},
+				Projection:            mapProjection(gsi.Projection),
+				ProvisionedThroughput: mapProvisionedThroughput(gsi.ProvisionedThroughput),
+			})
 
-			case entry.LocalSecondaryIndex != nil:
-				lsi := entry.LocalSecondaryIndex
-				req.LocalSecondaryIndexes = append(req.LocalSecondaryIndexes, &dynamodb.LocalSecondaryIndex{
-					IndexName: &lsi.Name,
-					KeySchema: []*dynamodb.KeySchemaElement{
-						{AttributeName: &lsi.SortKey, KeyType: aws.String("RANGE")},
-					},
-					Projection: mapProjection(lsi.Projection),
-				})
+		case entry.LocalSecondaryIndex != nil:
+			lsi := entry.LocalSecondaryIndex
+			req.LocalSecondaryIndexes = append(req.LocalSecondaryIndexes, &dynamodb.LocalSecondaryIndex{
+				IndexName: &lsi.Name,
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: &lsi.SortKey, KeyType: aws.String("RANGE")},
+				},
+				Projection: mapProjection(lsi.Projection),
+			})
+
+		case entry.ProvisionedThroughput != nil:
+			hasThroughput = true
+			req.ProvisionedThroughput = mapProvisionedThroughput(entry.ProvisionedThroughput)
 
-			case entry.ProvisionedThroughput != nil:
-				req.ProvisionedThroughput = mapProvisionedThroughput(entry.ProvisionedThroughput)
+		case entry.BillingMode != nil:
+			payPerRequest = entry.BillingMode.PayPerRequest
 
-			default:
-				panic(repr.String(entry))
+		default:
+			panic(repr.String(entry))
+		}
+	}
+	if err := checkCreateTableKeys(req); err != nil {
+		return nil, err
+	}
+	if payPerRequest {
+		if hasThroughput {
+			return nil, errors.New("PROVISIONED THROUGHPUT cannot be used with BILLING MODE PAY_PER_REQUEST")
+		}
+		// On-demand tables and their indexes must not specify throughput.
+		req.BillingMode = aws.String(dynamodb.BillingModePayPerRequest)
+		req.ProvisionedThroughput = nil
+	}
+	return req, nil
+}
+
+// checkCreateTableKeys checks that the table has a HASH KEY, and that every key attribute of its indexes is declared
+// with a type, which DynamoDB requires in the attribute definitions.
+func checkCreateTableKeys(req *dynamodb.CreateTableInput) error {
+	defined := map[string]bool{}
+	for _, def := range req.AttributeDefinitions {
+		defined[*def.AttributeName] = true
+	}
+	keys := map[string]int{}
+	for _, key := range req.KeySchema {
+		keys[*key.KeyType]++
+	}
+	switch {
+	case keys[dynamodb.KeyTypeHash] != 1:
+		return fmt.Errorf("table %q must have exactly one HASH KEY attribute", *req.TableName)
+	case keys[dynamodb.KeyTypeRange] > 1:
+		return fmt.Errorf("table %q can have at most one RANGE KEY attribute", *req.TableName)
+	}
+	check := func(index string, keySchema []*dynamodb.KeySchemaElement) error {
+		for _, key := range keySchema {
+			if !defined[*key.AttributeName] {
+				return fmt.Errorf("key attribute %q of index %q is not defined, declare it with %s STRING, NUMBER or BINARY", *key.AttributeName, index, *key.AttributeName)
 			}
 		}
-		_, err := dynamo.CreateTableWithContext(ctx, req)
-		return &DriverResult{count: 0}, err
-	}), nil
+		return nil
+	}
+	for _, gsi := range req.GlobalSecondaryIndexes {
+		if err := check(*gsi.IndexName, gsi.KeySchema); err != nil {
+			return err
+		}
+	}
+	for _, lsi := range req.LocalSecondaryIndexes {
+		if err := check(*lsi.IndexName, lsi.KeySchema); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func mapProjection(projection *parser.Projection) *dynamodb.Projection {
@@ -93,6 +192,9 @@ func mapProjection(projection *parser.Projection) *dynamodb.Projection {
 }
 
 func mapProvisionedThroughput(throughput *parser.ProvisionedThroughput) *dynamodb.ProvisionedThroughput {
+	if throughput == nil {
+		return nil
+	}
 	return &dynamodb.ProvisionedThroughput{
 		ReadCapacityUnits:  &throughput.ReadCapacityUnits,
 		WriteCapacityUnits: &throughput.WriteCapacityUnits,
@@ -0,0 +1,76 @@
+package querybuilder
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+func TestCreateTableKeyAttributes(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		query string
+		err   string
+	}{
+		{"no hash key", `CREATE TABLE movies (title STRING, year NUMBER RANGE KEY)`,
+			`table "movies" must have exactly one HASH KEY attribute`},
+		{"two hash keys", `CREATE TABLE movies (title STRING HASH KEY, year NUMBER HASH KEY)`,
+			`table "movies" must have exactly one HASH KEY attribute`},
+		{"two range keys", `CREATE TABLE movies (title STRING HASH KEY, year NUMBER RANGE KEY, rating NUMBER RANGE KEY)`,
+			`table "movies" can have at most one RANGE KEY attribute`},
+		{"undefined global index key", `CREATE TABLE movies (title STRING HASH KEY, ` +
+			`GLOBAL SECONDARY INDEX director_title HASH(director) RANGE(title) PROJECTION ALL)`,
+			`key attribute "director" of index "director_title" is not defined, declare it with director STRING, NUMBER or BINARY`},
+		{"undefined local index key", `CREATE TABLE movies (title STRING HASH KEY, year NUMBER RANGE KEY, ` +
+			`LOCAL SECONDARY INDEX title_rating RANGE(rating) PROJECTION KEYS ONLY)`,
+			`key attribute "rating" of index "title_rating" is not defined, declare it with rating STRING, NUMBER or BINARY`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ast, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			_, err = buildCreateTableInput(ast.CreateTable)
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestCreateTableBillingMode(t *testing.T) {
+	build := func(t *testing.T, query string) (*dynamodb.CreateTableInput, error) {
+		t.Helper()
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		return buildCreateTableInput(ast.CreateTable)
+	}
+
+	t.Run("provisioned by default", func(t *testing.T) {
+		req, err := build(t, `CREATE TABLE movies (title STRING HASH KEY, PROVISIONED THROUGHPUT READ 1 WRITE 2)`)
+		require.NoError(t, err)
+		require.Nil(t, req.BillingMode)
+		require.Equal(t, int64(1), aws.Int64Value(req.ProvisionedThroughput.ReadCapacityUnits))
+		require.Equal(t, int64(2), aws.Int64Value(req.ProvisionedThroughput.WriteCapacityUnits))
+	})
+
+	t.Run("pay per request", func(t *testing.T) {
+		req, err := build(t, `CREATE TABLE movies (title STRING HASH KEY, year NUMBER, `+
+			`GLOBAL SECONDARY INDEX year_title HASH(year) RANGE(title) PROJECTION ALL, BILLING MODE PAY_PER_REQUEST)`)
+		require.NoError(t, err)
+		require.Equal(t, dynamodb.BillingModePayPerRequest, aws.StringValue(req.BillingMode))
+		require.Nil(t, req.ProvisionedThroughput)
+		require.Nil(t, req.GlobalSecondaryIndexes[0].ProvisionedThroughput)
+	})
+
+	t.Run("pay per request rejects throughput", func(t *testing.T) {
+		for _, query := range []string{
+			`CREATE TABLE movies (title STRING HASH KEY, BILLING MODE PAY_PER_REQUEST, PROVISIONED THROUGHPUT READ 1 WRITE 1)`,
+			`CREATE TABLE movies (title STRING HASH KEY, year NUMBER, BILLING MODE PAY_PER_REQUEST, ` +
+				`GLOBAL SECONDARY INDEX year_title HASH(year) RANGE(title) PROJECTION ALL PROVISIONED THROUGHPUT READ 1 WRITE 1)`,
+		} {
+			_, err := build(t, query)
+			require.EqualError(t, err, "PROVISIONED THROUGHPUT cannot be used with BILLING MODE PAY_PER_REQUEST", query)
+		}
+	})
+}
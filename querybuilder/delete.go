@@ -0,0 +1,160 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/alecthomas/repr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// PreparedDelete is a DELETE compiled into a DeleteItem request. Like UPDATE, the WHERE clause must pin each key
+// attribute of the table with an equality condition, and any other conditions are checked with a
+// ConditionExpression.
+type PreparedDelete struct {
+	Delete *dynamodb.DeleteItemInput
+	// KeyParams maps each key attribute to the placeholder its value is bound from.
+	KeyParams map[string]string
+	// ValueParams are the placeholders used by the condition expression.
+	ValueParams      []string
+	NamedParams      NamedParams
+	PositionalParams map[int]string
+	FixedParams      map[string]interface{}
+	ListParams       NamedParams
+	// Conditional is set if the WHERE clause has conditions other than the key, such as a version check. Do then
+	// returns the ConditionalCheckFailedException of an item that does not match them.
+	Conditional bool
+	// FailOnMissingItem makes Do return the ConditionalCheckFailedException of an item that does not exist even if
+	// the WHERE clause only has the key, rather than affecting no rows.
+	FailOnMissingItem bool
+	// listCondition compiles the condition expression again once a list param is bound to a slice.
+	listCondition *listExpression
+}
+
+var (
+	_ ExecStmt          = &PreparedDelete{}
+	_ TransactWriteStmt = &PreparedDelete{}
+)
+
+func PrepareDelete(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) (*PreparedDelete, error) {
+	if ast.Delete == nil {
+		return nil, fmt.Errorf("expected DELETE but got %s", repr.String(ast))
+	}
+	table, err := tables.Get(ctx, ast.Delete.From)
+	if err != nil {
+		return nil, err
+	}
+	return prepareDelete(table, ast.Delete)
+}
+
+func prepareDelete(table *schema.Table, del *parser.Delete) (*PreparedDelete, error) {
+	ctx := NewContext(table, "")
+	if err := prepareValuesAndPlaceholders(ctx, del); err != nil {
+		return nil, err
+	}
+	if len(ctx.PositionalParams) > 0 && len(ctx.NamedParams) > 0 {
+		return nil, errors.New("cannot mix positional params (?) with named params (:param)")
+	}
+
+	if err := checkItemWhere("DELETE", table, del.Where); err != nil {
+		return nil, err
+	}
+	kf := extractKeyExpressions(del.Where.Conjunction(), ctx.IsKey)
+	keyParams, err := itemKeyParams("DELETE", table, kf.Key)
+	if err != nil {
+		return nil, err
+	}
+	conditionExpr, err := itemConditionExpression(&visitor{Context: ctx}, kf.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreparedDelete{
+		Delete: &dynamodb.DeleteItemInput{
+			TableName:                &table.Name,
+			ConditionExpression:      aws.String(conditionExpr),
+			ExpressionAttributeNames: ctx.ExpressionAttributeNames(),
+			ReturnValues:             del.Returning,
+		},
+		KeyParams:        keyParams,
+		ValueParams:      expressionPlaceholders(conditionExpr),
+		NamedParams:      ctx.NamedParams,
+		PositionalParams: ctx.PositionalParams,
+		FixedParams:      ctx.FixedParams,
+		ListParams:       ctx.ListParams,
+		Conditional:      len(kf.Filter.And) > 0,
+		listCondition:    newListExpression(ctx, false, true, kf.Filter),
+	}, nil
+}
+
+// NumInput returns the number of arguments the delete expects to be bound.
+func (p *PreparedDelete) NumInput() int {
+	return len(p.NamedParams) + len(p.PositionalParams)
+}
+
+// NewRequest binds the arguments into a DeleteItem request.
+func (p *PreparedDelete) NewRequest(args []driver.NamedValue) (*dynamodb.DeleteItemInput, error) {
+	values, lists, err := bindArgs(p.FixedParams, p.NamedParams, p.PositionalParams, p.ListParams, args)
+	if err != nil {
+		return nil, err
+	}
+	req := *p.Delete
+	req.Key, req.ExpressionAttributeValues, err = bindItem(p.KeyParams, p.ValueParams, values, lists)
+	if err != nil {
+		return nil, err
+	}
+	req.ConditionExpression, err = p.listCondition.expand(req.ConditionExpression, lists)
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// Do deletes the item with DeleteItem. If the WHERE clause has conditions other than the key, the
+// ConditionalCheckFailedException of an item that does not match them, or does not exist, is returned. Otherwise a
+// missing item is not deleted and zero rows are affected, unless FailOnMissingItem is set.
+func (p *PreparedDelete) Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
+	req, err := p.NewRequest(args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := dynamo.DeleteItemWithContext(ctx, req)
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException && !p.Conditional && !p.FailOnMissingItem {
+		return &DriverResult{count: 0}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &DriverResult{
+		count:    1,
+		returned: resp.Attributes,
+	}, nil
+}
+
+// TransactWriteItems returns the delete as a Delete. If its condition fails, the whole transaction is cancelled.
+func (p *PreparedDelete) TransactWriteItems(args []driver.NamedValue) ([]*dynamodb.TransactWriteItem, error) {
+	if p.Delete.ReturnValues != nil && *p.Delete.ReturnValues != "NONE" {
+		return nil, errors.New("cannot use RETURNING in a transaction")
+	}
+	req, err := p.NewRequest(args)
+	if err != nil {
+		return nil, err
+	}
+	return []*dynamodb.TransactWriteItem{{
+		Delete: &dynamodb.Delete{
+			TableName:                 req.TableName,
+			Key:                       req.Key,
+			ConditionExpression:       req.ConditionExpression,
+			ExpressionAttributeNames:  req.ExpressionAttributeNames,
+			ExpressionAttributeValues: req.ExpressionAttributeValues,
+		},
+	}}, nil
+}
@@ -0,0 +1,62 @@
+package querybuilder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alecthomas/repr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// DescribeColumns are the columns of the rows returned by DESCRIBE.
+var DescribeColumns = []string{"attribute", "type", "key_type", "index"}
+
+// PreparedDescribe is a DESCRIBE, which lists the key schema of a table and of each of its secondary indexes.
+type PreparedDescribe struct {
+	Table string
+}
+
+func PrepareDescribe(ast *parser.AST) (*PreparedDescribe, error) {
+	if ast.Describe == nil {
+		return nil, fmt.Errorf("expected DESCRIBE but got %s", repr.String(ast))
+	}
+	return &PreparedDescribe{Table: ast.Describe.Table}, nil
+}
+
+// Rows calls DescribeTable and returns a row per key attribute, in DescribeColumns order. The key attributes of the
+// table come first, with an empty index, followed by those of the local then the global secondary indexes. The
+// schema is always fetched from DynamoDB, rather than the schema cache, so that it reflects indexes that are being
+// created or deleted.
+func (p *PreparedDescribe) Rows(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) ([][]string, error) {
+	resp, err := dynamo.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(p.Table)})
+	if err != nil {
+		return nil, err
+	}
+	return describeRows(resp.Table), nil
+}
+
+func describeRows(desc *dynamodb.TableDescription) [][]string {
+	types := map[string]string{}
+	for _, def := range desc.AttributeDefinitions {
+		types[aws.StringValue(def.AttributeName)] = aws.StringValue(def.AttributeType)
+	}
+	var rows [][]string
+	add := func(index string, keySchema []*dynamodb.KeySchemaElement) {
+		for _, key := range keySchema {
+			name := aws.StringValue(key.AttributeName)
+			rows = append(rows, []string{name, types[name], aws.StringValue(key.KeyType), index})
+		}
+	}
+	add("", desc.KeySchema)
+	for _, lsi := range desc.LocalSecondaryIndexes {
+		add(aws.StringValue(lsi.IndexName), lsi.KeySchema)
+	}
+	for _, gsi := range desc.GlobalSecondaryIndexes {
+		add(aws.StringValue(gsi.IndexName), gsi.KeySchema)
+	}
+	return rows
+}
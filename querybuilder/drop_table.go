@@ -0,0 +1,36 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/alecthomas/repr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// PrepareDropTable prepares a DROP TABLE. The table is removed from the schema cache once it is deleted. With
+// IF EXISTS, dropping a table that does not exist affects zero rows instead of returning an error.
+func PrepareDropTable(ast *parser.AST, tables *schema.TableLoader) (ExecStmt, error) {
+	stmt := ast.DropTable
+	if stmt == nil {
+		return nil, fmt.Errorf("expected DROP TABLE but got %s", repr.String(ast))
+	}
+	return execStatementFunc(func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
+		_, err := dynamo.DeleteTableWithContext(ctx, &dynamodb.DeleteTableInput{TableName: aws.String(stmt.Table)})
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == dynamodb.ErrCodeResourceNotFoundException && stmt.IfExists {
+			return &DriverResult{count: 0}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		tables.Forget(stmt.Table)
+		return &DriverResult{count: 1}, nil
+	}), nil
+}
@@ -13,12 +13,21 @@ type ExecStmt interface {
 	Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error)
 }
 
+// TransactWriteStmt is implemented by statements that can be executed as part of a TransactWriteItems call.
+type TransactWriteStmt interface {
+	TransactWriteItems(args []driver.NamedValue) ([]*dynamodb.TransactWriteItem, error)
+}
+
 type execStatementFunc func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error)
 
 func (e execStatementFunc) Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
 	return e(ctx, dynamo, args)
 }
 
+// ErrNoLastInsertID is returned by the LastInsertId of every result, as DynamoDB has no auto-increment keys.
+var ErrNoLastInsertID = errors.New("LastInsertId is not supported, DynamoDB has no auto-increment keys")
+
+// DriverResult is the result of a statement run with Exec.
 type DriverResult struct {
 	count    int
 	returned map[string]*dynamodb.AttributeValue
@@ -26,14 +35,24 @@ type DriverResult struct {
 
 var _ driver.Result = &DriverResult{}
 
+// NewDriverResult returns a result that affected the given number of items.
+func NewDriverResult(rowsAffected int) *DriverResult {
+	return &DriverResult{count: rowsAffected}
+}
+
+// LastInsertId always returns ErrNoLastInsertID.
 func (i *DriverResult) LastInsertId() (int64, error) {
-	return 0, errors.New("LastInsertId is not supported")
+	return 0, ErrNoLastInsertID
 }
 
+// RowsAffected returns the number of items written, updated or deleted: each item of an INSERT or REPLACE, and one
+// for an UPDATE or DELETE, unless its WHERE clause did not match an item. Statements that change tables rather than
+// items affect none, except DROP TABLE, which affects the table it drops.
 func (i *DriverResult) RowsAffected() (int64, error) {
 	return int64(i.count), nil
 }
 
+// Item returns the attributes returned by RETURNING, if any.
 func (i *DriverResult) Item() map[string]*dynamodb.AttributeValue {
 	return i.returned
 }
@@ -0,0 +1,208 @@
+package querybuilder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/repr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// ExplainColumns are the columns of the row returned by EXPLAIN.
+var ExplainColumns = []string{
+	"operation", "table", "index", "key_condition", "filter", "projection", "update", "attribute_names",
+	"attribute_values", "plan",
+}
+
+// PreparedExplain is an EXPLAIN, which describes the request a SELECT, UPDATE or DELETE is compiled into, without
+// sending it. The statement is compiled exactly as it would be to run it, so EXPLAIN fails if the statement would.
+type PreparedExplain struct {
+	// Row describes the request, in ExplainColumns order.
+	Row []string
+}
+
+func PrepareExplain(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) (*PreparedExplain, error) {
+	if ast.Explain == nil {
+		return nil, fmt.Errorf("expected EXPLAIN but got %s", repr.String(ast))
+	}
+	stmt := ast.Explain.Statement
+	switch {
+	case stmt.Select != nil:
+		table, err := tables.Get(ctx, stmt.Select.From)
+		if err != nil {
+			return nil, err
+		}
+		pq, err := PrepareSelect(table, stmt.Select)
+		if err != nil {
+			return nil, err
+		}
+		return &PreparedExplain{Row: explainQuery(table, pq)}, nil
+	case stmt.Update != nil:
+		table, err := tables.Get(ctx, stmt.Update.Table)
+		if err != nil {
+			return nil, err
+		}
+		p, err := prepareUpdate(table, stmt.Update)
+		if err != nil {
+			return nil, err
+		}
+		req := p.Update
+		return &PreparedExplain{Row: explainRow{
+			operation: "UpdateItem",
+			table:     table.Name,
+			key:       explainItemKey(table, p.KeyParams),
+			filter:    aws.StringValue(req.ConditionExpression),
+			update:    aws.StringValue(req.UpdateExpression),
+			names:     req.ExpressionAttributeNames,
+			values:    explainValues(p.FixedParams, p.NamedParams, p.PositionalParams),
+			plan:      fmt.Sprintf("UpdateItem table %q", table.Name),
+		}.strings()}, nil
+	case stmt.Delete != nil:
+		table, err := tables.Get(ctx, stmt.Delete.From)
+		if err != nil {
+			return nil, err
+		}
+		p, err := prepareDelete(table, stmt.Delete)
+		if err != nil {
+			return nil, err
+		}
+		req := p.Delete
+		return &PreparedExplain{Row: explainRow{
+			operation: "DeleteItem",
+			table:     table.Name,
+			key:       explainItemKey(table, p.KeyParams),
+			filter:    aws.StringValue(req.ConditionExpression),
+			names:     req.ExpressionAttributeNames,
+			values:    explainValues(p.FixedParams, p.NamedParams, p.PositionalParams),
+			plan:      fmt.Sprintf("DeleteItem table %q", table.Name),
+		}.strings()}, nil
+	default:
+		return nil, fmt.Errorf("EXPLAIN only supports SELECT, UPDATE and DELETE, got: %s", stmt)
+	}
+}
+
+// Rows returns the row describing the request. DynamoDB is not called.
+func (p *PreparedExplain) Rows(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) ([][]string, error) {
+	return [][]string{p.Row}, nil
+}
+
+// explainRow is the row of an EXPLAIN, before it is formatted.
+type explainRow struct {
+	operation, table, index, key, filter, projection, update string
+	names                                                    map[string]*string
+	values                                                   []string
+	plan                                                     string
+}
+
+func (r explainRow) strings() []string {
+	names := make([]string, 0, len(r.names))
+	for alias, name := range r.names {
+		names = append(names, fmt.Sprintf("%s = %s", alias, aws.StringValue(name)))
+	}
+	sort.Strings(names)
+	return []string{
+		r.operation, r.table, r.index, r.key, r.filter, r.projection, r.update, strings.Join(names, ", "),
+		strings.Join(r.values, ", "), r.plan,
+	}
+}
+
+func explainQuery(table *schema.Table, pq *PreparedQuery) []string {
+	row := explainRow{
+		values: explainValues(pq.FixedParams, pq.NamedParams, pq.PositionalParams),
+		plan:   pq.Plan,
+	}
+	switch {
+	case pq.Query != nil:
+		req := pq.Query
+		row.operation, row.table, row.index = "Query", aws.StringValue(req.TableName), aws.StringValue(req.IndexName)
+		row.key = aws.StringValue(req.KeyConditionExpression)
+		row.filter = aws.StringValue(req.FilterExpression)
+		row.projection = aws.StringValue(req.ProjectionExpression)
+		row.names = req.ExpressionAttributeNames
+	case pq.Scan != nil:
+		req := pq.Scan
+		row.operation, row.table, row.index = "Scan", aws.StringValue(req.TableName), aws.StringValue(req.IndexName)
+		row.filter = aws.StringValue(req.FilterExpression)
+		row.projection = aws.StringValue(req.ProjectionExpression)
+		row.names = req.ExpressionAttributeNames
+	case pq.GetItem != nil:
+		req := pq.GetItem
+		row.operation, row.table = "GetItem", aws.StringValue(req.TableName)
+		row.key = explainItemKey(table, pq.KeyParams)
+		row.projection = aws.StringValue(req.ProjectionExpression)
+		row.names = req.ExpressionAttributeNames
+	case pq.BatchGet != nil:
+		for table, req := range pq.BatchGet.RequestItems {
+			row.operation, row.table = "BatchGetItem", table
+			row.projection = aws.StringValue(req.ProjectionExpression)
+			row.names = req.ExpressionAttributeNames
+		}
+		row.key = explainKey(table, pq.BatchKeyParams)
+	}
+	return row.strings()
+}
+
+// explainItemKey describes the key of the single item a statement reads or writes.
+func explainItemKey(table *schema.Table, keyParams map[string]string) string {
+	keys := make(map[string][]string, len(keyParams))
+	for attr, param := range keyParams {
+		keys[attr] = []string{param}
+	}
+	return explainKey(table, keys)
+}
+
+// explainKey describes the values each key attribute is looked up with, as a condition on the partition key and
+// then the sort key.
+func explainKey(table *schema.Table, keys map[string][]string) string {
+	var conds []string
+	for _, attr := range []string{table.HashKey, table.SortKey} {
+		params, ok := keys[attr]
+		if !ok {
+			continue
+		}
+		if len(params) == 1 {
+			conds = append(conds, fmt.Sprintf("%s = %s", attr, params[0]))
+		} else {
+			conds = append(conds, fmt.Sprintf("%s IN (%s)", attr, strings.Join(params, ", ")))
+		}
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// explainValues describes the expression attribute values, sorted by placeholder. Literals are shown with their
+// value, and placeholders bound from arguments with a ?, as EXPLAIN does not bind arguments.
+func explainValues(fixedParams map[string]interface{}, namedParams NamedParams, positionalParams map[int]string) []string {
+	values := make([]string, 0, len(fixedParams)+len(namedParams)+len(positionalParams))
+	for name, value := range fixedParams {
+		values = append(values, fmt.Sprintf("%s = %s", name, explainLiteral(value)))
+	}
+	for name := range namedParams {
+		values = append(values, name+" = ?")
+	}
+	for _, name := range positionalParams {
+		values = append(values, name+" = ?")
+	}
+	sort.Strings(values)
+	return values
+}
+
+func explainLiteral(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return strconv.Quote(value)
+	case json.Number:
+		return value.String()
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(value))
+	default:
+		return "NULL"
+	}
+}
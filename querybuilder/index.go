@@ -0,0 +1,274 @@
+package querybuilder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// Selectivity of the sort key condition of a candidate table or index, from least to most selective.
+const (
+	sortKeyUnconstrained = iota
+	sortKeyRange
+	sortKeyEqual
+)
+
+// indexCandidate is the base table or a secondary index that a SELECT without USE INDEX could Query.
+type indexCandidate struct {
+	// index is the name of the secondary index, or empty for the base table.
+	index       string
+	hashKey     string
+	sortKey     string
+	selectivity int
+	covering    bool
+}
+
+func (c *indexCandidate) better(other *indexCandidate) bool {
+	if c.selectivity != other.selectivity {
+		return c.selectivity > other.selectivity
+	}
+	if c.covering != other.covering {
+		return c.covering
+	}
+	// Otherwise prefer the base table, then the index declared first.
+	return c.index == "" && other.index != ""
+}
+
+// selectIndex picks the table or secondary index to Query for a SELECT without USE INDEX. Every candidate must have
+// an equality condition on its partition key. Of those, the one with the most selective sort key condition is used,
+// preferring one that projects every attribute the statement reads. It returns ok=false if there is nothing to
+// Query, in which case the table is scanned.
+func selectIndex(table *schema.Table, ast *parser.Select) (candidate *indexCandidate, ok bool) {
+	attrs, all := referencedAttributes(ast)
+	candidates := []*indexCandidate{{hashKey: table.HashKey, sortKey: table.SortKey, covering: true}}
+	for i := range table.Indexes {
+		idx := &table.Indexes[i]
+		if idx.Global && ast.Consistent() {
+			// Global secondary indexes do not support consistent reads.
+			continue
+		}
+		covering := idx.Projection == dynamodb.ProjectionTypeAll
+		if !covering && !all {
+			covering = true
+			for _, attr := range attrs {
+				if !idx.Projects(table, attr) {
+					covering = false
+					break
+				}
+			}
+		}
+		// A Query on a local secondary index fetches attributes that are not projected from the table, but only if
+		// they are named in the projection expression. A global secondary index can only return what it projects.
+		if !covering && (idx.Global || all) {
+			continue
+		}
+		candidates = append(candidates, &indexCandidate{
+			index:    idx.Name,
+			hashKey:  idx.HashKey,
+			sortKey:  idx.SortKey,
+			covering: covering,
+		})
+	}
+
+	var best *indexCandidate
+	for _, c := range candidates {
+		if !c.matchOrder(ast) {
+			continue
+		}
+		var valid bool
+		c.selectivity, valid = keySelectivity(ast.Where.Conjunction(), c.hashKey, c.sortKey)
+		// An invalid key condition on the base table is still used, so that the error is reported rather than
+		// silently falling back to a Scan.
+		if !valid && (c.index != "" || !hasHashKeyCondition(ast.Where.Conjunction(), c.hashKey)) {
+			continue
+		}
+		if best == nil || c.better(best) {
+			best = c
+		}
+	}
+	return best, best != nil
+}
+
+// matchOrder returns true if querying the candidate returns items in the order the statement asks for. A bare
+// ASC/DESC orders by the sort key of the table, so is only satisfied by the base table.
+func (c *indexCandidate) matchOrder(ast *parser.Select) bool {
+	switch {
+	case ast.OrderBy != nil:
+		return isAttribute(ast.OrderBy.Path, c.sortKey)
+	case ast.Descending != nil:
+		return c.index == ""
+	default:
+		return true
+	}
+}
+
+// keySelectivity returns how selective the key condition on hashKey and sortKey is. valid is false unless the WHERE
+// clause has exactly one equality condition on hashKey and at most one condition on sortKey that DynamoDB accepts
+// in a KeyConditionExpression, once a range on sortKey is folded into BETWEEN.
+func keySelectivity(where *parser.AndExpression, hashKey, sortKey string) (selectivity int, valid bool) {
+	if where == nil {
+		return sortKeyUnconstrained, false
+	}
+	where = foldSortKeyRange(where, sortKey)
+	valid = true
+	hashConditions, sortConditions := 0, 0
+	for _, term := range where.And {
+		switch {
+		case term.Function != nil && term.Function.FirstArgIsRef():
+			path := term.Function.Args[0].DocumentPath
+			if isAttribute(path, hashKey) {
+				valid = false
+			} else if isAttribute(path, sortKey) {
+				sortConditions++
+				if term.Function.Function == "begins_with" {
+					selectivity = sortKeyRange
+				} else {
+					valid = false
+				}
+			}
+		case term.Operand != nil && term.Operand.Size != nil:
+			if path := term.Operand.Path(); isAttribute(path, hashKey) || isAttribute(path, sortKey) {
+				valid = false
+			}
+		case term.Operand != nil:
+			path := term.Operand.Operand
+			rhs := term.Operand.ConditionRHS
+			if isAttribute(path, hashKey) {
+				hashConditions++
+				if rhs.Compare == nil || rhs.Compare.Operator != "=" {
+					valid = false
+				}
+			} else if isAttribute(path, sortKey) {
+				sortConditions++
+				switch {
+				case checkSortKeyCondition(sortKey, rhs) != nil:
+					valid = false
+				case rhs.Compare != nil && rhs.Compare.Operator == "=":
+					selectivity = sortKeyEqual
+				default:
+					selectivity = sortKeyRange
+				}
+			}
+		}
+	}
+	return selectivity, valid && hashConditions == 1 && sortConditions <= 1
+}
+
+// referencedAttributes returns the top level attributes read by the projection and WHERE clause of a SELECT. all is
+// true if the whole item is returned.
+func referencedAttributes(ast *parser.Select) (attrs []string, all bool) {
+	seen := map[string]bool{}
+	collect := func(node parser.Node, next func() error) error {
+		if path, ok := node.(*parser.DocumentPath); ok && len(path.Fragment) > 0 {
+			attr := path.Fragment[0].Symbol
+			if !seen[attr] {
+				seen[attr] = true
+				attrs = append(attrs, attr)
+			}
+		}
+		return next()
+	}
+	if ast.Where != nil {
+		_ = parser.Visit(ast.Where, collect)
+	}
+	if ast.Projection != nil {
+		_ = parser.Visit(ast.Projection, collect)
+	}
+	return attrs, ast.Projection != nil && ast.Projection.All
+}
+
+// ErrNotProjected is returned for a SELECT with USE INDEX that reads an attribute the global secondary index does not
+// project.
+var ErrNotProjected = errors.New("attribute is not projected into the index")
+
+// checkQueriedIndex returns an error wrapping ErrNotProjected if a SELECT that queries the global secondary index given
+// with USE INDEX reads attributes it does not project. A Query of a global secondary index does not fetch them from the
+// table, so a column of one would always be NULL, and a condition on one would match nothing. SELECT * returns the
+// attributes the index projects. A local secondary index fetches the others from the table.
+func checkQueriedIndex(table *schema.Table, idx *schema.Index, ast *parser.Select) error {
+	if !idx.Global || idx.Projection == dynamodb.ProjectionTypeAll {
+		return nil
+	}
+	attrs, _ := referencedAttributes(ast)
+	for _, attr := range attrs {
+		if !idx.Projects(table, attr) {
+			return fmt.Errorf("%w: global secondary index %q does not project %q, query the table or an index that projects it instead", ErrNotProjected, idx.Name, attr)
+		}
+	}
+	return nil
+}
+
+// checkScannedIndex returns an error if a SELECT with WITH (SCAN) and USE INDEX reads attributes that the index can't
+// return. A Scan of a global secondary index only sees the attributes it projects, so filtering on any other
+// attribute would silently match nothing. A local secondary index fetches the others from the table.
+func checkScannedIndex(table *schema.Table, idx *schema.Index, ast *parser.Select) error {
+	if !idx.Global || idx.Projection == dynamodb.ProjectionTypeAll {
+		return nil
+	}
+	attrs, all := referencedAttributes(ast)
+	if all {
+		return fmt.Errorf("WITH (SCAN) of global secondary index %q can not return whole items, it does not project every attribute", idx.Name)
+	}
+	for _, attr := range attrs {
+		if !idx.Projects(table, attr) {
+			return fmt.Errorf("WITH (SCAN) of global secondary index %q can not read %q, it is not projected into the index", idx.Name, attr)
+		}
+	}
+	return nil
+}
+
+// describePlan returns a human readable description of how a SELECT is executed, for PreparedQuery.Plan.
+func describePlan(table *schema.Table, index string, auto bool, scan bool, segments int) string {
+	op := "Query"
+	if scan {
+		op = "Scan"
+	}
+	plan := fmt.Sprintf("%s table %q", op, table.Name)
+	if index != "" {
+		plan = fmt.Sprintf("%s index %q of table %q", op, index, table.Name)
+	}
+	if auto {
+		plan += " (index selected automatically)"
+	}
+	if segments > 1 {
+		plan += fmt.Sprintf(" in %d parallel segments", segments)
+	}
+	return plan
+}
+
+// scanReason describes the equality conditions on a partition key that would let a SELECT Query the table, or the
+// index given with USE INDEX, rather than Scan it. It also names the indexes that have such a condition but could not
+// be queried, such as a global secondary index that does not project every attribute read.
+func scanReason(table *schema.Table, index string, where *parser.AndExpression) string {
+	type candidate struct{ what, hashKey string }
+	candidates := []candidate{{fmt.Sprintf("table %q", table.Name), table.HashKey}}
+	if index != "" {
+		candidates = []candidate{{fmt.Sprintf("index %q", index), table.GetIndex(index).HashKey}}
+	} else {
+		for _, idx := range table.Indexes {
+			// Local secondary indexes share the partition key of the table.
+			if idx.Global {
+				candidates = append(candidates, candidate{fmt.Sprintf("index %q", idx.Name), idx.HashKey})
+			}
+		}
+	}
+	var missing, unusable []string
+	for _, c := range candidates {
+		if hasHashKeyCondition(where, c.hashKey) {
+			unusable = append(unusable, c.what)
+		} else {
+			missing = append(missing, fmt.Sprintf("%s = value for %s", c.hashKey, c.what))
+		}
+	}
+	// The partition key of the table, or of the given index, is always missing, or there would be a Query.
+	reason := "WHERE needs " + strings.Join(missing, ", or ") + ", ANDed with its other conditions, to Query"
+	if len(unusable) > 0 {
+		reason += fmt.Sprintf("; %s can not be queried by this SELECT", strings.Join(unusable, " and "))
+	}
+	return reason
+}
@@ -0,0 +1,75 @@
+package querybuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+func TestSelectIndex(t *testing.T) {
+	table := schema.NewTableFromCreate(fixtures.GameScores.Create)
+	tests := []struct {
+		name  string
+		query string
+		plan  string
+	}{
+		{
+			name:  "PartitionKeyOfTable",
+			query: `SELECT * FROM gamescores WHERE UserId = "103"`,
+			plan:  `Query table "gamescores"`,
+		},
+		{
+			name:  "SortKeyOfLocalIndexIsMoreSelective",
+			query: `SELECT UserId, Wins FROM gamescores WHERE UserId = "103" AND Wins > 3`,
+			plan:  `Query index "UserWinsIndex" of table "gamescores" (index selected automatically)`,
+		},
+		{
+			name:  "SortKeyOfTableIsMoreSelective",
+			query: `SELECT UserId, Wins FROM gamescores WHERE UserId = "103" AND GameTitle = "Meteor Blasters" AND Wins > 3`,
+			plan:  `Query table "gamescores"`,
+		},
+		{
+			name:  "PreferCoveringOnTie",
+			query: `SELECT UserId, Wins, TopScore FROM gamescores WHERE UserId = "103" AND Wins > 3 AND GameTitle > "M"`,
+			plan:  `Query table "gamescores"`,
+		},
+		{
+			name:  "GlobalIndexCoversKeys",
+			query: `SELECT UserId FROM gamescores WHERE GameTitle = "Meteor Blasters"`,
+			plan:  `Query index "GameTitleIndex" of table "gamescores" (index selected automatically)`,
+		},
+		{
+			name:  "GlobalIndexDoesNotCoverFilter",
+			query: `SELECT UserId FROM gamescores WHERE GameTitle = "Meteor Blasters" AND Wins > 3`,
+			plan:  `Scan table "gamescores"`,
+		},
+		{
+			name:  "InvalidSortKeyConditionOnIndex",
+			query: `SELECT UserId FROM gamescores WHERE UserId = "103" AND Wins IN (1, 2)`,
+			plan:  `Query table "gamescores"`,
+		},
+		{
+			name:  "DescendingUsesTable",
+			query: `SELECT UserId, Wins FROM gamescores WHERE UserId = "103" AND Wins > 3 DESC`,
+			plan:  `Query table "gamescores"`,
+		},
+		{
+			name:  "ExplicitIndex",
+			query: `SELECT * FROM gamescores USE INDEX (UserWinsIndex) WHERE UserId = "103"`,
+			plan:  `Query index "UserWinsIndex" of table "gamescores"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ast, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			pq, err := PrepareSelect(table, ast.Select)
+			require.NoError(t, err)
+			require.Equal(t, test.plan, pq.Plan)
+		})
+	}
+}
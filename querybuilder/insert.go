@@ -3,10 +3,16 @@ package querybuilder
 import (
 	"context"
 	"database/sql/driver"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/alecthomas/repr"
 	"github.com/aws/aws-sdk-go/aws"
@@ -22,113 +28,235 @@ type PreparedInsert struct {
 	Table       *schema.Table
 	Placeholder string
 	Values      []map[string]*dynamodb.AttributeValue
-	Returning   *string
-	Replace     bool
+	// Documents are the document literals of VALUES that have placeholders, which are converted to items once the
+	// arguments are bound. The other literals are in Values.
+	Documents        []*parser.JSONObject
+	NamedParams      NamedParams
+	PositionalParams map[int]string
+	Returning        *string
+	Replace          bool
 }
 
 func PrepareInsert(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) (*PreparedInsert, error) {
-	var ins *parser.Insert
-	replace := false
+	ins, _, err := insertStatement(ast)
+	if err != nil {
+		return nil, err
+	}
+	table, err := tables.Get(ctx, ins.Into)
+	if err != nil {
+		return nil, err
+	}
+	return prepareInsert(table, ast)
+}
+
+// insertStatement returns the INSERT or REPLACE statement in the AST, and whether it is a REPLACE.
+func insertStatement(ast *parser.AST) (*parser.Insert, bool, error) {
 	switch {
 	case ast.Insert != nil:
-		ins = ast.Insert
-		if ins.Returning != nil && *ins.Returning != "NONE" {
-			return nil, errors.New("RETURNING is not allowed on INSERT")
+		if ast.Insert.Returning != nil && *ast.Insert.Returning != "NONE" {
+			return nil, false, errors.New("RETURNING is not allowed on INSERT")
 		}
+		return ast.Insert, false, nil
 	case ast.Replace != nil:
-		ins = ast.Replace
-		replace = true
+		if ast.Replace.IfNotExists {
+			return nil, false, errors.New("IF NOT EXISTS cannot be used with REPLACE, use INSERT instead")
+		}
+		return ast.Replace, true, nil
 	default:
-		return nil, fmt.Errorf("expected INSERT but got %s", repr.String(ast))
+		return nil, false, fmt.Errorf("expected INSERT but got %s", repr.String(ast))
 	}
-	table, err := tables.Get(ctx, ins.Into)
+}
+
+func prepareInsert(table *schema.Table, ast *parser.AST) (*PreparedInsert, error) {
+	ins, replace, err := insertStatement(ast)
 	if err != nil {
 		return nil, err
 	}
-	var literals []string
+	if ins.Select != nil {
+		return nil, errors.New("INSERT ... SELECT must be prepared with PrepareInsertSelect")
+	}
+	if ins.OnDuplicate != nil {
+		return nil, errors.New("INSERT ... ON DUPLICATE KEY UPDATE must be prepared with PrepareUpsert")
+	}
+	var values []map[string]*dynamodb.AttributeValue
+	var documents []*parser.JSONObject
 	var usePlaceholder bool
 	var placeholder string
+	ctx := NewContext(table, "")
 	for _, v := range ins.Values {
+		var (
+			item map[string]*dynamodb.AttributeValue
+			err  error
+		)
 		switch {
 		case v.PositionalPlaceholder:
 			usePlaceholder = true
+			continue
 		case v.PlaceHolder != nil:
 			usePlaceholder = true
 			placeholder = *v.PlaceHolder
+			continue
 		case v.Object != nil:
-			literals = append(literals, v.Object.String())
+			if prepareDocumentPlaceholders(ctx, v.Object) {
+				documents = append(documents, v.Object)
+				continue
+			}
+			item, err = jsonObjectToItem(v.Object, nil)
 		case v.Str != nil:
-			literals = append(literals, *v.Str)
+			item, err = jsonStringToDynamodbMap(*v.Str)
 		default:
 			return nil, fmt.Errorf("VALUES expression may must be a placeholder or string, but was %s", repr.String(v))
 		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, item)
 	}
 	if usePlaceholder && len(ins.Values) > 1 {
 		return nil, errors.New("when using placeholder parameters, INSERT may contain exactly one placeholder")
 	}
-
-	var values []map[string]*dynamodb.AttributeValue
-	if len(literals) > 0 {
-		values = make([]map[string]*dynamodb.AttributeValue, 0, len(literals))
-		for _, l := range literals {
-			av, err := jsonStringToDynamodbMap(l)
-			if err != nil {
-				return nil, err
-			}
-			values = append(values, av)
-		}
+	if len(ctx.PositionalParams) > 0 && len(ctx.NamedParams) > 0 {
+		return nil, errors.New("cannot mix positional params (?) with named params (:param)")
 	}
 
 	return &PreparedInsert{
-		Table:       table,
-		Placeholder: placeholder,
-		Values:      values,
-		Returning:   ins.Returning,
-		Replace:     replace,
+		Table:            table,
+		Placeholder:      placeholder,
+		Values:           values,
+		Documents:        documents,
+		NamedParams:      ctx.NamedParams,
+		PositionalParams: ctx.PositionalParams,
+		Returning:        ins.Returning,
+		Replace:          replace,
 	}, nil
 }
 
-type driverResult struct {
-	count int
+// prepareDocumentPlaceholders registers the placeholders of a document literal, naming positional placeholders as
+// in a WHERE clause. It returns whether the document has any.
+func prepareDocumentPlaceholders(ctx *Context, obj *parser.JSONObject) bool {
+	found := false
+	parser.Walk(obj, func(node parser.Node) bool {
+		value, ok := node.(*parser.Value)
+		switch {
+		case !ok:
+		case value.PlaceHolder != nil:
+			ctx.NamedParams[*value.PlaceHolder] = Empty{}
+			found = true
+		case value.PositionalPlaceholder:
+			num, name := ctx.NextPositionalParam()
+			ctx.PositionalParams[num] = name
+			*value = parser.Value{PlaceHolder: &name}
+			found = true
+		}
+		return true
+	})
+	return found
+}
+
+// NumInput returns the number of arguments the insert expects to be bound. Document literals take an argument for
+// each of their placeholders, other literal VALUES take no arguments, otherwise a single argument holding one or more
+// documents is expected.
+func (p *PreparedInsert) NumInput() int {
+	if len(p.Documents) > 0 {
+		return len(p.NamedParams) + len(p.PositionalParams)
+	}
+	if len(p.Values) > 0 {
+		return 0
+	}
+	return 1
 }
 
-var _ driver.Result = &driverResult{}
+const (
+	// MaxBatchWriteItems is the maximum number of items in a single BatchWriteItem or TransactWriteItems call.
+	MaxBatchWriteItems = 25
+	// batchWriteBackoff is the initial delay before resubmitting unprocessed items, doubled on each retry.
+	batchWriteBackoff = 50 * time.Millisecond
+	// batchWriteRetries is the most times a statement resubmits unprocessed items, across all of its batches.
+	batchWriteRetries = 10
+	// maxListedKeys is the most keys that FormatKeys lists.
+	maxListedKeys = 10
+)
 
-func (i driverResult) LastInsertId() (int64, error) {
-	return 0, errors.New("LastInsertId is not supported")
-}
+// ErrUnprocessedItems is returned when DynamoDB still leaves items of a BatchWriteItem unprocessed once the retries
+// are exhausted, or the context is done before they are retried. Use errors.As with an *UnprocessedItemsError to find
+// which items were not written.
+var ErrUnprocessedItems = errors.New("items were left unprocessed")
 
-func (i driverResult) RowsAffected() (int64, error) {
-	return int64(i.count), nil
+// UnprocessedItemsError is the error of a statement that wrote some of its items in batches, but not all of them.
+type UnprocessedItemsError struct {
+	// Written is the number of items that were written.
+	Written int
+	// Keys are the keys of the items that were not written.
+	Keys []map[string]*dynamodb.AttributeValue
+	// Err is the error of the context if it was done before the items could be retried.
+	Err error
 }
 
-func (p *PreparedInsert) Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
-	if len(args) > 0 && len(p.Values) > 0 {
-		return nil, errors.New("no arguments expected")
+func (e *UnprocessedItemsError) Error() string {
+	msg := fmt.Sprintf("%s: %d items were written, %d were not: %s", ErrUnprocessedItems, e.Written, len(e.Keys), FormatKeys(e.Keys))
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
 	}
-	var values []map[string]*dynamodb.AttributeValue
-	if len(p.Values) > 0 {
-		values = p.Values
-	} else {
-		if len(args) > 1 && len(p.Values) == 0 {
-			return nil, errors.New("too many arguments")
-		}
-		arg := args[0]
-		if arg.Name != p.Placeholder {
-			return nil, fmt.Errorf("unexpected named argument %q", arg.Name)
+	return msg
+}
+
+func (e *UnprocessedItemsError) Unwrap() error { return e.Err }
+
+func (e *UnprocessedItemsError) Is(target error) bool {
+	return target == ErrUnprocessedItems
+}
+
+// FormatKeys formats the keys of items for an error message, as in {title: "Heat", year: 1995}, {title: "Ronin",
+// year: 1998}. Only the first few keys are listed.
+func FormatKeys(keys []map[string]*dynamodb.AttributeValue) string {
+	formatted := make([]string, 0, maxListedKeys)
+	for i, key := range keys {
+		if i == maxListedKeys {
+			formatted = append(formatted, fmt.Sprintf("and %d more", len(keys)-maxListedKeys))
+			break
 		}
-		var err error
-		values, err = argToListOfMaps(arg.Value)
-		if err != nil {
-			return nil, err
+		formatted = append(formatted, formatKey(key))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// formatKey formats the key of an item for an error message, as in {title: "Heat", year: 1995}.
+func formatKey(key map[string]*dynamodb.AttributeValue) string {
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		av := key[name]
+		value := ""
+		switch {
+		case av.S != nil:
+			value = strconv.Quote(*av.S)
+		case av.N != nil:
+			value = *av.N
+		case av.B != nil:
+			value = strconv.Quote(base64.StdEncoding.EncodeToString(av.B))
 		}
+		parts[i] = name + ": " + value
 	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
 
+// Do writes a single document with PutItem. Multiple documents are written with TransactWriteItems for INSERT, so
+// that the whole statement fails if any document already exists, and with BatchWriteItem for REPLACE, which has no
+// conditions to check.
+func (p *PreparedInsert) Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
+	values, err := p.values(args)
+	if err != nil {
+		return nil, err
+	}
 	if len(values) == 0 {
 		return nil, errors.New("no values to insert")
 	}
 	if len(values) == 1 {
-		resp, err := dynamo.PutItem(p.toPutItem(values[0]))
+		resp, err := dynamo.PutItemWithContext(ctx, p.toPutItem(values[0]))
 		if err != nil {
 			return nil, err
 		}
@@ -140,13 +268,159 @@ func (p *PreparedInsert) Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAP
 	if p.Returning != nil && *p.Returning != "NONE" {
 		return nil, errors.New("cannot use RETURNING with more than 1 item")
 	}
-	_, err := dynamo.TransactWriteItems(p.toTransactWrite(values))
+	if p.Replace {
+		if err := p.batchWrite(ctx, dynamo, values); err != nil {
+			return nil, err
+		}
+		return &DriverResult{count: len(values)}, nil
+	}
+	if len(values) > MaxBatchWriteItems {
+		return nil, fmt.Errorf("INSERT of more than %d items is not supported, use REPLACE to write them in batches", MaxBatchWriteItems)
+	}
+	_, err = dynamo.TransactWriteItemsWithContext(ctx, p.toTransactWrite(values))
 	if err != nil {
 		return nil, err
 	}
 	return &DriverResult{count: len(values)}, nil
 }
 
+// Write writes items that were not bound from the VALUES of the statement, such as those read by INSERT ... SELECT.
+// For INSERT, at most MaxBatchWriteItems items are written at once in a transaction, which fails if any of them
+// already exists. For REPLACE, any number of items are written in batches.
+func (p *PreparedInsert) Write(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, items []map[string]*dynamodb.AttributeValue) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if p.Replace {
+		return p.batchWrite(ctx, dynamo, items)
+	}
+	if len(items) > MaxBatchWriteItems {
+		return fmt.Errorf("cannot INSERT more than %d items at once", MaxBatchWriteItems)
+	}
+	_, err := dynamo.TransactWriteItemsWithContext(ctx, p.toTransactWrite(items))
+	return err
+}
+
+// batchWrite writes the items in batches of MaxBatchWriteItems, resubmitting any items DynamoDB leaves unprocessed
+// with exponential backoff. The statement gives up once it has retried batchWriteRetries times, or would have to wait
+// past the deadline of the context, and returns an *UnprocessedItemsError with the keys of the items left unwritten.
+func (p *PreparedInsert) batchWrite(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, items []map[string]*dynamodb.AttributeValue) error {
+	retries := 0
+	for start := 0; start < len(items); start += MaxBatchWriteItems {
+		end := start + MaxBatchWriteItems
+		if end > len(items) {
+			end = len(items)
+		}
+		requests := make([]*dynamodb.WriteRequest, 0, end-start)
+		for _, item := range items[start:end] {
+			requests = append(requests, &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: item}})
+		}
+		pending := map[string][]*dynamodb.WriteRequest{p.Table.Name: requests}
+		backoff := batchWriteBackoff
+		for {
+			resp, err := dynamo.BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{RequestItems: pending})
+			if err != nil {
+				return err
+			}
+			pending = resp.UnprocessedItems
+			if len(pending) == 0 {
+				break
+			}
+			unprocessed := func(err error) error {
+				return p.unprocessedItemsError(len(items), pending[p.Table.Name], items[end:], err)
+			}
+			if retries == batchWriteRetries {
+				return unprocessed(nil)
+			}
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+				return unprocessed(context.DeadlineExceeded)
+			}
+			// Unprocessed items are usually the result of throttling, so back off before retrying them.
+			select {
+			case <-ctx.Done():
+				return unprocessed(ctx.Err())
+			case <-time.After(backoff):
+			}
+			retries++
+			backoff *= 2
+		}
+	}
+	return nil
+}
+
+// unprocessedItemsError returns the error of a batch write of total items, which left the requests unprocessed and
+// did not get to the remaining items.
+func (p *PreparedInsert) unprocessedItemsError(total int, unprocessed []*dynamodb.WriteRequest, remaining []map[string]*dynamodb.AttributeValue, err error) error {
+	keys := make([]map[string]*dynamodb.AttributeValue, 0, len(unprocessed)+len(remaining))
+	for _, req := range unprocessed {
+		keys = append(keys, p.itemKey(req.PutRequest.Item))
+	}
+	for _, item := range remaining {
+		keys = append(keys, p.itemKey(item))
+	}
+	return &UnprocessedItemsError{Written: total - len(keys), Keys: keys, Err: err}
+}
+
+// itemKey returns the key attributes of an item.
+func (p *PreparedInsert) itemKey(item map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue {
+	key := map[string]*dynamodb.AttributeValue{p.Table.HashKey: item[p.Table.HashKey]}
+	if p.Table.SortKey != "" {
+		key[p.Table.SortKey] = item[p.Table.SortKey]
+	}
+	return key
+}
+
+// TransactWriteItems returns a Put for each document to insert.
+func (p *PreparedInsert) TransactWriteItems(args []driver.NamedValue) ([]*dynamodb.TransactWriteItem, error) {
+	if p.Returning != nil && *p.Returning != "NONE" {
+		return nil, errors.New("cannot use RETURNING in a transaction")
+	}
+	values, err := p.values(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no values to insert")
+	}
+	return p.toTransactWrite(values).TransactItems, nil
+}
+
+// values returns the documents to insert, either the literal VALUES or the documents bound to the placeholder.
+func (p *PreparedInsert) values(args []driver.NamedValue) ([]map[string]*dynamodb.AttributeValue, error) {
+	if len(p.Documents) > 0 {
+		bound, _, err := bindArgs(nil, p.NamedParams, p.PositionalParams, nil, args)
+		if err != nil {
+			return nil, err
+		}
+		items := append([]map[string]*dynamodb.AttributeValue{}, p.Values...)
+		for _, doc := range p.Documents {
+			item, err := jsonObjectToItem(doc, bound)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	if len(args) > 0 && len(p.Values) > 0 {
+		return nil, errors.New("no arguments expected")
+	}
+	if len(p.Values) > 0 {
+		return p.Values, nil
+	}
+	if len(args) == 0 {
+		return nil, errors.New("missing argument with the documents to insert")
+	}
+	if len(args) > 1 {
+		return nil, errors.New("too many arguments")
+	}
+	arg := args[0]
+	if arg.Name != "" && ":"+arg.Name != p.Placeholder {
+		return nil, fmt.Errorf("unexpected named argument %q", arg.Name)
+	}
+	return argToListOfMaps(arg.Value)
+}
+
 func (p *PreparedInsert) toTransactWrite(items []map[string]*dynamodb.AttributeValue) *dynamodb.TransactWriteItemsInput {
 	conditionExpr, exprAttrNames := p.conditionExpr()
 
@@ -189,12 +463,111 @@ func (p *PreparedInsert) conditionExpr() (conditionExpr *string, exprAttrNames m
 	return
 }
 
+// jsonStringToDynamodbMap converts a JSON string to an item. Numbers are kept as written, so that they do not lose
+// precision as a float64.
 func jsonStringToDynamodbMap(v string) (map[string]*dynamodb.AttributeValue, error) {
+	dec := json.NewDecoder(strings.NewReader(v))
+	dec.UseNumber()
 	var asMap map[string]interface{}
-	if err := json.Unmarshal([]byte(v), &asMap); err != nil {
+	if err := dec.Decode(&asMap); err != nil {
 		return nil, err
 	}
-	return dynamodbattribute.MarshalMap(asMap)
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the JSON object")
+	}
+	return dynamodbattribute.MarshalMap(attributeNumbers(asMap))
+}
+
+// attributeNumbers replaces the json.Numbers of a decoded document by dynamodbattribute.Numbers, which are marshaled
+// as numbers rather than strings.
+func attributeNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		return dynamodbattribute.Number(v)
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = attributeNumbers(elem)
+		}
+	case map[string]interface{}:
+		for k, elem := range v {
+			v[k] = attributeNumbers(elem)
+		}
+	}
+	return v
+}
+
+// jsonObjectToItem converts a document literal to an item, taking its keys verbatim. Unlike a JSON string, which is
+// marshaled by dynamodbattribute, empty strings, maps and lists are kept as they are rather than stored as NULL.
+// A null is stored as NULL, so that the attribute exists, rather than being left out of the item. Placeholders are
+// replaced with their bound values, which are nil for a document without placeholders.
+func jsonObjectToItem(obj *parser.JSONObject, bound map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, error) {
+	item := make(map[string]*dynamodb.AttributeValue, len(obj.Entries))
+	for _, entry := range obj.Entries {
+		av, err := jsonValueToAttributeValue(entry.Value, bound)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", entry.Key, err)
+		}
+		item[entry.Key] = av
+	}
+	return item, nil
+}
+
+func jsonValueToAttributeValue(v *parser.JSONValue, bound map[string]*dynamodb.AttributeValue) (*dynamodb.AttributeValue, error) {
+	switch {
+	case v.PlaceHolder != nil:
+		av, ok := bound[*v.PlaceHolder]
+		if !ok {
+			return nil, fmt.Errorf("missing argument for binding %q", *v.PlaceHolder)
+		}
+		return av, nil
+	case v.Object != nil:
+		m, err := jsonObjectToItem(v.Object, bound)
+		if err != nil {
+			return nil, err
+		}
+		return &dynamodb.AttributeValue{M: m}, nil
+	case v.Array != nil:
+		l := make([]*dynamodb.AttributeValue, len(v.Array.Entries))
+		for i, entry := range v.Array.Entries {
+			av, err := jsonValueToAttributeValue(entry, bound)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			l[i] = av
+		}
+		return &dynamodb.AttributeValue{L: l}, nil
+	case v.Set != nil:
+		return setAttributeValue(v.Set)
+	case v.Number != nil:
+		return &dynamodb.AttributeValue{N: aws.String(v.NumberLiteral())}, nil
+	case v.Str != nil:
+		return &dynamodb.AttributeValue{S: aws.String(*v.Str)}, nil
+	case v.Boolean != nil:
+		return &dynamodb.AttributeValue{BOOL: aws.Bool(bool(*v.Boolean))}, nil
+	default:
+		return &dynamodb.AttributeValue{NULL: aws.Bool(true)}, nil
+	}
+}
+
+// setAttributeValue converts a string_set(), number_set() or binary_set() literal, which the parser has already
+// validated.
+func setAttributeValue(set *parser.SetLiteral) (*dynamodb.AttributeValue, error) {
+	av := &dynamodb.AttributeValue{}
+	for _, value := range set.Values {
+		switch set.Type {
+		case "string_set":
+			av.SS = append(av.SS, aws.String(*value.Str))
+		case "number_set":
+			av.NS = append(av.NS, aws.String(value.NumberLiteral()))
+		case "binary_set":
+			b, err := base64.StdEncoding.DecodeString(*value.Str)
+			if err != nil {
+				return nil, err
+			}
+			av.BS = append(av.BS, b)
+		}
+	}
+	return av, nil
 }
 
 func argToListOfMaps(v interface{}) ([]map[string]*dynamodb.AttributeValue, error) {
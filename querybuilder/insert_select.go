@@ -0,0 +1,118 @@
+package querybuilder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alecthomas/repr"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// PreparedInsertSelect is an INSERT or REPLACE that writes the items read by a SELECT, as in
+// INSERT INTO dest SELECT * FROM src WHERE ... The items are read page by page and written in batches of
+// MaxBatchWriteItems with Write, so the source table is never held in memory.
+type PreparedInsertSelect struct {
+	// Insert writes the items to the destination table.
+	Insert *PreparedInsert
+	// Source is the SELECT the items are read with.
+	Source *PreparedQuery
+	// Attributes are the names the columns of Source are written to, in column order. A column is written to its
+	// alias if it has one, otherwise to the top-level attribute it selects. It is empty for SELECT *, which writes the
+	// items as they are.
+	Attributes []string
+}
+
+func PrepareInsertSelect(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) (*PreparedInsertSelect, error) {
+	ins, _, err := insertStatement(ast)
+	if err != nil {
+		return nil, err
+	}
+	if ins.Select == nil {
+		return nil, fmt.Errorf("expected INSERT ... SELECT but got %s", repr.String(ast))
+	}
+	table, err := tables.Get(ctx, ins.Into)
+	if err != nil {
+		return nil, err
+	}
+	source, err := tables.Get(ctx, ins.Select.From)
+	if err != nil {
+		return nil, err
+	}
+	return prepareInsertSelect(table, source, ast)
+}
+
+func prepareInsertSelect(table, source *schema.Table, ast *parser.AST) (*PreparedInsertSelect, error) {
+	ins, replace, err := insertStatement(ast)
+	if err != nil {
+		return nil, err
+	}
+	if ins.Returning != nil && *ins.Returning != "NONE" {
+		return nil, errors.New("cannot use RETURNING with INSERT ... SELECT")
+	}
+	if ins.Select.Projection.Count {
+		return nil, errors.New("cannot insert the result of SELECT COUNT(*)")
+	}
+	if ins.Select.Projection.All && len(ins.Select.Projection.Columns) > 0 {
+		return nil, errors.New("cannot insert the result of SELECT * with other columns, select either * or the columns to insert")
+	}
+	query, err := PrepareSelect(source, ins.Select)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := insertSelectAttributes(table, query.Columns)
+	if err != nil {
+		return nil, err
+	}
+	return &PreparedInsertSelect{
+		Insert: &PreparedInsert{
+			Table:     table,
+			Returning: ins.Returning,
+			Replace:   replace,
+		},
+		Source:     query,
+		Attributes: attrs,
+	}, nil
+}
+
+// insertSelectAttributes returns the attribute each column is written to. Every key attribute of the destination
+// table must be written, or DynamoDB would reject each item.
+func insertSelectAttributes(table *schema.Table, cols []*parser.ProjectionColumn) ([]string, error) {
+	if len(cols) == 0 {
+		return nil, nil
+	}
+	attrs := make([]string, 0, len(cols))
+	seen := map[string]bool{}
+	for _, col := range cols {
+		var attr string
+		switch {
+		case col.Alias != nil:
+			attr = *col.Alias
+		case col.DocumentPath != nil && col.DocumentPath.Attribute() != "":
+			attr = col.DocumentPath.Attribute()
+		default:
+			return nil, fmt.Errorf("column %s must be named with AS to be inserted", col)
+		}
+		if col.Function != nil && len(col.Function.Args) != 1 {
+			return nil, fmt.Errorf("column %s must select a single path to be inserted", col)
+		}
+		if seen[attr] {
+			return nil, fmt.Errorf("attribute %q is inserted more than once", attr)
+		}
+		seen[attr] = true
+		attrs = append(attrs, attr)
+	}
+	for _, key := range []string{table.HashKey, table.SortKey} {
+		if key != "" && !seen[key] {
+			return nil, fmt.Errorf("key attribute %q of table %q must be selected", key, table.Name)
+		}
+	}
+	return attrs, nil
+}
+
+// NumInput returns the number of arguments the SELECT expects to be bound.
+func (p *PreparedInsertSelect) NumInput() int {
+	return p.Source.NumInput()
+}
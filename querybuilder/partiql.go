@@ -0,0 +1,594 @@
+package querybuilder
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// ToPartiQL compiles a SELECT, INSERT, UPDATE or DELETE into a PartiQL statement and its parameters, for DynamoDB's
+// ExecuteStatement API rather than the classic operations. Every value is passed as a ? parameter, in the order the
+// parameters appear in the statement. Placeholders are bound from args, which are only needed if the statement has
+// any.
+//
+// Clauses that are options of the request rather than part of a PartiQL statement, such as LIMIT and
+// WITH (CONSISTENT), are rejected, as are computed columns and REPLACE, which PartiQL can't express.
+func ToPartiQL(ast *parser.AST, args ...driver.NamedValue) (string, []*dynamodb.AttributeValue, error) {
+	c := &partiQLCompiler{named: map[string]interface{}{}}
+	for _, arg := range args {
+		if arg.Name != "" {
+			c.named[":"+arg.Name] = arg.Value
+		} else {
+			c.positional = append(c.positional, arg)
+		}
+	}
+	sort.Slice(c.positional, func(i, j int) bool { return c.positional[i].Ordinal < c.positional[j].Ordinal })
+	var err error
+	switch {
+	case ast.Select != nil:
+		err = c.selectStmt(ast.Select)
+	case ast.Insert != nil:
+		err = c.insert(ast.Insert)
+	case ast.Replace != nil:
+		err = errors.New("PartiQL does not support REPLACE, its INSERT fails if the item exists")
+	case ast.Update != nil:
+		err = c.update(ast.Update)
+	case ast.Delete != nil:
+		err = c.delete(ast.Delete)
+	default:
+		err = fmt.Errorf("only SELECT, INSERT, UPDATE and DELETE can be compiled to PartiQL, got: %s", ast)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if c.nextPositional != len(c.positional) {
+		return "", nil, fmt.Errorf("wrong number of arguments, expected %d, got %d", c.nextPositional, len(c.positional))
+	}
+	return c.String(), c.params, nil
+}
+
+type partiQLCompiler struct {
+	strings.Builder
+	params []*dynamodb.AttributeValue
+	// named are the values of named arguments, by placeholder.
+	named map[string]interface{}
+	// positional are the positional arguments, in order, of which nextPositional have been bound.
+	positional     []driver.NamedValue
+	nextPositional int
+}
+
+func (c *partiQLCompiler) selectStmt(s *parser.Select) error {
+	switch {
+	case s.Limit != nil:
+		return errors.New("PartiQL does not support LIMIT, set Limit on the ExecuteStatement request instead")
+	case s.Offset != nil:
+		return errors.New("PartiQL does not support OFFSET")
+	case len(s.Hints) > 0:
+		return errors.New("PartiQL does not support WITH (...), set ConsistentRead on the ExecuteStatement request instead")
+	case s.Descending != nil && s.OrderBy == nil:
+		return errors.New("PartiQL does not support DESC without ORDER BY, use ORDER BY the sort key")
+	case s.Projection.Count:
+		return errors.New("PartiQL does not support COUNT(*)")
+	case s.Projection.All && len(s.Projection.Columns) > 0:
+		return errors.New("PartiQL does not support selecting other columns with *")
+	}
+	c.WriteString("SELECT ")
+	if s.Projection.All {
+		c.WriteString("*")
+	}
+	for i, col := range s.Projection.Columns {
+		if col.DocumentPath == nil || col.Alias != nil {
+			return fmt.Errorf("PartiQL only supports selecting attributes, not %s", col)
+		}
+		if i > 0 {
+			c.WriteString(", ")
+		}
+		c.path(col.DocumentPath)
+	}
+	c.WriteString(" FROM ")
+	c.ident(s.From)
+	if s.Index != nil {
+		c.WriteString(".")
+		c.ident(*s.Index)
+	}
+	if err := c.where(s.Where); err != nil {
+		return err
+	}
+	if s.OrderBy != nil {
+		c.WriteString(" ORDER BY ")
+		c.path(s.OrderBy.Path)
+		descending := s.OrderBy.Descending
+		if descending == nil {
+			descending = s.Descending
+		}
+		if descending != nil && bool(*descending) {
+			c.WriteString(" DESC")
+		}
+	}
+	return nil
+}
+
+func (c *partiQLCompiler) insert(ins *parser.Insert) error {
+	switch {
+	case ins.Select != nil:
+		return errors.New("PartiQL does not support INSERT ... SELECT")
+	case ins.OnDuplicate != nil:
+		return errors.New("PartiQL does not support ON DUPLICATE KEY UPDATE")
+	case ins.Returning != nil && *ins.Returning != "NONE":
+		return errors.New("PartiQL does not support RETURNING on INSERT")
+	case len(ins.Values) != 1:
+		return errors.New("PartiQL can only INSERT a single item")
+	}
+	c.WriteString("INSERT INTO ")
+	c.ident(ins.Into)
+	c.WriteString(" VALUE ")
+	value := ins.Values[0]
+	if value.Object != nil {
+		return c.jsonObject(value.Object)
+	}
+	var (
+		item map[string]*dynamodb.AttributeValue
+		err  error
+	)
+	switch {
+	case value.Str != nil:
+		item, err = jsonStringToDynamodbMap(*value.Str)
+	case value.PlaceHolder != nil || value.PositionalPlaceholder:
+		var arg interface{}
+		if arg, err = c.arg(&value.Value); err != nil {
+			return err
+		}
+		var items []map[string]*dynamodb.AttributeValue
+		if items, err = argToListOfMaps(arg); err == nil && len(items) != 1 {
+			err = errors.New("PartiQL can only INSERT a single item")
+		}
+		if err == nil {
+			item = items[0]
+		}
+	default:
+		err = fmt.Errorf("VALUES must be a document, a JSON string or a placeholder, not %s", value.Value)
+	}
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(item))
+	for key := range item {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	c.WriteString("{")
+	for i, key := range keys {
+		if i > 0 {
+			c.WriteString(", ")
+		}
+		c.WriteString(partiQLString(key) + ": ")
+		c.param(item[key])
+	}
+	c.WriteString("}")
+	return nil
+}
+
+func (c *partiQLCompiler) update(u *parser.Update) error {
+	c.WriteString("UPDATE ")
+	c.ident(u.Table)
+	for _, action := range u.Actions {
+		for _, set := range action.Set {
+			c.WriteString(" SET ")
+			c.path(set.Path)
+			c.WriteString(" = ")
+			if err := c.setOperand(&set.SetOperand); err != nil {
+				return err
+			}
+			if set.Arithmetic != nil {
+				c.WriteString(" " + set.Arithmetic.Operator + " ")
+				if err := c.setOperand(set.Arithmetic.Operand); err != nil {
+					return err
+				}
+			}
+		}
+		for _, path := range action.Remove {
+			c.WriteString(" REMOVE ")
+			c.path(path)
+		}
+		// ADD and DELETE are written as the assignments they stand for, as PartiQL has neither.
+		for _, add := range action.Add {
+			av, err := c.attributeValue(add.Value)
+			if err != nil {
+				return err
+			}
+			c.WriteString(" SET ")
+			c.path(add.Path)
+			c.WriteString(" = ")
+			switch {
+			case av.N != nil:
+				c.path(add.Path)
+				c.WriteString(" + ")
+				c.param(av)
+			case av.SS != nil || av.NS != nil || av.BS != nil:
+				c.WriteString("set_add(")
+				c.path(add.Path)
+				c.WriteString(", ")
+				c.param(av)
+				c.WriteString(")")
+			default:
+				return fmt.Errorf("ADD %s requires a number or a set", add.Path)
+			}
+		}
+		for _, del := range action.Delete {
+			av, err := c.attributeValue(del.Value)
+			if err != nil {
+				return err
+			}
+			c.WriteString(" SET ")
+			c.path(del.Path)
+			c.WriteString(" = set_delete(")
+			c.path(del.Path)
+			c.WriteString(", ")
+			c.param(av)
+			c.WriteString(")")
+		}
+	}
+	if err := c.where(u.Where); err != nil {
+		return err
+	}
+	return c.returning(u.Returning)
+}
+
+func (c *partiQLCompiler) setOperand(o *parser.SetOperand) error {
+	if o.Function == nil {
+		return c.operand(o.Value)
+	}
+	if o.Function.Function != "list_append" {
+		return fmt.Errorf("PartiQL does not support %s() in SET", o.Function.Function)
+	}
+	return c.function(o.Function)
+}
+
+func (c *partiQLCompiler) delete(d *parser.Delete) error {
+	c.WriteString("DELETE FROM ")
+	c.ident(d.From)
+	if err := c.where(d.Where); err != nil {
+		return err
+	}
+	return c.returning(d.Returning)
+}
+
+// partiQLReturning maps RETURNING to the PartiQL clause returning the same attributes.
+var partiQLReturning = map[string]string{
+	"ALL_OLD":     "ALL OLD *",
+	"ALL_NEW":     "ALL NEW *",
+	"UPDATED_OLD": "MODIFIED OLD *",
+	"UPDATED_NEW": "MODIFIED NEW *",
+}
+
+func (c *partiQLCompiler) returning(r *string) error {
+	if r == nil || *r == "NONE" {
+		return nil
+	}
+	c.WriteString(" RETURNING " + partiQLReturning[*r])
+	return nil
+}
+
+func (c *partiQLCompiler) where(where *parser.ConditionExpression) error {
+	if where == nil {
+		return nil
+	}
+	c.WriteString(" WHERE ")
+	return c.or(where)
+}
+
+func (c *partiQLCompiler) or(expr *parser.ConditionExpression) error {
+	for i, and := range expr.Or {
+		if i > 0 {
+			c.WriteString(" OR ")
+		}
+		for j, cond := range and.And {
+			if j > 0 {
+				c.WriteString(" AND ")
+			}
+			if err := c.condition(cond); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *partiQLCompiler) condition(cond *parser.Condition) error {
+	switch {
+	case cond.Parenthesized != nil:
+		c.WriteString("(")
+		if err := c.or(cond.Parenthesized.ConditionExpression); err != nil {
+			return err
+		}
+		c.WriteString(")")
+		return nil
+	case cond.Not != nil:
+		c.WriteString("NOT ")
+		return c.condition(cond.Not.Condition)
+	case cond.Operand != nil:
+		return c.conditionOperand(cond.Operand)
+	default:
+		fn := cond.Function
+		switch fn.Function {
+		case "attribute_exists":
+			c.path(fn.Args[0].DocumentPath)
+			c.WriteString(" IS NOT MISSING")
+			return nil
+		case "attribute_not_exists":
+			c.path(fn.Args[0].DocumentPath)
+			c.WriteString(" IS MISSING")
+			return nil
+		default:
+			return c.function(fn)
+		}
+	}
+}
+
+func (c *partiQLCompiler) conditionOperand(cond *parser.ConditionOperand) error {
+	lhs := func() error {
+		if cond.Size != nil {
+			return c.function(cond.Size)
+		}
+		c.path(cond.Operand)
+		return nil
+	}
+	rhs := cond.ConditionRHS
+	not := rhs.NotBetween != nil || rhs.NotIn != nil
+	if not {
+		// NOT applies to the whole condition, which is parenthesized so that it does not bind to the attribute.
+		c.WriteString("NOT (")
+	}
+	if rhs.Like != nil {
+		if rhs.Like.Pattern.Str == nil {
+			return errors.New("LIKE requires a string literal pattern, such as 'prefix%'")
+		}
+		prefix, err := likePrefix(*rhs.Like.Pattern.Str)
+		if err != nil {
+			return err
+		}
+		c.WriteString("begins_with(")
+		if err := lhs(); err != nil {
+			return err
+		}
+		c.WriteString(", ")
+		c.param(&dynamodb.AttributeValue{S: &prefix})
+		c.WriteString(")")
+		return nil
+	}
+	if err := lhs(); err != nil {
+		return err
+	}
+	switch {
+	case rhs.Compare != nil:
+		operator := rhs.Compare.Operator
+		if operator == "!=" {
+			operator = "<>"
+		}
+		c.WriteString(" " + operator + " ")
+		if err := c.operand(rhs.Compare.Operand); err != nil {
+			return err
+		}
+	case rhs.Between != nil || rhs.NotBetween != nil:
+		between := rhs.Between
+		if between == nil {
+			between = rhs.NotBetween
+		}
+		c.WriteString(" BETWEEN ")
+		if err := c.operand(between.Start); err != nil {
+			return err
+		}
+		c.WriteString(" AND ")
+		if err := c.operand(between.End); err != nil {
+			return err
+		}
+	case rhs.In != nil || rhs.NotIn != nil:
+		in := rhs.In
+		if in == nil {
+			in = rhs.NotIn
+		}
+		c.WriteString(" IN [")
+		if err := c.in(in); err != nil {
+			return err
+		}
+		c.WriteString("]")
+	case rhs.Is != nil:
+		// As in WHERE, IS NULL tests whether the attribute is absent.
+		if rhs.Is.Not {
+			c.WriteString(" IS NOT MISSING")
+		} else {
+			c.WriteString(" IS MISSING")
+		}
+	}
+	if not {
+		c.WriteString(")")
+	}
+	return nil
+}
+
+// in writes the values of an IN list. A single placeholder bound to a slice stands for its elements, as in WHERE.
+func (c *partiQLCompiler) in(in *parser.In) error {
+	if len(in.Values) == 1 && (in.Values[0].PlaceHolder != nil || in.Values[0].PositionalPlaceholder) {
+		arg, err := c.arg(in.Values[0])
+		if err != nil {
+			return err
+		}
+		if elems, ok := sliceElements(arg); ok {
+			for i, elem := range elems {
+				if i > 0 {
+					c.WriteString(", ")
+				}
+				av, err := toAttributeValue(elem)
+				if err != nil {
+					return fmt.Errorf("binding %s: %w", in.Values[0], err)
+				}
+				c.param(av)
+			}
+			return nil
+		}
+		av, err := toAttributeValue(arg)
+		if err != nil {
+			return fmt.Errorf("binding %s: %w", in.Values[0], err)
+		}
+		c.param(av)
+		return nil
+	}
+	for i, value := range in.Values {
+		if i > 0 {
+			c.WriteString(", ")
+		}
+		if err := c.value(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *partiQLCompiler) function(fn *parser.FunctionExpression) error {
+	c.WriteString(fn.Function + "(")
+	for i, arg := range fn.Args {
+		if i > 0 {
+			c.WriteString(", ")
+		}
+		if arg.DocumentPath != nil {
+			c.path(arg.DocumentPath)
+		} else if err := c.value(arg.Value); err != nil {
+			return err
+		}
+	}
+	c.WriteString(")")
+	return nil
+}
+
+func (c *partiQLCompiler) operand(o *parser.Operand) error {
+	if o.SymbolRef != nil {
+		c.path(o.SymbolRef)
+		return nil
+	}
+	return c.value(o.Value)
+}
+
+func (c *partiQLCompiler) value(v *parser.Value) error {
+	av, err := c.attributeValue(v)
+	if err != nil {
+		return err
+	}
+	c.param(av)
+	return nil
+}
+
+// attributeValue returns a literal as an attribute value, or the argument a placeholder is bound to.
+func (c *partiQLCompiler) attributeValue(v *parser.Value) (*dynamodb.AttributeValue, error) {
+	if v.PlaceHolder == nil && !v.PositionalPlaceholder {
+		return jsonValueToAttributeValue(&parser.JSONValue{Value: *v}, nil)
+	}
+	arg, err := c.arg(v)
+	if err != nil {
+		return nil, err
+	}
+	av, err := toAttributeValue(arg)
+	if err != nil {
+		return nil, fmt.Errorf("binding %s: %w", v, err)
+	}
+	return av, nil
+}
+
+// arg returns the argument a placeholder is bound to. Positional placeholders are bound in the order they appear.
+func (c *partiQLCompiler) arg(v *parser.Value) (interface{}, error) {
+	if v.PlaceHolder != nil {
+		arg, ok := c.named[*v.PlaceHolder]
+		if !ok {
+			return nil, fmt.Errorf("missing argument for binding %q", *v.PlaceHolder)
+		}
+		return arg, nil
+	}
+	if c.nextPositional >= len(c.positional) {
+		return nil, fmt.Errorf("missing argument %d", c.nextPositional+1)
+	}
+	arg := c.positional[c.nextPositional]
+	c.nextPositional++
+	return arg.Value, nil
+}
+
+func (c *partiQLCompiler) param(av *dynamodb.AttributeValue) {
+	c.params = append(c.params, av)
+	c.WriteString("?")
+}
+
+func (c *partiQLCompiler) jsonObject(obj *parser.JSONObject) error {
+	c.WriteString("{")
+	for i, entry := range obj.Entries {
+		if i > 0 {
+			c.WriteString(", ")
+		}
+		c.WriteString(partiQLString(entry.Key) + ": ")
+		if err := c.jsonValue(entry.Value); err != nil {
+			return fmt.Errorf("%q: %w", entry.Key, err)
+		}
+	}
+	c.WriteString("}")
+	return nil
+}
+
+func (c *partiQLCompiler) jsonValue(v *parser.JSONValue) error {
+	switch {
+	case v.Object != nil:
+		return c.jsonObject(v.Object)
+	case v.Array != nil:
+		c.WriteString("[")
+		for i, entry := range v.Array.Entries {
+			if i > 0 {
+				c.WriteString(", ")
+			}
+			if err := c.jsonValue(entry); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		c.WriteString("]")
+		return nil
+	case v.Set != nil:
+		av, err := setAttributeValue(v.Set)
+		if err != nil {
+			return err
+		}
+		c.param(av)
+		return nil
+	default:
+		return c.value(&v.Value)
+	}
+}
+
+// path writes a document path with every attribute name quoted, so that reserved words need no substitution.
+func (c *partiQLCompiler) path(p *parser.DocumentPath) {
+	for i, fragment := range p.Fragment {
+		if i > 0 {
+			c.WriteString(".")
+		}
+		c.ident(fragment.Symbol)
+		for _, accessor := range fragment.Accessors {
+			if accessor.Key != nil {
+				c.WriteString(".")
+				c.ident(*accessor.Key)
+			} else {
+				c.WriteString("[" + strconv.Itoa(*accessor.Index) + "]")
+			}
+		}
+	}
+}
+
+// ident writes a table, index or attribute name as a PartiQL quoted identifier.
+func (c *partiQLCompiler) ident(name string) {
+	c.WriteString(`"` + strings.ReplaceAll(name, `"`, `""`) + `"`)
+}
+
+// partiQLString quotes a string literal, which PartiQL only uses for the attribute names of a document.
+func partiQLString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
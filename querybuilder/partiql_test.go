@@ -0,0 +1,123 @@
+package querybuilder
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+func TestToPartiQL(t *testing.T) {
+	s := func(v string) *dynamodb.AttributeValue { return &dynamodb.AttributeValue{S: aws.String(v)} }
+	n := func(v string) *dynamodb.AttributeValue { return &dynamodb.AttributeValue{N: aws.String(v)} }
+	tests := []struct {
+		name      string
+		query     string
+		args      []driver.NamedValue
+		statement string
+		params    []*dynamodb.AttributeValue
+		err       string
+	}{
+		{
+			name:      "SelectAll",
+			query:     `SELECT * FROM gamescores WHERE UserId = "103" AND Wins > 3`,
+			statement: `SELECT * FROM "gamescores" WHERE "UserId" = ? AND "Wins" > ?`,
+			params:    []*dynamodb.AttributeValue{s("103"), n("3")},
+		},
+		{
+			name:      "SelectPathsFromIndex",
+			query:     `SELECT UserId, scores[0], meta.views FROM gamescores USE INDEX (UserWinsIndex) WHERE UserId = :id ORDER BY Wins DESC`,
+			args:      []driver.NamedValue{{Name: "id", Value: "103"}},
+			statement: `SELECT "UserId", "scores"[0], "meta"."views" FROM "gamescores"."UserWinsIndex" WHERE "UserId" = ? ORDER BY "Wins" DESC`,
+			params:    []*dynamodb.AttributeValue{s("103")},
+		},
+		{
+			name:      "Conditions",
+			query:     `SELECT * FROM t WHERE a != ? AND b NOT BETWEEN 1 AND 2 AND (c IN (?) OR d IS NULL) AND e LIKE 'pre%' AND attribute_exists(f) AND NOT contains(g, 'x')`,
+			args:      []driver.NamedValue{{Ordinal: 1, Value: int64(5)}, {Ordinal: 2, Value: []string{"x", "y"}}},
+			statement: `SELECT * FROM "t" WHERE "a" <> ? AND NOT ("b" BETWEEN ? AND ?) AND ("c" IN [?, ?] OR "d" IS MISSING) AND begins_with("e", ?) AND "f" IS NOT MISSING AND NOT contains("g", ?)`,
+			params:    []*dynamodb.AttributeValue{n("5"), n("1"), n("2"), s("x"), s("y"), s("pre"), s("x")},
+		},
+		{
+			name:      "InsertDocument",
+			query:     `INSERT INTO t VALUES ({"id": "a", "tags": ["x", :tag], "it's": 1})`,
+			args:      []driver.NamedValue{{Name: "tag", Value: "y"}},
+			statement: `INSERT INTO "t" VALUE {'id': ?, 'tags': [?, ?], 'it''s': ?}`,
+			params:    []*dynamodb.AttributeValue{s("a"), s("x"), s("y"), n("1")},
+		},
+		{
+			name:      "InsertPlaceholder",
+			query:     `INSERT INTO t VALUES (?)`,
+			args:      []driver.NamedValue{{Ordinal: 1, Value: map[string]interface{}{"id": "a", "n": 2}}},
+			statement: `INSERT INTO "t" VALUE {'id': ?, 'n': ?}`,
+			params:    []*dynamodb.AttributeValue{s("a"), n("2")},
+		},
+		{
+			name:      "Update",
+			query:     `UPDATE t SET views = views + 1, tags = list_append(tags, :tags) ADD score 2 REMOVE old WHERE id = 'a' RETURNING UPDATED_NEW`,
+			args:      []driver.NamedValue{{Name: "tags", Value: &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{s("z")}}}},
+			statement: `UPDATE "t" SET "views" = "views" + ? SET "tags" = list_append("tags", ?) SET "score" = "score" + ? REMOVE "old" WHERE "id" = ? RETURNING MODIFIED NEW *`,
+			params:    []*dynamodb.AttributeValue{n("1"), {L: []*dynamodb.AttributeValue{s("z")}}, n("2"), s("a")},
+		},
+		{
+			name:      "Delete",
+			query:     `DELETE FROM t WHERE id = 'a' RETURNING ALL_OLD`,
+			statement: `DELETE FROM "t" WHERE "id" = ? RETURNING ALL OLD *`,
+			params:    []*dynamodb.AttributeValue{s("a")},
+		},
+		{
+			name:  "Limit",
+			query: `SELECT * FROM t WHERE id = 'a' LIMIT 10`,
+			err:   "PartiQL does not support LIMIT, set Limit on the ExecuteStatement request instead",
+		},
+		{
+			name:  "StarWithColumns",
+			query: `SELECT *, title FROM t WHERE id = 'a'`,
+			err:   "PartiQL does not support selecting other columns with *",
+		},
+		{
+			name:  "Replace",
+			query: `REPLACE INTO t VALUES ({"id": "a"})`,
+			err:   "PartiQL does not support REPLACE, its INSERT fails if the item exists",
+		},
+		{
+			name:  "IfNotExists",
+			query: `UPDATE t SET views = if_not_exists(views, 0) WHERE id = 'a'`,
+			err:   "PartiQL does not support if_not_exists() in SET",
+		},
+		{
+			name:  "MissingArgument",
+			query: `SELECT * FROM t WHERE id = :id`,
+			err:   `missing argument for binding ":id"`,
+		},
+		{
+			name:  "TooManyArguments",
+			query: `SELECT * FROM t WHERE id = ?`,
+			args:  []driver.NamedValue{{Ordinal: 1, Value: "a"}, {Ordinal: 2, Value: "b"}},
+			err:   "wrong number of arguments, expected 1, got 2",
+		},
+		{
+			name:  "ShowTables",
+			query: `SHOW TABLES`,
+			err:   "only SELECT, INSERT, UPDATE and DELETE can be compiled to PartiQL, got: SHOW TABLES",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ast, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			statement, params, err := ToPartiQL(ast, test.args...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.statement, statement)
+			require.Equal(t, test.params, params)
+		})
+	}
+}
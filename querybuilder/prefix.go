@@ -0,0 +1,55 @@
+package querybuilder
+
+import (
+	"strings"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// PrefixTables prepends prefix to the name of every table the statement uses, for Config.TablePrefix, so that the
+// same statements can be run against the tables of each environment. It must be called before the statement is
+// prepared, as the schema is looked up by the prefixed name. Names that already start with the prefix are left as
+// they are. SHOW TABLES is unchanged and lists the tables by their full names.
+func PrefixTables(ast *parser.AST, prefix string) {
+	if prefix == "" {
+		return
+	}
+	apply := func(table *string) {
+		*table = PrefixTable(*table, prefix)
+	}
+	switch {
+	case ast.Select != nil:
+		apply(&ast.Select.From)
+	case ast.Insert != nil, ast.Replace != nil:
+		ins := ast.Insert
+		if ins == nil {
+			ins = ast.Replace
+		}
+		apply(&ins.Into)
+		if ins.Select != nil {
+			apply(&ins.Select.From)
+		}
+	case ast.Update != nil:
+		apply(&ast.Update.Table)
+	case ast.Delete != nil:
+		apply(&ast.Delete.From)
+	case ast.CreateTable != nil:
+		apply(&ast.CreateTable.Table)
+	case ast.DropTable != nil:
+		apply(&ast.DropTable.Table)
+	case ast.AlterTable != nil:
+		apply(&ast.AlterTable.Table)
+	case ast.Describe != nil:
+		apply(&ast.Describe.Table)
+	case ast.Explain != nil:
+		PrefixTables(ast.Explain.Statement, prefix)
+	}
+}
+
+// PrefixTable returns the name of a table with the prefix, unless it already starts with it.
+func PrefixTable(name, prefix string) string {
+	if strings.HasPrefix(name, prefix) {
+		return name
+	}
+	return prefix + name
+}
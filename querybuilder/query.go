@@ -4,40 +4,88 @@ import (
 	"bytes"
 	"context"
 	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/alecthomas/repr"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 
 	"github.com/mightyguava/dynamosql/parser"
 	"github.com/mightyguava/dynamosql/schema"
 )
 
+const (
+	// maxScanSegments is the maximum TotalSegments DynamoDB accepts for a parallel Scan.
+	maxScanSegments = 1000000
+	// maxInValues is the maximum number of values DynamoDB accepts in an IN list.
+	maxInValues = 100
+	// maxBatchGetKeys is the maximum number of keys in a single BatchGetItem call.
+	maxBatchGetKeys = 100
+)
+
 var (
 	errPositionalArg = errors.New("unexpected positional arg, use sql.NamedArg to pass named arguments")
 	errNamedArg      = errors.New("unexpected named arg, to use named args, provided named placeholders like :param")
 )
 
+// PreparedQuery is a SELECT compiled into either a Query or, when the partition key is not constrained, a Scan.
+// When the WHERE clause pins the whole primary key of the table, and has no other conditions, it is compiled into a
+// GetItem instead, or a BatchGetItem if key attributes are looked up with IN. Exactly one of Query, Scan, GetItem and
+// BatchGet is set.
 type PreparedQuery struct {
-	Query            *dynamodb.QueryInput
-	Limit            int
+	Query   *dynamodb.QueryInput
+	Scan    *dynamodb.ScanInput
+	GetItem *dynamodb.GetItemInput
+	// BatchGet is the BatchGetItem request without its keys. Use NewBatchGetRequests to bind the keys.
+	BatchGet *dynamodb.BatchGetItemInput
+	// KeyParams maps each key attribute to the placeholder its value is bound from, for a GetItem.
+	KeyParams map[string]string
+	// BatchKeyParams maps each key attribute to the placeholders of the values it is looked up with, for a
+	// BatchGetItem. The keys are every combination of the values of the key attributes.
+	BatchKeyParams map[string][]string
+	// Limit is the maximum number of items to return, or 0 for no limit. It is enforced client side because the
+	// Limit on the request is only set when there is no filter expression.
+	Limit int
+	// Offset is the number of items to skip client side before returning results. DynamoDB has no native offset,
+	// so the skipped items are still read.
+	Offset int
+	// LimitParam is the placeholder LIMIT is bound from, if it is not a literal. Use BindLimit to resolve it.
+	LimitParam string
+	// Count is set for SELECT COUNT(*). The request has Select=COUNT and the number of matching items must be summed
+	// across all pages.
+	Count bool
+	// All is set for SELECT *, whose items are returned whole, in a column before Columns.
+	All bool
+	// Segments is the number of segments to split a Scan into and read in parallel, from WITH (SEGMENTS = n). It is
+	// 0 unless a parallel Scan was requested. Results from a parallel Scan are not returned in any particular order.
+	Segments int
+	// Plan describes how the query is executed, such as which table or index is read, for debugging.
+	Plan string
+	// ScanReason explains which conditions the WHERE clause is missing to Query rather than Scan. It is set for a
+	// Scan unless one was asked for with WITH (SCAN) or WITH (SEGMENTS = n).
+	ScanReason       string
 	Columns          []*parser.ProjectionColumn
 	NamedParams      NamedParams
 	PositionalParams map[int]string
 	FixedParams      map[string]interface{}
+	// ListParams are the placeholders that make up a whole IN list, as in IN (:ids). If one is bound to a slice, it
+	// is expanded into one expression value per element.
+	ListParams NamedParams
+	// listFilter compiles the filter of a Query or Scan again once a list param is bound to a slice.
+	listFilter *listExpression
 }
 
-func PrepareQuery(ctx context.Context, tables *schema.TableLoader, query string) (*PreparedQuery, error) {
-	ast, err := parser.Parse(query)
-	if err != nil {
-		return nil, err
-	}
+// PrepareQuery prepares a SELECT. With ignoreIndex, its USE INDEX is ignored, as if the SELECT did not have one, which
+// lets a SELECT that fails with ErrNotProjected be prepared again without copying its AST.
+func PrepareQuery(ctx context.Context, tables *schema.TableLoader, ast *parser.AST, ignoreIndex bool) (*PreparedQuery, error) {
 	sel := ast.Select
 	if sel == nil {
 		return nil, fmt.Errorf("expected SELECT but got %s", repr.String(ast))
@@ -46,27 +94,200 @@ func PrepareQuery(ctx context.Context, tables *schema.TableLoader, query string)
 	if err != nil {
 		return nil, err
 	}
-	return prepare(table, sel)
+	return prepareSelect(table, sel, ignoreIndex)
+}
+
+// NumInput returns the number of arguments the query expects to be bound.
+func (pq *PreparedQuery) NumInput() int {
+	return len(pq.NamedParams) + len(pq.PositionalParams)
 }
 
 func (pq *PreparedQuery) NewRequest(args []driver.NamedValue) (*dynamodb.QueryInput, error) {
-	values, err := bindArgs(pq.FixedParams, pq.NamedParams, pq.PositionalParams, args)
+	values, lists, err := bindArgs(pq.FixedParams, pq.NamedParams, pq.PositionalParams, pq.ListParams, args)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := pq.bindLimit(values)
 	if err != nil {
 		return nil, err
 	}
 	req := *pq.Query
 	req.ExpressionAttributeValues = values
+	req.FilterExpression, err = pq.listFilter.expand(req.FilterExpression, lists)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && req.FilterExpression == nil {
+		req.Limit = aws.Int64(int64(pq.Offset + limit))
+	}
+	return &req, nil
+}
+
+func (pq *PreparedQuery) NewScanRequest(args []driver.NamedValue) (*dynamodb.ScanInput, error) {
+	values, lists, err := bindArgs(pq.FixedParams, pq.NamedParams, pq.PositionalParams, pq.ListParams, args)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := pq.bindLimit(values)
+	if err != nil {
+		return nil, err
+	}
+	req := *pq.Scan
+	req.FilterExpression, err = pq.listFilter.expand(req.FilterExpression, lists)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && req.FilterExpression == nil {
+		req.Limit = aws.Int64(int64(pq.Offset + limit))
+	}
+	if len(values) > 0 {
+		// DynamoDB rejects an empty ExpressionAttributeValues, which is possible for a Scan without a WHERE clause.
+		req.ExpressionAttributeValues = values
+	}
+	return &req, nil
+}
+
+// NewGetItemRequest binds the arguments into the key of a GetItem request.
+func (pq *PreparedQuery) NewGetItemRequest(args []driver.NamedValue) (*dynamodb.GetItemInput, error) {
+	values, lists, err := bindArgs(pq.FixedParams, pq.NamedParams, pq.PositionalParams, pq.ListParams, args)
+	if err != nil {
+		return nil, err
+	}
+	req := *pq.GetItem
+	req.Key, _, err = bindItem(pq.KeyParams, nil, values, lists)
+	if err != nil {
+		return nil, err
+	}
 	return &req, nil
 }
 
-func bindArgs(fixedParams map[string]interface{}, namedParams NamedParams, positionalParams map[int]string, args []driver.NamedValue) (map[string]*dynamodb.AttributeValue, error) {
-	values := make(map[string]*dynamodb.AttributeValue, len(namedParams)+len(fixedParams))
+// NewBatchGetRequests binds the arguments into the keys to look up, and returns BatchGetItem requests for them of up
+// to 100 keys each. Duplicate keys are only looked up once, as DynamoDB rejects them.
+func (pq *PreparedQuery) NewBatchGetRequests(args []driver.NamedValue) ([]*dynamodb.BatchGetItemInput, error) {
+	values, lists, err := bindArgs(pq.FixedParams, pq.NamedParams, pq.PositionalParams, pq.ListParams, args)
+	if err != nil {
+		return nil, err
+	}
+	attrs := make([]string, 0, len(pq.BatchKeyParams))
+	for attr := range pq.BatchKeyParams {
+		attrs = append(attrs, attr)
+	}
+	sort.Strings(attrs)
+	keys := []map[string]*dynamodb.AttributeValue{{}}
+	for _, attr := range attrs {
+		var avs []*dynamodb.AttributeValue
+		for _, placeholder := range pq.BatchKeyParams[attr] {
+			if names, ok := lists[placeholder]; ok {
+				for _, name := range names {
+					avs = append(avs, values[name])
+				}
+				continue
+			}
+			avs = append(avs, values[placeholder])
+		}
+		product := make([]map[string]*dynamodb.AttributeValue, 0, len(keys)*len(avs))
+		for _, key := range keys {
+			for _, av := range avs {
+				next := make(map[string]*dynamodb.AttributeValue, len(key)+1)
+				for k, v := range key {
+					next[k] = v
+				}
+				next[attr] = av
+				product = append(product, next)
+			}
+		}
+		keys = product
+	}
+
+	seen := map[string]bool{}
+	var reqs []*dynamodb.BatchGetItemInput
+	for _, key := range keys {
+		id := ""
+		for _, attr := range attrs {
+			id += key[attr].String()
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if len(reqs) == 0 || len(reqs[len(reqs)-1].RequestItems[pq.batchGetTable()].Keys) == maxBatchGetKeys {
+			reqs = append(reqs, pq.newBatchGetRequest())
+		}
+		ka := reqs[len(reqs)-1].RequestItems[pq.batchGetTable()]
+		ka.Keys = append(ka.Keys, key)
+	}
+	return reqs, nil
+}
+
+// batchGetTable returns the name of the table a BatchGetItem reads.
+func (pq *PreparedQuery) batchGetTable() string {
+	for table := range pq.BatchGet.RequestItems {
+		return table
+	}
+	return ""
+}
+
+// newBatchGetRequest returns a copy of the BatchGetItem request without any keys.
+func (pq *PreparedQuery) newBatchGetRequest() *dynamodb.BatchGetItemInput {
+	table := pq.batchGetTable()
+	ka := *pq.BatchGet.RequestItems[table]
+	return &dynamodb.BatchGetItemInput{RequestItems: map[string]*dynamodb.KeysAndAttributes{table: &ka}}
+}
+
+// BindLimit returns the maximum number of items to return with the given arguments, or 0 for no limit.
+func (pq *PreparedQuery) BindLimit(args []driver.NamedValue) (int, error) {
+	if pq.LimitParam == "" {
+		return pq.Limit, nil
+	}
+	values, _, err := bindArgs(pq.FixedParams, pq.NamedParams, pq.PositionalParams, pq.ListParams, args)
+	if err != nil {
+		return 0, err
+	}
+	return pq.bindLimit(values)
+}
+
+// bindLimit resolves a LIMIT placeholder from the bound values and removes it, since it is not part of any
+// expression.
+func (pq *PreparedQuery) bindLimit(values map[string]*dynamodb.AttributeValue) (int, error) {
+	if pq.LimitParam == "" {
+		return pq.Limit, nil
+	}
+	av := values[pq.LimitParam]
+	delete(values, pq.LimitParam)
+	if av.N == nil {
+		return 0, fmt.Errorf("binding %q: LIMIT must be a positive integer, got a non-numeric value", pq.LimitParam)
+	}
+	n, err := strconv.Atoi(*av.N)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("binding %q: LIMIT must be a positive integer, got %s", pq.LimitParam, *av.N)
+	}
+	return n, nil
+}
+
+// bindArgs binds the arguments to their placeholders. lists maps each list param that was bound to a slice to the
+// placeholders of its elements, which must be substituted for it in the expressions with expandListParams.
+func bindArgs(fixedParams map[string]interface{}, namedParams NamedParams, positionalParams map[int]string, listParams NamedParams, args []driver.NamedValue) (values map[string]*dynamodb.AttributeValue, lists map[string][]string, err error) {
+	values = make(map[string]*dynamodb.AttributeValue, len(namedParams)+len(fixedParams))
+	lists = map[string][]string{}
+	bind := func(name string, value interface{}) error {
+		if _, ok := listParams[name]; ok {
+			if elems, ok := sliceElements(value); ok {
+				return bindList(values, lists, name, elems)
+			}
+		}
+		av, err := toAttributeValue(value)
+		if err != nil {
+			return err
+		}
+		values[name] = av
+		return nil
+	}
 
 	// Bind fixed params
 	for k, v := range fixedParams {
 		av, err := toAttributeValue(v)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		values[k] = av
 	}
@@ -76,45 +297,166 @@ func bindArgs(fixedParams map[string]interface{}, namedParams NamedParams, posit
 		namedParams = namedParams.Clone()
 		for _, arg := range args {
 			if arg.Name == "" {
-				return nil, errPositionalArg
+				return nil, nil, errPositionalArg
 			}
 			name := ":" + arg.Name
 			_, ok := namedParams[name]
 			if !ok {
-				return nil, fmt.Errorf("binding %q not found", name)
+				return nil, nil, fmt.Errorf("binding %q not found", name)
 			}
-			av, err := toAttributeValue(arg.Value)
-			if err != nil {
-				return nil, err
+			if err := bind(name, arg.Value); err != nil {
+				return nil, nil, fmt.Errorf("binding %q: %w", name, err)
 			}
-			values[name] = av
 			delete(namedParams, name)
 		}
 
 		if len(namedParams) > 0 {
 			for k := range namedParams {
-				return nil, fmt.Errorf("missing argument for binding %q", k)
+				return nil, nil, fmt.Errorf("missing argument for binding %q", k)
 			}
 		}
 	} else {
 		// Bind positional params using the args
 		if len(args) != len(positionalParams) {
-			return nil, fmt.Errorf("wrong number of arguments, expected %d, got %d", len(positionalParams), len(args))
+			return nil, nil, fmt.Errorf("wrong number of arguments, expected %d, got %d", len(positionalParams), len(args))
 		}
 		for _, arg := range args {
 			if arg.Name != "" {
-				return nil, errNamedArg
+				return nil, nil, errNamedArg
 			}
-			name := positionalParams[arg.Ordinal]
-			av, err := toAttributeValue(arg.Value)
-			if err != nil {
-				return nil, err
+			name, ok := positionalParams[arg.Ordinal]
+			if !ok {
+				return nil, nil, fmt.Errorf("unexpected argument %d, expected %d positional arguments", arg.Ordinal, len(positionalParams))
+			}
+			if err := bind(name, arg.Value); err != nil {
+				return nil, nil, fmt.Errorf("binding argument %d: %w", arg.Ordinal, err)
 			}
-			values[name] = av
 		}
 	}
 
-	return values, nil
+	return values, lists, nil
+}
+
+// sliceElements returns the elements of a slice or array bound to a list param. Byte slices are binary values, not
+// lists.
+func sliceElements(value interface{}) ([]interface{}, bool) {
+	if _, ok := value.([]byte); ok {
+		return nil, false
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	elems := make([]interface{}, rv.Len())
+	for i := range elems {
+		elems[i] = rv.Index(i).Interface()
+	}
+	return elems, true
+}
+
+// bindList binds each element of a slice to its own placeholder, derived from the placeholder of the list.
+func bindList(values map[string]*dynamodb.AttributeValue, lists map[string][]string, name string, elems []interface{}) error {
+	if len(elems) == 0 {
+		return errors.New("cannot bind an empty slice to IN, DynamoDB requires at least one value")
+	}
+	if len(elems) > maxInValues {
+		return fmt.Errorf("cannot bind %d values to IN, DynamoDB accepts at most %d", len(elems), maxInValues)
+	}
+	names := make([]string, len(elems))
+	for i, elem := range elems {
+		av, err := toAttributeValue(elem)
+		if err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+		names[i] = fmt.Sprintf("%s_%d", name, i)
+		values[names[i]] = av
+	}
+	lists[name] = names
+	return nil
+}
+
+// listExpression is a filter or condition expression with an IN list placeholder. It keeps the AST the expression is
+// compiled from, so that once the list is bound to a slice the expression can be compiled again with a placeholder
+// for each element, and IN (:ids) becomes IN (:ids_0, :ids_1, ...).
+type listExpression struct {
+	ctx *Context
+	// scan is set for the filter of a Scan, as for the visitor.
+	scan bool
+	// item is set for the condition of an UPDATE or DELETE, which also requires the item to exist.
+	item   bool
+	filter parser.Node
+}
+
+// newListExpression returns the listExpression of a filter or condition, or nil if the statement has no IN list
+// placeholders.
+func newListExpression(ctx *Context, scan, item bool, filter parser.Node) *listExpression {
+	if ctx.ListParams == nil {
+		return nil
+	}
+	return &listExpression{ctx: ctx, scan: scan, item: item, filter: filter}
+}
+
+// expand compiles the expression again with the placeholders of the elements bound to list params, or returns expr
+// if none were bound to a slice.
+func (e *listExpression) expand(expr *string, lists map[string][]string) (*string, error) {
+	if e == nil || len(lists) == 0 {
+		return expr, nil
+	}
+	v := &visitor{Context: e.ctx, scan: e.scan, lists: lists}
+	var out string
+	var err error
+	if e.item {
+		out, err = itemConditionExpression(v, e.filter.(*parser.AndExpression))
+	} else {
+		out, err = v.VisitFilterExpression(e.filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CheckArgument returns the value that an argument is bound with, or an error if it can't be bound. database/sql would
+// otherwise convert arguments to the few types of driver.Value, so that slices, maps and structs could not be bound.
+//
+// Values the driver binds itself are returned unchanged: strings, numbers, bools, []byte, json.Number,
+// dynamodbattribute.Number, *dynamodb.AttributeValue, dynamodbattribute.Marshaler, maps, structs, slices and arrays.
+// Other driver.Valuer values, such as sql.NullString, are replaced by their Value. Pointers are dereferenced, except
+// to maps and structs, and nil pointers are nil. Slices are only checked when bound, as what they may hold depends on
+// the placeholder: a list for IN, or the items of an INSERT.
+func CheckArgument(value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case nil, string, []byte, bool, json.Number, dynamodbattribute.Number, *dynamodb.AttributeValue,
+		dynamodbattribute.Marshaler:
+		return value, nil
+	case driver.Valuer:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, nil
+		}
+		v, err := value.(driver.Valuer).Value()
+		if err != nil {
+			return nil, err
+		}
+		return CheckArgument(v)
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64,
+		reflect.Map, reflect.Struct, reflect.Slice, reflect.Array:
+		return value, nil
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if kind := rv.Elem().Kind(); kind == reflect.Map || kind == reflect.Struct {
+			return value, nil
+		}
+		return CheckArgument(rv.Elem().Interface())
+	}
+	return nil, fmt.Errorf("invalid value type %s", reflect.TypeOf(value))
 }
 
 func toAttributeValue(attr interface{}) (*dynamodb.AttributeValue, error) {
@@ -123,17 +465,52 @@ func toAttributeValue(attr interface{}) (*dynamodb.AttributeValue, error) {
 		return v, nil
 	case string:
 		return &dynamodb.AttributeValue{S: &v}, nil
-	case float64:
-		return &dynamodb.AttributeValue{N: aws.String(strconv.FormatFloat(v, 'g', -1, 64))}, nil
-	case int64:
-		return &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(v, 10))}, nil
+	case []byte:
+		return &dynamodb.AttributeValue{B: v}, nil
 	case bool:
 		return &dynamodb.AttributeValue{BOOL: &v}, nil
 	case nil:
 		return &dynamodb.AttributeValue{NULL: aws.Bool(true)}, nil
-	default:
-		return nil, fmt.Errorf("invalid value type %s", reflect.TypeOf(v))
+	case json.Number:
+		return &dynamodb.AttributeValue{N: aws.String(v.String())}, nil
+	case dynamodbattribute.Number:
+		return &dynamodb.AttributeValue{N: aws.String(v.String())}, nil
+	case dynamodbattribute.Marshaler:
+		return marshalMap(v)
 	}
+	// database/sql only converts to int64 and float64 when the default converter is used, but CheckArgument lets any
+	// numeric type through.
+	rv := reflect.ValueOf(attr)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(rv.Int(), 10))}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &dynamodb.AttributeValue{N: aws.String(strconv.FormatUint(rv.Uint(), 10))}, nil
+	case reflect.Float32:
+		return &dynamodb.AttributeValue{N: aws.String(strconv.FormatFloat(rv.Float(), 'g', -1, 32))}, nil
+	case reflect.Float64:
+		return &dynamodb.AttributeValue{N: aws.String(strconv.FormatFloat(rv.Float(), 'g', -1, 64))}, nil
+	case reflect.Map, reflect.Struct:
+		return marshalMap(attr)
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return &dynamodb.AttributeValue{NULL: aws.Bool(true)}, nil
+		}
+		if kind := rv.Elem().Kind(); kind == reflect.Map || kind == reflect.Struct {
+			return marshalMap(attr)
+		}
+	}
+	return nil, fmt.Errorf("invalid value type %s", reflect.TypeOf(attr))
+}
+
+// marshalMap marshals a map or struct to an M attribute with dynamodbattribute, which honours dynamodbav tags and
+// marshals nested maps, structs and slices too.
+func marshalMap(v interface{}) (*dynamodb.AttributeValue, error) {
+	av, err := dynamodbattribute.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal %s: %w", reflect.TypeOf(v), err)
+	}
+	return av, nil
 }
 
 type KeyExpression struct{}
@@ -149,38 +526,182 @@ func (p NamedParams) Clone() NamedParams {
 	return copy
 }
 
-func prepare(table *schema.Table, ast *parser.Select) (*PreparedQuery, error) {
+// PrepareSelect compiles a SELECT into a Query or Scan request for the given table schema. It can be used to build
+// DynamoDB requests without going through database/sql, for callers that already use the AWS SDK. The result is
+// deterministic for a given statement and schema. Use NewRequest or NewScanRequest to bind arguments into a request.
+func PrepareSelect(table *schema.Table, ast *parser.Select) (*PreparedQuery, error) {
+	return prepareSelect(table, ast, false)
+}
+
+func prepareSelect(table *schema.Table, ast *parser.Select, ignoreIndex bool) (*PreparedQuery, error) {
 	index := ""
-	if ast.Index != nil {
+	autoIndex := false
+	forceScan := ast.Scan()
+	if ast.Index != nil && !ignoreIndex {
 		index = *ast.Index
 		if !table.HasIndex(index) {
 			return nil, fmt.Errorf("unrecognized index %q fro table %q", *ast.Index, ast.From)
 		}
+		check := checkQueriedIndex
+		if forceScan {
+			check = checkScannedIndex
+		}
+		if err := check(table, table.GetIndex(index), ast); err != nil {
+			return nil, err
+		}
+	} else if forceScan {
+		// WITH (SCAN) reads the table unless an index is given, it is not a reason to pick one.
+	} else if candidate, ok := selectIndex(table, ast); ok && candidate.index != "" {
+		index = candidate.index
+		autoIndex = true
+	}
+	if ast.Consistent() && index != "" && table.GetIndex(index).Global {
+		return nil, fmt.Errorf("WITH (CONSISTENT) is not supported on global secondary index %q", index)
 	}
 	ctx := NewContext(table, index)
 	visit := &visitor{Context: ctx}
 	if err := prepareValuesAndPlaceholders(ctx, ast.Where); err != nil {
 		return nil, err
 	}
-	kf := extractKeyExpressions(ast.Where, ctx.IsKey)
-	keyExpr, err := buildKeyExpression(ctx, kf.Key)
+	limit, limitParam, err := prepareLimit(ctx, ast.Limit)
 	if err != nil {
 		return nil, err
 	}
-	filterExpr, err := buildFilterExpression(ctx, kf.Filter)
-	if err != nil {
-		return nil, err
+	if len(ctx.PositionalParams) > 0 && len(ctx.NamedParams) > 0 {
+		return nil, errors.New("cannot mix positional params (?) with named params (:param)")
 	}
 	var projectionExpr *string
-	if !ast.Projection.All {
+	if !ast.Projection.All && !ast.Projection.Count {
 		expr, err := buildProjectionExpression(ctx, ast.Projection)
 		if err != nil {
 			return nil, err
 		}
 		projectionExpr = aws.String(expr)
 	}
-	if len(ctx.PositionalParams) > 0 && len(ctx.NamedParams) > 0 {
-		return nil, errors.New("cannot mix positional params (?) with named params (:param)")
+	pq := &PreparedQuery{
+		Limit:            limit,
+		LimitParam:       limitParam,
+		Count:            ast.Projection.Count,
+		All:              ast.Projection.All,
+		Columns:          ast.Projection.Columns,
+		NamedParams:      visit.Context.NamedParams,
+		PositionalParams: visit.Context.PositionalParams,
+		FixedParams:      visit.Context.FixedParams,
+		ListParams:       visit.Context.ListParams,
+	}
+
+	if pq.Count && ast.Limit != nil {
+		// DynamoDB would stop counting after Limit items have been evaluated, which is not what LIMIT means in SQL.
+		return nil, errors.New("LIMIT cannot be used with COUNT(*)")
+	}
+	if ast.Offset != nil {
+		if pq.Count {
+			return nil, errors.New("OFFSET cannot be used with COUNT(*)")
+		}
+		if *ast.Offset < 0 {
+			return nil, fmt.Errorf("OFFSET must be a non-negative integer, got %d", *ast.Offset)
+		}
+		pq.Offset = *ast.Offset
+	}
+
+	descending, err := scanDescending(ctx, ast)
+	if err != nil {
+		return nil, err
+	}
+	segments, parallel := ast.Segments()
+	if parallel && (segments < 1 || segments > maxScanSegments) {
+		return nil, fmt.Errorf("SEGMENTS must be between 1 and %d, got %d", maxScanSegments, segments)
+	}
+
+	if forceScan && descending != nil {
+		return nil, errors.New("ORDER BY and ASC/DESC can not be used with WITH (SCAN), a Scan returns items in no particular order")
+	}
+	if batchKeyParams, ok := batchGetKeyParams(table, index, pq.Count, forceScan || parallel || descending != nil, ast.Where.Conjunction()); ok {
+		// Each item is read by its key, rather than scanning the table for them. Like a GetItem, the names only
+		// cover the projection.
+		ka := &dynamodb.KeysAndAttributes{
+			ProjectionExpression:     projectionExpr,
+			ExpressionAttributeNames: ctx.ExpressionAttributeNames(),
+		}
+		if ast.Consistent() {
+			ka.ConsistentRead = aws.Bool(true)
+		}
+		pq.BatchGet = &dynamodb.BatchGetItemInput{RequestItems: map[string]*dynamodb.KeysAndAttributes{ast.From: ka}}
+		pq.BatchKeyParams = batchKeyParams
+		pq.Plan = fmt.Sprintf("BatchGetItem table %q", table.Name)
+		return pq, nil
+	}
+	if forceScan || !hasHashKeyCondition(ast.Where.Conjunction(), ctx.HashKey) {
+		// Without a partition key there is nothing to Query on, so fall back to a Scan with the whole WHERE clause
+		// as the filter. WITH (SCAN) does the same even if the partition key is given.
+		if descending != nil {
+			return nil, fmt.Errorf("ORDER BY and ASC/DESC require the partition key in the WHERE clause, such as: WHERE %s = :param", ctx.HashKey)
+		}
+		pq.Segments = segments
+		visit.scan = true
+		filterExpr, err := visit.VisitFilterExpression(ast.Where)
+		if err != nil {
+			return nil, err
+		}
+		req := &dynamodb.ScanInput{
+			TableName:            &ast.From,
+			ProjectionExpression: projectionExpr,
+		}
+		if pq.Count {
+			req.Select = aws.String(dynamodb.SelectCount)
+		}
+		if ast.Consistent() {
+			req.ConsistentRead = aws.Bool(true)
+		}
+		if filterExpr != "" {
+			req.FilterExpression = aws.String(filterExpr)
+		}
+		req.ExpressionAttributeNames = ctx.ExpressionAttributeNames()
+		if index != "" {
+			req.IndexName = aws.String(index)
+		}
+		if pq.Limit > 0 && filterExpr == "" {
+			// Only push the limit down if there is no filter, since DynamoDB applies limits BEFORE the filter.
+			req.Limit = aws.Int64(int64(pq.Offset + pq.Limit))
+		}
+		pq.Scan = req
+		pq.listFilter = newListExpression(ctx, true, false, ast.Where)
+		pq.Plan = describePlan(table, index, autoIndex, true, segments)
+		if forceScan {
+			pq.Plan += " (forced by WITH (SCAN))"
+		} else if !parallel {
+			pq.ScanReason = scanReason(table, index, ast.Where.Conjunction())
+		}
+		return pq, nil
+	}
+
+	if parallel {
+		return nil, fmt.Errorf("WITH (SEGMENTS = n) requires a Scan, and cannot be used when the partition key %q is in the WHERE clause", ctx.HashKey)
+	}
+	kf := extractKeyExpressions(ast.Where.Conjunction(), ctx.IsKey)
+	kf.Key = foldSortKeyRange(kf.Key, ctx.SortKey)
+	if keyParams, ok := getItemKeyParams(table, index, pq.Count, kf); ok {
+		// There is at most one item, so read it directly, which is cheaper and faster than a Query. The key
+		// attributes are not substituted yet, so the names only cover the projection.
+		pq.GetItem = &dynamodb.GetItemInput{
+			TableName:                &ast.From,
+			ProjectionExpression:     projectionExpr,
+			ExpressionAttributeNames: ctx.ExpressionAttributeNames(),
+		}
+		if ast.Consistent() {
+			pq.GetItem.ConsistentRead = aws.Bool(true)
+		}
+		pq.KeyParams = keyParams
+		pq.Plan = fmt.Sprintf("GetItem table %q", table.Name)
+		return pq, nil
+	}
+	keyExpr, err := buildKeyExpression(ctx, kf.Key)
+	if err != nil {
+		return nil, err
+	}
+	filterExpr, err := buildFilterExpression(ctx, kf.Filter)
+	if err != nil {
+		return nil, err
 	}
 
 	req := &dynamodb.QueryInput{
@@ -188,6 +709,12 @@ func prepare(table *schema.Table, ast *parser.Select) (*PreparedQuery, error) {
 		ProjectionExpression:   projectionExpr,
 		KeyConditionExpression: aws.String(keyExpr),
 	}
+	if pq.Count {
+		req.Select = aws.String(dynamodb.SelectCount)
+	}
+	if ast.Consistent() {
+		req.ConsistentRead = aws.Bool(true)
+	}
 	if filterExpr != "" {
 		req.FilterExpression = aws.String(filterExpr)
 	}
@@ -195,23 +722,141 @@ func prepare(table *schema.Table, ast *parser.Select) (*PreparedQuery, error) {
 	if index != "" {
 		req.IndexName = aws.String(index)
 	}
-	limit := 0
-	if ast.Limit != nil && filterExpr == "" {
-		// Only apply the limit if there is no filter, since DynamoDB applies limits BEFORE the filter.
-		req.Limit = aws.Int64(int64(*ast.Limit))
-		limit = *ast.Limit
+	if pq.Limit > 0 && filterExpr == "" {
+		// Only push the limit down if there is no filter, since DynamoDB applies limits BEFORE the filter.
+		req.Limit = aws.Int64(int64(pq.Offset + pq.Limit))
+	}
+	if descending != nil {
+		req.ScanIndexForward = aws.Bool(!*descending)
+	}
+	pq.Query = req
+	pq.listFilter = newListExpression(ctx, false, false, kf.Filter)
+	pq.Plan = describePlan(table, index, autoIndex, false, 0)
+	return pq, nil
+}
+
+// getItemKeyParams returns the placeholder each key attribute is bound from if a SELECT can be a GetItem, which is
+// when it reads items of the table, not a COUNT(*) or an index, and its WHERE clause pins each key attribute with an
+// equality condition and has no other conditions.
+func getItemKeyParams(table *schema.Table, index string, count bool, kf *keyAndFilter) (map[string]string, bool) {
+	if index != "" || count || len(kf.Filter.And) > 0 {
+		return nil, false
+	}
+	keyParams, err := itemKeyParams("SELECT", table, kf.Key)
+	if err != nil {
+		return nil, false
+	}
+	return keyParams, true
+}
+
+// batchGetKeyParams returns the placeholders of the values each key attribute is looked up with if a SELECT can be a
+// BatchGetItem. That is when it reads items of the table, not a COUNT(*), an index, or in parallel or in order, and its
+// WHERE clause has an IN or equality condition on each key attribute, at least one of them IN, and no other conditions.
+func batchGetKeyParams(table *schema.Table, index string, count, ordered bool, where *parser.AndExpression) (map[string][]string, bool) {
+	if index != "" || count || ordered || where == nil {
+		return nil, false
+	}
+	params := map[string][]string{}
+	in := false
+	for _, term := range where.And {
+		if term.Operand == nil || term.Operand.Size != nil {
+			return nil, false
+		}
+		name := term.Operand.Operand.Attribute()
+		if _, ok := params[name]; ok || name == "" || name != table.HashKey && name != table.SortKey {
+			return nil, false
+		}
+		rhs := term.Operand.ConditionRHS
+		switch {
+		case rhs.Compare != nil && rhs.Compare.Operator == "=" && rhs.Compare.Operand.Value != nil:
+			params[name] = []string{*rhs.Compare.Operand.Value.PlaceHolder}
+		case rhs.In != nil:
+			for _, v := range rhs.In.Values {
+				params[name] = append(params[name], *v.PlaceHolder)
+			}
+			in = true
+		default:
+			return nil, false
+		}
+	}
+	_, hashKey := params[table.HashKey]
+	_, sortKey := params[table.SortKey]
+	if !in || !hashKey || table.SortKey != "" && !sortKey {
+		return nil, false
+	}
+	return params, true
+}
+
+// prepareLimit returns the value of a literal LIMIT, or registers the placeholder a LIMIT is bound from.
+func prepareLimit(ctx *Context, ast *parser.Limit) (int, string, error) {
+	if ast == nil {
+		return 0, "", nil
+	}
+	limit := ast.Value
+	switch {
+	case limit.PlaceHolder != nil:
+		name := *limit.PlaceHolder
+		if _, ok := ctx.NamedParams[name]; ok {
+			// The bound value is removed from the expression values, so it cannot be shared with the WHERE clause.
+			return 0, "", fmt.Errorf("placeholder %s used in LIMIT may not also be used in WHERE", name)
+		}
+		ctx.NamedParams[name] = Empty{}
+		return 0, name, nil
+	case limit.PositionalPlaceholder:
+		num, name := ctx.NextPositionalParam()
+		ctx.PositionalParams[num] = name
+		return 0, name, nil
+	case limit.Number != nil:
+		n := int(*limit.Number)
+		if float64(n) != *limit.Number || n <= 0 {
+			return 0, "", fmt.Errorf("LIMIT must be a positive integer, got %s", limit)
+		}
+		return n, "", nil
+	default:
+		return 0, "", fmt.Errorf("LIMIT must be a positive integer, got %s", limit)
+	}
+}
+
+// scanDescending returns the sort direction requested by ORDER BY or a bare ASC/DESC, or nil if there is none.
+func scanDescending(ctx *Context, ast *parser.Select) (*bool, error) {
+	if ast.OrderBy == nil {
+		if ast.Descending == nil {
+			return nil, nil
+		}
+		return aws.Bool(bool(*ast.Descending)), nil
 	}
 	if ast.Descending != nil {
-		req.ScanIndexForward = aws.Bool(!bool(*ast.Descending))
+		return nil, errors.New("ASC/DESC may not be used after ORDER BY, use ORDER BY <sort key> ASC|DESC instead")
 	}
-	return &PreparedQuery{
-		Query:            req,
-		Limit:            limit,
-		Columns:          ast.Projection.Columns,
-		NamedParams:      visit.Context.NamedParams,
-		PositionalParams: visit.Context.PositionalParams,
-		FixedParams:      visit.Context.FixedParams,
-	}, nil
+	path := ast.OrderBy.Path.String()
+	if ctx.SortKey == "" {
+		return nil, fmt.Errorf("cannot ORDER BY %q, the table or index has no sort key", path)
+	}
+	if !isAttribute(ast.OrderBy.Path, ctx.SortKey) {
+		return nil, fmt.Errorf("cannot ORDER BY %q, DynamoDB can only order by the sort key %q", path, ctx.SortKey)
+	}
+	return aws.Bool(ast.OrderBy.Descending != nil && bool(*ast.OrderBy.Descending)), nil
+}
+
+// hasHashKeyCondition returns true if the top level of the WHERE clause pins the partition key with an equality
+// condition, which is what DynamoDB requires to issue a Query rather than a Scan.
+func hasHashKeyCondition(expr *parser.AndExpression, hashKey string) bool {
+	if expr == nil {
+		return false
+	}
+	for _, term := range expr.And {
+		if term.Operand != nil && term.Operand.Size == nil && isAttribute(term.Operand.Operand, hashKey) &&
+			term.Operand.ConditionRHS.Compare != nil && term.Operand.ConditionRHS.Compare.Operator == "=" {
+			return true
+		}
+	}
+	return false
+}
+
+// isAttribute returns true if the path addresses the top level attribute name, and not a value inside another
+// attribute that is written the same, as info.rating is for an attribute named `info.rating`.
+func isAttribute(path *parser.DocumentPath, name string) bool {
+	return name != "" && path.Attribute() == name
 }
 
 // Context tracks expression state as DynamoDB request is built.
@@ -222,10 +867,14 @@ type Context struct {
 	PositionalParams map[int]string
 	FixedParams      map[string]interface{}
 	Substitutions    map[string]string
+	// ListParams are the placeholders that make up a whole IN list. It is nil if there are none.
+	ListParams NamedParams
 
 	positionalParamCount int
 	genParamCount        int
 	genSubCount          int
+	// aliases is the reverse of Substitutions, mapping each substituted symbol to its placeholder.
+	aliases map[string]string
 }
 
 func NewContext(table *schema.Table, index string) *Context {
@@ -255,7 +904,7 @@ func (c *Context) ExpressionAttributeNames() map[string]*string {
 
 // IsKey returns true if the field is a hash key or sort key for the selected table/index.
 func (c *Context) IsKey(field string) bool {
-	return c.HashKey == field || c.SortKey == field
+	return field != "" && (c.HashKey == field || c.SortKey == field)
 }
 
 // NextPositionalParam returns the next generated positional placeholder name. DynamoDB does not support positional
@@ -280,40 +929,83 @@ func (c *Context) BuildPath(path *parser.DocumentPath) string {
 			buf.WriteString(".")
 		}
 		buf.WriteString(c.substitute(frag.Symbol))
-		for _, idx := range frag.Indexes {
+		for _, accessor := range frag.Accessors {
+			if accessor.Key != nil {
+				// Expressions only dereference maps with a dot, so the key must be a valid attribute name or be
+				// substituted.
+				buf.WriteString(".")
+				buf.WriteString(c.substitute(*accessor.Key))
+				continue
+			}
 			buf.WriteRune('[')
-			buf.WriteString(strconv.Itoa(idx))
+			buf.WriteString(strconv.Itoa(*accessor.Index))
 			buf.WriteRune(']')
 		}
 	}
 	return buf.String()
 }
 
+// substitute returns the attribute name placeholder for the symbol if it can't be used in an expression as-is,
+// because it is a reserved word or contains special characters. The same symbol always maps to the same placeholder.
 func (c *Context) substitute(symbol string) string {
-	if parser.IsReservedWord(symbol) {
-		sub := "#" + symbol
-		c.Substitutions[sub] = symbol
+	if sub, ok := c.aliases[symbol]; ok {
 		return sub
 	}
-	if !validIdentifierRegexp.MatchString(symbol) {
+	var sub string
+	switch {
+	case parser.IsReservedWord(symbol):
+		sub = "#" + symbol
+	case !validIdentifierRegexp.MatchString(symbol):
 		c.genSubCount++
-		sub := fmt.Sprintf("#_gen%d", c.genSubCount)
-		c.Substitutions[sub] = symbol
-		return sub
+		sub = fmt.Sprintf("#_gen%d", c.genSubCount)
+	default:
+		return symbol
+	}
+	if c.Substitutions == nil {
+		c.Substitutions = make(map[string]string)
 	}
-	return symbol
+	if c.aliases == nil {
+		c.aliases = make(map[string]string)
+	}
+	c.Substitutions[sub] = symbol
+	c.aliases[symbol] = sub
+	return sub
 }
 
-func prepareValuesAndPlaceholders(ctx *Context, expr *parser.AndExpression) error {
-	if expr == nil {
-		return nil
-	}
-	return parser.Visit(expr, func(node parser.Node, next func() error) error {
+// prepareValuesAndPlaceholders replaces every value in the node with a placeholder, and registers the placeholders
+// with the context. Literal values are bound as fixed params, and positional placeholders are named.
+func prepareValuesAndPlaceholders(ctx *Context, node parser.Node) error {
+	uses := map[string]int{}
+	err := parser.Visit(node, func(node parser.Node, next func() error) error {
+		if node, ok := node.(*parser.In); ok && len(node.Values) == 1 &&
+			(node.Values[0].PlaceHolder != nil || node.Values[0].PositionalPlaceholder) {
+			// IN (:list) can be bound to a slice. Visit the value first so that a positional placeholder is named.
+			if err := next(); err != nil {
+				return err
+			}
+			if ctx.ListParams == nil {
+				ctx.ListParams = NamedParams{}
+			}
+			ctx.ListParams[*node.Values[0].PlaceHolder] = Empty{}
+			return nil
+		}
+		if node, ok := node.(*parser.Like); ok {
+			// Rewrite the pattern to the prefix before it is bound, LIKE is compiled to begins_with().
+			if node.Pattern.Str == nil {
+				return errors.New("LIKE requires a string literal pattern, such as 'prefix%'")
+			}
+			prefix, err := likePrefix(*node.Pattern.Str)
+			if err != nil {
+				return err
+			}
+			node.Pattern.Str = &prefix
+		}
 		if node, ok := node.(*parser.Value); ok {
 			var replace parser.Value
 			switch {
 			case node.PlaceHolder != nil:
 				ctx.NamedParams[*node.PlaceHolder] = Empty{}
+				uses[*node.PlaceHolder]++
 				replace = *node
 			case node.PositionalPlaceholder:
 				num, str := ctx.NextPositionalParam()
@@ -321,7 +1013,8 @@ func prepareValuesAndPlaceholders(ctx *Context, expr *parser.AndExpression) erro
 				replace = parser.Value{PlaceHolder: &str}
 			case node.Number != nil:
 				name := ctx.NextGeneratedParam()
-				ctx.FixedParams[name] = *node.Number
+				// Bound as written, so that it does not lose precision as a float64.
+				ctx.FixedParams[name] = json.Number(node.NumberLiteral())
 				replace = parser.Value{PlaceHolder: &name}
 			case node.Str != nil:
 				name := ctx.NextGeneratedParam()
@@ -342,6 +1035,26 @@ func prepareValuesAndPlaceholders(ctx *Context, expr *parser.AndExpression) erro
 		}
 		return next()
 	})
+	if err != nil {
+		return err
+	}
+	for name := range ctx.ListParams {
+		if uses[name] > 1 {
+			// A slice is expanded to several values, so the placeholder cannot also stand for a single value.
+			return fmt.Errorf("placeholder %s used in IN (%s) may not be used elsewhere in WHERE", name, name)
+		}
+	}
+	return nil
+}
+
+// likePrefix returns the prefix matched by a LIKE pattern of the form 'prefix%'. DynamoDB has no general pattern
+// matching, so any other pattern is an error.
+func likePrefix(pattern string) (string, error) {
+	prefix := strings.TrimSuffix(pattern, "%")
+	if prefix == pattern || prefix == "" || strings.ContainsAny(prefix, "%_") {
+		return "", fmt.Errorf("LIKE pattern %q is not supported, DynamoDB can only match a prefix with begins_with(), such as 'prefix%%'", pattern)
+	}
+	return prefix, nil
 }
 
 // matches an identifier that is valid for use in an expression
@@ -361,8 +1074,8 @@ func extractKeyExpressions(expr *parser.AndExpression, isKey func(string) bool)
 		return v
 	}
 	for _, term := range expr.And {
-		if term.Operand != nil && isKey(term.Operand.Operand.String()) ||
-			term.Function != nil && term.Function.FirstArgIsRef() && isKey(term.Function.Args[0].DocumentPath.String()) {
+		if term.Operand != nil && isKey(term.Operand.Path().Attribute()) ||
+			term.Function != nil && term.Function.FirstArgIsRef() && isKey(term.Function.Args[0].DocumentPath.Attribute()) {
 			v.Key.And = append(v.Key.And, term)
 		} else {
 			v.Filter.And = append(v.Filter.And, term)
@@ -371,6 +1084,59 @@ func extractKeyExpressions(expr *parser.AndExpression, isKey func(string) bool)
 	return v
 }
 
+// foldSortKeyRange returns the key conditions with the pair sortKey >= a AND sortKey <= b, in either order, replaced
+// by sortKey BETWEEN a AND b, as a KeyConditionExpression can only have one condition on the sort key. Nothing is
+// folded unless those are the only two conditions on the sort key. Other pairs, such as > and <, have no single
+// equivalent, and are left for buildKeyExpression to reject.
+func foldSortKeyRange(key *parser.AndExpression, sortKey string) *parser.AndExpression {
+	if key == nil || sortKey == "" {
+		return key
+	}
+	var lower, upper *parser.Condition
+	conditions := 0
+	for _, term := range key.And {
+		switch {
+		case term.Function != nil && term.Function.FirstArgIsRef():
+			if isAttribute(term.Function.Args[0].DocumentPath, sortKey) {
+				conditions++
+			}
+		case term.Operand != nil && isAttribute(term.Operand.Path(), sortKey):
+			conditions++
+			if compare := term.Operand.ConditionRHS.Compare; term.Operand.Size == nil && compare != nil {
+				switch compare.Operator {
+				case ">=":
+					lower = term
+				case "<=":
+					upper = term
+				}
+			}
+		}
+	}
+	if conditions != 2 || lower == nil || upper == nil {
+		return key
+	}
+	folded := &parser.AndExpression{}
+	for _, term := range key.And {
+		switch term {
+		case lower:
+			folded.And = append(folded.And, &parser.Condition{
+				Pos: lower.Pos,
+				Operand: &parser.ConditionOperand{
+					Operand: lower.Operand.Operand,
+					ConditionRHS: &parser.ConditionRHS{Between: &parser.Between{
+						Start: lower.Operand.ConditionRHS.Compare.Operand,
+						End:   upper.Operand.ConditionRHS.Compare.Operand,
+					}},
+				},
+			})
+		case upper:
+		default:
+			folded.And = append(folded.And, term)
+		}
+	}
+	return folded
+}
+
 type errHashKey string
 
 func (hashKey errHashKey) Error() string {
@@ -383,30 +1149,41 @@ func buildKeyExpression(ctx *Context, key *parser.AndExpression) (string, error)
 	for _, subExpr := range key.And {
 		var expr, key string
 		if subExpr.Function != nil {
-			key = subExpr.Function.Args[0].DocumentPath.String()
+			key = subExpr.Function.Args[0].DocumentPath.Attribute()
 			if ctx.HashKey == key {
-				return "", errHashKey(ctx.HashKey)
+				return "", atCondition(subExpr, errHashKey(ctx.HashKey))
 			} else if subExpr.Function.Function != "begins_with" {
-				return "", fmt.Errorf("sort key %q may not be used with function %s()", key, subExpr.Function.Function)
+				return "", atCondition(subExpr, fmt.Errorf("sort key %q may not be used with function %s()", key, subExpr.Function.Function))
 			}
 			expr = visitor.VisitSimpleExpression(subExpr.Function)
 		} else {
-			key = subExpr.Operand.Operand.String()
-			if key == ctx.HashKey {
-				if subExpr.Operand.ConditionRHS.Compare == nil || subExpr.Operand.ConditionRHS.Compare.Operator != "=" {
-					return "", errHashKey(ctx.HashKey)
+			key = subExpr.Operand.Path().Attribute()
+			rhs := subExpr.Operand.ConditionRHS
+			if subExpr.Operand.Size != nil {
+				if key == ctx.HashKey {
+					return "", atCondition(subExpr, errHashKey(ctx.HashKey))
+				}
+				return "", atCondition(subExpr, fmt.Errorf("sort key %q may not be used with function size()", key))
+			} else if key == ctx.HashKey {
+				if rhs.Between != nil {
+					return "", atCondition(subExpr, fmt.Errorf("partition key %q may not be used with BETWEEN, only with =", key))
 				}
+				if rhs.Compare == nil || rhs.Compare.Operator != "=" {
+					return "", atCondition(subExpr, errHashKey(ctx.HashKey))
+				}
+			} else if err := checkSortKeyCondition(key, rhs); err != nil {
+				return "", atCondition(subExpr, err)
 			}
 			expr = visitor.VisitSimpleExpression(subExpr.Operand)
 		}
 		if ctx.HashKey == key {
 			if hashExpr != "" {
-				return "", fmt.Errorf("partition key %q can only appear once in WHERE clause", key)
+				return "", atCondition(subExpr, fmt.Errorf("partition key %q can only appear once in WHERE clause", key))
 			}
 			hashExpr = expr
 		} else if ctx.SortKey == key {
 			if sortExpr != "" {
-				return "", fmt.Errorf("sort key %q can only appear once in WHERE clause", key)
+				return "", atCondition(subExpr, fmt.Errorf("sort key %q can only appear once in WHERE clause, except in a range such as %[1]s >= :a AND %[1]s <= :b, which is read as %[1]s BETWEEN :a AND :b", key))
 			}
 			sortExpr = expr
 		}
@@ -420,6 +1197,24 @@ func buildKeyExpression(ctx *Context, key *parser.AndExpression) (string, error)
 	return hashExpr, nil
 }
 
+// checkSortKeyCondition returns an error if the condition is not one DynamoDB accepts on a sort key in a
+// KeyConditionExpression: =, <, <=, >, >=, BETWEEN or begins_with().
+func checkSortKeyCondition(key string, rhs *parser.ConditionRHS) error {
+	switch {
+	case rhs.In != nil:
+		return fmt.Errorf("sort key %q may not be used with IN", key)
+	case rhs.NotIn != nil:
+		return fmt.Errorf("sort key %q may not be used with NOT IN", key)
+	case rhs.NotBetween != nil:
+		return fmt.Errorf("sort key %q may not be used with NOT BETWEEN", key)
+	case rhs.Is != nil:
+		return fmt.Errorf("sort key %q may not be used with IS NULL or IS NOT NULL, it is present on every item", key)
+	case rhs.Compare != nil && (rhs.Compare.Operator == "<>" || rhs.Compare.Operator == "!="):
+		return fmt.Errorf("sort key %q may not be used with %s, only =, <, <=, >, >=, BETWEEN and begins_with() are allowed", key, rhs.Compare.Operator)
+	}
+	return nil
+}
+
 func buildFilterExpression(ctx *Context, filter *parser.AndExpression) (string, error) {
 	v := &visitor{Context: ctx}
 	return v.VisitFilterExpression(filter)
@@ -434,6 +1229,10 @@ type Acc struct {
 
 type visitor struct {
 	*Context
+	// scan is set when building the filter of a Scan, where key attributes may appear anywhere.
+	scan bool
+	// lists are the placeholders of the elements of each list param bound to a slice, which its IN is expanded to.
+	lists map[string][]string
 }
 
 // VisitFilterExpression visits all nodes in the filter expression tree to build a filter expression.
@@ -469,12 +1268,12 @@ func (v *visitor) VisitFilterExpression(n interface{}) (string, error) {
 	case *parser.Condition:
 		switch {
 		case node.Operand != nil:
-			if v.Context.IsKey(node.Operand.Operand.String()) {
-				return "", fmt.Errorf("partition key %q may not appear in nested expression", node.Operand.Operand.String())
+			if !v.scan && v.Context.IsKey(node.Operand.Path().Attribute()) {
+				return "", fmt.Errorf("partition key %q may not appear in nested expression", node.Operand.Path().String())
 			}
 			return v.VisitSimpleExpression(node.Operand), nil
 		case node.Function != nil:
-			if node.Function.FirstArgIsRef() && v.Context.IsKey(node.Function.Args[0].DocumentPath.String()) {
+			if !v.scan && node.Function.FirstArgIsRef() && v.Context.IsKey(node.Function.Args[0].DocumentPath.Attribute()) {
 				return "", fmt.Errorf("partition key %q may not appear in nested expression", node.Function.Args[0].DocumentPath)
 			}
 			return v.VisitSimpleExpression(node.Function), nil
@@ -502,11 +1301,34 @@ func (v *visitor) VisitFilterExpression(n interface{}) (string, error) {
 	}
 }
 
+// visitComparison compares lhs with an operator, BETWEEN or IN.
+func (v *visitor) visitComparison(lhs string, rhs *parser.ConditionRHS) string {
+	if rhs.NotBetween != nil || rhs.NotIn != nil {
+		// DynamoDB has no NOT IN or NOT BETWEEN operators, so negate the whole comparison.
+		positive := &parser.ConditionRHS{Between: rhs.NotBetween, In: rhs.NotIn}
+		return fmt.Sprintf("NOT (%s %s)", lhs, v.VisitSimpleExpression(positive))
+	}
+	return lhs + " " + v.VisitSimpleExpression(rhs)
+}
+
 // VisitSimpleExpression visits unary and binary expressions down to the leaf nodes.
 func (v *visitor) VisitSimpleExpression(n interface{}) string {
 	switch node := n.(type) {
 	case *parser.ConditionOperand:
-		return v.BuildPath(node.Operand) + " " + v.VisitSimpleExpression(node.ConditionRHS)
+		if node.Size != nil {
+			return v.visitComparison(v.VisitSimpleExpression(node.Size), node.ConditionRHS)
+		}
+		if like := node.ConditionRHS.Like; like != nil {
+			return fmt.Sprintf("begins_with(%s, %s)", v.BuildPath(node.Operand), v.VisitSimpleExpression(like.Pattern))
+		}
+		if is := node.ConditionRHS.Is; is != nil {
+			// DynamoDB has no null comparison, absence is tested with functions instead.
+			if is.Not {
+				return fmt.Sprintf("attribute_exists(%s)", v.BuildPath(node.Operand))
+			}
+			return fmt.Sprintf("attribute_not_exists(%s)", v.BuildPath(node.Operand))
+		}
+		return v.visitComparison(v.BuildPath(node.Operand), node.ConditionRHS)
 	case *parser.FunctionExpression:
 		argStr := make([]string, len(node.Args))
 		for i, arg := range node.Args {
@@ -535,7 +1357,16 @@ func (v *visitor) VisitSimpleExpression(n interface{}) string {
 		return fmt.Sprintf("BETWEEN %s AND %s",
 			v.VisitSimpleExpression(node.Start), v.VisitSimpleExpression(node.End))
 	case *parser.In:
-		return fmt.Sprintf(" IN (%s)", v.VisitSimpleExpression(node.Values))
+		if len(node.Values) == 1 && node.Values[0].PlaceHolder != nil {
+			if names, ok := v.lists[*node.Values[0].PlaceHolder]; ok {
+				return fmt.Sprintf("IN (%s)", strings.Join(names, ", "))
+			}
+		}
+		values := make([]string, len(node.Values))
+		for i, value := range node.Values {
+			values[i] = v.VisitSimpleExpression(value)
+		}
+		return fmt.Sprintf("IN (%s)", strings.Join(values, ", "))
 	case *parser.Operand:
 		if node.SymbolRef != nil {
 			return v.BuildPath(node.SymbolRef)
@@ -562,21 +1393,49 @@ func buildProjectionExpression(ctx *Context, expr *parser.ProjectionExpression)
 				return "", err
 			}
 			cols = append(cols, fc...)
+		} else if col.Case != nil {
+			cols = append(cols, extractProjectionsFromExpression(col.Case)...)
+		} else if col.Arithmetic != nil {
+			cols = append(cols, extractProjectionsFromExpression(col.Arithmetic)...)
 		} else {
 			return "", fmt.Errorf("unexpected ProjectionColumn %v", *col)
 		}
 	}
 	buf := &bytes.Buffer{}
-	for i, col := range cols {
-		if i != 0 {
+	seen := map[string]bool{}
+	for _, col := range cols {
+		// DynamoDB rejects a path that is projected twice, which a CASE or computed column can repeat.
+		path := ctx.BuildPath(col)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if buf.Len() != 0 {
 			buf.WriteString(", ")
 		}
-		buf.WriteString(ctx.BuildPath(col))
+		buf.WriteString(path)
 	}
 	return buf.String(), nil
 }
 
+// extractProjectionsFromExpression returns the attributes that a CASE or computed column reads, which must be
+// projected for it to be evaluated on each item.
+func extractProjectionsFromExpression(expr parser.Node) []*parser.DocumentPath {
+	var cols []*parser.DocumentPath
+	parser.Walk(expr, func(node parser.Node) bool {
+		if path, ok := node.(*parser.DocumentPath); ok {
+			cols = append(cols, path)
+			return false
+		}
+		return true
+	})
+	return cols
+}
+
 func extractProjectionsFromFunction(expr *parser.FunctionExpression) ([]*parser.DocumentPath, error) {
+	if expr.Function == "size" {
+		return nil, errors.New("size() can not be used in a projection, DynamoDB can only project attributes")
+	}
 	if expr.Function != "document" {
 		return nil, fmt.Errorf("function %q not allowed in projection", expr.Function)
 	}
@@ -2,12 +2,17 @@ package querybuilder
 
 import (
 	"bufio"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/alecthomas/repr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/sebdah/goldie/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -50,7 +55,7 @@ func TestBuildQuery(t *testing.T) {
 		ast, err := parser.Parse(queryStr)
 		msg := fmt.Sprintf("Parse: %s\n%s", queryStr, repr.String(ast, repr.Indent("  ")))
 		require.NoError(t, err, msg)
-		query, err := prepare(getTable(queryStr), ast.Select)
+		query, err := PrepareSelect(getTable(queryStr), ast.Select)
 		require.NoError(t, err, msg)
 		parsed = append(parsed, item{
 			Query:    queryStr,
@@ -88,7 +93,7 @@ func TestInvalidQueries(t *testing.T) {
 		ast, err := parser.Parse(queryStr)
 		if err == nil {
 			var q *PreparedQuery
-			q, err = prepare(table, ast.Select)
+			q, err = PrepareSelect(table, ast.Select)
 			assert.Error(t, err, "Query: %s\nPrepared Query: %s", queryStr, testutil.MarshalJSON(q))
 		}
 		var errStr string
@@ -113,6 +118,428 @@ func TestSubstitute(t *testing.T) {
 	require.Equal(t, "#select", ctx.substitute("select"))
 	require.Equal(t, "#select", ctx.substitute("select"))
 	require.Equal(t, "#_gen1", ctx.substitute("foo.bar"))
-	require.Equal(t, "#_gen2", ctx.substitute("foo.bar"))
-	require.Equal(t, "#_gen3", ctx.substitute("foo.bar2"))
+	require.Equal(t, "#_gen1", ctx.substitute("foo.bar"))
+	require.Equal(t, "#_gen2", ctx.substitute("foo.bar2"))
+	require.Equal(t, "#zone", ctx.substitute("zone"))
+	require.Equal(t, map[string]string{
+		"#select": "select",
+		"#_gen1":  "foo.bar",
+		"#_gen2":  "foo.bar2",
+		"#zone":   "zone",
+	}, ctx.Substitutions)
+}
+
+func TestSubstituteWithoutTable(t *testing.T) {
+	ctx := Context{}
+	require.Equal(t, "#year", ctx.substitute("year"))
+	require.Equal(t, map[string]*string{"#year": aws.String("year")}, ctx.ExpressionAttributeNames())
+}
+
+func TestNumInput(t *testing.T) {
+	table := schema.NewTableFromCreate(fixtures.GameScores.Create)
+	tests := []struct {
+		query    string
+		numInput int
+	}{
+		{`SELECT * FROM gamescores WHERE UserId = "101"`, 0},
+		{`SELECT * FROM gamescores WHERE UserId = :UserId AND TopScore > :TopScore`, 2},
+		{`SELECT * FROM gamescores WHERE UserId = :UserId AND TopScore > :Score AND Wins < :Score`, 2},
+		{`SELECT * FROM gamescores WHERE UserId = ? AND TopScore > ? AND Wins < 3`, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			ast, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			require.Len(t, parser.Placeholders(ast), test.numInput)
+			q, err := PrepareSelect(table, ast.Select)
+			require.NoError(t, err)
+			require.Equal(t, test.numInput, q.NumInput())
+		})
+	}
+}
+
+func TestBindArgs(t *testing.T) {
+	table := schema.NewTableFromCreate(fixtures.GameScores.Create)
+	prepareQuery := func(t *testing.T, query string) *PreparedQuery {
+		t.Helper()
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		q, err := PrepareSelect(table, ast.Select)
+		require.NoError(t, err)
+		return q
+	}
+
+	t.Run("named", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = :UserId AND TopScore > :TopScore AND Wins = 3`)
+		req, err := q.NewRequest([]driver.NamedValue{
+			{Name: "UserId", Value: "101"},
+			{Name: "TopScore", Value: 1000},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			":UserId":   {S: aws.String("101")},
+			":TopScore": {N: aws.String("1000")},
+			":_gen1":    {N: aws.String("3")},
+		}, req.ExpressionAttributeValues)
+	})
+
+	t.Run("number literals keep their precision", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = "101" AND TopScore > 12345678901234567890 AND Wins = 0.1`)
+		req, err := q.NewRequest(nil)
+		require.NoError(t, err)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			":_gen1": {S: aws.String("101")},
+			":_gen2": {N: aws.String("12345678901234567890")},
+			":_gen3": {N: aws.String("0.1")},
+		}, req.ExpressionAttributeValues)
+	})
+
+	t.Run("positional", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = ? AND TopScore > ? AND Wins < ?`)
+		req, err := q.NewRequest([]driver.NamedValue{
+			{Ordinal: 1, Value: "101"},
+			{Ordinal: 2, Value: uint8(7)},
+			{Ordinal: 3, Value: float32(1.5)},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			":_pos1": {S: aws.String("101")},
+			":_pos2": {N: aws.String("7")},
+			":_pos3": {N: aws.String("1.5")},
+		}, req.ExpressionAttributeValues)
+	})
+
+	t.Run("positional binds left to right", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = ? AND GameTitle BETWEEN ? AND ? AND begins_with(Name, ?)`)
+		req, err := q.NewRequest([]driver.NamedValue{
+			{Ordinal: 1, Value: "101"},
+			{Ordinal: 2, Value: "A"},
+			{Ordinal: 3, Value: "M"},
+			{Ordinal: 4, Value: "Bob"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "UserId = :_pos1 AND GameTitle BETWEEN :_pos2 AND :_pos3", *req.KeyConditionExpression)
+		require.Equal(t, "begins_with(#Name, :_pos4)", *req.FilterExpression)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			":_pos1": {S: aws.String("101")},
+			":_pos2": {S: aws.String("A")},
+			":_pos3": {S: aws.String("M")},
+			":_pos4": {S: aws.String("Bob")},
+		}, req.ExpressionAttributeValues)
+	})
+
+	t.Run("contains binds its value", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = :UserId AND contains(Studio.Tags, :tag) AND contains(Scores[0].Data, 100)`)
+		req, err := q.NewRequest([]driver.NamedValue{
+			{Name: "UserId", Value: "101"},
+			{Name: "tag", Value: "indie"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "contains(Studio.Tags, :tag) AND contains(Scores[0].#Data, :_gen1)", *req.FilterExpression)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			":UserId": {S: aws.String("101")},
+			":tag":    {S: aws.String("indie")},
+			":_gen1":  {N: aws.String("100")},
+		}, req.ExpressionAttributeValues)
+	})
+
+	t.Run("positional rejects named args", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = ?`)
+		_, err := q.NewRequest([]driver.NamedValue{{Name: "UserId", Ordinal: 1, Value: "101"}})
+		require.Equal(t, errNamedArg, err)
+	})
+
+	t.Run("positional rejects unknown ordinals", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = ?`)
+		_, err := q.NewRequest([]driver.NamedValue{{Ordinal: 2, Value: "101"}})
+		require.EqualError(t, err, "unexpected argument 2, expected 1 positional arguments")
+	})
+
+	t.Run("limit placeholder", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = :UserId LIMIT :n`)
+		require.Equal(t, 2, q.NumInput())
+		args := []driver.NamedValue{{Name: "UserId", Value: "101"}, {Name: "n", Value: 5}}
+		req, err := q.NewRequest(args)
+		require.NoError(t, err)
+		require.Equal(t, int64(5), *req.Limit)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{":UserId": {S: aws.String("101")}}, req.ExpressionAttributeValues)
+		limit, err := q.BindLimit(args)
+		require.NoError(t, err)
+		require.Equal(t, 5, limit)
+	})
+
+	t.Run("positional limit with a filter is not pushed down", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = ? AND Wins > ? LIMIT ?`)
+		args := []driver.NamedValue{{Ordinal: 1, Value: "101"}, {Ordinal: 2, Value: 3}, {Ordinal: 3, Value: int64(2)}}
+		req, err := q.NewRequest(args)
+		require.NoError(t, err)
+		require.Nil(t, req.Limit)
+		require.Len(t, req.ExpressionAttributeValues, 2)
+		limit, err := q.BindLimit(args)
+		require.NoError(t, err)
+		require.Equal(t, 2, limit)
+	})
+
+	t.Run("limit placeholder must be bound to a positive integer", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = :UserId LIMIT :n`)
+		_, err := q.NewRequest([]driver.NamedValue{{Name: "UserId", Value: "101"}, {Name: "n", Value: 2.5}})
+		require.EqualError(t, err, `binding ":n": LIMIT must be a positive integer, got 2.5`)
+		_, err = q.BindLimit([]driver.NamedValue{{Name: "UserId", Value: "101"}, {Name: "n", Value: "ten"}})
+		require.EqualError(t, err, `binding ":n": LIMIT must be a positive integer, got a non-numeric value`)
+	})
+
+	t.Run("invalid type names the binding", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = :UserId`)
+		_, err := q.NewRequest([]driver.NamedValue{{Name: "UserId", Value: make(chan int)}})
+		require.EqualError(t, err, `binding ":UserId": invalid value type chan int`)
+		_, err = q.NewRequest([]driver.NamedValue{{Name: "UserId", Value: map[string]interface{}{"": 1}}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `binding ":UserId": cannot marshal map[string]interface {}: `)
+	})
+
+	t.Run("maps and structs are marshaled to maps", func(t *testing.T) {
+		type name struct {
+			First string `dynamodbav:"first"`
+			Last  string `dynamodbav:"last,omitempty"`
+		}
+		type player struct {
+			Name    *name
+			Aliases []string `dynamodbav:"aliases,stringset"`
+			Skipped string   `dynamodbav:"-"`
+		}
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = :UserId AND Name = :name AND Stats = :stats`)
+		req, err := q.NewRequest([]driver.NamedValue{
+			{Name: "UserId", Value: "101"},
+			{Name: "name", Value: &player{Name: &name{First: "Bob"}, Aliases: []string{"B"}, Skipped: "x"}},
+			{Name: "stats", Value: map[string]interface{}{"wins": 3, "history": []interface{}{map[string]int{"game": 1}}}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			":UserId": {S: aws.String("101")},
+			":name": {M: map[string]*dynamodb.AttributeValue{
+				"Name":    {M: map[string]*dynamodb.AttributeValue{"first": {S: aws.String("Bob")}}},
+				"aliases": {SS: aws.StringSlice([]string{"B"})},
+			}},
+			":stats": {M: map[string]*dynamodb.AttributeValue{
+				"wins":    {N: aws.String("3")},
+				"history": {L: []*dynamodb.AttributeValue{{M: map[string]*dynamodb.AttributeValue{"game": {N: aws.String("1")}}}}},
+			}},
+		}, req.ExpressionAttributeValues)
+	})
+
+	t.Run("missing argument", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = :UserId`)
+		_, err := q.NewRequest(nil)
+		require.EqualError(t, err, `missing argument for binding ":UserId"`)
+	})
+
+	t.Run("IN list placeholder expands a slice", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = :UserId AND NOT Wins IN (:wins) AND Name IN (:names)`)
+		req, err := q.NewRequest([]driver.NamedValue{
+			{Name: "UserId", Value: "101"},
+			{Name: "wins", Value: []int{1, 2, 3}},
+			{Name: "names", Value: "Bob"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "NOT Wins IN (:wins_0, :wins_1, :wins_2) AND #Name IN (:names)", *req.FilterExpression)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			":UserId": {S: aws.String("101")},
+			":wins_0": {N: aws.String("1")},
+			":wins_1": {N: aws.String("2")},
+			":wins_2": {N: aws.String("3")},
+			":names":  {S: aws.String("Bob")},
+		}, req.ExpressionAttributeValues)
+	})
+
+	t.Run("positional IN list placeholder", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId IN (?)`)
+		req, err := q.NewScanRequest([]driver.NamedValue{{Ordinal: 1, Value: []string{"101", "102"}}})
+		require.NoError(t, err)
+		require.Equal(t, "UserId IN (:_pos1_0, :_pos1_1)", *req.FilterExpression)
+		require.Len(t, req.ExpressionAttributeValues, 2)
+		// The prepared expression is not modified by binding.
+		require.Equal(t, "UserId IN (:_pos1)", *q.Scan.FilterExpression)
+	})
+
+	t.Run("IN list placeholders are expanded whatever their spacing and names", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = :UserId AND Wins IN(:w) AND Losses IN ( :wins )`)
+		req, err := q.NewRequest([]driver.NamedValue{
+			{Name: "UserId", Value: "101"},
+			{Name: "w", Value: []int{1, 2}},
+			{Name: "wins", Value: []int{3}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Wins IN (:w_0, :w_1) AND Losses IN (:wins_0)", *req.FilterExpression)
+		require.Equal(t, "Wins IN (:w) AND Losses IN (:wins)", *q.Query.FilterExpression)
+	})
+
+	t.Run("IN list placeholder rejects empty slices", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId IN (:ids)`)
+		_, err := q.NewScanRequest([]driver.NamedValue{{Name: "ids", Value: []string{}}})
+		require.EqualError(t, err, `binding ":ids": cannot bind an empty slice to IN, DynamoDB requires at least one value`)
+		_, err = q.NewScanRequest([]driver.NamedValue{{Name: "ids", Value: make([]int, 101)}})
+		require.EqualError(t, err, `binding ":ids": cannot bind 101 values to IN, DynamoDB accepts at most 100`)
+	})
+
+	t.Run("slices are only expanded in IN lists", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = :UserId`)
+		_, err := q.NewRequest([]driver.NamedValue{{Name: "UserId", Value: []string{"101"}}})
+		require.EqualError(t, err, `binding ":UserId": invalid value type []string`)
+	})
+}
+
+func TestCheckArgument(t *testing.T) {
+	str, n := "a", 3
+	var nilStr *string
+	var nilValuer *sql.NullString
+	type item struct{ A int }
+	for _, test := range []struct {
+		name     string
+		value    interface{}
+		expected interface{}
+		err      string
+	}{
+		{name: "string", value: "a", expected: "a"},
+		{name: "uint8", value: uint8(1), expected: uint8(1)},
+		{name: "bytes", value: []byte("a"), expected: []byte("a")},
+		{name: "json number", value: json.Number("1.5"), expected: json.Number("1.5")},
+		{name: "slice", value: []int{1, 2}, expected: []int{1, 2}},
+		{name: "map", value: map[string]int{"a": 1}, expected: map[string]int{"a": 1}},
+		{name: "struct", value: item{A: 1}, expected: item{A: 1}},
+		{name: "pointer to struct", value: &item{A: 1}, expected: &item{A: 1}},
+		{name: "pointers are dereferenced", value: &str, expected: "a"},
+		{name: "pointer to pointer", value: func() **int { p := &n; return &p }(), expected: 3},
+		{name: "nil pointer", value: nilStr, expected: nil},
+		{name: "valuer", value: sql.NullString{String: "a", Valid: true}, expected: "a"},
+		{name: "null valuer", value: sql.NullInt64{}, expected: nil},
+		{name: "nil valuer", value: nilValuer, expected: nil},
+		{name: "attribute value", value: &dynamodb.AttributeValue{S: aws.String("a")}, expected: &dynamodb.AttributeValue{S: aws.String("a")}},
+		{name: "chan", value: make(chan int), err: "invalid value type chan int"},
+		{name: "complex", value: 1i, err: "invalid value type complex128"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := CheckArgument(test.value)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, v)
+		})
+	}
+}
+
+func TestNewBatchGetRequests(t *testing.T) {
+	table := schema.NewTableFromCreate(fixtures.GameScores.Create)
+	prepareQuery := func(t *testing.T, query string) *PreparedQuery {
+		t.Helper()
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		q, err := PrepareSelect(table, ast.Select)
+		require.NoError(t, err)
+		require.NotNil(t, q.BatchGet)
+		return q
+	}
+	key := func(user, game string) map[string]*dynamodb.AttributeValue {
+		return map[string]*dynamodb.AttributeValue{"UserId": {S: aws.String(user)}, "GameTitle": {S: aws.String(game)}}
+	}
+
+	t.Run("every combination of the key values", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT Wins FROM gamescores WHERE UserId IN (:users) AND GameTitle IN ("A", "B", "A")`)
+		reqs, err := q.NewBatchGetRequests([]driver.NamedValue{{Name: "users", Value: []string{"1", "2"}}})
+		require.NoError(t, err)
+		require.Len(t, reqs, 1)
+		require.Equal(t, &dynamodb.KeysAndAttributes{
+			Keys:                 []map[string]*dynamodb.AttributeValue{key("1", "A"), key("2", "A"), key("1", "B"), key("2", "B")},
+			ProjectionExpression: aws.String("Wins"),
+		}, reqs[0].RequestItems["gamescores"])
+		// The prepared request is not modified by binding.
+		require.Nil(t, q.BatchGet.RequestItems["gamescores"].Keys)
+	})
+
+	t.Run("split into requests of 100 keys", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId IN (?) AND GameTitle IN (?)`)
+		users := make([]string, 15)
+		for i := range users {
+			users[i] = fmt.Sprint(i)
+		}
+		reqs, err := q.NewBatchGetRequests([]driver.NamedValue{
+			{Ordinal: 1, Value: users},
+			{Ordinal: 2, Value: users[:10]},
+		})
+		require.NoError(t, err)
+		require.Len(t, reqs, 2)
+		require.Len(t, reqs[0].RequestItems["gamescores"].Keys, 100)
+		require.Len(t, reqs[1].RequestItems["gamescores"].Keys, 50)
+	})
+
+	t.Run("key attributes cannot be bound to an unexpanded list", func(t *testing.T) {
+		q := prepareQuery(t, `SELECT * FROM gamescores WHERE UserId = :user AND GameTitle IN (:games)`)
+		_, err := q.NewBatchGetRequests([]driver.NamedValue{{Name: "user", Value: []string{"1"}}, {Name: "games", Value: "A"}})
+		require.EqualError(t, err, `binding ":user": invalid value type []string`)
+	})
+}
+
+func TestPrepareSelectIsDeterministic(t *testing.T) {
+	table := schema.NewTableFromCreate(fixtures.Movies.Create)
+	query := `SELECT title, info.rating, info.actors FROM movies WHERE title = :title AND year > 2009 AND info.rating > 7 AND contains(info.actors, "Will Smith")`
+	var first *PreparedQuery
+	for i := 0; i < 10; i++ {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		q, err := PrepareSelect(table, ast.Select)
+		require.NoError(t, err)
+		if first == nil {
+			first = q
+			continue
+		}
+		require.Equal(t, first, q)
+	}
+	require.Equal(t, "title = :title AND #year > :_gen1", *first.Query.KeyConditionExpression)
+	require.Equal(t, "info.rating > :_gen2 AND contains(info.actors, :_gen3)", *first.Query.FilterExpression)
+	require.Equal(t, "title, info.rating, info.actors", *first.Query.ProjectionExpression)
+}
+
+func TestAttributeNamesWithDotsAndBrackets(t *testing.T) {
+	table := schema.NewTableFromCreate(&dynamodb.CreateTableInput{
+		TableName: aws.String("events"),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("user.id"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			{AttributeName: aws.String("at[utc]"), KeyType: aws.String(dynamodb.KeyTypeRange)},
+		},
+	})
+	prepare := func(t *testing.T, query string) *PreparedQuery {
+		t.Helper()
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		q, err := PrepareSelect(table, ast.Select)
+		require.NoError(t, err)
+		return q
+	}
+
+	t.Run("quoted attribute names are keys", func(t *testing.T) {
+		q := prepare(t, "SELECT `user.id`, data['a.b'], data['c[0]'].d FROM events WHERE `user.id` = :user AND `at[utc]` > :since ORDER BY `at[utc]` DESC")
+		require.NotNil(t, q.Query)
+		require.Equal(t, "#_gen1 = :user AND #_gen4 > :since", *q.Query.KeyConditionExpression)
+		require.Equal(t, "#_gen1, #data.#_gen2, #data.#_gen3.d", *q.Query.ProjectionExpression)
+		require.Equal(t, map[string]*string{
+			"#_gen1": aws.String("user.id"),
+			"#data":  aws.String("data"),
+			"#_gen2": aws.String("a.b"),
+			"#_gen3": aws.String("c[0]"),
+			"#_gen4": aws.String("at[utc]"),
+		}, q.Query.ExpressionAttributeNames)
+	})
+
+	t.Run("a map entry spelled like a key is not a key", func(t *testing.T) {
+		q := prepare(t, "SELECT * FROM events WHERE user.id = :user")
+		require.Nil(t, q.Query)
+		require.NotNil(t, q.Scan)
+		require.Equal(t, "#user.id = :user", *q.Scan.FilterExpression)
+
+		ast, err := parser.Parse("SELECT * FROM events WHERE `user.id` = :user ORDER BY at.utc")
+		require.NoError(t, err)
+		_, err = PrepareSelect(table, ast.Select)
+		require.EqualError(t, err, `cannot ORDER BY "at.utc", DynamoDB can only order by the sort key "at[utc]"`)
+	})
 }
@@ -0,0 +1,81 @@
+package querybuilder
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/repr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// ShowTablesColumns are the columns of the rows returned by SHOW TABLES.
+var ShowTablesColumns = []string{"table"}
+
+// PreparedShowTables is a SHOW TABLES, which lists the tables in the account and region.
+type PreparedShowTables struct {
+	// Like matches the table names to return, or is nil to return every table.
+	Like *regexp.Regexp
+}
+
+func PrepareShowTables(ast *parser.AST) (*PreparedShowTables, error) {
+	if ast.ShowTables == nil {
+		return nil, fmt.Errorf("expected SHOW TABLES but got %s", repr.String(ast))
+	}
+	prepared := &PreparedShowTables{}
+	if ast.ShowTables.Like != nil {
+		prepared.Like = LikeRegexp(*ast.ShowTables.Like)
+	}
+	return prepared, nil
+}
+
+// Rows pages through ListTables and returns a row per table, in the order DynamoDB lists them. ListTables cannot
+// filter, so the LIKE pattern is matched client side.
+func (p *PreparedShowTables) Rows(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) ([][]string, error) {
+	var (
+		rows      [][]string
+		startName *string
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := dynamo.ListTablesWithContext(ctx, &dynamodb.ListTablesInput{ExclusiveStartTableName: startName})
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range resp.TableNames {
+			if p.Like == nil || p.Like.MatchString(aws.StringValue(name)) {
+				rows = append(rows, []string{aws.StringValue(name)})
+			}
+		}
+		if resp.LastEvaluatedTableName == nil {
+			return rows, nil
+		}
+		startName = resp.LastEvaluatedTableName
+	}
+}
+
+// LikeRegexp compiles a SQL LIKE pattern, in which % matches any sequence of characters and _ matches any single
+// character, to an anchored regular expression.
+func LikeRegexp(pattern string) *regexp.Regexp {
+	var re strings.Builder
+	re.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			re.WriteString(".*")
+		case '_':
+			re.WriteString(".")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	re.WriteString("$")
+	return regexp.MustCompile(re.String())
+}
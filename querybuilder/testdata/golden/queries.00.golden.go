@@ -6,6 +6,8 @@ querybuilder.item{
       KeyConditionExpression: &"UserId = :UserId",
       TableName: &"gamescores",
     },
+    All: true,
+    Plan: "Query table \"gamescores\"",
     NamedParams: querybuilder.NamedParams{
       ":UserId": querybuilder.Empty{      },
     },
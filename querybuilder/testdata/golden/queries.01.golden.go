@@ -7,6 +7,8 @@ querybuilder.item{
       KeyConditionExpression: &"UserId = :UserId",
       TableName: &"gamescores",
     },
+    All: true,
+    Plan: "Query table \"gamescores\"",
     NamedParams: querybuilder.NamedParams{
       ":MinTopScore": querybuilder.Empty{      },
       ":UserId": querybuilder.Empty{      },
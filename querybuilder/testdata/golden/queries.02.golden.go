@@ -7,6 +7,8 @@ querybuilder.item{
       KeyConditionExpression: &"UserId = :UserId AND GameTitle BETWEEN :MinGameTitle AND :MaxGameTitle",
       TableName: &"gamescores",
     },
+    All: true,
+    Plan: "Query table \"gamescores\"",
     NamedParams: querybuilder.NamedParams{
       ":MaxGameTitle": querybuilder.Empty{      },
       ":MinGameTitle": querybuilder.Empty{      },
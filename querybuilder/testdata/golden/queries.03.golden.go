@@ -7,13 +7,15 @@ querybuilder.item{
       KeyConditionExpression: &"UserId = :_gen1 AND GameTitle BETWEEN :_gen2 AND :_gen3",
       TableName: &"gamescores",
     },
+    All: true,
+    Plan: "Query table \"gamescores\"",
     NamedParams: querybuilder.NamedParams{    },
     PositionalParams: map[int]string{    },
     FixedParams: map[string]interface {}{
       ":_gen1": "103",
       ":_gen2": "Galaxy",
       ":_gen3": "Meteor",
-      ":_gen4": 1000,
+      ":_gen4": json.Number("1000"),
     },
   },
 }
\ No newline at end of file
@@ -6,6 +6,8 @@ querybuilder.item{
       KeyConditionExpression: &"UserId = :_gen1 AND begins_with(GameTitle, :_gen2)",
       TableName: &"gamescores",
     },
+    All: true,
+    Plan: "Query table \"gamescores\"",
     NamedParams: querybuilder.NamedParams{    },
     PositionalParams: map[int]string{    },
     FixedParams: map[string]interface {}{
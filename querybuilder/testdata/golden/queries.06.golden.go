@@ -11,6 +11,7 @@ querybuilder.item{
       ProjectionExpression: &"UserId, TopScore, Scores[3], Scores[3][2], Studio.#Name, Studio.#Location.Country, Studio.Employees[3]",
       TableName: &"gamescores",
     },
+    Plan: "Query table \"gamescores\"",
     Columns: []*parser.ProjectionColumn{
       {
         Function: &parser.FunctionExpression{
@@ -39,8 +40,10 @@ querybuilder.item{
                 Fragment: []*parser.PathFragment{
                   {
                     Symbol: "Scores",
-                    Indexes: []int{
-                      3,
+                    Accessors: []*parser.PathAccessor{
+                      {
+                        Index: &3,
+                      },
                     },
                   },
                 },
@@ -51,9 +54,13 @@ querybuilder.item{
                 Fragment: []*parser.PathFragment{
                   {
                     Symbol: "Scores",
-                    Indexes: []int{
-                      3,
-                      2,
+                    Accessors: []*parser.PathAccessor{
+                      {
+                        Index: &3,
+                      },
+                      {
+                        Index: &2,
+                      },
                     },
                   },
                 },
@@ -94,8 +101,10 @@ querybuilder.item{
                   },
                   {
                     Symbol: "Employees",
-                    Indexes: []int{
-                      3,
+                    Accessors: []*parser.PathAccessor{
+                      {
+                        Index: &3,
+                      },
                     },
                   },
                 },
@@ -12,6 +12,7 @@ querybuilder.item{
       ProjectionExpression: &"title, #year",
       TableName: &"movies",
     },
+    Plan: "Query table \"movies\"",
     Columns: []*parser.ProjectionColumn{
       {
         DocumentPath: &parser.DocumentPath{
@@ -37,7 +38,7 @@ querybuilder.item{
     },
     PositionalParams: map[int]string{    },
     FixedParams: map[string]interface {}{
-      ":_gen1": 2009,
+      ":_gen1": json.Number("2009"),
       ":_gen2": true,
     },
   },
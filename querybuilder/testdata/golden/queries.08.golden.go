@@ -10,6 +10,7 @@ querybuilder.item{
       ProjectionExpression: &"#_gen1, foo.bar",
       TableName: &"movies",
     },
+    Plan: "Query table \"movies\"",
     Columns: []*parser.ProjectionColumn{
       {
         DocumentPath: &parser.DocumentPath{
@@ -8,6 +8,8 @@ querybuilder.item{
       KeyConditionExpression: &"GameTitle = :title",
       TableName: &"gamescores",
     },
+    All: true,
+    Plan: "Query index \"GameTitleIndex\" of table \"gamescores\"",
     NamedParams: querybuilder.NamedParams{
       ":title": querybuilder.Empty{      },
     },
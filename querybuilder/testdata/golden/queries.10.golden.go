@@ -9,6 +9,8 @@ querybuilder.item{
       TableName: &"gamescores",
     },
     Limit: 1,
+    All: true,
+    Plan: "Query table \"gamescores\"",
     NamedParams: querybuilder.NamedParams{    },
     PositionalParams: map[int]string{    },
     FixedParams: map[string]interface {}{
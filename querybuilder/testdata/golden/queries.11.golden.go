@@ -7,6 +7,8 @@ querybuilder.item{
       KeyConditionExpression: &"UserId = :_pos1",
       TableName: &"gamescores",
     },
+    All: true,
+    Plan: "Query table \"gamescores\"",
     NamedParams: querybuilder.NamedParams{    },
     PositionalParams: map[int]string{
       1: ":_pos1",
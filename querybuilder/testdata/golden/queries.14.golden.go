@@ -0,0 +1,19 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE Wins = 3 LIMIT 10",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      FilterExpression: &"Wins = :_gen1",
+      TableName: &"gamescores",
+    },
+    Limit: 10,
+    All: true,
+    Plan: "Scan table \"gamescores\"",
+    ScanReason: "WHERE needs UserId = value for table \"gamescores\", or GameTitle = value for index \"GameTitleIndex\", ANDed with its other conditions, to Query",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": json.Number("3"),
+    },
+  },
+}
\ No newline at end of file
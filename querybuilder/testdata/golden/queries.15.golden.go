@@ -0,0 +1,29 @@
+querybuilder.item{
+  Query: "SELECT GameTitle FROM gamescores WHERE NOT UserId = :UserId",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      FilterExpression: &"NOT UserId = :UserId",
+      ProjectionExpression: &"GameTitle",
+      TableName: &"gamescores",
+    },
+    Plan: "Scan table \"gamescores\"",
+    ScanReason: "WHERE needs UserId = value for table \"gamescores\", or GameTitle = value for index \"GameTitleIndex\", ANDed with its other conditions, to Query",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "GameTitle",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{
+      ":UserId": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
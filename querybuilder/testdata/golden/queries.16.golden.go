@@ -0,0 +1,20 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId > :UserId AND begins_with(GameTitle, \"Galaxy\")",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      FilterExpression: &"UserId > :UserId AND begins_with(GameTitle, :_gen1)",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Scan table \"gamescores\"",
+    ScanReason: "WHERE needs UserId = value for table \"gamescores\", or GameTitle = value for index \"GameTitleIndex\", ANDed with its other conditions, to Query",
+    NamedParams: querybuilder.NamedParams{
+      ":UserId": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "Galaxy",
+    },
+  },
+}
\ No newline at end of file
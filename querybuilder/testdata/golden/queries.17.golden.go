@@ -0,0 +1,19 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores USE INDEX (GameTitleIndex) WHERE UserId = \"101\"",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      FilterExpression: &"UserId = :_gen1",
+      IndexName: &"GameTitleIndex",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Scan index \"GameTitleIndex\" of table \"gamescores\"",
+    ScanReason: "WHERE needs GameTitle = value for index \"GameTitleIndex\", ANDed with its other conditions, to Query",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "101",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,18 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = \"103\" ORDER BY GameTitle ASC",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      KeyConditionExpression: &"UserId = :_gen1",
+      ScanIndexForward: &true,
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+    },
+  },
+}
\ No newline at end of file
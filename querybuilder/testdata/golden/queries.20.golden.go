@@ -0,0 +1,19 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores USE INDEX (UserWinsIndex) WHERE UserId = \"103\" ORDER BY Wins DESC",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      IndexName: &"UserWinsIndex",
+      KeyConditionExpression: &"UserId = :_gen1",
+      ScanIndexForward: &false,
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query index \"UserWinsIndex\" of table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,20 @@
+querybuilder.item{
+  Query: "SELECT COUNT(*) FROM gamescores WHERE UserId = \"103\" AND Wins > 10",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      IndexName: &"UserWinsIndex",
+      KeyConditionExpression: &"UserId = :_gen1 AND Wins > :_gen2",
+      Select: &"COUNT",
+      TableName: &"gamescores",
+    },
+    Count: true,
+    Plan: "Query index \"UserWinsIndex\" of table \"gamescores\" (index selected automatically)",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+      ":_gen2": json.Number("10"),
+    },
+  },
+}
\ No newline at end of file
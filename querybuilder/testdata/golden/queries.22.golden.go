@@ -0,0 +1,16 @@
+querybuilder.item{
+  Query: "SELECT COUNT(*) FROM gamescores",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      Select: &"COUNT",
+      TableName: &"gamescores",
+    },
+    Count: true,
+    Plan: "Scan table \"gamescores\"",
+    ScanReason: "WHERE needs UserId = value for table \"gamescores\", or GameTitle = value for index \"GameTitleIndex\", ANDed with its other conditions, to Query",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,22 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = \"103\" AND Wins IS NULL AND Name.first IS NOT NULL",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#Name": &"Name",
+        "#first": &"first",
+      },
+      FilterExpression: &"attribute_not_exists(Wins) AND attribute_exists(#Name.#first)",
+      KeyConditionExpression: &"UserId = :_gen1",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+    },
+  },
+}
\ No newline at end of file
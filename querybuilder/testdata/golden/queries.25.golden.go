@@ -0,0 +1,18 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = \"103\" AND GameTitle LIKE 'Galaxy%'",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      KeyConditionExpression: &"UserId = :_gen1 AND begins_with(GameTitle, :_gen2)",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+      ":_gen2": "Galaxy",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,22 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = \"103\" AND NOT Name LIKE \"B%\"",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#Name": &"Name",
+      },
+      FilterExpression: &"NOT begins_with(#Name, :_gen2)",
+      KeyConditionExpression: &"UserId = :_gen1",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+      ":_gen2": "B",
+    },
+  },
+}
\ No newline at end of file
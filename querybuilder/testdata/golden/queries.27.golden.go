@@ -0,0 +1,21 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = \"103\" AND Wins IN (1, 2, 3)",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      FilterExpression: &"Wins IN (:_gen2, :_gen3, :_gen4)",
+      KeyConditionExpression: &"UserId = :_gen1",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+      ":_gen2": json.Number("1"),
+      ":_gen3": json.Number("2"),
+      ":_gen4": json.Number("3"),
+    },
+  },
+}
\ No newline at end of file
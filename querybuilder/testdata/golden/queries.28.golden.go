@@ -0,0 +1,22 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = \"103\" AND Wins NOT IN (1, 2) AND TopScore NOT BETWEEN 10 AND 20",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      FilterExpression: &"NOT (Wins IN (:_gen2, :_gen3)) AND NOT (TopScore BETWEEN :_gen4 AND :_gen5)",
+      KeyConditionExpression: &"UserId = :_gen1",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+      ":_gen2": json.Number("1"),
+      ":_gen3": json.Number("2"),
+      ":_gen4": json.Number("10"),
+      ":_gen5": json.Number("20"),
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,24 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = \"103\" AND (Wins NOT IN (:a, :b) OR NOT (Losses NOT BETWEEN 1 AND 2) OR TopScore IN (5))",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      FilterExpression: &"(NOT (Wins IN (:a, :b)) OR NOT (NOT (Losses BETWEEN :_gen2 AND :_gen3)) OR TopScore IN (:_gen4))",
+      KeyConditionExpression: &"UserId = :_gen1",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{
+      ":a": querybuilder.Empty{      },
+      ":b": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+      ":_gen2": json.Number("1"),
+      ":_gen3": json.Number("2"),
+      ":_gen4": json.Number("5"),
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,19 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = :UserId LIMIT :n",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      KeyConditionExpression: &"UserId = :UserId",
+      TableName: &"gamescores",
+    },
+    LimitParam: ":n",
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{
+      ":UserId": querybuilder.Empty{      },
+      ":n": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
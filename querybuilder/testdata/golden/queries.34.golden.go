@@ -0,0 +1,21 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = ? AND Wins > ? LIMIT ?",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      FilterExpression: &"Wins > :_pos2",
+      KeyConditionExpression: &"UserId = :_pos1",
+      TableName: &"gamescores",
+    },
+    LimitParam: ":_pos3",
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{
+      1: ":_pos1",
+      2: ":_pos2",
+      3: ":_pos3",
+    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
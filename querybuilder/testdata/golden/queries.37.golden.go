@@ -0,0 +1,46 @@
+querybuilder.item{
+  Query: "SELECT Name.first AS first, TopScore AS score FROM gamescores WHERE UserId = \"103\"",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#Name": &"Name",
+        "#first": &"first",
+      },
+      KeyConditionExpression: &"UserId = :_gen1",
+      ProjectionExpression: &"#Name.#first, TopScore",
+      TableName: &"gamescores",
+    },
+    Plan: "Query table \"gamescores\"",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "Name",
+            },
+            {
+              Symbol: "first",
+            },
+          },
+        },
+        Alias: &"first",
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "TopScore",
+            },
+          },
+        },
+        Alias: &"score",
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,21 @@
+querybuilder.item{
+  Query: "SELECT * FROM movies WHERE year > 2000 WITH (SEGMENTS = 8)",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#year": &"year",
+      },
+      FilterExpression: &"#year > :_gen1",
+      TableName: &"movies",
+    },
+    All: true,
+    Segments: 8,
+    Plan: "Scan table \"movies\" in 8 parallel segments",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": json.Number("2000"),
+    },
+  },
+}
\ No newline at end of file
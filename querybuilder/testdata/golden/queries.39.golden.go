@@ -0,0 +1,17 @@
+querybuilder.item{
+  Query: "SELECT COUNT(*) FROM movies WITH (CONSISTENT, SEGMENTS = 4)",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      ConsistentRead: &true,
+      Select: &"COUNT",
+      TableName: &"movies",
+    },
+    Count: true,
+    Segments: 4,
+    Plan: "Scan table \"movies\" in 4 parallel segments",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,48 @@
+querybuilder.item{
+  Query: "SELECT UserId, GameTitle, TopScore FROM gamescores WHERE GameTitle = \"Meteor Blasters\" AND TopScore > 1000",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      IndexName: &"GameTitleIndex",
+      KeyConditionExpression: &"GameTitle = :_gen1 AND TopScore > :_gen2",
+      ProjectionExpression: &"UserId, GameTitle, TopScore",
+      TableName: &"gamescores",
+    },
+    Plan: "Query index \"GameTitleIndex\" of table \"gamescores\" (index selected automatically)",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "UserId",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "GameTitle",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "TopScore",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "Meteor Blasters",
+      ":_gen2": json.Number("1000"),
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,18 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE GameTitle = \"Meteor Blasters\"",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      FilterExpression: &"GameTitle = :_gen1",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Scan table \"gamescores\"",
+    ScanReason: "WHERE needs UserId = value for table \"gamescores\", ANDed with its other conditions, to Query; index \"GameTitleIndex\" can not be queried by this SELECT",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "Meteor Blasters",
+    },
+  },
+}
\ No newline at end of file
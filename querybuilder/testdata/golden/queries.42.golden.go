@@ -0,0 +1,48 @@
+querybuilder.item{
+  Query: "SELECT UserId, Wins, TopScore FROM gamescores WHERE UserId = \"103\" AND Wins = 3",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      IndexName: &"UserWinsIndex",
+      KeyConditionExpression: &"UserId = :_gen1 AND Wins = :_gen2",
+      ProjectionExpression: &"UserId, Wins, TopScore",
+      TableName: &"gamescores",
+    },
+    Plan: "Query index \"UserWinsIndex\" of table \"gamescores\" (index selected automatically)",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "UserId",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "Wins",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "TopScore",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+      ":_gen2": json.Number("3"),
+    },
+  },
+}
\ No newline at end of file
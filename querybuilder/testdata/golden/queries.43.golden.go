@@ -0,0 +1,39 @@
+querybuilder.item{
+  Query: "SELECT UserId, Wins FROM gamescores WHERE UserId = \"103\" ORDER BY Wins DESC",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      IndexName: &"UserWinsIndex",
+      KeyConditionExpression: &"UserId = :_gen1",
+      ProjectionExpression: &"UserId, Wins",
+      ScanIndexForward: &false,
+      TableName: &"gamescores",
+    },
+    Plan: "Query index \"UserWinsIndex\" of table \"gamescores\" (index selected automatically)",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "UserId",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "Wins",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+    },
+  },
+}
\ No newline at end of file
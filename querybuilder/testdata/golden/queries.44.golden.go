@@ -0,0 +1,30 @@
+querybuilder.item{
+  Query: "SELECT UserId FROM gamescores WHERE GameTitle = \"Meteor Blasters\" WITH (CONSISTENT)",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      ConsistentRead: &true,
+      FilterExpression: &"GameTitle = :_gen1",
+      ProjectionExpression: &"UserId",
+      TableName: &"gamescores",
+    },
+    Plan: "Scan table \"gamescores\"",
+    ScanReason: "WHERE needs UserId = value for table \"gamescores\", ANDed with its other conditions, to Query; index \"GameTitleIndex\" can not be queried by this SELECT",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "UserId",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "Meteor Blasters",
+    },
+  },
+}
\ No newline at end of file
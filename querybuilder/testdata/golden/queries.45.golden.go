@@ -0,0 +1,72 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = \"103\" AND Wins IN (:wins)",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      FilterExpression: &"Wins IN (:wins)",
+      KeyConditionExpression: &"UserId = :_gen1",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{
+      ":wins": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+    },
+    ListParams: querybuilder.NamedParams{
+      ":wins": querybuilder.Empty{      },
+    },
+    listFilter: &querybuilder.listExpression{
+      ctx: &querybuilder.Context{
+        HashKey: "UserId",
+        SortKey: "GameTitle",
+        NamedParams: map[string]querybuilder.Empty{
+          ":wins": querybuilder.Empty{          },
+        },
+        PositionalParams: map[int]string{        },
+        FixedParams: map[string]interface {}{
+          ":_gen1": "103",
+        },
+        Substitutions: map[string]string{        },
+        ListParams: querybuilder.NamedParams{
+          ":wins": querybuilder.Empty{          },
+        },
+        genParamCount: 1,
+      },
+      filter: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Pos: lexer.Position{
+              Offset: 50,
+              Line: 1,
+              Column: 51,
+            },
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "Wins",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                In: &parser.In{
+                  Values: []*parser.Value{
+                    {
+                      Scalar: parser.Scalar{
+                      },
+                      PlaceHolder: &":wins",
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,73 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId IN (?)",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      FilterExpression: &"UserId IN (:_pos1)",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Scan table \"gamescores\"",
+    ScanReason: "WHERE needs UserId = value for table \"gamescores\", or GameTitle = value for index \"GameTitleIndex\", ANDed with its other conditions, to Query",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{
+      1: ":_pos1",
+    },
+    FixedParams: map[string]interface {}{    },
+    ListParams: querybuilder.NamedParams{
+      ":_pos1": querybuilder.Empty{      },
+    },
+    listFilter: &querybuilder.listExpression{
+      ctx: &querybuilder.Context{
+        HashKey: "UserId",
+        SortKey: "GameTitle",
+        NamedParams: map[string]querybuilder.Empty{        },
+        PositionalParams: map[int]string{
+          1: ":_pos1",
+        },
+        FixedParams: map[string]interface {}{        },
+        Substitutions: map[string]string{        },
+        ListParams: querybuilder.NamedParams{
+          ":_pos1": querybuilder.Empty{          },
+        },
+        positionalParamCount: 1,
+      },
+      scan: true,
+      filter: &parser.ConditionExpression{
+        Or: []*parser.AndExpression{
+          {
+            And: []*parser.Condition{
+              {
+                Pos: lexer.Position{
+                  Offset: 31,
+                  Line: 1,
+                  Column: 32,
+                },
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "UserId",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    In: &parser.In{
+                      Values: []*parser.Value{
+                        {
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":_pos1",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
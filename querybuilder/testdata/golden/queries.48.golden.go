@@ -0,0 +1,25 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE (Wins = 3 OR Losses = 1) AND TopScore > 100 OR NOT (Name = \"Bob\" OR Name = \"Alice\")",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#Name": &"Name",
+      },
+      FilterExpression: &"(Wins = :_gen1 OR Losses = :_gen2) AND TopScore > :_gen3 OR NOT (#Name = :_gen4 OR #Name = :_gen5)",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Scan table \"gamescores\"",
+    ScanReason: "WHERE needs UserId = value for table \"gamescores\", or GameTitle = value for index \"GameTitleIndex\", ANDed with its other conditions, to Query",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": json.Number("3"),
+      ":_gen2": json.Number("1"),
+      ":_gen3": json.Number("100"),
+      ":_gen4": "Bob",
+      ":_gen5": "Alice",
+    },
+  },
+}
\ No newline at end of file
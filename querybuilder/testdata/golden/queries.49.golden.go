@@ -0,0 +1,75 @@
+querybuilder.item{
+  Query: "SELECT Name['first name'], Name[\"last\"], Scores[0]['a.b'][1] FROM gamescores WHERE UserId = \"103\" AND Name['first name'] = \"Bob\"",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#Name": &"Name",
+        "#_gen1": &"first name",
+        "#_gen2": &"a.b",
+        "#last": &"last",
+      },
+      FilterExpression: &"#Name.#_gen1 = :_gen2",
+      KeyConditionExpression: &"UserId = :_gen1",
+      ProjectionExpression: &"#Name.#_gen1, #Name.#last, Scores[0].#_gen2[1]",
+      TableName: &"gamescores",
+    },
+    Plan: "Query table \"gamescores\"",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "Name",
+              Accessors: []*parser.PathAccessor{
+                {
+                  Key: &"first name",
+                },
+              },
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "Name",
+              Accessors: []*parser.PathAccessor{
+                {
+                  Key: &"last",
+                },
+              },
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "Scores",
+              Accessors: []*parser.PathAccessor{
+                {
+                  Index: &0,
+                },
+                {
+                  Key: &"a.b",
+                },
+                {
+                  Index: &1,
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+      ":_gen2": "Bob",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,26 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = \"103\" AND size(Scores) > 3 AND size(Name.first) NOT BETWEEN 1 AND :max",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#Name": &"Name",
+        "#first": &"first",
+      },
+      FilterExpression: &"size(Scores) > :_gen2 AND NOT (size(#Name.#first) BETWEEN :_gen3 AND :max)",
+      KeyConditionExpression: &"UserId = :_gen1",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{
+      ":max": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+      ":_gen2": json.Number("3"),
+      ":_gen3": json.Number("1"),
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,23 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE size(Tags) IN (1, 2) OR size(year) = 0",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#year": &"year",
+      },
+      FilterExpression: &"size(Tags) IN (:_gen1, :_gen2) OR size(#year) = :_gen3",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Scan table \"gamescores\"",
+    ScanReason: "WHERE needs UserId = value for table \"gamescores\", or GameTitle = value for index \"GameTitleIndex\", ANDed with its other conditions, to Query",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": json.Number("1"),
+      ":_gen2": json.Number("2"),
+      ":_gen3": json.Number("0"),
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,23 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = \"103\" AND attribute_type(Scores, L) AND attribute_type(Name, 'NULL')",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#Name": &"Name",
+      },
+      FilterExpression: &"attribute_type(Scores, :_gen2) AND attribute_type(#Name, :_gen3)",
+      KeyConditionExpression: &"UserId = :_gen1",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+      ":_gen2": "L",
+      ":_gen3": "NULL",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,21 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = :UserId AND GameTitle = :GameTitle",
+  Prepared: &querybuilder.PreparedQuery{
+    GetItem: &dynamodb.GetItemInput{
+      _: struct {}{      },
+      TableName: &"gamescores",
+    },
+    KeyParams: map[string]string{
+      "GameTitle": ":GameTitle",
+      "UserId": ":UserId",
+    },
+    All: true,
+    Plan: "GetItem table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{
+      ":GameTitle": querybuilder.Empty{      },
+      ":UserId": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
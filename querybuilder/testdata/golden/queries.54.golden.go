@@ -0,0 +1,46 @@
+querybuilder.item{
+  Query: "SELECT title, info.rating FROM movies WHERE year = 1995 AND title = \"Heat\" LIMIT 1 WITH (CONSISTENT)",
+  Prepared: &querybuilder.PreparedQuery{
+    GetItem: &dynamodb.GetItemInput{
+      _: struct {}{      },
+      ConsistentRead: &true,
+      ProjectionExpression: &"title, info.rating",
+      TableName: &"movies",
+    },
+    KeyParams: map[string]string{
+      "title": ":_gen2",
+      "year": ":_gen1",
+    },
+    Limit: 1,
+    Plan: "GetItem table \"movies\"",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "title",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "info",
+            },
+            {
+              Symbol: "rating",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": json.Number("1995"),
+      ":_gen2": "Heat",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,20 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = \"103\" AND GameTitle = \"Galaxy\" AND TopScore > 1000",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      FilterExpression: &"TopScore > :_gen3",
+      KeyConditionExpression: &"UserId = :_gen1 AND GameTitle = :_gen2",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+      ":_gen2": "Galaxy",
+      ":_gen3": json.Number("1000"),
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,19 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = \"Galaxy\" AND TopScore = 1000",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      IndexName: &"GameTitleIndex",
+      KeyConditionExpression: &"GameTitle = :_gen1 AND TopScore = :_gen2",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query index \"GameTitleIndex\" of table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "Galaxy",
+      ":_gen2": json.Number("1000"),
+    },
+  },
+}
\ No newline at end of file
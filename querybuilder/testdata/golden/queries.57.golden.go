@@ -0,0 +1,32 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = :UserId AND GameTitle IN (\"A\", \"B\")",
+  Prepared: &querybuilder.PreparedQuery{
+    BatchGet: &dynamodb.BatchGetItemInput{
+      _: struct {}{      },
+      RequestItems: map[string]*dynamodb.KeysAndAttributes{
+        "gamescores": &dynamodb.KeysAndAttributes{
+          _: struct {}{          },
+        },
+      },
+    },
+    BatchKeyParams: map[string][]string{
+      "GameTitle": []string{
+        ":_gen1",
+        ":_gen2",
+      },
+      "UserId": []string{
+        ":UserId",
+      },
+    },
+    All: true,
+    Plan: "BatchGetItem table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{
+      ":UserId": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "A",
+      ":_gen2": "B",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,59 @@
+querybuilder.item{
+  Query: "SELECT title, info.rating FROM movies WHERE title IN (:titles) AND year IN (1995, 1998) WITH (CONSISTENT)",
+  Prepared: &querybuilder.PreparedQuery{
+    BatchGet: &dynamodb.BatchGetItemInput{
+      _: struct {}{      },
+      RequestItems: map[string]*dynamodb.KeysAndAttributes{
+        "movies": &dynamodb.KeysAndAttributes{
+          _: struct {}{          },
+          ConsistentRead: &true,
+          ProjectionExpression: &"title, info.rating",
+        },
+      },
+    },
+    BatchKeyParams: map[string][]string{
+      "title": []string{
+        ":titles",
+      },
+      "year": []string{
+        ":_gen1",
+        ":_gen2",
+      },
+    },
+    Plan: "BatchGetItem table \"movies\"",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "title",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "info",
+            },
+            {
+              Symbol: "rating",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{
+      ":titles": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": json.Number("1995"),
+      ":_gen2": json.Number("1998"),
+    },
+    ListParams: querybuilder.NamedParams{
+      ":titles": querybuilder.Empty{      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,24 @@
+querybuilder.item{
+  Query: "SELECT * FROM movies WHERE title IN (\"Heat\", \"Ronin\") AND year = 1995 AND rating > 5",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#year": &"year",
+      },
+      FilterExpression: &"title IN (:_gen1, :_gen2) AND #year = :_gen3 AND rating > :_gen4",
+      TableName: &"movies",
+    },
+    All: true,
+    Plan: "Scan table \"movies\"",
+    ScanReason: "WHERE needs title = value for table \"movies\", ANDed with its other conditions, to Query",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "Heat",
+      ":_gen2": "Ronin",
+      ":_gen3": json.Number("1995"),
+      ":_gen4": json.Number("5"),
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,40 @@
+querybuilder.item{
+  Query: "SELECT UserId, Wins FROM gamescores USE INDEX (UserWinsIndex) WHERE UserId = \"103\" AND Wins > 3 WITH (SCAN, SEGMENTS = 4)",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      FilterExpression: &"UserId = :_gen1 AND Wins > :_gen2",
+      IndexName: &"UserWinsIndex",
+      ProjectionExpression: &"UserId, Wins",
+      TableName: &"gamescores",
+    },
+    Segments: 4,
+    Plan: "Scan index \"UserWinsIndex\" of table \"gamescores\" in 4 parallel segments (forced by WITH (SCAN))",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "UserId",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "Wins",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+      ":_gen2": json.Number("3"),
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,19 @@
+querybuilder.item{
+  Query: "SELECT COUNT(*) FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = \"Galaxy\" WITH (SCAN)",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      FilterExpression: &"GameTitle = :_gen1",
+      IndexName: &"GameTitleIndex",
+      Select: &"COUNT",
+      TableName: &"gamescores",
+    },
+    Count: true,
+    Plan: "Scan index \"GameTitleIndex\" of table \"gamescores\" (forced by WITH (SCAN))",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "Galaxy",
+    },
+  },
+}
\ No newline at end of file
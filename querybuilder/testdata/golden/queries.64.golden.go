@@ -0,0 +1,130 @@
+querybuilder.item{
+  Query: "SELECT UserId, CASE WHEN Wins > 10 THEN \"pro\" WHEN attribute_exists(Losses) THEN Losses ELSE UserId END AS rank FROM gamescores WHERE UserId = \"103\"",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      KeyConditionExpression: &"UserId = :_gen1",
+      ProjectionExpression: &"UserId, Wins, Losses",
+      TableName: &"gamescores",
+    },
+    Plan: "Query table \"gamescores\"",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "UserId",
+            },
+          },
+        },
+      },
+      {
+        Case: &parser.CaseExpression{
+          Whens: []*parser.CaseWhen{
+            {
+              Condition: &parser.ConditionExpression{
+                Or: []*parser.AndExpression{
+                  {
+                    And: []*parser.Condition{
+                      {
+                        Pos: lexer.Position{
+                          Offset: 25,
+                          Line: 1,
+                          Column: 26,
+                        },
+                        Operand: &parser.ConditionOperand{
+                          Operand: &parser.DocumentPath{
+                            Fragment: []*parser.PathFragment{
+                              {
+                                Symbol: "Wins",
+                              },
+                            },
+                          },
+                          ConditionRHS: &parser.ConditionRHS{
+                            Compare: &parser.Compare{
+                              Operator: ">",
+                              Operand: &parser.Operand{
+                                Value: &parser.Value{
+                                  Scalar: parser.Scalar{
+                                    Number: &10,
+                                    NumberText: &"10",
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              Result: &parser.Operand{
+                Value: &parser.Value{
+                  Scalar: parser.Scalar{
+                    Str: &"pro",
+                  },
+                },
+              },
+            },
+            {
+              Condition: &parser.ConditionExpression{
+                Or: []*parser.AndExpression{
+                  {
+                    And: []*parser.Condition{
+                      {
+                        Pos: lexer.Position{
+                          Offset: 51,
+                          Line: 1,
+                          Column: 52,
+                        },
+                        Function: &parser.FunctionExpression{
+                          Function: "attribute_exists",
+                          Args: []*parser.FunctionArgument{
+                            {
+                              DocumentPath: &parser.DocumentPath{
+                                Fragment: []*parser.PathFragment{
+                                  {
+                                    Symbol: "Losses",
+                                  },
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              Result: &parser.Operand{
+                SymbolRef: &parser.DocumentPath{
+                  Fragment: []*parser.PathFragment{
+                    {
+                      Symbol: "Losses",
+                    },
+                  },
+                },
+              },
+            },
+          },
+          Else: &parser.Operand{
+            SymbolRef: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "UserId",
+                },
+              },
+            },
+          },
+        },
+        Alias: &"rank",
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+    },
+  },
+}
\ No newline at end of file
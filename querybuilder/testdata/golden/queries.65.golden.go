@@ -0,0 +1,101 @@
+querybuilder.item{
+  Query: "SELECT UserId, Wins - Losses AS net, Wins / (Wins + Losses) FROM gamescores WHERE UserId = \"103\"",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      KeyConditionExpression: &"UserId = :_gen1",
+      ProjectionExpression: &"UserId, Wins, Losses",
+      TableName: &"gamescores",
+    },
+    Plan: "Query table \"gamescores\"",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "UserId",
+            },
+          },
+        },
+      },
+      {
+        Arithmetic: &parser.Arithmetic{
+          Operand: &parser.ArithmeticOperand{
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "Wins",
+                },
+              },
+            },
+          },
+          Ops: []*parser.ArithmeticOp{
+            {
+              Operator: "-",
+              Operand: &parser.ArithmeticOperand{
+                DocumentPath: &parser.DocumentPath{
+                  Fragment: []*parser.PathFragment{
+                    {
+                      Symbol: "Losses",
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+        Alias: &"net",
+      },
+      {
+        Arithmetic: &parser.Arithmetic{
+          Operand: &parser.ArithmeticOperand{
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "Wins",
+                },
+              },
+            },
+          },
+          Ops: []*parser.ArithmeticOp{
+            {
+              Operator: "/",
+              Operand: &parser.ArithmeticOperand{
+                Parenthesized: &parser.Arithmetic{
+                  Operand: &parser.ArithmeticOperand{
+                    DocumentPath: &parser.DocumentPath{
+                      Fragment: []*parser.PathFragment{
+                        {
+                          Symbol: "Wins",
+                        },
+                      },
+                    },
+                  },
+                  Ops: []*parser.ArithmeticOp{
+                    {
+                      Operator: "+",
+                      Operand: &parser.ArithmeticOperand{
+                        DocumentPath: &parser.DocumentPath{
+                          Fragment: []*parser.PathFragment{
+                            {
+                              Symbol: "Losses",
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+    },
+  },
+}
\ No newline at end of file
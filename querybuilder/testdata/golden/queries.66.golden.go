@@ -0,0 +1,22 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = ? AND GameTitle BETWEEN ? AND ? AND Wins BETWEEN ? AND ?",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      FilterExpression: &"Wins BETWEEN :_pos4 AND :_pos5",
+      KeyConditionExpression: &"UserId = :_pos1 AND GameTitle BETWEEN :_pos2 AND :_pos3",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{
+      1: ":_pos1",
+      2: ":_pos2",
+      3: ":_pos3",
+      4: ":_pos4",
+      5: ":_pos5",
+    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,20 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores USE INDEX (UserWinsIndex) WHERE UserId = \"103\" AND Wins BETWEEN 5 AND 10",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      IndexName: &"UserWinsIndex",
+      KeyConditionExpression: &"UserId = :_gen1 AND Wins BETWEEN :_gen2 AND :_gen3",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query index \"UserWinsIndex\" of table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+      ":_gen2": json.Number("5"),
+      ":_gen3": json.Number("10"),
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,41 @@
+querybuilder.item{
+  Query: "SELECT UserId, TopScore FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = \"Galaxy\" AND TopScore BETWEEN :lo AND :hi",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      IndexName: &"GameTitleIndex",
+      KeyConditionExpression: &"GameTitle = :_gen1 AND TopScore BETWEEN :lo AND :hi",
+      ProjectionExpression: &"UserId, TopScore",
+      TableName: &"gamescores",
+    },
+    Plan: "Query index \"GameTitleIndex\" of table \"gamescores\"",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "UserId",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "TopScore",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{
+      ":hi": querybuilder.Empty{      },
+      ":lo": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "Galaxy",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,42 @@
+querybuilder.item{
+  Query: "SELECT document(Studio.Location) AS location FROM gamescores WHERE UserId = :UserId",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#Location": &"Location",
+      },
+      KeyConditionExpression: &"UserId = :UserId",
+      ProjectionExpression: &"Studio.#Location",
+      TableName: &"gamescores",
+    },
+    Plan: "Query table \"gamescores\"",
+    Columns: []*parser.ProjectionColumn{
+      {
+        Function: &parser.FunctionExpression{
+          Function: "document",
+          Args: []*parser.FunctionArgument{
+            {
+              DocumentPath: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "Studio",
+                  },
+                  {
+                    Symbol: "Location",
+                  },
+                },
+              },
+            },
+          },
+        },
+        Alias: &"location",
+      },
+    },
+    NamedParams: querybuilder.NamedParams{
+      ":UserId": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
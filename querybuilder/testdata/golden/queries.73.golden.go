@@ -0,0 +1,23 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = ? AND NOT contains(Scores[0].Data, 100)",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#Data": &"Data",
+      },
+      FilterExpression: &"NOT contains(Scores[0].#Data, :_gen1)",
+      KeyConditionExpression: &"UserId = :_pos1",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{
+      1: ":_pos1",
+    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": json.Number("100"),
+    },
+  },
+}
\ No newline at end of file
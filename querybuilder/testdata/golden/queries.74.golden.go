@@ -0,0 +1,19 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = :UserId AND GameTitle >= :MinGameTitle AND GameTitle <= :MaxGameTitle",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      KeyConditionExpression: &"UserId = :UserId AND GameTitle BETWEEN :MinGameTitle AND :MaxGameTitle",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{
+      ":MaxGameTitle": querybuilder.Empty{      },
+      ":MinGameTitle": querybuilder.Empty{      },
+      ":UserId": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
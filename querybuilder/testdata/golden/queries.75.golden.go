@@ -0,0 +1,21 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE GameTitle <= \"Meteor\" AND UserId = \"103\" AND TopScore > 1000 AND GameTitle >= \"Galaxy\"",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      FilterExpression: &"TopScore > :_gen3",
+      KeyConditionExpression: &"UserId = :_gen2 AND GameTitle BETWEEN :_gen4 AND :_gen1",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "Meteor",
+      ":_gen2": "103",
+      ":_gen3": json.Number("1000"),
+      ":_gen4": "Galaxy",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,38 @@
+querybuilder.item{
+  Query: "SELECT UserId, TopScore FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = \"Galaxy\"",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      IndexName: &"GameTitleIndex",
+      KeyConditionExpression: &"GameTitle = :_gen1",
+      ProjectionExpression: &"UserId, TopScore",
+      TableName: &"gamescores",
+    },
+    Plan: "Query index \"GameTitleIndex\" of table \"gamescores\"",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "UserId",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "TopScore",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "Galaxy",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,42 @@
+querybuilder.item{
+  Query: "SELECT *, TopScore * 2 AS DoubleScore FROM gamescores WHERE UserId = \"103\"",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      KeyConditionExpression: &"UserId = :_gen1",
+      TableName: &"gamescores",
+    },
+    All: true,
+    Plan: "Query table \"gamescores\"",
+    Columns: []*parser.ProjectionColumn{
+      {
+        Arithmetic: &parser.Arithmetic{
+          Operand: &parser.ArithmeticOperand{
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "TopScore",
+                },
+              },
+            },
+          },
+          Ops: []*parser.ArithmeticOp{
+            {
+              Operator: "*",
+              Operand: &parser.ArithmeticOperand{
+                Number: &2,
+                NumberText: &"2",
+              },
+            },
+          },
+        },
+        Alias: &"DoubleScore",
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "103",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,79 @@
+querybuilder.item{
+  Query: "SELECT `a.b`, `c[0]`, info['x.y'], info['z[1]'].w FROM movies WHERE title = :title AND `a.b` = 1 AND info['p.q'] > 2",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#_gen1": &"a.b",
+        "#_gen2": &"c[0]",
+        "#_gen3": &"x.y",
+        "#_gen4": &"z[1]",
+        "#_gen5": &"p.q",
+      },
+      FilterExpression: &"#_gen1 = :_gen1 AND info.#_gen5 > :_gen2",
+      KeyConditionExpression: &"title = :title",
+      ProjectionExpression: &"#_gen1, #_gen2, info.#_gen3, info.#_gen4.w",
+      TableName: &"movies",
+    },
+    Plan: "Query table \"movies\"",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "a.b",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "c[0]",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "info",
+              Accessors: []*parser.PathAccessor{
+                {
+                  Key: &"x.y",
+                },
+              },
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "info",
+              Accessors: []*parser.PathAccessor{
+                {
+                  Key: &"z[1]",
+                },
+              },
+            },
+            {
+              Symbol: "w",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{
+      ":title": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": json.Number("1"),
+      ":_gen2": json.Number("2"),
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,55 @@
+querybuilder.item{
+  Query: "SELECT count, mode, end FROM movies WHERE title = :title AND scan = 1",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#count": &"count",
+        "#end": &"end",
+        "#mode": &"mode",
+        "#scan": &"scan",
+      },
+      FilterExpression: &"#scan = :_gen1",
+      KeyConditionExpression: &"title = :title",
+      ProjectionExpression: &"#count, #mode, #end",
+      TableName: &"movies",
+    },
+    Plan: "Query table \"movies\"",
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "count",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "mode",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "end",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{
+      ":title": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": json.Number("1"),
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,22 @@
+querybuilder.item{
+  Query: "SELECT * FROM movies WHERE title = :title AND year = 12345678901234567890",
+  Prepared: &querybuilder.PreparedQuery{
+    GetItem: &dynamodb.GetItemInput{
+      _: struct {}{      },
+      TableName: &"movies",
+    },
+    KeyParams: map[string]string{
+      "title": ":title",
+      "year": ":_gen1",
+    },
+    All: true,
+    Plan: "GetItem table \"movies\"",
+    NamedParams: querybuilder.NamedParams{
+      ":title": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": json.Number("12345678901234567890"),
+    },
+  },
+}
\ No newline at end of file
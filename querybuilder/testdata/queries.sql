@@ -20,3 +20,97 @@ SELECT * FROM gamescores WHERE UserId = ? AND TopScore > ?
 -- positional placeholders (?) when key expression appears after filter expression
 -- https://github.com/mightyguava/dynamosql/issues/1
 SELECT * FROM gamescores WHERE TopScore > ? AND UserId = ?
+-- Scan when the partition key is not constrained
+SELECT * FROM gamescores
+SELECT * FROM gamescores WHERE Wins = 3 LIMIT 10
+SELECT GameTitle FROM gamescores WHERE NOT UserId = :UserId
+SELECT * FROM gamescores WHERE UserId > :UserId AND begins_with(GameTitle, "Galaxy")
+SELECT * FROM gamescores USE INDEX (GameTitleIndex) WHERE UserId = "101"
+-- ORDER BY the sort key
+SELECT * FROM gamescores WHERE UserId = "103" ORDER BY GameTitle DESC LIMIT 1
+SELECT * FROM gamescores WHERE UserId = "103" ORDER BY GameTitle ASC
+SELECT * FROM gamescores USE INDEX (UserWinsIndex) WHERE UserId = "103" ORDER BY Wins DESC
+-- COUNT(*) on a Query and a Scan
+SELECT COUNT(*) FROM gamescores WHERE UserId = "103" AND Wins > 10
+SELECT COUNT(*) FROM gamescores
+-- IS NULL and IS NOT NULL
+SELECT * FROM gamescores WHERE UserId = "103" AND Wins IS NULL AND Name.first IS NOT NULL
+SELECT * FROM gamescores WHERE UserId IS NOT NULL
+-- LIKE with a prefix pattern on the sort key, and in a filter
+SELECT * FROM gamescores WHERE UserId = "103" AND GameTitle LIKE 'Galaxy%'
+SELECT * FROM gamescores WHERE UserId = "103" AND NOT Name LIKE "B%"
+-- IN, NOT IN and NOT BETWEEN, nested in AND/OR
+SELECT * FROM gamescores WHERE UserId = "103" AND Wins IN (1, 2, 3)
+SELECT * FROM gamescores WHERE UserId = "103" AND Wins NOT IN (1, 2) AND TopScore NOT BETWEEN 10 AND 20
+SELECT * FROM gamescores WHERE UserId = "103" AND (Wins NOT IN (:a, :b) OR NOT (Losses NOT BETWEEN 1 AND 2) OR TopScore IN (5))
+-- WITH (CONSISTENT) on a Query, a local secondary index and a Scan
+SELECT * FROM gamescores WHERE UserId = "103" WITH (CONSISTENT)
+SELECT * FROM gamescores USE INDEX (UserWinsIndex) WHERE UserId = "103" with (consistent)
+SELECT * FROM gamescores WITH (CONSISTENT)
+-- LIMIT placeholders
+SELECT * FROM gamescores WHERE UserId = :UserId LIMIT :n
+SELECT * FROM gamescores WHERE UserId = ? AND Wins > ? LIMIT ?
+-- OFFSET skips items client side, so the pushed down limit includes them
+SELECT * FROM gamescores WHERE UserId = "103" LIMIT 10 OFFSET 20
+SELECT * FROM gamescores OFFSET 5
+-- column aliases do not change the projection expression
+SELECT Name.first AS first, TopScore AS score FROM gamescores WHERE UserId = "103"
+SELECT * FROM movies WHERE year > 2000 WITH (SEGMENTS = 8)
+SELECT COUNT(*) FROM movies WITH (CONSISTENT, SEGMENTS = 4)
+-- Automatic index selection without USE INDEX
+SELECT UserId, GameTitle, TopScore FROM gamescores WHERE GameTitle = "Meteor Blasters" AND TopScore > 1000
+SELECT * FROM gamescores WHERE GameTitle = "Meteor Blasters"
+SELECT UserId, Wins, TopScore FROM gamescores WHERE UserId = "103" AND Wins = 3
+SELECT UserId, Wins FROM gamescores WHERE UserId = "103" ORDER BY Wins DESC
+SELECT UserId FROM gamescores WHERE GameTitle = "Meteor Blasters" WITH (CONSISTENT)
+-- IN lists bound from a single placeholder
+SELECT * FROM gamescores WHERE UserId = "103" AND Wins IN (:wins)
+SELECT * FROM gamescores WHERE UserId IN (?)
+-- OR at the top level can not pin the partition key, so it is a Scan with the whole WHERE clause as the filter
+SELECT * FROM gamescores WHERE UserId = :UserId OR Wins = 3
+SELECT * FROM gamescores WHERE (Wins = 3 OR Losses = 1) AND TopScore > 100 OR NOT (Name = "Bob" OR Name = "Alice")
+-- map keys in brackets are dereferenced with a dot, and substituted if they are not valid attribute names
+SELECT Name['first name'], Name["last"], Scores[0]['a.b'][1] FROM gamescores WHERE UserId = "103" AND Name['first name'] = "Bob"
+-- size() of an attribute can be compared in a filter
+SELECT * FROM gamescores WHERE UserId = "103" AND size(Scores) > 3 AND size(Name.first) NOT BETWEEN 1 AND :max
+SELECT * FROM gamescores WHERE size(Tags) IN (1, 2) OR size(year) = 0
+-- attribute_type() type codes are passed as string values
+SELECT * FROM gamescores WHERE UserId = "103" AND attribute_type(Scores, L) AND attribute_type(Name, 'NULL')
+-- GetItem when the whole primary key is pinned and there are no other conditions
+SELECT * FROM gamescores WHERE UserId = :UserId AND GameTitle = :GameTitle
+SELECT title, info.rating FROM movies WHERE year = 1995 AND title = "Heat" LIMIT 1 WITH (CONSISTENT)
+-- Query when the key is not fully pinned, or there are other conditions
+SELECT * FROM gamescores WHERE UserId = "103" AND GameTitle = "Galaxy" AND TopScore > 1000
+SELECT * FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = "Galaxy" AND TopScore = 1000
+-- BatchGetItem when each key attribute is looked up with IN or equality, and there are no other conditions
+SELECT * FROM gamescores WHERE UserId = :UserId AND GameTitle IN ("A", "B")
+SELECT title, info.rating FROM movies WHERE title IN (:titles) AND year IN (1995, 1998) WITH (CONSISTENT)
+-- Scan when a key attribute is not looked up, or there are other conditions
+SELECT * FROM gamescores WHERE UserId IN ("1", "2")
+SELECT * FROM movies WHERE title IN ("Heat", "Ronin") AND year = 1995 AND rating > 5
+-- WITH (SCAN) scans even when the partition key is given, with the whole WHERE clause as the filter
+SELECT * FROM gamescores WHERE UserId = "103" AND GameTitle = "Galaxy" WITH (SCAN)
+SELECT UserId, Wins FROM gamescores USE INDEX (UserWinsIndex) WHERE UserId = "103" AND Wins > 3 WITH (SCAN, SEGMENTS = 4)
+SELECT COUNT(*) FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = "Galaxy" WITH (SCAN)
+-- CASE projects the attributes it reads
+SELECT UserId, CASE WHEN Wins > 10 THEN "pro" WHEN attribute_exists(Losses) THEN Losses ELSE UserId END AS rank FROM gamescores WHERE UserId = "103"
+-- Computed columns project the attributes they read
+SELECT UserId, Wins - Losses AS net, Wins / (Wins + Losses) FROM gamescores WHERE UserId = "103"
+-- BETWEEN on the sort key of the table or an index is a key condition
+SELECT * FROM gamescores WHERE UserId = ? AND GameTitle BETWEEN ? AND ? AND Wins BETWEEN ? AND ?
+SELECT * FROM gamescores USE INDEX (UserWinsIndex) WHERE UserId = "103" AND Wins BETWEEN 5 AND 10
+SELECT UserId, TopScore FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = "Galaxy" AND TopScore BETWEEN :lo AND :hi
+-- BETWEEN on the partition key can only filter a Scan
+SELECT * FROM gamescores WHERE UserId BETWEEN "101" AND "103" WITH (SCAN)
+SELECT document(Studio.Location) AS location FROM gamescores WHERE UserId = :UserId
+-- contains() binds its second argument as a value, whether a placeholder, a string or a number
+SELECT * FROM gamescores WHERE UserId = :UserId AND contains(Studio.Tags, :tag)
+SELECT * FROM gamescores WHERE UserId = "103" AND contains(Studio.Location.Name, "Melbourne")
+SELECT * FROM gamescores WHERE UserId = ? AND NOT contains(Scores[0].Data, 100)
+SELECT * FROM gamescores WHERE UserId = :UserId AND GameTitle >= :MinGameTitle AND GameTitle <= :MaxGameTitle
+SELECT * FROM gamescores WHERE GameTitle <= "Meteor" AND UserId = "103" AND TopScore > 1000 AND GameTitle >= "Galaxy"
+SELECT UserId, TopScore FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = "Galaxy"
+SELECT *, TopScore * 2 AS DoubleScore FROM gamescores WHERE UserId = "103"
+SELECT `a.b`, `c[0]`, info['x.y'], info['z[1]'].w FROM movies WHERE title = :title AND `a.b` = 1 AND info['p.q'] > 2
+SELECT count, mode, end FROM movies WHERE title = :title AND scan = 1
+SELECT * FROM movies WHERE title = :title AND year = 12345678901234567890
@@ -1,19 +1,11 @@
--- Partition key required
-SELECT * FROM gamescores
--- Partition key required
-SELECT * FROM gamescores WHERE Wins = 3
 -- $ numbered placeholder is not allowed
 SELECT * FROM gamescores WHERE UserId = "101" AND Wins = $1
--- OR may not appear at the top level
-SELECT * FROM gamescores WHERE UserId = :UserId OR Wins = 3
 -- Partition key may not appear in a nested expression
 SELECT * FROM gamescores WHERE UserId = :UserId AND (Wins = 3 OR UserId = "105")
--- Partition key may not appear in a NOT expression
-SELECT * FROM gamescores WHERE NOT UserId = :UserId
 -- Partition key must be in an equality condition
-SELECT * FROM gamescores WHERE UserId > :UserId
+SELECT * FROM gamescores WHERE UserId = :UserId AND UserId > :OtherUserId
 -- Partition key must be in an equality condition
-SELECT * FROM gamescores WHERE begins_with(UserId, 5)
+SELECT * FROM gamescores WHERE UserId = :UserId AND begins_with(UserId, 5)
 -- Partition key may not appear twice
 SELECT * FROM gamescores WHERE UserId = :UserId AND UserId = :UserId2
 -- Sort key may not appear twice
@@ -23,4 +15,63 @@ SELECT * FROM gamescores WHERE UserId = :UserId AND attribute_exists(GameTitle)
 -- Mix ? and : placeholders
 SELECT * FROM gamescores WHERE UserId = :UserId AND Wins = ?
 -- Use JSON in query
-SELECT * FROM gamescores WHERE UserId = {id: 10}
\ No newline at end of file
+SELECT * FROM gamescores WHERE UserId = {id: 10}
+-- Scans have no sort order
+SELECT * FROM gamescores WHERE Wins = 3 DESC
+-- Sort key may not be used with a not equals condition
+SELECT * FROM gamescores WHERE UserId = :UserId AND GameTitle <> "A"
+-- Sort key may not be used with IN in a Query, only to look up whole keys without other conditions
+SELECT * FROM gamescores WHERE UserId = :UserId AND GameTitle IN ("A", "B") AND Wins > 3
+-- ORDER BY must use the sort key
+SELECT * FROM gamescores WHERE UserId = "103" ORDER BY TopScore DESC
+-- ORDER BY must use the sort key of the index
+SELECT * FROM gamescores USE INDEX (UserWinsIndex) WHERE UserId = "103" ORDER BY GameTitle
+-- ORDER BY may not be combined with a bare DESC
+SELECT * FROM gamescores WHERE UserId = "103" ORDER BY GameTitle DESC DESC
+-- Scans have no sort order
+SELECT * FROM gamescores ORDER BY GameTitle
+-- LIMIT does not apply to COUNT(*)
+SELECT COUNT(*) FROM gamescores WHERE UserId = "103" LIMIT 1
+-- The sort key is always present
+SELECT * FROM gamescores WHERE UserId = "103" AND GameTitle IS NULL
+-- LIKE must be a prefix pattern
+SELECT * FROM gamescores WHERE UserId = "103" AND GameTitle LIKE '%Invaders'
+SELECT * FROM gamescores WHERE UserId = "103" AND GameTitle LIKE 'Galaxy_Invaders%'
+SELECT * FROM gamescores WHERE UserId = "103" AND GameTitle LIKE 'Galaxy'
+SELECT * FROM gamescores WHERE UserId = "103" AND GameTitle LIKE :pattern
+-- NOT IN and NOT BETWEEN are not key conditions
+SELECT * FROM gamescores WHERE UserId = "103" AND GameTitle NOT IN ("a")
+SELECT * FROM gamescores WHERE UserId = "103" AND GameTitle NOT BETWEEN "a" AND "b"
+-- Global secondary indexes do not support consistent reads
+SELECT * FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = "Galaxy Invaders" WITH (CONSISTENT)
+-- LIMIT must be a positive integer
+SELECT * FROM gamescores WHERE UserId = "103" LIMIT 1.5
+SELECT * FROM gamescores WHERE UserId = "103" LIMIT 0
+SELECT * FROM gamescores WHERE UserId = "103" LIMIT "10"
+-- LIMIT placeholders cannot be shared with WHERE
+SELECT * FROM gamescores WHERE UserId = "103" AND Wins > :n LIMIT :n
+-- LIMIT placeholders cannot be mixed with positional placeholders
+SELECT * FROM gamescores WHERE UserId = ? LIMIT :n
+-- OFFSET must not be negative, and does not apply to COUNT(*)
+SELECT * FROM gamescores WHERE UserId = "103" OFFSET -1
+SELECT COUNT(*) FROM gamescores WHERE UserId = "103" OFFSET 1
+SELECT * FROM gamescores WHERE UserId = :UserId WITH (SEGMENTS = 2)
+SELECT * FROM gamescores WITH (SEGMENTS = 0)
+-- a placeholder bound to an IN list may not be used as a single value
+SELECT * FROM gamescores WHERE UserId = "103" AND Wins IN (:w) AND Losses = :w
+-- size() is not a key condition, and can not be projected
+SELECT * FROM gamescores WHERE UserId = "103" AND size(GameTitle) > 3
+SELECT * FROM gamescores WHERE size(UserId) = 3 AND UserId = "103"
+SELECT size(Scores) FROM gamescores WHERE UserId = "103"
+-- WITH (SCAN) can not be ordered, or read attributes a global secondary index does not project
+SELECT * FROM gamescores WHERE UserId = "103" ORDER BY GameTitle WITH (SCAN)
+SELECT * FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = "Galaxy" WITH (SCAN)
+SELECT UserId FROM gamescores USE INDEX (GameTitleIndex) WHERE Wins > 3 WITH (SCAN)
+-- Partition key may only be compared with =, not BETWEEN
+SELECT * FROM gamescores WHERE UserId = "103" AND UserId BETWEEN "101" AND "105"
+SELECT * FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = "Galaxy" AND GameTitle BETWEEN "A" AND "Z"
+-- Only >= and <= on the sort key fold into BETWEEN
+SELECT * FROM gamescores WHERE UserId = :UserId AND GameTitle > :MinGameTitle AND GameTitle < :MaxGameTitle
+-- A global secondary index only returns the attributes it projects
+SELECT UserId, Wins FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = "Galaxy"
+SELECT * FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = "Galaxy" AND Wins > 3
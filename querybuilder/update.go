@@ -0,0 +1,327 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/repr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// matches the value placeholders in an expression
+var placeholderRegexp = regexp.MustCompile(`:[a-zA-Z0-9_]+`)
+
+// PreparedUpdate is an UPDATE compiled into an UpdateItem request. The WHERE clause must pin each key attribute of
+// the table with an equality condition. Any other conditions, and the existence of the item, are checked with a
+// ConditionExpression, so an UPDATE never creates an item.
+type PreparedUpdate struct {
+	Update *dynamodb.UpdateItemInput
+	// KeyParams maps each key attribute to the placeholder its value is bound from.
+	KeyParams map[string]string
+	// ValueParams are the placeholders used by the update and condition expressions, which are the only ones
+	// DynamoDB accepts in ExpressionAttributeValues.
+	ValueParams      []string
+	NamedParams      NamedParams
+	PositionalParams map[int]string
+	FixedParams      map[string]interface{}
+	ListParams       NamedParams
+	// Conditional is set if the WHERE clause has conditions other than the key, such as a version check. Do then
+	// returns the ConditionalCheckFailedException of an item that does not match them.
+	Conditional bool
+	// FailOnMissingItem makes Do return the ConditionalCheckFailedException of an item that does not exist even if
+	// the WHERE clause only has the key, rather than affecting no rows.
+	FailOnMissingItem bool
+	// listCondition compiles the condition expression again once a list param is bound to a slice.
+	listCondition *listExpression
+}
+
+var (
+	_ ExecStmt          = &PreparedUpdate{}
+	_ TransactWriteStmt = &PreparedUpdate{}
+)
+
+func PrepareUpdate(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) (*PreparedUpdate, error) {
+	if ast.Update == nil {
+		return nil, fmt.Errorf("expected UPDATE but got %s", repr.String(ast))
+	}
+	table, err := tables.Get(ctx, ast.Update.Table)
+	if err != nil {
+		return nil, err
+	}
+	return prepareUpdate(table, ast.Update)
+}
+
+func prepareUpdate(table *schema.Table, upd *parser.Update) (*PreparedUpdate, error) {
+	ctx := NewContext(table, "")
+	visit := &visitor{Context: ctx}
+	// Placeholders are numbered in the order they appear, so the actions must be visited before the WHERE clause.
+	if err := prepareValuesAndPlaceholders(ctx, upd); err != nil {
+		return nil, err
+	}
+	if len(ctx.PositionalParams) > 0 && len(ctx.NamedParams) > 0 {
+		return nil, errors.New("cannot mix positional params (?) with named params (:param)")
+	}
+
+	if err := checkItemWhere("UPDATE", table, upd.Where); err != nil {
+		return nil, err
+	}
+	kf := extractKeyExpressions(upd.Where.Conjunction(), ctx.IsKey)
+	keyParams, err := itemKeyParams("UPDATE", table, kf.Key)
+	if err != nil {
+		return nil, err
+	}
+	updateExpr, err := buildUpdateExpression(visit, upd.Actions)
+	if err != nil {
+		return nil, err
+	}
+	conditionExpr, err := itemConditionExpression(&visitor{Context: ctx}, kf.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreparedUpdate{
+		Update: &dynamodb.UpdateItemInput{
+			TableName:                &table.Name,
+			UpdateExpression:         aws.String(updateExpr),
+			ConditionExpression:      aws.String(conditionExpr),
+			ExpressionAttributeNames: ctx.ExpressionAttributeNames(),
+			ReturnValues:             upd.Returning,
+		},
+		KeyParams:        keyParams,
+		ValueParams:      expressionPlaceholders(updateExpr, conditionExpr),
+		NamedParams:      ctx.NamedParams,
+		PositionalParams: ctx.PositionalParams,
+		FixedParams:      ctx.FixedParams,
+		ListParams:       ctx.ListParams,
+		Conditional:      len(kf.Filter.And) > 0,
+		listCondition:    newListExpression(ctx, false, true, kf.Filter),
+	}, nil
+}
+
+// checkItemWhere returns an error if the WHERE clause of an UPDATE or DELETE has an OR at the top level. The key of
+// the item must be ANDed with the other conditions, which can use OR inside parentheses.
+func checkItemWhere(stmt string, table *schema.Table, where *parser.ConditionExpression) error {
+	if where == nil || len(where.Or) == 1 {
+		return nil
+	}
+	key := table.HashKey + " = :param"
+	if table.SortKey != "" {
+		key += " AND " + table.SortKey + " = :param"
+	}
+	return fmt.Errorf("%s can not have OR at the top level of the WHERE clause, which must AND the key of the item with its other conditions, put the OR in parentheses, as in WHERE %s AND (a = :a OR b = :b)", stmt, key)
+}
+
+// itemKeyParams returns the placeholder each key attribute is bound from, for an UPDATE or DELETE of a single item.
+// Every key attribute must appear exactly once, in an equality condition.
+func itemKeyParams(stmt string, table *schema.Table, key *parser.AndExpression) (map[string]string, error) {
+	keyErr := fmt.Errorf("%s requires each key attribute in the WHERE clause, in an equality condition, such as: WHERE %s = :param", stmt, table.HashKey)
+	if table.SortKey != "" {
+		keyErr = fmt.Errorf("%s requires each key attribute in the WHERE clause, in an equality condition, such as: WHERE %s = :param AND %s = :param", stmt, table.HashKey, table.SortKey)
+	}
+	params := map[string]string{}
+	for _, term := range key.And {
+		if term.Operand == nil || term.Operand.Size != nil {
+			return nil, atCondition(term, keyErr)
+		}
+		name := term.Operand.Operand.Attribute()
+		compare := term.Operand.ConditionRHS.Compare
+		if compare == nil || compare.Operator != "=" || compare.Operand.Value == nil {
+			return nil, atCondition(term, keyErr)
+		}
+		if _, ok := params[name]; ok {
+			return nil, atCondition(term, fmt.Errorf("key attribute %q can only appear once in WHERE clause", name))
+		}
+		params[name] = *compare.Operand.Value.PlaceHolder
+	}
+	for _, name := range []string{table.HashKey, table.SortKey} {
+		if _, ok := params[name]; name != "" && !ok {
+			return nil, keyErr
+		}
+	}
+	return params, nil
+}
+
+// itemConditionExpression returns the condition of an UPDATE or DELETE, which requires the item to exist and match
+// the conditions in WHERE on attributes other than the key.
+func itemConditionExpression(v *visitor, filter *parser.AndExpression) (string, error) {
+	conditionExpr := fmt.Sprintf("attribute_exists(%s)", v.substitute(v.HashKey))
+	filterExpr, err := v.VisitFilterExpression(filter)
+	if err != nil {
+		return "", err
+	}
+	if filterExpr != "" {
+		conditionExpr += " AND " + filterExpr
+	}
+	return conditionExpr, nil
+}
+
+// expressionPlaceholders returns the placeholders used by the expressions, in the order they first appear.
+func expressionPlaceholders(exprs ...string) []string {
+	var placeholders []string
+	seen := map[string]bool{}
+	for _, placeholder := range placeholderRegexp.FindAllString(strings.Join(exprs, " "), -1) {
+		if !seen[placeholder] {
+			seen[placeholder] = true
+			placeholders = append(placeholders, placeholder)
+		}
+	}
+	return placeholders
+}
+
+// buildUpdateExpression builds the update expression from the actions. DynamoDB allows each of SET, REMOVE, ADD and
+// DELETE only once, so the clauses of each kind are merged in the order they first appear.
+func buildUpdateExpression(v *visitor, actions []*parser.UpdateAction) (string, error) {
+	var order []string
+	clauses := map[string][]string{}
+	add := func(keyword string, path *parser.DocumentPath, expr string) error {
+		if v.IsKey(path.Attribute()) {
+			return fmt.Errorf("key attribute %q cannot be updated", path)
+		}
+		if _, ok := clauses[keyword]; !ok {
+			order = append(order, keyword)
+		}
+		clauses[keyword] = append(clauses[keyword], expr)
+		return nil
+	}
+	for _, action := range actions {
+		for _, set := range action.Set {
+			value := visitSetOperand(v, &set.SetOperand)
+			if set.Arithmetic != nil {
+				value += " " + set.Arithmetic.Operator + " " + visitSetOperand(v, set.Arithmetic.Operand)
+			}
+			if err := add("SET", set.Path, v.BuildPath(set.Path)+" = "+value); err != nil {
+				return "", err
+			}
+		}
+		for _, path := range action.Remove {
+			if err := add("REMOVE", path, v.BuildPath(path)); err != nil {
+				return "", err
+			}
+		}
+		for _, expr := range action.Add {
+			if err := add("ADD", expr.Path, v.BuildPath(expr.Path)+" "+v.VisitSimpleExpression(expr.Value)); err != nil {
+				return "", err
+			}
+		}
+		for _, expr := range action.Delete {
+			if err := add("DELETE", expr.Path, v.BuildPath(expr.Path)+" "+v.VisitSimpleExpression(expr.Value)); err != nil {
+				return "", err
+			}
+		}
+	}
+	out := make([]string, 0, len(order))
+	for _, keyword := range order {
+		out = append(out, keyword+" "+strings.Join(clauses[keyword], ", "))
+	}
+	return strings.Join(out, " "), nil
+}
+
+// visitSetOperand returns the expression for a value on the right hand side of SET. Functions and their arguments
+// are validated by the parser.
+func visitSetOperand(v *visitor, operand *parser.SetOperand) string {
+	if operand.Function != nil {
+		return v.VisitSimpleExpression(operand.Function)
+	}
+	return v.VisitSimpleExpression(operand.Value)
+}
+
+// NumInput returns the number of arguments the update expects to be bound.
+func (p *PreparedUpdate) NumInput() int {
+	return len(p.NamedParams) + len(p.PositionalParams)
+}
+
+// NewRequest binds the arguments into an UpdateItem request.
+func (p *PreparedUpdate) NewRequest(args []driver.NamedValue) (*dynamodb.UpdateItemInput, error) {
+	values, lists, err := bindArgs(p.FixedParams, p.NamedParams, p.PositionalParams, p.ListParams, args)
+	if err != nil {
+		return nil, err
+	}
+	req := *p.Update
+	req.Key, req.ExpressionAttributeValues, err = bindItem(p.KeyParams, p.ValueParams, values, lists)
+	if err != nil {
+		return nil, err
+	}
+	req.ConditionExpression, err = p.listCondition.expand(req.ConditionExpression, lists)
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// bindItem returns the key of the item and the expression attribute values of an UPDATE or DELETE.
+func bindItem(keyParams map[string]string, valueParams []string, values map[string]*dynamodb.AttributeValue, lists map[string][]string) (key, exprValues map[string]*dynamodb.AttributeValue, err error) {
+	key = make(map[string]*dynamodb.AttributeValue, len(keyParams))
+	for attr, placeholder := range keyParams {
+		if _, ok := lists[placeholder]; ok {
+			return nil, nil, fmt.Errorf("binding %q: key attribute %q cannot be bound to a list", placeholder, attr)
+		}
+		key[attr] = values[placeholder]
+	}
+	exprValues = make(map[string]*dynamodb.AttributeValue, len(valueParams))
+	for _, placeholder := range valueParams {
+		if names, ok := lists[placeholder]; ok {
+			for _, name := range names {
+				exprValues[name] = values[name]
+			}
+			continue
+		}
+		exprValues[placeholder] = values[placeholder]
+	}
+	if len(exprValues) == 0 {
+		// DynamoDB rejects an empty ExpressionAttributeValues, which is possible for an UPDATE that only removes.
+		exprValues = nil
+	}
+	return key, exprValues, nil
+}
+
+// Do updates the item with UpdateItem. If the WHERE clause has conditions other than the key, the
+// ConditionalCheckFailedException of an item that does not match them, or does not exist, is returned. Otherwise a
+// missing item is not updated and zero rows are affected, unless FailOnMissingItem is set.
+func (p *PreparedUpdate) Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
+	req, err := p.NewRequest(args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := dynamo.UpdateItemWithContext(ctx, req)
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException && !p.Conditional && !p.FailOnMissingItem {
+		return &DriverResult{count: 0}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &DriverResult{
+		count:    1,
+		returned: resp.Attributes,
+	}, nil
+}
+
+// TransactWriteItems returns the update as an Update. If its condition fails, the whole transaction is cancelled.
+func (p *PreparedUpdate) TransactWriteItems(args []driver.NamedValue) ([]*dynamodb.TransactWriteItem, error) {
+	if p.Update.ReturnValues != nil && *p.Update.ReturnValues != "NONE" {
+		return nil, errors.New("cannot use RETURNING in a transaction")
+	}
+	req, err := p.NewRequest(args)
+	if err != nil {
+		return nil, err
+	}
+	return []*dynamodb.TransactWriteItem{{
+		Update: &dynamodb.Update{
+			TableName:                 req.TableName,
+			Key:                       req.Key,
+			UpdateExpression:          req.UpdateExpression,
+			ConditionExpression:       req.ConditionExpression,
+			ExpressionAttributeNames:  req.ExpressionAttributeNames,
+			ExpressionAttributeValues: req.ExpressionAttributeValues,
+		},
+	}}, nil
+}
@@ -0,0 +1,132 @@
+package querybuilder
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+func TestPrepareUpdate(t *testing.T) {
+	table := schema.NewTableFromCreate(fixtures.Movies.Create)
+	prepare := func(t *testing.T, query string) (*PreparedUpdate, error) {
+		t.Helper()
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		return prepareUpdate(table, ast.Update)
+	}
+
+	t.Run("SET functions", func(t *testing.T) {
+		p, err := prepare(t, `UPDATE movies SET tags = list_append(tags, :new), views = if_not_exists(views, 0) WHERE title = :title AND year = :year`)
+		require.NoError(t, err)
+		req, err := p.NewRequest([]driver.NamedValue{
+			{Name: "new", Value: "Drama"},
+			{Name: "title", Value: "Rush Hour"},
+			{Name: "year", Value: 1998},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "SET tags = list_append(tags, :new), #views = if_not_exists(#views, :_gen1)", *req.UpdateExpression)
+		require.Equal(t, "attribute_exists(title)", *req.ConditionExpression)
+		require.Equal(t, map[string]*string{"#views": aws.String("views")}, req.ExpressionAttributeNames)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			"title": {S: aws.String("Rush Hour")},
+			"year":  {N: aws.String("1998")},
+		}, req.Key)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			":new":   {S: aws.String("Drama")},
+			":_gen1": {N: aws.String("0")},
+		}, req.ExpressionAttributeValues)
+	})
+
+	t.Run("actions are merged by kind and conditions are kept", func(t *testing.T) {
+		p, err := prepare(t, `UPDATE movies REMOVE info.plot SET director = ? ADD views 1 SET rating = rating WHERE title = ? AND year = ? AND director <> ?`)
+		require.NoError(t, err)
+		require.Equal(t, "REMOVE info.plot SET director = :_pos1, rating = rating ADD #views :_gen1", *p.Update.UpdateExpression)
+		require.Equal(t, "attribute_exists(title) AND director <> :_pos4", *p.Update.ConditionExpression)
+		require.Equal(t, map[string]string{"title": ":_pos2", "year": ":_pos3"}, p.KeyParams)
+		require.Equal(t, []string{":_pos1", ":_gen1", ":_pos4"}, p.ValueParams)
+		require.Equal(t, 4, p.NumInput())
+	})
+
+	t.Run("SET arithmetic", func(t *testing.T) {
+		p, err := prepare(t, `UPDATE movies SET views = views + :n, stock = stock-1, total = 10 + info.total, plays = if_not_exists(plays, 0) - :n WHERE title = :title AND year = :year`)
+		require.NoError(t, err)
+		require.Equal(t, "SET #views = #views + :n, stock = stock - :_gen1, #total = :_gen2 + info.#total, plays = if_not_exists(plays, :_gen3) - :n", *p.Update.UpdateExpression)
+		require.Equal(t, []string{":n", ":_gen1", ":_gen2", ":_gen3"}, p.ValueParams)
+		require.Equal(t, map[string]interface{}{":_gen1": json.Number("1"), ":_gen2": json.Number("10"), ":_gen3": json.Number("0")}, p.FixedParams)
+	})
+
+	t.Run("IN list placeholder in the condition", func(t *testing.T) {
+		p, err := prepare(t, `UPDATE movies SET director = :d WHERE title = :title AND year = :year AND director IN (:directors)`)
+		require.NoError(t, err)
+		req, err := p.NewRequest([]driver.NamedValue{
+			{Name: "d", Value: "Ratner"},
+			{Name: "title", Value: "Rush Hour"},
+			{Name: "year", Value: 1998},
+			{Name: "directors", Value: []string{"Brett", "Ratner"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "attribute_exists(title) AND director IN (:directors_0, :directors_1)", *req.ConditionExpression)
+		require.Equal(t, "attribute_exists(title) AND director IN (:directors)", *p.Update.ConditionExpression)
+	})
+
+	t.Run("size() in the condition", func(t *testing.T) {
+		p, err := prepare(t, `UPDATE movies SET tags = ? WHERE title = ? AND year = ? AND size(tags) < 10`)
+		require.NoError(t, err)
+		require.Equal(t, "attribute_exists(title) AND size(tags) < :_gen1", *p.Update.ConditionExpression)
+		require.Equal(t, map[string]string{"title": ":_pos2", "year": ":_pos3"}, p.KeyParams)
+	})
+
+	t.Run("OR and parentheses in the condition", func(t *testing.T) {
+		p, err := prepare(t, `UPDATE movies SET director = :d WHERE title = :t AND year = :y AND (director = :a OR director = :b) AND attribute_exists(rating)`)
+		require.NoError(t, err)
+		require.Equal(t, "attribute_exists(title) AND (director = :a OR director = :b) AND attribute_exists(rating)", *p.Update.ConditionExpression)
+		require.Equal(t, map[string]string{"title": ":t", "year": ":y"}, p.KeyParams)
+
+		p, err = prepare(t, `UPDATE movies SET director = :d WHERE NOT (director = :a AND (rating > 5 OR rating IS NULL)) AND (title = :t AND (year = :y))`)
+		require.NoError(t, err)
+		require.Equal(t, "attribute_exists(title) AND NOT (director = :a AND (rating > :_gen1 OR attribute_not_exists(rating)))", *p.Update.ConditionExpression)
+		require.Equal(t, map[string]string{"title": ":t", "year": ":y"}, p.KeyParams)
+	})
+
+	t.Run("only removing has no values", func(t *testing.T) {
+		p, err := prepare(t, `UPDATE movies REMOVE director WHERE title = "Heat" AND year = 1995`)
+		require.NoError(t, err)
+		req, err := p.NewRequest(nil)
+		require.NoError(t, err)
+		require.Nil(t, req.ExpressionAttributeValues)
+		require.Len(t, req.Key, 2)
+	})
+
+	for _, test := range []struct {
+		name  string
+		query string
+		err   string
+	}{
+		{"missing sort key", `UPDATE movies SET director = :d WHERE title = :t`,
+			"UPDATE requires each key attribute in the WHERE clause, in an equality condition, such as: WHERE title = :param AND year = :param"},
+		{"key range", `UPDATE movies SET director = :d WHERE title = :t AND year > :y`,
+			"1:54: UPDATE requires each key attribute in the WHERE clause, in an equality condition, such as: WHERE title = :param AND year = :param"},
+		{"update key", `UPDATE movies SET year = :y2 WHERE title = :t AND year = :y`,
+			`key attribute "year" cannot be updated`},
+		{"size() of a key", `UPDATE movies SET director = :d WHERE title = :t AND size(year) = :y`,
+			"1:54: UPDATE requires each key attribute in the WHERE clause, in an equality condition, such as: WHERE title = :param AND year = :param"},
+		{"OR at the top level", `UPDATE movies SET director = :d WHERE title = :t AND year = :y OR director = :old`,
+			"UPDATE can not have OR at the top level of the WHERE clause, which must AND the key of the item with its other conditions, put the OR in parentheses, as in WHERE title = :param AND year = :param AND (a = :a OR b = :b)"},
+		{"key in an OR", `UPDATE movies SET director = :d WHERE title = :t AND year = :y AND (title = :t OR director = :old)`,
+			`partition key "title" may not appear in nested expression`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ast, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			require.EqualError(t, Validate(ast, table), test.err)
+		})
+	}
+}
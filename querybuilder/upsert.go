@@ -0,0 +1,204 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/alecthomas/repr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// PreparedUpsert is an INSERT ... ON DUPLICATE KEY UPDATE of a single item. The item is written with a PutItem that
+// fails if its key exists, and if it does, the assignments of ON DUPLICATE KEY UPDATE are made to the existing item
+// with an UpdateItem instead, leaving its other attributes as they are.
+//
+// Each of the two requests is atomic, but the statement as a whole is not: if the existing item is deleted between
+// them, the UpdateItem fails with a ConditionalCheckFailedException rather than creating a partial item. Use REPLACE
+// to overwrite the whole item in a single request.
+type PreparedUpsert struct {
+	Insert *PreparedInsert
+	// Update is the UpdateItem request without its key, which is that of the inserted item.
+	Update *dynamodb.UpdateItemInput
+	// ValueParams are the placeholders used by the update and condition expressions.
+	ValueParams      []string
+	NamedParams      NamedParams
+	PositionalParams map[int]string
+	FixedParams      map[string]interface{}
+}
+
+var _ ExecStmt = &PreparedUpsert{}
+
+func PrepareUpsert(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) (*PreparedUpsert, error) {
+	ins, _, err := insertStatement(ast)
+	if err != nil {
+		return nil, err
+	}
+	table, err := tables.Get(ctx, ins.Into)
+	if err != nil {
+		return nil, err
+	}
+	return prepareUpsert(table, ast)
+}
+
+func prepareUpsert(table *schema.Table, ast *parser.AST) (*PreparedUpsert, error) {
+	ins, replace, err := insertStatement(ast)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case ins.OnDuplicate == nil:
+		return nil, fmt.Errorf("expected INSERT ... ON DUPLICATE KEY UPDATE but got %s", repr.String(ast))
+	case replace:
+		return nil, errors.New("ON DUPLICATE KEY UPDATE cannot be used with REPLACE, which overwrites existing items")
+	case ins.Select != nil:
+		return nil, errors.New("ON DUPLICATE KEY UPDATE cannot be used with INSERT ... SELECT")
+	case ins.IfNotExists:
+		return nil, errors.New("ON DUPLICATE KEY UPDATE cannot be used with IF NOT EXISTS")
+	case len(ins.Values) > 1:
+		return nil, errors.New("ON DUPLICATE KEY UPDATE can only insert a single item")
+	}
+	// The ON DUPLICATE KEY UPDATE clause is compiled on a copy, so that the INSERT is prepared without it.
+	values := *ins
+	values.OnDuplicate = nil
+	insert, err := prepareInsert(table, &parser.AST{Insert: &values})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := NewContext(table, "")
+	// Positional placeholders are numbered in the order they appear, so those of the update follow the insert's.
+	ctx.positionalParamCount = insert.positionalInputs()
+	upd := &parser.Update{Table: ins.Into, Actions: []*parser.UpdateAction{{Set: ins.OnDuplicate}}}
+	if err := prepareValuesAndPlaceholders(ctx, upd); err != nil {
+		return nil, err
+	}
+	insertNamed := insert.Placeholder != "" || len(insert.NamedParams) > 0
+	if (insertNamed || len(ctx.NamedParams) > 0) && (insert.positionalInputs() > 0 || len(ctx.PositionalParams) > 0) {
+		return nil, errors.New("cannot mix positional params (?) with named params (:param)")
+	}
+	updateExpr, err := buildUpdateExpression(&visitor{Context: ctx}, upd.Actions)
+	if err != nil {
+		return nil, err
+	}
+	conditionExpr, err := itemConditionExpression(&visitor{Context: ctx}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &PreparedUpsert{
+		Insert: insert,
+		Update: &dynamodb.UpdateItemInput{
+			TableName:                &table.Name,
+			UpdateExpression:         aws.String(updateExpr),
+			ConditionExpression:      aws.String(conditionExpr),
+			ExpressionAttributeNames: ctx.ExpressionAttributeNames(),
+		},
+		ValueParams:      expressionPlaceholders(updateExpr, conditionExpr),
+		NamedParams:      ctx.NamedParams,
+		PositionalParams: ctx.PositionalParams,
+		FixedParams:      ctx.FixedParams,
+	}, nil
+}
+
+// positionalInputs returns the number of positional arguments the insert expects, which come before those of
+// ON DUPLICATE KEY UPDATE.
+func (p *PreparedInsert) positionalInputs() int {
+	if len(p.Documents) > 0 {
+		return len(p.PositionalParams)
+	}
+	if len(p.Values) == 0 && p.Placeholder == "" {
+		return 1
+	}
+	return 0
+}
+
+// NumInput returns the number of arguments the insert and update expect. A named argument used by both is only
+// counted once.
+func (p *PreparedUpsert) NumInput() int {
+	n := p.Insert.NumInput() + len(p.PositionalParams)
+	for name := range p.NamedParams {
+		if _, ok := p.Insert.NamedParams[name]; !ok && name != p.Insert.Placeholder {
+			n++
+		}
+	}
+	return n
+}
+
+// splitArgs returns the arguments of the insert and those of the update. Positional arguments are split by
+// position, and a named argument is passed to each of them that uses it.
+func (p *PreparedUpsert) splitArgs(args []driver.NamedValue) (insertArgs, updateArgs []driver.NamedValue, err error) {
+	positional := p.Insert.positionalInputs()
+	for _, arg := range args {
+		if arg.Name == "" {
+			if arg.Ordinal <= positional {
+				insertArgs = append(insertArgs, arg)
+			} else {
+				updateArgs = append(updateArgs, arg)
+			}
+			continue
+		}
+		name := ":" + arg.Name
+		_, inInsert := p.Insert.NamedParams[name]
+		inInsert = inInsert || name == p.Insert.Placeholder
+		_, inUpdate := p.NamedParams[name]
+		if !inInsert && !inUpdate {
+			return nil, nil, fmt.Errorf("binding %q not found", name)
+		}
+		if inInsert {
+			insertArgs = append(insertArgs, arg)
+		}
+		if inUpdate {
+			updateArgs = append(updateArgs, arg)
+		}
+	}
+	return insertArgs, updateArgs, nil
+}
+
+// Do inserts the item, or updates it if an item with the same key exists. One row is affected either way.
+func (p *PreparedUpsert) Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
+	insertArgs, updateArgs, err := p.splitArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	items, err := p.Insert.values(insertArgs)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) != 1 {
+		return nil, errors.New("ON DUPLICATE KEY UPDATE can only insert a single item")
+	}
+	item := items[0]
+	values, _, err := bindArgs(p.FixedParams, p.NamedParams, p.PositionalParams, nil, updateArgs)
+	if err != nil {
+		return nil, err
+	}
+	_, err = dynamo.PutItemWithContext(ctx, p.Insert.toPutItem(item))
+	if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != dynamodb.ErrCodeConditionalCheckFailedException {
+		if err != nil {
+			return nil, err
+		}
+		return &DriverResult{count: 1}, nil
+	}
+	req := *p.Update
+	req.Key = map[string]*dynamodb.AttributeValue{}
+	for _, attr := range []string{p.Insert.Table.HashKey, p.Insert.Table.SortKey} {
+		if attr != "" {
+			req.Key[attr] = item[attr]
+		}
+	}
+	_, req.ExpressionAttributeValues, err = bindItem(nil, p.ValueParams, values, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dynamo.UpdateItemWithContext(ctx, &req); err != nil {
+		return nil, err
+	}
+	return &DriverResult{count: 1}, nil
+}
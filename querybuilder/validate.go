@@ -0,0 +1,163 @@
+package querybuilder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/alecthomas/participle/lexer"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// ValidationError is returned by Validate. Pos is the position of the condition the error refers to, or the zero
+// value if the error is not about a single condition.
+type ValidationError struct {
+	Pos lexer.Position
+	Err error
+}
+
+func (v *ValidationError) Error() string {
+	if v.Pos.Line == 0 {
+		return v.Err.Error()
+	}
+	return fmt.Sprintf("%d:%d: %s", v.Pos.Line, v.Pos.Column, v.Err)
+}
+
+func (v *ValidationError) Unwrap() error {
+	return v.Err
+}
+
+// conditionError records the condition an error was found in, so that Validate can report its position. It
+// otherwise behaves exactly like the error it wraps.
+type conditionError struct {
+	pos lexer.Position
+	err error
+}
+
+func (c *conditionError) Error() string {
+	return c.err.Error()
+}
+
+func (c *conditionError) Unwrap() error {
+	return c.err
+}
+
+func atCondition(cond *parser.Condition, err error) error {
+	return &conditionError{pos: cond.Pos, err: err}
+}
+
+// Validate checks a statement against the schema of the table it operates on, without calling DynamoDB. It reports
+// the same errors as preparing the statement would. In addition, literal INSERT and REPLACE documents must contain
+// the key attributes of the table. CREATE TABLE and SHOW TABLES are only checked on their own. Errors are returned
+// as a *ValidationError.
+func Validate(ast *parser.AST, table *schema.Table) error {
+	err := validate(ast, table)
+	if err == nil {
+		return nil
+	}
+	verr := &ValidationError{Err: err}
+	var cerr *conditionError
+	if errors.As(err, &cerr) {
+		verr.Pos = cerr.pos
+		verr.Err = cerr.err
+	}
+	return verr
+}
+
+func validate(ast *parser.AST, table *schema.Table) error {
+	switch {
+	case ast.Select != nil:
+		if err := checkTableName(ast.Select.From, table); err != nil {
+			return err
+		}
+		_, err := PrepareSelect(table, ast.Select)
+		return err
+
+	case ast.Insert != nil, ast.Replace != nil:
+		ins, _, err := insertStatement(ast)
+		if err != nil {
+			return err
+		}
+		if err := checkTableName(ins.Into, table); err != nil {
+			return err
+		}
+		prepared, err := prepareInsert(table, ast)
+		if err != nil {
+			return err
+		}
+		for i, item := range prepared.Values {
+			if err := checkKeyAttributes(item, table); err != nil {
+				return fmt.Errorf("document %d: %w", i+1, err)
+			}
+		}
+		return nil
+
+	case ast.Update != nil:
+		if err := checkTableName(ast.Update.Table, table); err != nil {
+			return err
+		}
+		_, err := prepareUpdate(table, ast.Update)
+		return err
+
+	case ast.Delete != nil:
+		if err := checkTableName(ast.Delete.From, table); err != nil {
+			return err
+		}
+		_, err := prepareDelete(table, ast.Delete)
+		return err
+
+	case ast.CreateTable != nil:
+		// The table does not exist yet, so there is no schema to check against.
+		_, err := buildCreateTableInput(ast.CreateTable)
+		return err
+
+	case ast.DropTable != nil:
+		return checkTableName(ast.DropTable.Table, table)
+
+	case ast.AlterTable != nil:
+		if err := checkTableName(ast.AlterTable.Table, table); err != nil {
+			return err
+		}
+		_, err := buildUpdateTableInput(ast.AlterTable)
+		return err
+
+	case ast.Describe != nil:
+		return checkTableName(ast.Describe.Table, table)
+
+	case ast.ShowTables != nil:
+		// SHOW TABLES is not about any one table.
+		return nil
+
+	default:
+		return errors.New("unsupported statement")
+	}
+}
+
+func checkTableName(name string, table *schema.Table) error {
+	if name != table.Name {
+		return fmt.Errorf("statement is on table %q, but the schema is for table %q", name, table.Name)
+	}
+	return nil
+}
+
+// checkKeyAttributes checks that the item has the key attributes of the table, as scalar values.
+func checkKeyAttributes(item map[string]*dynamodb.AttributeValue, table *schema.Table) error {
+	for _, key := range []struct {
+		kind string
+		name string
+	}{{"partition", table.HashKey}, {"sort", table.SortKey}} {
+		if key.name == "" {
+			continue
+		}
+		av, ok := item[key.name]
+		if !ok {
+			return fmt.Errorf("missing %s key %q", key.kind, key.name)
+		}
+		if av.S == nil && av.N == nil && av.B == nil {
+			return fmt.Errorf("%s key %q must be a string, number or binary value", key.kind, key.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package querybuilder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alecthomas/participle/lexer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+func TestValidate(t *testing.T) {
+	table := schema.NewTableFromCreate(fixtures.Movies.Create)
+	tests := []struct {
+		name  string
+		query string
+		err   string
+		pos   lexer.Position
+	}{
+		{name: "valid query", query: `SELECT * FROM movies WHERE title = :title AND year > 2000`},
+		{name: "valid scan", query: `SELECT title FROM movies WHERE year > 2000`},
+		{name: "valid insert", query: `INSERT INTO movies VALUES ('{"title": "Rush Hour", "year": 1998}')`},
+		{name: "valid placeholder insert", query: `INSERT INTO movies VALUES (?)`},
+		{name: "valid create table", query: `CREATE TABLE other (id STRING HASH KEY)`},
+		{name: "valid drop table", query: `DROP TABLE movies`},
+		{name: "valid delete", query: `DELETE FROM movies WHERE title = ? AND year = ? AND attribute_exists(director) RETURNING ALL_OLD`},
+		{
+			name:  "delete key range",
+			query: `DELETE FROM movies WHERE title = ? AND year > ?`,
+			err:   `1:40: DELETE requires each key attribute in the WHERE clause, in an equality condition, such as: WHERE title = :param AND year = :param`,
+			pos:   lexer.Position{Offset: 39, Line: 1, Column: 40},
+		},
+		{
+			name:  "wrong table",
+			query: `SELECT * FROM gamescores WHERE UserId = ?`,
+			err:   `statement is on table "gamescores", but the schema is for table "movies"`,
+		},
+		{
+			name:  "unknown index",
+			query: `SELECT * FROM movies USE INDEX (missing) WHERE title = ?`,
+			err:   `unrecognized index "missing" fro table "movies"`,
+		},
+		{
+			name:  "partition key inequality",
+			query: `SELECT * FROM movies WHERE title = ? AND title > ?`,
+			err:   `1:42: partition key must appear exactly once in the WHERE clause, in an equality condition, such as: WHERE title = :param`,
+			pos:   lexer.Position{Offset: 41, Line: 1, Column: 42},
+		},
+		{
+			name:  "sort key operator",
+			query: "SELECT * FROM movies\nWHERE title = ? AND year <> ?",
+			err:   `2:21: sort key "year" may not be used with <>, only =, <, <=, >, >=, BETWEEN and begins_with() are allowed`,
+			pos:   lexer.Position{Offset: 41, Line: 2, Column: 21},
+		},
+		{
+			name:  "mixed placeholders",
+			query: `SELECT * FROM movies WHERE title = ? AND year = :year`,
+			err:   `cannot mix positional params (?) with named params (:param)`,
+		},
+		{
+			name:  "insert missing sort key",
+			query: `INSERT INTO movies VALUES ('{"title": "Rush Hour", "year": 1998}'), ('{"title": "Heat"}')`,
+			err:   `document 2: missing sort key "year"`,
+		},
+		{
+			name:  "insert non-scalar key",
+			query: `REPLACE INTO movies VALUES ('{"title": ["Rush Hour"], "year": 1998}')`,
+			err:   `document 1: partition key "title" must be a string, number or binary value`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ast, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			err = Validate(ast, table)
+			if test.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, test.err)
+			var verr *ValidationError
+			require.True(t, errors.As(err, &verr))
+			require.Equal(t, test.pos, verr.Pos)
+		})
+	}
+}
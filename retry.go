@@ -0,0 +1,244 @@
+package dynamosql
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// RetryPolicy configures how the driver retries requests that DynamoDB throttles, with exponential backoff. This is
+// on top of the retries the AWS SDK makes itself. Any other error is returned as soon as it happens.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a request is made, including the first. Zero, the default, and one disable
+	// retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, and is doubled for each retry after it. Defaults to 50ms.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts. Zero means the delay is not capped.
+	MaxDelay time.Duration
+	// Jitter is the fraction of each delay, between 0 and 1, that is randomised so that clients throttled at the same
+	// time do not retry in lockstep.
+	Jitter float64
+}
+
+const defaultRetryBaseDelay = 50 * time.Millisecond
+
+func (p RetryPolicy) validate() error {
+	switch {
+	case p.MaxAttempts < 0:
+		return errors.New("RetryPolicy.MaxAttempts must not be negative")
+	case p.BaseDelay < 0 || p.MaxDelay < 0:
+		return errors.New("RetryPolicy delays must not be negative")
+	case p.Jitter < 0 || p.Jitter > 1:
+		return errors.New("RetryPolicy.Jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// delay returns how long to wait before the given retry, counting from 1.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	delay := p.BaseDelay
+	if delay == 0 {
+		delay = defaultRetryBaseDelay
+	}
+	for i := 1; i < retry && delay < math.MaxInt64/2 && (p.MaxDelay == 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay - time.Duration(p.Jitter*rand.Float64()*float64(delay)) // nolint: gosec
+}
+
+// isThrottled returns true for the errors DynamoDB returns when a request was rejected for exceeding the throughput
+// of a table or the account, which are safe to retry as nothing was written.
+func isThrottled(err error) bool {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+	switch awsErr.Code() {
+	case dynamodb.ErrCodeProvisionedThroughputExceededException, dynamodb.ErrCodeRequestLimitExceeded, "ThrottlingException":
+		return true
+	}
+	return false
+}
+
+// withRetries returns a client that retries throttled requests according to the policy. If the policy does not
+// retry, the client is returned as is.
+func withRetries(dynamo dynamodbiface.DynamoDBAPI, policy RetryPolicy) dynamodbiface.DynamoDBAPI {
+	if policy.MaxAttempts <= 1 {
+		return dynamo
+	}
+	return &retryClient{DynamoDBAPI: dynamo, policy: policy}
+}
+
+type retryClient struct {
+	dynamodbiface.DynamoDBAPI
+	policy RetryPolicy
+}
+
+// do calls fn until it succeeds, fails with an error that is not a throttle, or runs out of attempts. It does not
+// wait for a retry that would start after the deadline of the context, and returns the last error instead.
+func (c *retryClient) do(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= c.policy.MaxAttempts || !isThrottled(err) {
+			return err
+		}
+		delay := c.policy.delay(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *retryClient) QueryWithContext(ctx aws.Context, in *dynamodb.QueryInput, opts ...request.Option) (*dynamodb.QueryOutput, error) {
+	var out *dynamodb.QueryOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.QueryWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) ScanWithContext(ctx aws.Context, in *dynamodb.ScanInput, opts ...request.Option) (*dynamodb.ScanOutput, error) {
+	var out *dynamodb.ScanOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.ScanWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) GetItemWithContext(ctx aws.Context, in *dynamodb.GetItemInput, opts ...request.Option) (*dynamodb.GetItemOutput, error) {
+	var out *dynamodb.GetItemOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.GetItemWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) BatchGetItemWithContext(ctx aws.Context, in *dynamodb.BatchGetItemInput, opts ...request.Option) (*dynamodb.BatchGetItemOutput, error) {
+	var out *dynamodb.BatchGetItemOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.BatchGetItemWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) PutItemWithContext(ctx aws.Context, in *dynamodb.PutItemInput, opts ...request.Option) (*dynamodb.PutItemOutput, error) {
+	var out *dynamodb.PutItemOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.PutItemWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) UpdateItemWithContext(ctx aws.Context, in *dynamodb.UpdateItemInput, opts ...request.Option) (*dynamodb.UpdateItemOutput, error) {
+	var out *dynamodb.UpdateItemOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.UpdateItemWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) DeleteItemWithContext(ctx aws.Context, in *dynamodb.DeleteItemInput, opts ...request.Option) (*dynamodb.DeleteItemOutput, error) {
+	var out *dynamodb.DeleteItemOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.DeleteItemWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) BatchWriteItemWithContext(ctx aws.Context, in *dynamodb.BatchWriteItemInput, opts ...request.Option) (*dynamodb.BatchWriteItemOutput, error) {
+	var out *dynamodb.BatchWriteItemOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.BatchWriteItemWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) TransactWriteItemsWithContext(ctx aws.Context, in *dynamodb.TransactWriteItemsInput, opts ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+	var out *dynamodb.TransactWriteItemsOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.TransactWriteItemsWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) DescribeTableWithContext(ctx aws.Context, in *dynamodb.DescribeTableInput, opts ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	var out *dynamodb.DescribeTableOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.DescribeTableWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) ListTablesWithContext(ctx aws.Context, in *dynamodb.ListTablesInput, opts ...request.Option) (*dynamodb.ListTablesOutput, error) {
+	var out *dynamodb.ListTablesOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.ListTablesWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) CreateTableWithContext(ctx aws.Context, in *dynamodb.CreateTableInput, opts ...request.Option) (*dynamodb.CreateTableOutput, error) {
+	var out *dynamodb.CreateTableOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.CreateTableWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) UpdateTableWithContext(ctx aws.Context, in *dynamodb.UpdateTableInput, opts ...request.Option) (*dynamodb.UpdateTableOutput, error) {
+	var out *dynamodb.UpdateTableOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.UpdateTableWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) DeleteTableWithContext(ctx aws.Context, in *dynamodb.DeleteTableInput, opts ...request.Option) (*dynamodb.DeleteTableOutput, error) {
+	var out *dynamodb.DeleteTableOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.DeleteTableWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
+
+func (c *retryClient) UpdateTimeToLiveWithContext(ctx aws.Context, in *dynamodb.UpdateTimeToLiveInput, opts ...request.Option) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	var out *dynamodb.UpdateTimeToLiveOutput
+	err := c.do(ctx, func() (err error) {
+		out, err = c.DynamoDBAPI.UpdateTimeToLiveWithContext(ctx, in, opts...)
+		return err
+	})
+	return out, err
+}
@@ -0,0 +1,118 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryThrottledRequests(t *testing.T) {
+	const insert = `INSERT INTO items VALUES ({"id": 1})`
+	throttled := awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "slow down", nil)
+	tables := map[string]*dynamodb.CreateTableInput{
+		"items": {
+			TableName: aws.String("items"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		},
+	}
+	// connect returns a connection whose PutItem fails with err for the first failures calls, then succeeds.
+	connect := func(t *testing.T, policy RetryPolicy, failures int, err error, calls *int) driver.ExecerContext {
+		t.Helper()
+		m := &mockDynamoDB{tables: tables, putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			*calls++
+			if *calls <= failures {
+				return nil, err
+			}
+			return &dynamodb.PutItemOutput{}, nil
+		}}
+		connector, openErr := New(Config{DynamoDB: m, Retry: policy}).OpenConnector("")
+		require.NoError(t, openErr)
+		c, connErr := connector.Connect(context.Background())
+		require.NoError(t, connErr)
+		return c.(driver.ExecerContext)
+	}
+	ctx := context.Background()
+
+	t.Run("retries throttles until success", func(t *testing.T) {
+		calls := 0
+		c := connect(t, RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, Jitter: 0.5}, 3, throttled, &calls)
+		_, err := c.ExecContext(ctx, insert, nil)
+		require.NoError(t, err)
+		require.Equal(t, 4, calls)
+	})
+
+	t.Run("gives up after MaxAttempts", func(t *testing.T) {
+		calls := 0
+		c := connect(t, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}, 10, throttled, &calls)
+		_, err := c.ExecContext(ctx, insert, nil)
+		require.Equal(t, throttled, err)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		calls := 0
+		failed := awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+		c := connect(t, RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond}, 10, failed, &calls)
+		_, err := c.ExecContext(ctx, `INSERT INTO items VALUES ({"id": 1}) IF NOT EXISTS`, nil)
+		require.True(t, errors.Is(err, ErrConditionFailed))
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("does not wait past the deadline", func(t *testing.T) {
+		calls := 0
+		c := connect(t, RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}, 10, throttled, &calls)
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		start := time.Now()
+		_, err := c.ExecContext(ctx, insert, nil)
+		require.Equal(t, throttled, err)
+		require.Equal(t, 1, calls)
+		require.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("stops waiting when the context is cancelled", func(t *testing.T) {
+		calls := 0
+		c := connect(t, RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}, 10, throttled, &calls)
+		ctx, cancel := context.WithCancel(ctx)
+		time.AfterFunc(10*time.Millisecond, cancel)
+		_, err := c.ExecContext(ctx, insert, nil)
+		require.Equal(t, context.Canceled, err)
+		require.Equal(t, 1, calls)
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("delays double up to MaxDelay", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+		var delays []time.Duration
+		for retry := 1; retry <= 5; retry++ {
+			delays = append(delays, policy.delay(retry))
+		}
+		require.Equal(t, []time.Duration{
+			10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond,
+		}, delays)
+		require.Equal(t, defaultRetryBaseDelay, RetryPolicy{}.delay(1))
+	})
+
+	t.Run("jitter shortens delays", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, Jitter: 0.5}
+		for i := 0; i < 100; i++ {
+			delay := policy.delay(1)
+			require.True(t, delay > 50*time.Millisecond && delay <= 100*time.Millisecond, delay)
+		}
+	})
+
+	t.Run("invalid policies are rejected", func(t *testing.T) {
+		_, err := New(Config{DynamoDB: &mockDynamoDB{}, Retry: RetryPolicy{MaxAttempts: 3, Jitter: 2}}).OpenConnector("")
+		require.EqualError(t, err, "RetryPolicy.Jitter must be between 0 and 1")
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
@@ -17,84 +20,201 @@ type rows struct {
 	nextPage    func(lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*dynamodb.QueryOutput, error)
 	cols        []*parser.ProjectionColumn
 	mapToGoType bool
-	limit       int
+	// numberAsString is set if numbers are returned as the strings DynamoDB stores, for Config.NumberAsString.
+	numberAsString bool
+	limit          int
+	offset         int
+	// close, if set, releases the resources used to fetch pages.
+	close func()
+	// capacity is the capacity consumed by the pages fetched so far, if requested.
+	capacity *consumedCapacity
+	// all is set for SELECT *, whose items are returned whole in a document column that comes before cols.
+	all bool
 
 	nextRow int
 	count   int
 }
 
-var _ driver.Rows = &rows{}
+var (
+	_ driver.Rows                           = &rows{}
+	_ driver.RowsColumnTypeDatabaseTypeName = &rows{}
+	_ driver.RowsColumnTypeNullable         = &rows{}
+)
 
-// Returns the number of columns.
-// Caveat: the number of columns is always equal to the number of attributes in the first returned item.
+// Columns returns the names of the projected columns, as given by parser.ProjectionColumn.Name, in the order of the
+// projection, which is also the order Next fills in the values of a row, with nil for an attribute the item lacks.
+// SELECT * returns the item in a single "document" column, before any other columns, as in SELECT *, price * qty AS
+// total. A column aliased as an attribute of the item does not replace the attribute in the document.
 func (r *rows) Columns() []string {
-	if len(r.cols) == 0 {
-		return []string{"document"}
+	cols := make([]string, 0, len(r.cols)+1)
+	if r.all {
+		cols = append(cols, "document")
 	}
-
-	cols := make([]string, 0, len(r.cols))
 	for _, col := range r.cols {
-		if col.Function != nil {
-			cols = append(cols, "document")
-		} else {
-			cols = append(cols, col.String())
+		cols = append(cols, col.Name())
+	}
+	return cols
+}
+
+// ColumnTypeDatabaseTypeName returns the DynamoDB type of the column, such as S, N or M. DynamoDB does not have a
+// schema for non-key attributes, so the type is taken from the first item of the current page and is empty if the
+// attribute is absent.
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	if r.all {
+		if index == 0 {
+			return "M"
 		}
+		index--
+	}
+	if r.cols[index].Function != nil && subtreePath(r.cols[index].Function) == nil {
+		return "M"
+	}
+	if r.cols[index].Arithmetic != nil {
+		return "N"
 	}
+	if r.resp == nil || len(r.resp.Items) == 0 {
+		return ""
+	}
+	av := columnAttributeValue(r.cols[index], r.resp.Items[0])
+	if av == nil {
+		return ""
+	}
+	return attributeType(av)
+}
 
-	return cols
+// ColumnTypeNullable always reports columns as nullable because any attribute may be absent from an item.
+func (r *rows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	return true, true
+}
+
+func (r *rows) ConsumedCapacity() float64 {
+	return r.capacity.total()
 }
 
 func (r *rows) Close() error {
+	if r.close != nil {
+		r.close()
+	}
 	return nil
 }
 
-func (r *rows) Next(dest []driver.Value) error {
+// nextItem returns the next item as read from DynamoDB, after the OFFSET and up to the LIMIT, or io.EOF once there
+// are no more.
+func (r *rows) nextItem() (map[string]*dynamodb.AttributeValue, error) {
 	if r.limit > 0 && r.count >= r.limit {
-		return io.EOF
+		return nil, io.EOF
 	}
-	if r.nextRow >= len(r.resp.Items) {
-		resp, err := r.nextPage(r.resp.LastEvaluatedKey)
-		if err != nil {
-			return err
+	var row map[string]*dynamodb.AttributeValue
+	for {
+		if r.nextRow >= len(r.resp.Items) {
+			resp, err := r.nextPage(r.resp.LastEvaluatedKey)
+			if err != nil {
+				return nil, err
+			}
+			r.nextRow = 0
+			r.resp = resp
 		}
-		r.nextRow = 0
-		r.resp = resp
+		row = r.resp.Items[r.nextRow]
+		r.nextRow++
+		if r.offset == 0 {
+			break
+		}
+		// Discard items until the OFFSET is reached.
+		r.offset--
 	}
-	row := r.resp.Items[r.nextRow]
-	r.nextRow++
 	r.count++
+	return row, nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	row, err := r.nextItem()
+	if err != nil {
+		return err
+	}
 
 	// SELECT *
-	if len(r.cols) == 0 {
+	if r.all {
 		dest[0] = r.remap(row)
+		dest = dest[1:]
 	}
 
 	for i, col := range r.cols {
-		if col.Function != nil {
-			// SELECT document(...) returns the whole document
-			dest[i] = r.remap(row)
-		} else {
-			dest[i] = r.remap(pluck(&dynamodb.AttributeValue{M: row}, col.DocumentPath))
+		switch {
+		case col.Case != nil:
+			// DynamoDB can't evaluate CASE, so it is evaluated here on the attributes it projected.
+			if av := evalCase(col.Case, row); av != nil {
+				dest[i] = r.remap(convertValue(av, r.numberAsString))
+			} else {
+				dest[i] = nil
+			}
+		case col.Arithmetic != nil:
+			if av := evalArithmetic(col.Arithmetic, row); av != nil {
+				dest[i] = convertValue(av, r.numberAsString)
+			} else {
+				dest[i] = nil
+			}
+		case col.Function != nil:
+			if path := subtreePath(col.Function); path != nil {
+				dest[i] = r.remap(pluck(&dynamodb.AttributeValue{M: row}, path, r.numberAsString))
+			} else {
+				// document(a, b, ...) returns the item, which only has the projected paths.
+				dest[i] = r.remap(row)
+			}
+		default:
+			dest[i] = r.remap(pluck(&dynamodb.AttributeValue{M: row}, col.DocumentPath, r.numberAsString))
 		}
 	}
 	return nil
 }
 
+// columnAttributeValue returns the value of a column other than a document() of several paths for the item, or nil
+// if the item does not have it.
+func columnAttributeValue(col *parser.ProjectionColumn, item map[string]*dynamodb.AttributeValue) *dynamodb.AttributeValue {
+	switch {
+	case col.Case != nil:
+		return evalCase(col.Case, item)
+	case col.Arithmetic != nil:
+		return evalArithmetic(col.Arithmetic, item)
+	case col.Function != nil:
+		return pluckAttributeValue(&dynamodb.AttributeValue{M: item}, subtreePath(col.Function))
+	default:
+		return pluckAttributeValue(&dynamodb.AttributeValue{M: item}, col.DocumentPath)
+	}
+}
+
+// subtreePath returns the path of a document() column with a single path, which returns the subtree at that path, or
+// nil if the column has several paths.
+func subtreePath(fn *parser.FunctionExpression) *parser.DocumentPath {
+	if len(fn.Args) != 1 {
+		return nil
+	}
+	return fn.Args[0].DocumentPath
+}
+
 func (r *rows) remap(data interface{}) interface{} {
-	if !r.mapToGoType {
+	return remapCollections(data, r.mapToGoType, r.numberAsString)
+}
+
+// remapCollections converts lists and maps of attribute values to Go types if mapToGoType is set, for
+// AlwaysConvertCollectionsToGoType. Numbers in them are float64, or dynamodbattribute.Number if numberAsString is set.
+func remapCollections(data interface{}, mapToGoType, numberAsString bool) interface{} {
+	if !mapToGoType {
 		return data
 	}
+	decoder := dynamodbattribute.NewDecoder(func(d *dynamodbattribute.Decoder) {
+		d.UseNumber = numberAsString
+	})
 	switch data := data.(type) {
 	case []*dynamodb.AttributeValue:
 		out := make([]interface{}, len(data))
-		err := dynamodbattribute.UnmarshalList(data, &out)
+		err := decoder.Decode(&dynamodb.AttributeValue{L: data}, &out)
 		if err != nil {
 			panic(fmt.Sprintf("unexpected conversion error: %+v", err))
 		}
 		return out
 	case map[string]*dynamodb.AttributeValue:
 		out := make(map[string]interface{}, len(data))
-		err := dynamodbattribute.UnmarshalMap(data, &out)
+		err := decoder.Decode(&dynamodb.AttributeValue{M: data}, &out)
 		if err != nil {
 			panic(fmt.Sprintf("unexpected conversion error: %+v", err))
 		}
@@ -104,7 +224,16 @@ func (r *rows) remap(data interface{}) interface{} {
 	}
 }
 
-func pluck(pos *dynamodb.AttributeValue, path *parser.DocumentPath) driver.Value {
+func pluck(pos *dynamodb.AttributeValue, path *parser.DocumentPath, numberAsString bool) driver.Value {
+	av := pluckAttributeValue(pos, path)
+	if av == nil {
+		return nil
+	}
+	return convertValue(av, numberAsString)
+}
+
+// pluckAttributeValue returns the attribute at path, or nil if it does not exist.
+func pluckAttributeValue(pos *dynamodb.AttributeValue, path *parser.DocumentPath) *dynamodb.AttributeValue {
 	var ok bool
 	for _, frag := range path.Fragment {
 		if pos.M == nil {
@@ -114,7 +243,17 @@ func pluck(pos *dynamodb.AttributeValue, path *parser.DocumentPath) driver.Value
 		if !ok {
 			return nil
 		}
-		for _, idx := range frag.Indexes {
+		for _, accessor := range frag.Accessors {
+			if accessor.Key != nil {
+				if pos.M == nil {
+					return nil
+				}
+				if pos, ok = pos.M[*accessor.Key]; !ok {
+					return nil
+				}
+				continue
+			}
+			idx := *accessor.Index
 			switch {
 			case pos.L != nil:
 				if idx >= len(pos.L) {
@@ -141,17 +280,22 @@ func pluck(pos *dynamodb.AttributeValue, path *parser.DocumentPath) driver.Value
 			}
 		}
 	}
-	return convertValue(pos)
+	return pos
 }
 
-func convertValue(av *dynamodb.AttributeValue) interface{} {
+// convertValue converts an attribute value to the Go type it is returned as. Numbers are returned as the string
+// DynamoDB stores if numberAsString is set.
+func convertValue(av *dynamodb.AttributeValue, numberAsString bool) interface{} {
 	switch {
 	// bool
 	case av.BOOL != nil:
 		return *av.BOOL
-	// number (returned as string since db/sql supports string conversion)
+	// number
 	case av.N != nil:
-		return *av.N
+		if numberAsString {
+			return *av.N
+		}
+		return convertNumber(*av.N)
 	// string
 	case av.S != nil:
 		return *av.S
@@ -179,14 +323,80 @@ func convertValue(av *dynamodb.AttributeValue) interface{} {
 	}
 }
 
+// convertNumber returns integers that fit in an int64 as int64 and other numbers as float64. Integers that overflow
+// an int64 are returned as strings, since DynamoDB numbers can have up to 38 digits of precision.
+func convertNumber(n string) interface{} {
+	if i, err := strconv.ParseInt(n, 10, 64); err == nil {
+		return i
+	}
+	if strings.ContainsAny(n, ".eE") {
+		if f, err := strconv.ParseFloat(n, 64); err == nil {
+			return f
+		}
+	}
+	return n
+}
+
+// attributeType returns the DynamoDB data type descriptor of the attribute value.
+func attributeType(av *dynamodb.AttributeValue) string {
+	switch {
+	case av.BOOL != nil:
+		return "BOOL"
+	case av.N != nil:
+		return "N"
+	case av.S != nil:
+		return "S"
+	case av.B != nil:
+		return "B"
+	case av.L != nil:
+		return "L"
+	case av.M != nil:
+		return "M"
+	case av.NS != nil:
+		return "NS"
+	case av.SS != nil:
+		return "SS"
+	case av.BS != nil:
+		return "BS"
+	default:
+		return "NULL"
+	}
+}
+
+// oneRow is the item returned by a write with RETURNING. It has a column per returned attribute, in sorted order,
+// and no rows if nothing was returned.
 type oneRow struct {
-	item        map[string]*dynamodb.AttributeValue
-	consumed    bool
-	mapToGoType bool
+	item           map[string]*dynamodb.AttributeValue
+	cols           []string
+	consumed       bool
+	mapToGoType    bool
+	numberAsString bool
+	capacity       *consumedCapacity
+}
+
+func newOneRow(item map[string]*dynamodb.AttributeValue, mapToGoType, numberAsString bool) *oneRow {
+	cols := make([]string, 0, len(item))
+	for name := range item {
+		cols = append(cols, name)
+	}
+	sort.Strings(cols)
+	return &oneRow{item: item, cols: cols, mapToGoType: mapToGoType, numberAsString: numberAsString}
 }
 
 func (o *oneRow) Columns() []string {
-	return []string{"document"}
+	return o.cols
+}
+
+func (o *oneRow) ColumnTypeDatabaseTypeName(index int) string {
+	return attributeType(o.item[o.cols[index]])
+}
+
+func (o *oneRow) ColumnTypeNullable(index int) (nullable, ok bool) {
+	return true, true
+}
+
+func (o *oneRow) ConsumedCapacity() float64 {
+	return o.capacity.total()
 }
 
 func (o *oneRow) Close() error {
@@ -198,18 +408,96 @@ func (o *oneRow) Next(dest []driver.Value) error {
 		return io.EOF
 	}
 	o.consumed = true
+	for i, col := range o.cols {
+		dest[i] = remapCollections(convertValue(o.item[col], o.numberAsString), o.mapToGoType, o.numberAsString)
+	}
+	return nil
+}
 
-	if !o.mapToGoType {
-		dest[0] = o.item
-		return nil
+var (
+	_ driver.Rows                           = &oneRow{}
+	_ driver.RowsColumnTypeDatabaseTypeName = &oneRow{}
+	_ driver.RowsColumnTypeNullable         = &oneRow{}
+)
+
+// countRow is the single row result of SELECT COUNT(*).
+type countRow struct {
+	count    int64
+	consumed bool
+	capacity *consumedCapacity
+}
+
+func (c *countRow) Columns() []string {
+	return []string{"COUNT(*)"}
+}
+
+func (c *countRow) ColumnTypeDatabaseTypeName(index int) string {
+	return "N"
+}
+
+func (c *countRow) ColumnTypeNullable(index int) (nullable, ok bool) {
+	return false, true
+}
+
+func (c *countRow) ConsumedCapacity() float64 {
+	return c.capacity.total()
+}
+
+func (c *countRow) Close() error {
+	return nil
+}
+
+func (c *countRow) Next(dest []driver.Value) error {
+	if c.consumed {
+		return io.EOF
 	}
+	c.consumed = true
+	dest[0] = c.count
+	return nil
+}
 
-	out := make(map[string]interface{}, len(o.item))
-	if err := dynamodbattribute.UnmarshalMap(o.item, &out); err != nil {
-		return err
+var (
+	_ driver.Rows                           = &countRow{}
+	_ driver.RowsColumnTypeDatabaseTypeName = &countRow{}
+	_ driver.RowsColumnTypeNullable         = &countRow{}
+)
+
+// stringRows is a fixed set of rows of non-null string columns, such as the result of DESCRIBE or SHOW TABLES.
+type stringRows struct {
+	cols    []string
+	rows    [][]string
+	nextRow int
+}
+
+func (s *stringRows) Columns() []string {
+	return s.cols
+}
+
+func (s *stringRows) ColumnTypeDatabaseTypeName(index int) string {
+	return "S"
+}
+
+func (s *stringRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	return false, true
+}
+
+func (s *stringRows) Close() error {
+	return nil
+}
+
+func (s *stringRows) Next(dest []driver.Value) error {
+	if s.nextRow >= len(s.rows) {
+		return io.EOF
+	}
+	for i, value := range s.rows[s.nextRow] {
+		dest[i] = value
 	}
-	dest[0] = out
+	s.nextRow++
 	return nil
 }
 
-var _ driver.Rows = &oneRow{}
+var (
+	_ driver.Rows                           = &stringRows{}
+	_ driver.RowsColumnTypeDatabaseTypeName = &stringRows{}
+	_ driver.RowsColumnTypeNullable         = &stringRows{}
+)
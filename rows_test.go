@@ -1,17 +1,23 @@
 package dynamosql
 
 import (
+	"context"
+	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"io"
 	"strconv"
 	"testing"
+	"time"
 
-	"github.com/alecthomas/participle"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
 )
 
 func TestRows_PaginatesAndAppliesLimit(t *testing.T) {
@@ -59,10 +65,6 @@ func TestRows_PaginatesAndAppliesLimit(t *testing.T) {
 }
 
 func TestPluck(t *testing.T) {
-	projectionParser := participle.MustBuild(
-		&parser.ProjectionColumn{},
-		participle.Lexer(parser.Lexer),
-	)
 	item := map[string]*dynamodb.AttributeValue{
 		"field": {S: aws.String("foo")},
 		"numberSet": {
@@ -79,6 +81,9 @@ func TestPluck(t *testing.T) {
 		},
 		"nestedDocument": {M: map[string]*dynamodb.AttributeValue{
 			"nestedValue": {S: aws.String("nested")},
+			"key with spaces": {L: []*dynamodb.AttributeValue{
+				{M: map[string]*dynamodb.AttributeValue{"a.b": {BOOL: aws.Bool(true)}}},
+			}},
 			"nestedList": {
 				L: []*dynamodb.AttributeValue{
 					{N: aws.String("15")},
@@ -124,7 +129,7 @@ func TestPluck(t *testing.T) {
 		{
 			name:   "index nested list",
 			path:   "nestedDocument.nestedList[0]",
-			result: "15",
+			result: int64(15),
 		},
 		{
 			name:   "index deep nested list",
@@ -139,7 +144,7 @@ func TestPluck(t *testing.T) {
 		{
 			name:   "index number set",
 			path:   "numberSet[1]",
-			result: "101",
+			result: int64(101),
 		},
 		{
 			name:   "index out of bounds",
@@ -156,6 +161,26 @@ func TestPluck(t *testing.T) {
 			path:   "list[1].deepField[2].missingField",
 			result: nil,
 		},
+		{
+			name:   "quoted map key",
+			path:   "nestedDocument['nestedValue']",
+			result: "nested",
+		},
+		{
+			name:   "map keys that are not identifiers",
+			path:   `nestedDocument['key with spaces'][0]["a.b"]`,
+			result: true,
+		},
+		{
+			name:   "missing map key",
+			path:   "nestedDocument['missing']",
+			result: nil,
+		},
+		{
+			name:   "map key of a list",
+			path:   "list['0']",
+			result: nil,
+		},
 		{
 			name:   "missing nested index",
 			path:   "nestedDocument.nestedValue[2]",
@@ -164,10 +189,771 @@ func TestPluck(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			var projection parser.ProjectionColumn
-			require.NoError(t, projectionParser.ParseString(test.path, &projection))
-			v := pluck(&dynamodb.AttributeValue{M: item}, projection.DocumentPath)
+			path, err := parser.ParsePath(test.path)
+			require.NoError(t, err)
+			v := pluck(&dynamodb.AttributeValue{M: item}, path, false)
 			require.Equal(t, test.result, v)
 		})
 	}
 }
+
+func TestQueryPaginatesThroughClient(t *testing.T) {
+	items := make([]map[string]*dynamodb.AttributeValue, 10)
+	for i := range items {
+		items[i] = map[string]*dynamodb.AttributeValue{
+			"pk": {S: aws.String("a")},
+			"id": {N: aws.String(strconv.Itoa(i))},
+		}
+	}
+	tables := map[string]*dynamodb.CreateTableInput{
+		"items": {
+			TableName: aws.String("items"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeRange)},
+			},
+		},
+	}
+	readIDs := func(t *testing.T, r driver.Rows) []string {
+		t.Helper()
+		var ids []string
+		row := make([]driver.Value, 1)
+		for {
+			err := r.Next(row)
+			if err == io.EOF {
+				return ids
+			}
+			require.NoError(t, err)
+			ids = append(ids, strconv.FormatInt(row[0].(int64), 10))
+		}
+	}
+
+	t.Run("all pages", func(t *testing.T) {
+		calls := 0
+		query := pagedItems(items, 3)
+		c := newMockConn(&mockDynamoDB{tables: tables, query: func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			calls++
+			return query(ctx, in)
+		}})
+		r, err := c.QueryContext(context.Background(), `SELECT id FROM items WHERE pk = "a"`, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, readIDs(t, r))
+		require.Equal(t, 4, calls)
+	})
+
+	t.Run("limit with filter stops early", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, query: func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			require.Nil(t, in.Limit, "limit must not be pushed down with a filter")
+			return pagedItems(items, 2)(ctx, in)
+		}})
+		r, err := c.QueryContext(context.Background(), `SELECT id FROM items WHERE pk = "a" AND attribute_not_exists(deleted) LIMIT 5`, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"0", "1", "2", "3", "4"}, readIDs(t, r))
+	})
+
+	t.Run("offset skips items across pages", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, query: func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			require.Equal(t, int64(7), *in.Limit, "limit must include the skipped items")
+			return pagedItems(items, 3)(ctx, in)
+		}})
+		r, err := c.QueryContext(context.Background(), `SELECT id FROM items WHERE pk = "a" LIMIT 3 OFFSET 4`, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"4", "5", "6"}, readIDs(t, r))
+	})
+
+	t.Run("offset past the end", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, query: pagedItems(items, 3)})
+		r, err := c.QueryContext(context.Background(), `SELECT id FROM items WHERE pk = "a" OFFSET 20`, nil)
+		require.NoError(t, err)
+		require.Empty(t, readIDs(t, r))
+	})
+
+	t.Run("skips empty filtered pages", func(t *testing.T) {
+		query := pagedItems(items, 2)
+		c := newMockConn(&mockDynamoDB{tables: tables, query: func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			out, err := query(ctx, in)
+			if in.ExclusiveStartKey != nil && *in.ExclusiveStartKey["id"].N < "7" {
+				// Simulate the filter removing every item on the page.
+				out.Items = nil
+			}
+			return out, err
+		}})
+		r, err := c.QueryContext(context.Background(), `SELECT id FROM items WHERE pk = "a"`, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"0", "1", "8", "9"}, readIDs(t, r))
+	})
+
+	t.Run("cancelled context stops pagination", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		query := pagedItems(items, 2)
+		c := newMockConn(&mockDynamoDB{tables: tables, query: func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			calls++
+			return query(ctx, in)
+		}})
+		r, err := c.QueryContext(ctx, `SELECT id FROM items WHERE pk = "a"`, nil)
+		require.NoError(t, err)
+		row := make([]driver.Value, 1)
+		require.NoError(t, r.Next(row))
+		require.NoError(t, r.Next(row))
+		cancel()
+		require.Equal(t, context.Canceled, r.Next(row))
+		require.Equal(t, 1, calls)
+	})
+}
+
+func TestCountAggregatesPages(t *testing.T) {
+	tables := map[string]*dynamodb.CreateTableInput{
+		"items": {
+			TableName: aws.String("items"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		},
+	}
+	counts := []int64{3, 0, 4}
+	c := newMockConn(&mockDynamoDB{tables: tables, scan: func(ctx aws.Context, in *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+		require.Equal(t, dynamodb.SelectCount, *in.Select)
+		require.Nil(t, in.ProjectionExpression)
+		page := 0
+		if in.ExclusiveStartKey != nil {
+			n, _ := strconv.Atoi(*in.ExclusiveStartKey["page"].N)
+			page = n
+		}
+		out := &dynamodb.ScanOutput{Count: aws.Int64(counts[page])}
+		if page+1 < len(counts) {
+			out.LastEvaluatedKey = map[string]*dynamodb.AttributeValue{"page": {N: aws.String(strconv.Itoa(page + 1))}}
+		}
+		return out, nil
+	}})
+	r, err := c.QueryContext(context.Background(), `SELECT COUNT(*) FROM items WHERE attribute_exists(name)`, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"COUNT(*)"}, r.Columns())
+	row := make([]driver.Value, 1)
+	require.NoError(t, r.Next(row))
+	require.Equal(t, int64(7), row[0])
+	require.Equal(t, io.EOF, r.Next(row))
+}
+
+func TestGetItem(t *testing.T) {
+	tables := map[string]*dynamodb.CreateTableInput{
+		"items": {
+			TableName: aws.String("items"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeRange)},
+			},
+		},
+	}
+	item := map[string]*dynamodb.AttributeValue{
+		"pk":   {S: aws.String("a")},
+		"id":   {N: aws.String("1")},
+		"name": {S: aws.String("one")},
+	}
+
+	t.Run("returns the item", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, getItem: func(ctx aws.Context, in *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			require.Equal(t, map[string]*dynamodb.AttributeValue{"pk": {S: aws.String("a")}, "id": {N: aws.String("1")}}, in.Key)
+			require.Equal(t, "#name", *in.ProjectionExpression)
+			require.Equal(t, map[string]*string{"#name": aws.String("name")}, in.ExpressionAttributeNames)
+			return &dynamodb.GetItemOutput{Item: item}, nil
+		}})
+		r, err := c.QueryContext(context.Background(), `SELECT name FROM items WHERE pk = :pk AND id = :id`,
+			[]driver.NamedValue{{Name: "pk", Value: "a"}, {Name: "id", Value: 1}})
+		require.NoError(t, err)
+		row := make([]driver.Value, 1)
+		require.NoError(t, r.Next(row))
+		require.Equal(t, "one", row[0])
+		require.Equal(t, io.EOF, r.Next(row))
+	})
+
+	t.Run("no rows if the item does not exist", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, getItem: func(ctx aws.Context, in *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			return &dynamodb.GetItemOutput{}, nil
+		}})
+		r, err := c.QueryContext(context.Background(), `SELECT * FROM items WHERE pk = "a" AND id = 2`, nil)
+		require.NoError(t, err)
+		require.Equal(t, io.EOF, r.Next(make([]driver.Value, 1)))
+	})
+
+	t.Run("offset skips the item", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, getItem: func(ctx aws.Context, in *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			return &dynamodb.GetItemOutput{Item: item}, nil
+		}})
+		r, err := c.QueryContext(context.Background(), `SELECT * FROM items WHERE pk = "a" AND id = 1 OFFSET 1`, nil)
+		require.NoError(t, err)
+		require.Equal(t, io.EOF, r.Next(make([]driver.Value, 1)))
+	})
+}
+
+func TestBatchGet(t *testing.T) {
+	tables := map[string]*dynamodb.CreateTableInput{
+		"items": {
+			TableName: aws.String("items"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		},
+	}
+	ids := make([]int, 100)
+	for i := range ids {
+		ids[i] = i
+	}
+	readIDs := func(t *testing.T, r driver.Rows) []string {
+		t.Helper()
+		var ids []string
+		row := make([]driver.Value, 1)
+		for {
+			err := r.Next(row)
+			if err == io.EOF {
+				return ids
+			}
+			require.NoError(t, err)
+			ids = append(ids, strconv.FormatInt(row[0].(int64), 10))
+		}
+	}
+
+	t.Run("merges batches and retries unprocessed keys", func(t *testing.T) {
+		var sizes []int
+		c := newMockConn(&mockDynamoDB{tables: tables, batchGet: func(ctx aws.Context, in *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+			keys := in.RequestItems["items"].Keys
+			sizes = append(sizes, len(keys))
+			out := &dynamodb.BatchGetItemOutput{}
+			if len(keys) > 1 {
+				// Leave the last key unprocessed, as DynamoDB does when throttled.
+				out.UnprocessedKeys = map[string]*dynamodb.KeysAndAttributes{"items": {Keys: keys[len(keys)-1:]}}
+				keys = keys[:len(keys)-1]
+			}
+			for _, key := range keys {
+				// Only items with an even id exist.
+				if n, _ := strconv.Atoi(*key["id"].N); n%2 == 0 {
+					out.Responses = map[string][]map[string]*dynamodb.AttributeValue{"items": append(out.Responses["items"], key)}
+				}
+			}
+			return out, nil
+		}})
+		r, err := c.QueryContext(context.Background(), `SELECT id FROM items WHERE id IN (:ids) LIMIT 70 OFFSET 1`,
+			[]driver.NamedValue{{Name: "ids", Value: ids}})
+		require.NoError(t, err)
+		got := readIDs(t, r)
+		require.Len(t, got, 49)
+		require.Equal(t, "98", got[len(got)-1])
+		require.Equal(t, []int{100, 1}, sizes)
+	})
+
+	t.Run("retries keep the request options and give up at the deadline", func(t *testing.T) {
+		var reqs []*dynamodb.BatchGetItemInput
+		c := newMockConn(&mockDynamoDB{tables: tables, batchGet: func(ctx aws.Context, in *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+			reqs = append(reqs, in)
+			// Leave every key unprocessed, with only the keys and none of the options.
+			return &dynamodb.BatchGetItemOutput{UnprocessedKeys: map[string]*dynamodb.KeysAndAttributes{"items": {Keys: in.RequestItems["items"].Keys}}}, nil
+		}})
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		r, err := c.QueryContext(ctx, `SELECT id FROM items WHERE id IN (1, 2) WITH (CONSISTENT)`, nil)
+		if err == nil {
+			err = r.Next(make([]driver.Value, 1))
+		}
+		require.True(t, errors.Is(err, context.DeadlineExceeded), "%v", err)
+		require.Contains(t, err.Error(), `BatchGetItem left 2 keys unread: {id: 1}, {id: 2}`)
+		require.Greater(t, len(reqs), 1)
+		for _, req := range reqs {
+			ka := req.RequestItems["items"]
+			require.Len(t, ka.Keys, 2)
+			require.Equal(t, "id", aws.StringValue(ka.ProjectionExpression))
+			require.True(t, aws.BoolValue(ka.ConsistentRead))
+		}
+	})
+
+	t.Run("duplicate keys are looked up once", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, batchGet: func(ctx aws.Context, in *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+			return &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]*dynamodb.AttributeValue{"items": in.RequestItems["items"].Keys}}, nil
+		}})
+		r, err := c.QueryContext(context.Background(), `SELECT id FROM items WHERE id IN (1, 2, 3, 2)`, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"1", "2", "3"}, readIDs(t, r))
+	})
+}
+
+func TestScanTypes(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"pk":     {S: aws.String("a")},
+		"str":    {S: aws.String("hello")},
+		"int":    {N: aws.String("-42")},
+		"float":  {N: aws.String("3.25")},
+		"big":    {N: aws.String("123456789012345678901234567890")},
+		"bin":    {B: []byte("bytes")},
+		"flag":   {BOOL: aws.Bool(true)},
+		"absent": {NULL: aws.Bool(true)},
+		"list":   {L: []*dynamodb.AttributeValue{{N: aws.String("1")}, {S: aws.String("two")}}},
+		"object": {M: map[string]*dynamodb.AttributeValue{"nested": {BOOL: aws.Bool(false)}}},
+	}
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{
+			"items": {
+				TableName: aws.String("items"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				},
+			},
+		},
+		// The WHERE clause pins the whole key, so the item is read with GetItem.
+		getItem: func(ctx aws.Context, in *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			return &dynamodb.GetItemOutput{Item: item}, nil
+		},
+	}
+	db := sql.OpenDB(&connector{driver: &Driver{}, dynamo: m, tables: schema.NewTableLoader(m), mapToGoType: true})
+	defer db.Close()
+
+	var (
+		str    string
+		i      int64
+		f      float64
+		big    string
+		bin    []byte
+		b      bool
+		null   sql.NullString
+		list   interface{}
+		object interface{}
+	)
+	row := db.QueryRow(`SELECT str, int, float, big, bin, flag, absent, list, object FROM items WHERE pk = "a"`)
+	require.NoError(t, row.Scan(&str, &i, &f, &big, &bin, &b, &null, &list, &object))
+	require.Equal(t, "hello", str)
+	require.Equal(t, int64(-42), i)
+	require.Equal(t, 3.25, f)
+	require.Equal(t, "123456789012345678901234567890", big)
+	require.Equal(t, []byte("bytes"), bin)
+	require.True(t, b)
+	require.False(t, null.Valid)
+	require.Equal(t, []interface{}{1.0, "two"}, list)
+	require.Equal(t, map[string]interface{}{"nested": false}, object)
+
+	// Integers can be scanned into strings and floats, but not the other way around.
+	var s string
+	require.NoError(t, db.QueryRow(`SELECT int FROM items WHERE pk = "a"`).Scan(&s))
+	require.Equal(t, "-42", s)
+	require.Error(t, db.QueryRow(`SELECT float FROM items WHERE pk = "a"`).Scan(&i))
+}
+
+func TestColumnTypes(t *testing.T) {
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{
+			"items": {
+				TableName: aws.String("items"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				},
+			},
+		},
+		getItem: func(ctx aws.Context, in *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			return &dynamodb.GetItemOutput{Item: map[string]*dynamodb.AttributeValue{
+				"pk":    {S: aws.String("a")},
+				"score": {N: aws.String("10")},
+				"flag":  {BOOL: aws.Bool(true)},
+				"info":  {M: map[string]*dynamodb.AttributeValue{"tags": {L: []*dynamodb.AttributeValue{}}}},
+			}}, nil
+		},
+	}
+	db := sql.OpenDB(&connector{driver: &Driver{}, dynamo: m, tables: schema.NewTableLoader(m)})
+	defer db.Close()
+
+	columnTypes := func(t *testing.T, query string) []string {
+		t.Helper()
+		rows, err := db.Query(query)
+		require.NoError(t, err)
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		require.NoError(t, err)
+		var out []string
+		for _, ct := range types {
+			nullable, ok := ct.Nullable()
+			require.True(t, ok)
+			require.True(t, nullable)
+			out = append(out, ct.Name()+" "+ct.DatabaseTypeName())
+		}
+		return out
+	}
+
+	require.Equal(t, []string{"pk S", "score N", "flag BOOL", "info M", "info.tags L", "missing "},
+		columnTypes(t, `SELECT pk, score, flag, info, info.tags, missing FROM items WHERE pk = "a"`))
+	require.Equal(t, []string{"document M"}, columnTypes(t, `SELECT * FROM items WHERE pk = "a"`))
+	require.Equal(t, []string{"points N", "tags L", "doc S", "document M"},
+		columnTypes(t, `SELECT score AS points, info.tags AS tags, document(pk) AS doc, document(pk, info) FROM items WHERE pk = "a"`))
+}
+
+func TestCase(t *testing.T) {
+	tables := map[string]*dynamodb.CreateTableInput{
+		"items": {
+			TableName: aws.String("items"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		},
+	}
+	items := []map[string]*dynamodb.AttributeValue{
+		{"id": {N: aws.String("1")}, "status": {S: aws.String("A")}},
+		{"id": {N: aws.String("2")}, "status": {S: aws.String("B")}, "score": {N: aws.String("90")}},
+		{"id": {N: aws.String("3")}},
+	}
+
+	t.Run("evaluated on each row", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, scan: func(ctx aws.Context, in *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			// The attributes the CASE reads are projected once, along with the other columns.
+			require.Equal(t, "id, #status, score", *in.ProjectionExpression)
+			return &dynamodb.ScanOutput{Items: items}, nil
+		}})
+		r, err := c.QueryContext(context.Background(),
+			`SELECT id, CASE WHEN status = 'A' THEN 'active' WHEN score >= 50 THEN score ELSE status END AS label, CASE WHEN status IS NULL THEN TRUE END FROM items`, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"id", "label", "case"}, r.Columns())
+		require.Equal(t, "S", r.(driver.RowsColumnTypeDatabaseTypeName).ColumnTypeDatabaseTypeName(1))
+		var got [][]driver.Value
+		for {
+			row := make([]driver.Value, 3)
+			if err := r.Next(row); err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			got = append(got, row)
+		}
+		require.Equal(t, [][]driver.Value{
+			{int64(1), "active", nil},
+			{int64(2), int64(90), nil},
+			{int64(3), nil, true},
+		}, got)
+	})
+
+	item := map[string]*dynamodb.AttributeValue{
+		"name":  {S: aws.String("Heat")},
+		"year":  {N: aws.String("1995")},
+		"tags":  {SS: aws.StringSlice([]string{"crime", "drama"})},
+		"cast":  {L: []*dynamodb.AttributeValue{{S: aws.String("De Niro")}, {S: aws.String("Pacino")}}},
+		"seen":  {BOOL: aws.Bool(true)},
+		"info":  {M: map[string]*dynamodb.AttributeValue{"rating": {N: aws.String("8.3")}}},
+		"empty": {NULL: aws.Bool(true)},
+	}
+	for _, test := range []struct {
+		condition string
+		holds     bool
+	}{
+		{`name = "Heat"`, true},
+		{`name <> "Heat"`, false},
+		{`name <> 1995`, true},
+		{`name < "Ronin"`, true},
+		{`year = 1995.0`, true},
+		{`year > 2000`, false},
+		{`year > "2000"`, false},
+		{`info.rating BETWEEN 8 AND 9`, true},
+		{`info.rating NOT BETWEEN 8 AND 9`, false},
+		{`year IN (1994, 1995)`, true},
+		{`year NOT IN (1994, 1995)`, false},
+		{`missing = 1`, false},
+		{`missing <> 1`, false},
+		{`missing IS NULL`, true},
+		{`empty IS NOT NULL`, true},
+		{`seen = TRUE`, true},
+		{`name LIKE 'H_a%'`, true},
+		{`name LIKE 'eat%'`, false},
+		{`size(tags) = 2 AND size(name) > 3 AND size(info) = 1`, true},
+		{`size(year) = 4`, false},
+		{`attribute_exists(info.rating) AND attribute_not_exists(info.plot)`, true},
+		{`attribute_type(tags, SS) AND NOT attribute_type(year, S)`, true},
+		{`begins_with(name, "He")`, true},
+		{`contains(name, "ea") AND contains(tags, "drama") AND contains(cast, "Pacino")`, true},
+		{`contains(tags, "comedy")`, false},
+		{`year = 1 OR (name = "Heat" AND NOT seen = FALSE)`, true},
+		{`info.rating > year`, false},
+	} {
+		t.Run(test.condition, func(t *testing.T) {
+			ast, err := parser.Parse(`SELECT CASE WHEN ` + test.condition + ` THEN "yes" ELSE "no" END FROM items`)
+			require.NoError(t, err)
+			expected := "no"
+			if test.holds {
+				expected = "yes"
+			}
+			require.Equal(t, expected, *evalCase(ast.Select.Projection.Columns[0].Case, item).S)
+		})
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	tables := map[string]*dynamodb.CreateTableInput{
+		"orders": {
+			TableName: aws.String("orders"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		},
+	}
+	items := []map[string]*dynamodb.AttributeValue{
+		{"id": {N: aws.String("1")}, "price": {N: aws.String("2.5")}, "qty": {N: aws.String("4")}},
+		{"id": {N: aws.String("2")}, "price": {S: aws.String("free")}, "qty": {N: aws.String("1")}},
+		{"id": {N: aws.String("3")}, "price": {N: aws.String("0.1")}},
+	}
+
+	t.Run("evaluated on each row", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, scan: func(ctx aws.Context, in *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			require.Equal(t, "id, price, qty", *in.ProjectionExpression)
+			return &dynamodb.ScanOutput{Items: items}, nil
+		}})
+		r, err := c.QueryContext(context.Background(), `SELECT id, price * qty AS total, price + 0.2 FROM orders`, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"id", "total", "price + 0.2"}, r.Columns())
+		require.Equal(t, "N", r.(driver.RowsColumnTypeDatabaseTypeName).ColumnTypeDatabaseTypeName(1))
+		var got [][]driver.Value
+		for {
+			row := make([]driver.Value, 3)
+			if err := r.Next(row); err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			got = append(got, row)
+		}
+		// Rows whose operands are not numbers, or are missing, have a NULL result rather than failing the query.
+		require.Equal(t, [][]driver.Value{
+			{int64(1), int64(10), 2.7},
+			{int64(2), nil, nil},
+			{int64(3), nil, 0.3},
+		}, got)
+	})
+
+	t.Run("with *", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, scan: func(ctx aws.Context, in *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			require.Nil(t, in.ProjectionExpression)
+			return &dynamodb.ScanOutput{Items: items[:1]}, nil
+		}})
+		r, err := c.QueryContext(context.Background(), `SELECT *, price * qty AS total, price * 2 AS price FROM orders`, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"document", "total", "price"}, r.Columns())
+		types := r.(driver.RowsColumnTypeDatabaseTypeName)
+		require.Equal(t, "M", types.ColumnTypeDatabaseTypeName(0))
+		require.Equal(t, "N", types.ColumnTypeDatabaseTypeName(1))
+		row := make([]driver.Value, 3)
+		require.NoError(t, r.Next(row))
+		// The computed price is its own column, and the item keeps the price it was stored with.
+		require.Equal(t, items[0], row[0])
+		require.Equal(t, []driver.Value{int64(10), int64(5)}, row[1:])
+		require.Equal(t, io.EOF, r.Next(row))
+	})
+
+	item := map[string]*dynamodb.AttributeValue{
+		"a":    {N: aws.String("6")},
+		"b":    {N: aws.String("4")},
+		"zero": {N: aws.String("0")},
+		"big":  {N: aws.String("123456789012345678901234567890")},
+		"info": {M: map[string]*dynamodb.AttributeValue{"n": {N: aws.String("1.5")}}},
+	}
+	for _, test := range []struct {
+		expr     string
+		expected interface{}
+	}{
+		{`a + b * 2`, "14"},
+		{`(a + b) * 2`, "20"},
+		{`a - b - 1`, "1"},
+		{`a-1`, "5"},
+		{`a -2 * b`, "-2"},
+		{`a / b`, "1.5"},
+		{`a / b * b`, "6"},
+		{`info.n * 2`, "3"},
+		{`big + 1`, "123456789012345678901234567891"},
+		{`1 / 3`, "0.3333333333333333"},
+		{`a / zero`, nil},
+		{`a + missing`, nil},
+		{`a + info`, nil},
+	} {
+		t.Run(test.expr, func(t *testing.T) {
+			ast, err := parser.Parse(`SELECT ` + test.expr + ` FROM orders`)
+			require.NoError(t, err)
+			av := evalArithmetic(ast.Select.Projection.Columns[0].Arithmetic, item)
+			if test.expected == nil {
+				require.Nil(t, av)
+				return
+			}
+			require.Equal(t, test.expected, *av.N)
+		})
+	}
+}
+
+func TestNumberAsString(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"id":    {N: aws.String("12345678901234567890")},
+		"int":   {N: aws.String("-42")},
+		"float": {N: aws.String("0.1000000000000000055511151231257827")},
+		"list":  {L: []*dynamodb.AttributeValue{{N: aws.String("9007199254740993")}}},
+	}
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{
+			"items": {
+				TableName: aws.String("items"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				},
+			},
+		},
+		getItem: func(ctx aws.Context, in *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			// The bound key reaches DynamoDB exactly as it was read.
+			require.Equal(t, "12345678901234567890", aws.StringValue(in.Key["id"].N))
+			return &dynamodb.GetItemOutput{Item: item}, nil
+		},
+	}
+	db := sql.OpenDB(&connector{
+		driver: &Driver{}, dynamo: m, tables: schema.NewTableLoader(m), mapToGoType: true, numberAsString: true,
+	})
+	defer db.Close()
+
+	var (
+		id, i, f string
+		list     interface{}
+	)
+	row := db.QueryRow(`SELECT id, int, float, list FROM items WHERE id = ?`, json.Number("12345678901234567890"))
+	require.NoError(t, row.Scan(&id, &i, &f, &list))
+	require.Equal(t, "12345678901234567890", id)
+	require.Equal(t, "-42", i)
+	require.Equal(t, "0.1000000000000000055511151231257827", f)
+	require.Equal(t, []interface{}{dynamodbattribute.Number("9007199254740993")}, list)
+
+	// Numbers still scan into numeric targets.
+	var n int64
+	require.NoError(t, db.QueryRow(`SELECT int FROM items WHERE id = ?`, dynamodbattribute.Number(id)).Scan(&n))
+	require.Equal(t, int64(-42), n)
+}
+
+func TestDocumentSubtree(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"pk": {S: aws.String("a")},
+		"info": {M: map[string]*dynamodb.AttributeValue{
+			"studio": {M: map[string]*dynamodb.AttributeValue{
+				"name":      {S: aws.String("Warner")},
+				"locations": {L: []*dynamodb.AttributeValue{{S: aws.String("Burbank")}}},
+			}},
+		}},
+	}
+	var projection string
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{
+			"items": {
+				TableName: aws.String("items"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				},
+			},
+		},
+		getItem: func(ctx aws.Context, in *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			projection = aws.StringValue(in.ProjectionExpression)
+			return &dynamodb.GetItemOutput{Item: item}, nil
+		},
+	}
+	db := sql.OpenDB(&connector{driver: &Driver{}, dynamo: m, tables: schema.NewTableLoader(m), mapToGoType: true})
+	defer db.Close()
+
+	var studio, missing, doc interface{}
+	row := db.QueryRow(`SELECT document(info.studio), document(info.missing), document(pk, info.studio.name) FROM items WHERE pk = "a"`)
+	require.NoError(t, row.Scan(&studio, &missing, &doc))
+	require.Equal(t, "info.studio, info.#missing, pk, info.studio.#name", projection)
+	require.Equal(t, map[string]interface{}{"name": "Warner", "locations": []interface{}{"Burbank"}}, studio)
+	require.Nil(t, missing)
+	// With several paths, document() returns the item, which DynamoDB has pruned to the projected paths.
+	require.Equal(t, "a", doc.(map[string]interface{})["pk"])
+}
+
+func TestColumnsFollowProjection(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"pk": {S: aws.String("p")},
+		"id": {S: aws.String("1")},
+		"a":  {S: aws.String("A")},
+		"c":  {S: aws.String("C")},
+	}
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{
+			"items": {
+				TableName: aws.String("items"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+					{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeRange)},
+				},
+			},
+		},
+		query: func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			return &dynamodb.QueryOutput{Items: []map[string]*dynamodb.AttributeValue{item, item}}, nil
+		},
+		getItem: func(ctx aws.Context, in *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			return &dynamodb.GetItemOutput{Item: item}, nil
+		},
+		batchGet: func(ctx aws.Context, in *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+			return &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]*dynamodb.AttributeValue{"items": {item}}}, nil
+		},
+	}
+	connector, err := New(Config{DynamoDB: m}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	for _, query := range []string{
+		`SELECT c, a, b FROM items WHERE pk = "p"`,
+		`SELECT c, a, b FROM items WHERE pk = "p" AND id = "1"`,
+		`SELECT c, a, b FROM items WHERE pk = "p" AND id IN ("1", "2")`,
+	} {
+		t.Run(query, func(t *testing.T) {
+			rows, err := db.Query(query)
+			require.NoError(t, err)
+			defer rows.Close()
+			cols, err := rows.Columns()
+			require.NoError(t, err)
+			require.Equal(t, []string{"c", "a", "b"}, cols)
+			n := 0
+			for rows.Next() {
+				var c, a, b sql.NullString
+				require.NoError(t, rows.Scan(&c, &a, &b))
+				require.Equal(t, sql.NullString{String: "C", Valid: true}, c)
+				require.Equal(t, sql.NullString{String: "A", Valid: true}, a)
+				require.Equal(t, sql.NullString{}, b)
+				n++
+			}
+			require.NoError(t, rows.Err())
+			require.NotZero(t, n)
+		})
+	}
+}
+
+func TestAttributeNamesWithDotsAndBrackets(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"user.id": {S: aws.String("u")},
+		"a.b":     {S: aws.String("top level")},
+		"a":       {M: map[string]*dynamodb.AttributeValue{"b": {S: aws.String("nested")}}},
+		"m": {M: map[string]*dynamodb.AttributeValue{
+			"c.d":  {S: aws.String("dotted key")},
+			"e[0]": {S: aws.String("bracketed key")},
+			"e":    {L: []*dynamodb.AttributeValue{{S: aws.String("list element")}}},
+		}},
+	}
+	var key map[string]*dynamodb.AttributeValue
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{
+			"events": {
+				TableName: aws.String("events"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("user.id"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				},
+			},
+		},
+		getItem: func(ctx aws.Context, in *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			key = in.Key
+			return &dynamodb.GetItemOutput{Item: item}, nil
+		},
+	}
+	connector, err := New(Config{DynamoDB: m}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	var topLevel, nested, dotted, bracketed, element string
+	err = db.QueryRow("SELECT `a.b`, a.b, m['c.d'], m['e[0]'], m.e[0] FROM events WHERE `user.id` = 'u'").
+		Scan(&topLevel, &nested, &dotted, &bracketed, &element)
+	require.NoError(t, err)
+	require.Equal(t, map[string]*dynamodb.AttributeValue{"user.id": {S: aws.String("u")}}, key)
+	require.Equal(t, "top level", topLevel)
+	require.Equal(t, "nested", nested)
+	require.Equal(t, "dotted key", dotted)
+	require.Equal(t, "bracketed key", bracketed)
+	require.Equal(t, "list element", element)
+}
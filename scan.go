@@ -0,0 +1,112 @@
+package dynamosql
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// parallelScan reads the segments of a parallel Scan concurrently, one goroutine per segment, and merges their
+// pages into a single stream. next must be called from a single goroutine. close stops all segments, and must be
+// called once the results are no longer needed.
+type parallelScan struct {
+	parent context.Context
+	cancel context.CancelFunc
+	pages  chan scanPage
+}
+
+type scanPage struct {
+	resp *dynamodb.QueryOutput
+	err  error
+}
+
+func newParallelScan(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, req *dynamodb.ScanInput, segments int) *parallelScan {
+	scanCtx, cancel := context.WithCancel(ctx)
+	p := &parallelScan{
+		parent: ctx,
+		cancel: cancel,
+		pages:  make(chan scanPage),
+	}
+	wg := sync.WaitGroup{}
+	for segment := 0; segment < segments; segment++ {
+		segmentReq := *req
+		segmentReq.Segment = aws.Int64(int64(segment))
+		segmentReq.TotalSegments = aws.Int64(int64(segments))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.scanSegment(scanCtx, dynamo, &segmentReq)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(p.pages)
+	}()
+	return p
+}
+
+func (p *parallelScan) scanSegment(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, req *dynamodb.ScanInput) {
+	for {
+		resp, err := dynamo.ScanWithContext(ctx, req)
+		if err != nil {
+			p.send(ctx, scanPage{err: err})
+			return
+		}
+		page := &dynamodb.QueryOutput{
+			ConsumedCapacity: resp.ConsumedCapacity,
+			Count:            resp.Count,
+			Items:            resp.Items,
+			LastEvaluatedKey: resp.LastEvaluatedKey,
+			ScannedCount:     resp.ScannedCount,
+		}
+		if !p.send(ctx, scanPage{resp: page}) || resp.LastEvaluatedKey == nil {
+			return
+		}
+		req.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+}
+
+// send delivers a page to next, and returns false if the scan was stopped first.
+func (p *parallelScan) send(ctx context.Context, page scanPage) bool {
+	select {
+	case p.pages <- page:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// next returns the next page from any of the segments, or io.EOF once all segments are done. The first error from
+// a segment stops the others.
+func (p *parallelScan) next() (*dynamodb.QueryOutput, error) {
+	page, ok := <-p.pages
+	if !ok {
+		if err := p.parent.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	if page.err != nil {
+		p.cancel()
+		return nil, page.err
+	}
+	return page.resp, nil
+}
+
+// nextNonEmpty returns the next page that has items, since rows expects every page after the first to have one.
+func (p *parallelScan) nextNonEmpty() (*dynamodb.QueryOutput, error) {
+	for {
+		resp, err := p.next()
+		if err != nil || len(resp.Items) > 0 {
+			return resp, err
+		}
+	}
+}
+
+func (p *parallelScan) close() {
+	p.cancel()
+}
@@ -0,0 +1,226 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/querybuilder"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+func TestParallelScan(t *testing.T) {
+	tables := map[string]*dynamodb.CreateTableInput{
+		"items": {
+			TableName: aws.String("items"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		},
+	}
+	ctx := context.Background()
+	// segmentedScan serves two pages of two items for each segment, the ids are segment*10 + item number.
+	segmentedScan := func(seen *sync.Map) func(aws.Context, *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+		return func(ctx aws.Context, in *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			segment := *in.Segment
+			seen.Store(segment, *in.TotalSegments)
+			start := int64(0)
+			if in.ExclusiveStartKey != nil {
+				start = 2
+			}
+			out := &dynamodb.ScanOutput{Count: aws.Int64(2)}
+			for i := start; i < start+2; i++ {
+				out.Items = append(out.Items, map[string]*dynamodb.AttributeValue{
+					"id": {N: aws.String(strconv.FormatInt(segment*10+i, 10))},
+				})
+			}
+			if start == 0 {
+				out.LastEvaluatedKey = out.Items[1]
+			}
+			return out, nil
+		}
+	}
+
+	t.Run("merges all segments", func(t *testing.T) {
+		seen := &sync.Map{}
+		c := newMockConn(&mockDynamoDB{tables: tables, scan: segmentedScan(seen)})
+		rows, err := c.QueryContext(ctx, "SELECT id FROM items WITH (SEGMENTS = 4)", nil)
+		require.NoError(t, err)
+		var ids []int
+		dest := make([]driver.Value, 1)
+		for {
+			err := rows.Next(dest)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			ids = append(ids, int(dest[0].(int64)))
+		}
+		require.NoError(t, rows.Close())
+		sort.Ints(ids)
+		require.Equal(t, []int{0, 1, 2, 3, 10, 11, 12, 13, 20, 21, 22, 23, 30, 31, 32, 33}, ids)
+		for segment := int64(0); segment < 4; segment++ {
+			total, ok := seen.Load(segment)
+			require.True(t, ok, "segment %d was not scanned", segment)
+			require.Equal(t, int64(4), total)
+		}
+	})
+
+	t.Run("COUNT sums all segments", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, scan: segmentedScan(&sync.Map{})})
+		rows, err := c.QueryContext(ctx, "SELECT COUNT(*) FROM items WITH (SEGMENTS = 3)", nil)
+		require.NoError(t, err)
+		dest := make([]driver.Value, 1)
+		require.NoError(t, rows.Next(dest))
+		require.Equal(t, int64(12), dest[0])
+	})
+
+	t.Run("LIMIT stops early and Close stops the segments", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, scan: segmentedScan(&sync.Map{})})
+		rows, err := c.QueryContext(ctx, "SELECT id FROM items LIMIT 3 WITH (SEGMENTS = 8)", nil)
+		require.NoError(t, err)
+		dest := make([]driver.Value, 1)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, rows.Next(dest))
+		}
+		require.Equal(t, io.EOF, rows.Next(dest))
+		require.NoError(t, rows.Close())
+	})
+
+	t.Run("a failing segment returns its error", func(t *testing.T) {
+		failure := errors.New("throttled")
+		scan := segmentedScan(&sync.Map{})
+		c := newMockConn(&mockDynamoDB{tables: tables, scan: func(ctx aws.Context, in *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			if *in.Segment == 1 {
+				return nil, failure
+			}
+			return scan(ctx, in)
+		}})
+		rows, err := c.QueryContext(ctx, "SELECT id FROM items WITH (SEGMENTS = 2)", nil)
+		if err == nil {
+			dest := make([]driver.Value, 1)
+			for err == nil {
+				err = rows.Next(dest)
+			}
+			require.NoError(t, rows.Close())
+		}
+		require.Equal(t, failure, err)
+	})
+
+	t.Run("respects context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		c := newMockConn(&mockDynamoDB{tables: tables, scan: func(ctx aws.Context, in *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			if *in.Segment == 0 {
+				return &dynamodb.ScanOutput{Items: []map[string]*dynamodb.AttributeValue{{"id": {N: aws.String("1")}}}}, nil
+			}
+			// The other segments block until they are cancelled.
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}})
+		rows, err := c.QueryContext(ctx, "SELECT id FROM items WITH (SEGMENTS = 4)", nil)
+		require.NoError(t, err)
+		dest := make([]driver.Value, 1)
+		require.NoError(t, rows.Next(dest))
+		cancel()
+		require.Equal(t, context.Canceled, rows.Next(dest))
+		require.NoError(t, rows.Close())
+	})
+}
+
+func TestDisallowScan(t *testing.T) {
+	ctx := context.Background()
+	// Parallel scans call the mock concurrently.
+	var scans int64
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{"gamescores": fixtures.GameScores.Create},
+		scan: func(ctx aws.Context, in *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			atomic.AddInt64(&scans, 1)
+			return &dynamodb.ScanOutput{Count: aws.Int64(0)}, nil
+		},
+		query: func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			return &dynamodb.QueryOutput{Count: aws.Int64(0)}, nil
+		},
+	}
+	c := newMockConn(m)
+	c.disallowScan = true
+
+	_, err := c.QueryContext(ctx, `SELECT * FROM gamescores WHERE Wins > 3`, nil)
+	require.True(t, errors.Is(err, ErrScanDisallowed))
+	require.EqualError(t, err, `scan disallowed: WHERE needs UserId = value for table "gamescores", or GameTitle = value for index "GameTitleIndex", ANDed with its other conditions, to Query, or add WITH (SCAN) to scan anyway`)
+
+	// An OR outside of parentheses can't be used as a key condition.
+	_, err = c.QueryContext(ctx, `SELECT * FROM gamescores WHERE UserId = :user OR Wins > 3`, []driver.NamedValue{{Name: "user", Value: "101"}})
+	require.True(t, errors.Is(err, ErrScanDisallowed))
+
+	// The index has the condition on its partition key, but does not project every attribute.
+	_, err = c.QueryContext(ctx, `SELECT * FROM gamescores WHERE GameTitle = :title`, []driver.NamedValue{{Name: "title", Value: "Galaxy Invaders"}})
+	require.EqualError(t, err, `scan disallowed: WHERE needs UserId = value for table "gamescores", ANDed with its other conditions, to Query; index "GameTitleIndex" can not be queried by this SELECT, or add WITH (SCAN) to scan anyway`)
+
+	_, err = c.QueryContext(ctx, `SELECT * FROM gamescores USE INDEX (GameTitleIndex) WHERE TopScore > 3`, nil)
+	require.EqualError(t, err, `scan disallowed: WHERE needs GameTitle = value for index "GameTitleIndex", ANDed with its other conditions, to Query, or add WITH (SCAN) to scan anyway`)
+	require.Equal(t, int64(0), atomic.LoadInt64(&scans))
+
+	_, err = c.QueryContext(ctx, `SELECT * FROM gamescores WHERE UserId = :user`, []driver.NamedValue{{Name: "user", Value: "101"}})
+	require.NoError(t, err)
+	_, err = c.QueryContext(ctx, `SELECT * FROM gamescores WHERE Wins > 3 WITH (SCAN)`, nil)
+	require.NoError(t, err)
+	_, err = c.QueryContext(ctx, `SELECT * FROM gamescores WITH (SEGMENTS = 2)`, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), atomic.LoadInt64(&scans))
+}
+
+func TestUnprojectedIndexFallback(t *testing.T) {
+	ctx := context.Background()
+	var scanned *dynamodb.ScanInput
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{"gamescores": fixtures.GameScores.Create},
+		scan: func(ctx aws.Context, in *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			scanned = in
+			return &dynamodb.ScanOutput{}, nil
+		},
+	}
+	const query = `SELECT UserId, Wins FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = :title`
+	args := []driver.NamedValue{{Name: "title", Value: "Galaxy Invaders"}}
+
+	c := newMockConn(m)
+	_, err := c.QueryContext(ctx, query, args)
+	require.True(t, errors.Is(err, querybuilder.ErrNotProjected), "%v", err)
+
+	// Without USE INDEX, nothing but the table can return Wins, and the table has no condition on its partition key.
+	c.indexFallback = true
+	rows, err := c.QueryContext(ctx, query, args)
+	require.NoError(t, err)
+	require.Equal(t, io.EOF, rows.Next(make([]driver.Value, 2)))
+	require.Nil(t, scanned.IndexName)
+	require.Equal(t, "GameTitle = :title", aws.StringValue(scanned.FilterExpression))
+
+	// A prepared statement that falls back to the table can be run again, with literals as well as placeholders.
+	stmt, err := c.PrepareContext(ctx, `SELECT UserId, Wins FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = :title AND Wins > 3`)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		scanned = nil
+		rows, err := stmt.(driver.StmtQueryContext).QueryContext(ctx, args)
+		require.NoError(t, err)
+		require.Equal(t, io.EOF, rows.Next(make([]driver.Value, 2)))
+		require.Nil(t, scanned.IndexName)
+		require.Equal(t, "GameTitle = :title AND Wins > :_gen1", aws.StringValue(scanned.FilterExpression))
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			":title": {S: aws.String("Galaxy Invaders")},
+			":_gen1": {N: aws.String("3")},
+		}, scanned.ExpressionAttributeValues)
+	}
+
+	c.disallowScan = true
+	_, err = c.QueryContext(ctx, query, args)
+	require.True(t, errors.Is(err, ErrScanDisallowed), "%v", err)
+}
@@ -3,6 +3,7 @@ package schema
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
@@ -10,12 +11,33 @@ import (
 	"golang.org/x/sync/singleflight"
 )
 
-// Table contains the schema for a DynamoDB table
+// Table contains the schema for a DynamoDB table.
+//
+// A Table returned by a TableLoader is shared by every caller that gets it until it is loaded again, so it must not
+// be modified. Its methods only read it, and are safe for concurrent use. Index returns a copy of an index, which the
+// caller may modify.
 type Table struct {
 	Name    string
 	HashKey string
 	SortKey string
 	Indexes []Index
+	// AttributeTypes maps each key attribute of the table and its indexes to its type: S, N or B. DynamoDB has no
+	// schema for other attributes.
+	AttributeTypes map[string]string
+}
+
+// KeyAttribute is an attribute of the key of a table or index.
+type KeyAttribute struct {
+	Name string
+	// Type is the type of the attribute: S, N or B. It is empty if unknown.
+	Type string
+}
+
+// KeySchema is the key of a table or index.
+type KeySchema struct {
+	PartitionKey KeyAttribute
+	// SortKey is the zero KeyAttribute if there is no sort key.
+	SortKey KeyAttribute
 }
 
 // NewTable parses a dynamodb.TableDescription into a simplified Table schema
@@ -31,6 +53,7 @@ func NewTable(desc *dynamodb.TableDescription) *Table {
 			Name: *indexDesc.IndexName,
 		}
 		index.HashKey, index.SortKey = parseKeySchema(indexDesc.KeySchema)
+		index.Projection, index.NonKeyAttributes = parseProjection(indexDesc.Projection)
 		indexes = append(indexes, index)
 	}
 	for _, indexDesc := range desc.GlobalSecondaryIndexes {
@@ -39,14 +62,16 @@ func NewTable(desc *dynamodb.TableDescription) *Table {
 			Global: true,
 		}
 		index.HashKey, index.SortKey = parseKeySchema(indexDesc.KeySchema)
+		index.Projection, index.NonKeyAttributes = parseProjection(indexDesc.Projection)
 		indexes = append(indexes, index)
 	}
 	hash, sort := parseKeySchema(desc.KeySchema)
 	return &Table{
-		Name:    *desc.TableName,
-		HashKey: hash,
-		SortKey: sort,
-		Indexes: indexes,
+		Name:           *desc.TableName,
+		HashKey:        hash,
+		SortKey:        sort,
+		Indexes:        indexes,
+		AttributeTypes: parseAttributeDefinitions(desc.AttributeDefinitions),
 	}
 }
 
@@ -64,6 +89,7 @@ func NewTableFromCreate(desc *dynamodb.CreateTableInput) *Table {
 			Global: false,
 		}
 		index.HashKey, index.SortKey = parseKeySchema(indexDesc.KeySchema)
+		index.Projection, index.NonKeyAttributes = parseProjection(indexDesc.Projection)
 		indexes = append(indexes, index)
 	}
 	for _, indexDesc := range desc.GlobalSecondaryIndexes {
@@ -72,14 +98,16 @@ func NewTableFromCreate(desc *dynamodb.CreateTableInput) *Table {
 			Global: true,
 		}
 		index.HashKey, index.SortKey = parseKeySchema(indexDesc.KeySchema)
+		index.Projection, index.NonKeyAttributes = parseProjection(indexDesc.Projection)
 		indexes = append(indexes, index)
 	}
 	hash, sort := parseKeySchema(desc.KeySchema)
 	return &Table{
-		Name:    *desc.TableName,
-		HashKey: hash,
-		SortKey: sort,
-		Indexes: indexes,
+		Name:           *desc.TableName,
+		HashKey:        hash,
+		SortKey:        sort,
+		Indexes:        indexes,
+		AttributeTypes: parseAttributeDefinitions(desc.AttributeDefinitions),
 	}
 }
 
@@ -88,6 +116,32 @@ func (t *Table) IsKey(name string) bool {
 	return t.HashKey == name || t.SortKey == name
 }
 
+// PartitionKey returns the partition key of the table, which is also known as its hash key.
+func (t *Table) PartitionKey() KeyAttribute {
+	return t.keyAttribute(t.HashKey)
+}
+
+// KeySchema returns the primary key of the table.
+func (t *Table) KeySchema() KeySchema {
+	return KeySchema{PartitionKey: t.PartitionKey(), SortKey: t.keyAttribute(t.SortKey)}
+}
+
+// Index returns a copy of the index with a matching name, and false if not found.
+func (t *Table) Index(name string) (Index, bool) {
+	if idx := t.GetIndex(name); idx != nil {
+		idx.NonKeyAttributes = append([]string(nil), idx.NonKeyAttributes...)
+		return *idx, true
+	}
+	return Index{}, false
+}
+
+func (t *Table) keyAttribute(name string) KeyAttribute {
+	if name == "" {
+		return KeyAttribute{}
+	}
+	return KeyAttribute{Name: name, Type: t.AttributeTypes[name]}
+}
+
 // HasIndex returns true if the table contains an index with a matching name.
 func (t *Table) HasIndex(name string) bool {
 	for _, idx := range t.Indexes {
@@ -119,31 +173,97 @@ func parseKeySchema(schema []*dynamodb.KeySchemaElement) (hash, sort string) {
 	return
 }
 
+func parseAttributeDefinitions(defs []*dynamodb.AttributeDefinition) map[string]string {
+	if len(defs) == 0 {
+		return nil
+	}
+	types := make(map[string]string, len(defs))
+	for _, def := range defs {
+		types[aws.StringValue(def.AttributeName)] = aws.StringValue(def.AttributeType)
+	}
+	return types
+}
+
+func parseProjection(projection *dynamodb.Projection) (projectionType string, nonKeyAttributes []string) {
+	if projection == nil {
+		return "", nil
+	}
+	return aws.StringValue(projection.ProjectionType), aws.StringValueSlice(projection.NonKeyAttributes)
+}
+
 // Index is the schema for a secondary index.
 type Index struct {
 	Name    string
 	HashKey string
 	SortKey string
 	Global  bool
+	// Projection is the ProjectionType of the index: ALL, KEYS_ONLY or INCLUDE. It is empty if unknown.
+	Projection string
+	// NonKeyAttributes are the attributes projected into an INCLUDE index in addition to the keys.
+	NonKeyAttributes []string
 }
 
-// TableLoader is a loading cache of DynamoDB table schemas.
+// KeySchema returns the key of the index, with the types of its attributes from the table.
+func (i *Index) KeySchema(table *Table) KeySchema {
+	return KeySchema{PartitionKey: table.keyAttribute(i.HashKey), SortKey: table.keyAttribute(i.SortKey)}
+}
+
+// Projects returns true if the attribute is projected into the index. Key attributes of the table and the index
+// are always projected.
+func (i *Index) Projects(table *Table, attr string) bool {
+	if i.Projection == dynamodb.ProjectionTypeAll || table.IsKey(attr) || i.HashKey == attr || i.SortKey == attr {
+		return true
+	}
+	if i.Projection == dynamodb.ProjectionTypeInclude {
+		for _, include := range i.NonKeyAttributes {
+			if include == attr {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TableLoader is a loading cache of DynamoDB table schemas. It is safe for concurrent use.
 type TableLoader struct {
 	dynamo dynamodbiface.DynamoDBAPI
+	// ttl is how long a schema is cached before it is loaded again. Zero caches schemas until they are forgotten.
+	ttl time.Duration
+	// now returns the current time, or is nil to use time.Now.
+	now    func() time.Time
 	tables sync.Map
 	load   singleflight.Group
 }
 
+// cachedTable is a table schema and when it was loaded.
+type cachedTable struct {
+	table  *Table
+	loaded time.Time
+}
+
+// NewTableLoader returns a TableLoader that caches each schema until it is forgotten.
 func NewTableLoader(dynamo dynamodbiface.DynamoDBAPI) *TableLoader {
 	return &TableLoader{dynamo: dynamo}
 }
 
-// Get retrieves a cached table schema, loading it from DynamoDB if not found.
+// NewTableLoaderWithTTL returns a TableLoader that loads a schema again once it has been cached for longer than ttl,
+// so that changes made outside the driver, such as new indexes, are eventually seen. A ttl of zero caches schemas
+// until they are forgotten, like NewTableLoader.
+func NewTableLoaderWithTTL(dynamo dynamodbiface.DynamoDBAPI, ttl time.Duration) *TableLoader {
+	return &TableLoader{dynamo: dynamo, ttl: ttl}
+}
+
+// Get retrieves a cached table schema, loading it from DynamoDB if not found or if it has expired.
 // If multiple Get are issued against the same table concurrently, only a single request will be made to load the table.
+// The schema is loaded with DescribeTableWithContext, and Get returns the error of ctx as soon as it is done, without
+// loading the schema if it already is.
 func (l *TableLoader) Get(ctx context.Context, name string) (*Table, error) {
-	table, ok := l.tables.Load(name)
-	if ok {
-		return table.(*Table), nil
+	cached, ok := l.tables.Load(name)
+	if ok && !l.expired(cached.(*cachedTable)) {
+		return cached.(*cachedTable).table, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	resultChan := l.load.DoChan(name, func() (interface{}, error) {
@@ -152,7 +272,7 @@ func (l *TableLoader) Get(ctx context.Context, name string) (*Table, error) {
 			return nil, err
 		}
 		table := NewTable(desc.Table)
-		l.tables.Store(name, table)
+		l.tables.Store(name, &cachedTable{table: table, loaded: l.clock()})
 		return table, nil
 	})
 	select {
@@ -165,3 +285,22 @@ func (l *TableLoader) Get(ctx context.Context, name string) (*Table, error) {
 		return result.Val.(*Table), nil
 	}
 }
+
+// Forget removes a table schema from the cache, so that it is loaded again on the next Get. It must be called
+// whenever the driver creates, drops or alters a table.
+func (l *TableLoader) Forget(name string) {
+	l.tables.Delete(name)
+	// A load that is already in flight may have started before the change, so do not let the next Get wait for it.
+	l.load.Forget(name)
+}
+
+func (l *TableLoader) expired(cached *cachedTable) bool {
+	return l.ttl > 0 && l.clock().Sub(cached.loaded) >= l.ttl
+}
+
+func (l *TableLoader) clock() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
@@ -2,9 +2,14 @@ package schema
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mightyguava/dynamosql/testing/fixtures"
@@ -19,9 +24,10 @@ func TestNewTable(t *testing.T) {
 		require.NoError(t, err)
 		table := NewTable(desc.Table)
 		expectedTable := &Table{
-			Name:    "movies",
-			HashKey: "title",
-			SortKey: "year",
+			Name:           "movies",
+			HashKey:        "title",
+			SortKey:        "year",
+			AttributeTypes: map[string]string{"title": "S", "year": "N"},
 		}
 		require.Equal(t, expectedTable, table)
 		table = NewTableFromCreate(fixtures.Movies.Create)
@@ -38,17 +44,20 @@ func TestNewTable(t *testing.T) {
 			SortKey: "GameTitle",
 			Indexes: []Index{
 				{
-					Name:    "UserWinsIndex",
-					HashKey: "UserId",
-					SortKey: "Wins",
+					Name:       "UserWinsIndex",
+					HashKey:    "UserId",
+					SortKey:    "Wins",
+					Projection: "KEYS_ONLY",
 				},
 				{
-					Name:    "GameTitleIndex",
-					HashKey: "GameTitle",
-					SortKey: "TopScore",
-					Global:  true,
+					Name:       "GameTitleIndex",
+					HashKey:    "GameTitle",
+					SortKey:    "TopScore",
+					Global:     true,
+					Projection: "KEYS_ONLY",
 				},
 			},
+			AttributeTypes: map[string]string{"UserId": "S", "GameTitle": "S", "Wins": "N", "TopScore": "N"},
 		}
 		require.Equal(t, expectedTable, table)
 		table = NewTableFromCreate(fixtures.GameScores.Create)
@@ -65,9 +74,130 @@ func TestLoadTable(t *testing.T) {
 	require.NoError(t, err)
 
 	expectedTable := &Table{
-		Name:    "movies",
-		HashKey: "title",
-		SortKey: "year",
+		Name:           "movies",
+		HashKey:        "title",
+		SortKey:        "year",
+		AttributeTypes: map[string]string{"title": "S", "year": "N"},
 	}
 	require.Equal(t, expectedTable, table)
 }
+
+func TestKeySchema(t *testing.T) {
+	table := NewTableFromCreate(fixtures.GameScores.Create)
+	require.Equal(t, KeyAttribute{Name: "UserId", Type: "S"}, table.PartitionKey())
+	require.Equal(t, KeySchema{
+		PartitionKey: KeyAttribute{Name: "UserId", Type: "S"},
+		SortKey:      KeyAttribute{Name: "GameTitle", Type: "S"},
+	}, table.KeySchema())
+
+	index, ok := table.Index("GameTitleIndex")
+	require.True(t, ok)
+	require.True(t, index.Global)
+	require.Equal(t, KeySchema{
+		PartitionKey: KeyAttribute{Name: "GameTitle", Type: "S"},
+		SortKey:      KeyAttribute{Name: "TopScore", Type: "N"},
+	}, index.KeySchema(table))
+	index.Name = "changed"
+	require.True(t, table.HasIndex("GameTitleIndex"), "Index returns a copy")
+
+	_, ok = table.Index("missing")
+	require.False(t, ok)
+
+	hashOnly := NewTableFromCreate(&dynamodb.CreateTableInput{
+		TableName: aws.String("items"),
+		KeySchema: []*dynamodb.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeHash)}},
+	})
+	require.Equal(t, KeySchema{PartitionKey: KeyAttribute{Name: "id"}}, hashOnly.KeySchema())
+}
+
+// describeCounter serves DescribeTable for a single hash key table and counts the calls.
+type describeCounter struct {
+	dynamodbiface.DynamoDBAPI
+	lock  sync.Mutex
+	calls int
+}
+
+func (d *describeCounter) DescribeTableWithContext(ctx aws.Context, in *dynamodb.DescribeTableInput, opts ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.calls++
+	return &dynamodb.DescribeTableOutput{Table: &dynamodb.TableDescription{
+		TableName: in.TableName,
+		KeySchema: []*dynamodb.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeHash)}},
+	}}, nil
+}
+
+func TestTableLoaderCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("without a TTL schemas are cached until forgotten", func(t *testing.T) {
+		dynamo := &describeCounter{}
+		loader := NewTableLoader(dynamo)
+		for i := 0; i < 3; i++ {
+			_, err := loader.Get(ctx, "items")
+			require.NoError(t, err)
+		}
+		require.Equal(t, 1, dynamo.calls)
+		loader.Forget("items")
+		table, err := loader.Get(ctx, "items")
+		require.NoError(t, err)
+		require.Equal(t, "id", table.HashKey)
+		require.Equal(t, 2, dynamo.calls)
+	})
+
+	t.Run("schemas are loaded again once the TTL expires", func(t *testing.T) {
+		dynamo := &describeCounter{}
+		now := time.Unix(0, 0)
+		loader := NewTableLoaderWithTTL(dynamo, time.Minute)
+		loader.now = func() time.Time { return now }
+		get := func() {
+			_, err := loader.Get(ctx, "items")
+			require.NoError(t, err)
+		}
+		get()
+		now = now.Add(59 * time.Second)
+		get()
+		require.Equal(t, 1, dynamo.calls)
+		now = now.Add(time.Second)
+		get()
+		get()
+		require.Equal(t, 2, dynamo.calls)
+	})
+
+	t.Run("a done context does not load the schema", func(t *testing.T) {
+		dynamo := &describeCounter{}
+		loader := NewTableLoader(dynamo)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := loader.Get(cancelled, "items")
+		require.Equal(t, context.Canceled, err)
+		require.Equal(t, 0, dynamo.calls)
+
+		// A cached schema does not need loading, so is still returned.
+		_, err = loader.Get(ctx, "items")
+		require.NoError(t, err)
+		_, err = loader.Get(cancelled, "items")
+		require.NoError(t, err)
+		require.Equal(t, 1, dynamo.calls)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		dynamo := &describeCounter{}
+		loader := NewTableLoaderWithTTL(dynamo, time.Nanosecond)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					_, err := loader.Get(ctx, "items")
+					require.NoError(t, err)
+					if j%10 == 0 {
+						loader.Forget("items")
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
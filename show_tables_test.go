@@ -0,0 +1,60 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShowTables(t *testing.T) {
+	ctx := context.Background()
+	pages := [][]string{{"gamescores", "movie_ratings"}, {"movies", "users"}}
+	c := newMockConn(&mockDynamoDB{listTables: func(in *dynamodb.ListTablesInput) (*dynamodb.ListTablesOutput, error) {
+		page := 0
+		if in.ExclusiveStartTableName != nil {
+			require.Equal(t, "movie_ratings", *in.ExclusiveStartTableName)
+			page = 1
+		}
+		out := &dynamodb.ListTablesOutput{TableNames: aws.StringSlice(pages[page])}
+		if page == 0 {
+			out.LastEvaluatedTableName = aws.String("movie_ratings")
+		}
+		return out, nil
+	}})
+	query := func(t *testing.T, query string) []string {
+		t.Helper()
+		rows, err := c.QueryContext(ctx, query, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"table"}, rows.Columns())
+		var names []string
+		for {
+			dest := make([]driver.Value, 1)
+			err := rows.Next(dest)
+			if err == io.EOF {
+				return names
+			}
+			require.NoError(t, err)
+			names = append(names, dest[0].(string))
+		}
+	}
+
+	t.Run("lists every page", func(t *testing.T) {
+		require.Equal(t, []string{"gamescores", "movie_ratings", "movies", "users"}, query(t, "SHOW TABLES"))
+	})
+
+	t.Run("LIKE filters table names", func(t *testing.T) {
+		require.Equal(t, []string{"movie_ratings", "movies"}, query(t, "SHOW TABLES LIKE 'movie%'"))
+		require.Equal(t, []string{"movies"}, query(t, "SHOW TABLES LIKE 'movie_'"))
+		require.Empty(t, query(t, "SHOW TABLES LIKE 'games'"))
+	})
+
+	t.Run("Exec is an error", func(t *testing.T) {
+		_, err := c.ExecContext(ctx, "SHOW TABLES", nil)
+		require.EqualError(t, err, "SHOW TABLES returns rows, use Query() instead of Exec()")
+	})
+}
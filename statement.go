@@ -4,38 +4,79 @@ import (
 	"context"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"io"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
 	"github.com/mightyguava/dynamosql/querybuilder"
 )
 
+// stmtConfig is the configuration of the connection a statement was prepared on, which each kind of statement
+// embeds.
+type stmtConfig struct {
+	dynamo      dynamodbiface.DynamoDBAPI
+	mapToGoType bool
+	// numberAsString is set if numbers are returned as strings, for Config.NumberAsString.
+	numberAsString bool
+	// returnCapacity is set if the consumed capacity of the statement is reported by its result or rows.
+	returnCapacity bool
+}
+
 type execStmt struct {
 	legacyStmtMixin
+	stmtConfig
 	preparedStmt querybuilder.ExecStmt
-	dynamo       dynamodbiface.DynamoDBAPI
-	mapToGoType  bool
+	numInput     int
+	// tx is set if the statement was prepared in a transaction, in which case writes are buffered in it.
+	tx *tx
+}
+
+func (s *execStmt) NumInput() int {
+	return s.numInput
 }
 
 func (s *execStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
-	return s.preparedStmt.Do(ctx, s.dynamo, args)
+	if s.tx != nil {
+		return s.tx.add(s.preparedStmt, args)
+	}
+	dynamo, capacity := withConsumedCapacity(s.dynamo, s.returnCapacity)
+	result, err := s.preparedStmt.Do(ctx, dynamo, args)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	if capacity != nil {
+		return &capacityResult{Result: result, capacity: capacity}, nil
+	}
+	return result, nil
 }
 
 func (s *execStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-	result, err := s.preparedStmt.Do(ctx, s.dynamo, args)
+	if s.tx != nil {
+		return nil, errors.New("RETURNING is not supported in a transaction")
+	}
+	dynamo, capacity := withConsumedCapacity(s.dynamo, s.returnCapacity)
+	result, err := s.preparedStmt.Do(ctx, dynamo, args)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
-	return &oneRow{item: result.Item()}, nil
+	row := newOneRow(result.Item(), s.mapToGoType, s.numberAsString)
+	row.capacity = capacity
+	return row, nil
 }
 
 type queryStmt struct {
 	legacyStmtMixin
+	stmtConfig
 	preparedStmt *querybuilder.PreparedQuery
-	dynamo       dynamodbiface.DynamoDBAPI
-	mapToGoType  bool
+	numInput     int
+}
+
+func (s *queryStmt) NumInput() int {
+	return s.numInput
 }
 
 func (s *queryStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
@@ -44,20 +85,36 @@ func (s *queryStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (
 
 func (s *queryStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
 	q := s.preparedStmt
-	req, err := q.NewRequest(args)
+	dynamo, capacity := withConsumedCapacity(s.dynamo, s.returnCapacity)
+	if q.Segments > 1 {
+		return s.parallelScan(ctx, dynamo, capacity, args)
+	}
+	if q.BatchGet != nil {
+		return s.batchGet(ctx, dynamo, capacity, args)
+	}
+	fetch, err := s.newFetcher(dynamo, args)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := s.dynamo.QueryWithContext(ctx, req)
+	if q.Count {
+		return s.count(ctx, fetch, capacity)
+	}
+	limit, err := q.BindLimit(args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := fetch(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 	return &rows{
 		nextPage: func(lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*dynamodb.QueryOutput, error) {
 			for lastEvaluatedKey != nil {
-				req.ExclusiveStartKey = lastEvaluatedKey
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
 				// nolint: govet
-				resp, err := s.dynamo.QueryWithContext(ctx, req)
+				resp, err := fetch(ctx, lastEvaluatedKey)
 				if err != nil {
 					return nil, err
 				}
@@ -67,19 +124,378 @@ func (s *queryStmt) QueryContext(ctx context.Context, args []driver.NamedValue)
 				// An empty response does not necessarily indicate there are no more results. It's possible the
 				// filter expression filtered out all values in this range. Need to keep paging until LastEvaluatedKey
 				// is nil.
-				if resp.LastEvaluatedKey != nil {
-					lastEvaluatedKey = resp.LastEvaluatedKey
-				}
+				lastEvaluatedKey = resp.LastEvaluatedKey
 			}
 			return nil, io.EOF
 		},
-		cols:        q.Columns,
-		resp:        resp,
-		mapToGoType: s.mapToGoType,
-		limit:       q.Limit,
+		all:            q.All,
+		cols:           q.Columns,
+		resp:           resp,
+		mapToGoType:    s.mapToGoType,
+		numberAsString: s.numberAsString,
+		limit:          limit,
+		offset:         q.Offset,
+		capacity:       capacity,
+	}, nil
+}
+
+// count sums the per-page Count of a Select=COUNT request over all pages.
+func (s *queryStmt) count(ctx context.Context, fetch fetchFunc, capacity *consumedCapacity) (driver.Rows, error) {
+	var (
+		count            int64
+		lastEvaluatedKey map[string]*dynamodb.AttributeValue
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := fetch(ctx, lastEvaluatedKey)
+		if err != nil {
+			return nil, err
+		}
+		count += aws.Int64Value(resp.Count)
+		if resp.LastEvaluatedKey == nil {
+			return &countRow{count: count, capacity: capacity}, nil
+		}
+		lastEvaluatedKey = resp.LastEvaluatedKey
+	}
+}
+
+// parallelScan runs a Scan with WITH (SEGMENTS = n) as n concurrent segments. Rows are returned in the order
+// the pages arrive from the segments.
+func (s *queryStmt) parallelScan(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, capacity *consumedCapacity, args []driver.NamedValue) (driver.Rows, error) {
+	q := s.preparedStmt
+	req, err := q.NewScanRequest(args)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := q.BindLimit(args)
+	if err != nil {
+		return nil, err
+	}
+	scan := newParallelScan(ctx, dynamo, req, q.Segments)
+	if q.Count {
+		defer scan.close()
+		var count int64
+		for {
+			resp, err := scan.next()
+			if err == io.EOF {
+				return &countRow{count: count, capacity: capacity}, nil
+			} else if err != nil {
+				return nil, err
+			}
+			count += aws.Int64Value(resp.Count)
+		}
+	}
+	resp, err := scan.nextNonEmpty()
+	if err == io.EOF {
+		resp = &dynamodb.QueryOutput{}
+	} else if err != nil {
+		scan.close()
+		return nil, err
+	}
+	return &rows{
+		nextPage: func(map[string]*dynamodb.AttributeValue) (*dynamodb.QueryOutput, error) {
+			return scan.nextNonEmpty()
+		},
+		close:          scan.close,
+		all:            q.All,
+		cols:           q.Columns,
+		resp:           resp,
+		mapToGoType:    s.mapToGoType,
+		numberAsString: s.numberAsString,
+		limit:          limit,
+		offset:         q.Offset,
+		capacity:       capacity,
+	}, nil
+}
+
+// batchGet reads the items of a SELECT that looks up keys with IN, with a BatchGetItem request of up to 100 keys per
+// page. Rows are returned in no particular order.
+func (s *queryStmt) batchGet(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, capacity *consumedCapacity, args []driver.NamedValue) (driver.Rows, error) {
+	q := s.preparedStmt
+	reqs, err := q.NewBatchGetRequests(args)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := q.BindLimit(args)
+	if err != nil {
+		return nil, err
+	}
+	retries := 0
+	next := func() (*dynamodb.QueryOutput, error) {
+		for len(reqs) > 0 {
+			items, err := batchGetItems(ctx, dynamo, reqs[0], &retries)
+			if err != nil {
+				return nil, err
+			}
+			reqs = reqs[1:]
+			if len(items) > 0 {
+				return &dynamodb.QueryOutput{Items: items, Count: aws.Int64(int64(len(items)))}, nil
+			}
+		}
+		return nil, io.EOF
+	}
+	resp, err := next()
+	if err == io.EOF {
+		resp = &dynamodb.QueryOutput{}
+	} else if err != nil {
+		return nil, err
+	}
+	return &rows{
+		nextPage: func(map[string]*dynamodb.AttributeValue) (*dynamodb.QueryOutput, error) {
+			return next()
+		},
+		all:            q.All,
+		cols:           q.Columns,
+		resp:           resp,
+		mapToGoType:    s.mapToGoType,
+		numberAsString: s.numberAsString,
+		limit:          limit,
+		offset:         q.Offset,
+		capacity:       capacity,
 	}, nil
 }
 
+const (
+	// batchGetBackoff is the initial delay before resubmitting unprocessed keys, doubled on each retry.
+	batchGetBackoff = 50 * time.Millisecond
+	// batchGetRetries is the most times a statement resubmits unprocessed keys, across all of its requests.
+	batchGetRetries = 10
+)
+
+// batchGetItems returns the items found by a BatchGetItem request, resubmitting any keys DynamoDB leaves
+// unprocessed with exponential backoff and the options of the original request. It gives up once the statement has
+// retried batchGetRetries times, counted by retries, or would have to wait past the deadline of the context, and
+// returns an error naming the keys left unread.
+func batchGetItems(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, req *dynamodb.BatchGetItemInput, retries *int) ([]map[string]*dynamodb.AttributeValue, error) {
+	var items []map[string]*dynamodb.AttributeValue
+	backoff := batchGetBackoff
+	for {
+		resp, err := dynamo.BatchGetItemWithContext(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, found := range resp.Responses {
+			items = append(items, found...)
+		}
+		if len(resp.UnprocessedKeys) == 0 {
+			return items, nil
+		}
+		if *retries == batchGetRetries {
+			return nil, unreadKeysError(resp.UnprocessedKeys, nil)
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			return nil, unreadKeysError(resp.UnprocessedKeys, context.DeadlineExceeded)
+		}
+		req = retryBatchGet(req, resp.UnprocessedKeys)
+		// Unprocessed keys are usually the result of throttling, so back off before retrying them.
+		select {
+		case <-ctx.Done():
+			return nil, unreadKeysError(resp.UnprocessedKeys, ctx.Err())
+		case <-time.After(backoff):
+		}
+		*retries++
+		backoff *= 2
+	}
+}
+
+// retryBatchGet returns a copy of req that reads the unprocessed keys, with the projection and consistency of the
+// tables kept.
+func retryBatchGet(req *dynamodb.BatchGetItemInput, unprocessed map[string]*dynamodb.KeysAndAttributes) *dynamodb.BatchGetItemInput {
+	retry := *req
+	retry.RequestItems = make(map[string]*dynamodb.KeysAndAttributes, len(unprocessed))
+	for table, unread := range unprocessed {
+		ka := *unread
+		if orig, ok := req.RequestItems[table]; ok {
+			ka = *orig
+			ka.Keys = unread.Keys
+		}
+		retry.RequestItems[table] = &ka
+	}
+	return &retry
+}
+
+// unreadKeysError returns the error of a BatchGetItem that left keys unprocessed, wrapping the error of the context
+// if it was done before they could be retried.
+func unreadKeysError(unprocessed map[string]*dynamodb.KeysAndAttributes, err error) error {
+	var keys []map[string]*dynamodb.AttributeValue
+	for _, table := range unprocessed {
+		keys = append(keys, table.Keys...)
+	}
+	msg := fmt.Sprintf("BatchGetItem left %d keys unread: %s", len(keys), querybuilder.FormatKeys(keys))
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return errors.New(msg)
+}
+
+// fetchFunc retrieves the page of results starting at lastEvaluatedKey, or the first page if it is nil.
+type fetchFunc func(ctx context.Context, lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*dynamodb.QueryOutput, error)
+
+// newFetcher binds the arguments into a Query, Scan or GetItem request, depending on how the statement was
+// prepared. Scan and GetItem results are returned as a dynamodb.QueryOutput, which has the same shape, so that rows
+// can page through all of them.
+func (s *queryStmt) newFetcher(dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (fetchFunc, error) {
+	q := s.preparedStmt
+	if q.GetItem != nil {
+		req, err := q.NewGetItemRequest(args)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*dynamodb.QueryOutput, error) {
+			resp, err := dynamo.GetItemWithContext(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			// A GetItem is a single page, which is empty if the item does not exist.
+			out := &dynamodb.QueryOutput{ConsumedCapacity: resp.ConsumedCapacity, Count: aws.Int64(0)}
+			if resp.Item != nil {
+				out.Items = []map[string]*dynamodb.AttributeValue{resp.Item}
+				out.Count = aws.Int64(1)
+			}
+			return out, nil
+		}, nil
+	}
+	if q.Scan != nil {
+		req, err := q.NewScanRequest(args)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*dynamodb.QueryOutput, error) {
+			req.ExclusiveStartKey = lastEvaluatedKey
+			resp, err := dynamo.ScanWithContext(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return &dynamodb.QueryOutput{
+				ConsumedCapacity: resp.ConsumedCapacity,
+				Count:            resp.Count,
+				Items:            resp.Items,
+				LastEvaluatedKey: resp.LastEvaluatedKey,
+				ScannedCount:     resp.ScannedCount,
+			}, nil
+		}, nil
+	}
+	req, err := q.NewRequest(args)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*dynamodb.QueryOutput, error) {
+		req.ExclusiveStartKey = lastEvaluatedKey
+		return dynamo.QueryWithContext(ctx, req)
+	}, nil
+}
+
+// insertSelectStmt is an INSERT ... SELECT, which writes the items read by its SELECT as they are paged in.
+// insertSelectStmt reports the capacity consumed by both its reads and its writes.
+type insertSelectStmt struct {
+	legacyStmtMixin
+	stmtConfig
+	preparedStmt *querybuilder.PreparedInsertSelect
+	numInput     int
+}
+
+func (s *insertSelectStmt) NumInput() int {
+	return s.numInput
+}
+
+// ExecContext writes the items in batches of querybuilder.MaxBatchWriteItems as they are read. The statement is not
+// atomic: if it fails, the batches written before the error are kept.
+func (s *insertSelectStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	p := s.preparedStmt
+	dynamo, capacity := withConsumedCapacity(s.dynamo, s.returnCapacity)
+	source := &queryStmt{preparedStmt: p.Source, stmtConfig: stmtConfig{dynamo: dynamo}}
+	r, err := source.QueryContext(ctx, args)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	items := r.(*rows)
+	defer items.Close()
+	count := 0
+	batch := make([]map[string]*dynamodb.AttributeValue, 0, querybuilder.MaxBatchWriteItems)
+	write := func() error {
+		if err := p.Insert.Write(ctx, dynamo, batch); err != nil {
+			var unprocessed *querybuilder.UnprocessedItemsError
+			if errors.As(err, &unprocessed) {
+				// Also count the items written by the previous batches.
+				unprocessed.Written += count
+			}
+			return translateError(err)
+		}
+		count += len(batch)
+		batch = make([]map[string]*dynamodb.AttributeValue, 0, querybuilder.MaxBatchWriteItems)
+		return nil
+	}
+	for {
+		item, err := items.nextItem()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, translateError(err)
+		}
+		batch = append(batch, insertSelectItem(p, item))
+		if len(batch) == querybuilder.MaxBatchWriteItems {
+			if err := write(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := write(); err != nil {
+		return nil, err
+	}
+	var result driver.Result = querybuilder.NewDriverResult(count)
+	if capacity != nil {
+		result = &capacityResult{Result: result, capacity: capacity}
+	}
+	return result, nil
+}
+
+func (s *insertSelectStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return nil, errors.New("INSERT ... SELECT does not return rows, use Exec() instead of Query()")
+}
+
+// insertSelectItem returns the item to write for an item read by INSERT ... SELECT. Each column is written to its
+// attribute, and columns the item has no value for are left out.
+func insertSelectItem(p *querybuilder.PreparedInsertSelect, item map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue {
+	if len(p.Attributes) == 0 {
+		return item
+	}
+	out := make(map[string]*dynamodb.AttributeValue, len(p.Attributes))
+	for i, col := range p.Source.Columns {
+		if av := columnAttributeValue(col, item); av != nil {
+			out[p.Attributes[i]] = av
+		}
+	}
+	return out
+}
+
+// rowsStmt is a statement that returns a fixed set of string rows, such as DESCRIBE and SHOW TABLES.
+type rowsStmt struct {
+	legacyStmtMixin
+	// name of the statement, for errors.
+	name   string
+	rows   func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) ([][]string, error)
+	cols   []string
+	dynamo dynamodbiface.DynamoDBAPI
+}
+
+func (s *rowsStmt) NumInput() int {
+	return 0
+}
+
+func (s *rowsStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return nil, fmt.Errorf("%s returns rows, use Query() instead of Exec()", s.name)
+}
+
+func (s *rowsStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := s.rows(ctx, s.dynamo)
+	if err != nil {
+		return nil, err
+	}
+	return &stringRows{cols: s.cols, rows: rows}, nil
+}
+
 // wrapper type just for compile time type checking.
 type fullStmt interface {
 	driver.Stmt
@@ -90,13 +506,14 @@ type fullStmt interface {
 var (
 	_ fullStmt = &execStmt{}
 	_ fullStmt = &queryStmt{}
+	_ fullStmt = &insertSelectStmt{}
+	_ fullStmt = &rowsStmt{}
 )
 
 // mixin to provide no-op/panic implementations of useless db/sql methods
 type legacyStmtMixin struct{}
 
-func (d legacyStmtMixin) Close() error  { return nil }
-func (d legacyStmtMixin) NumInput() int { return -1 }
+func (d legacyStmtMixin) Close() error { return nil }
 func (d legacyStmtMixin) Exec(args []driver.Value) (driver.Result, error) {
 	panic("unexpected call to Exec")
 }
@@ -0,0 +1,96 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextCancellation(t *testing.T) {
+	tables := map[string]*dynamodb.CreateTableInput{
+		"items": {
+			TableName: aws.String("items"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		},
+	}
+	// endlessPages returns a page with no items and a LastEvaluatedKey on every call, like a Scan whose filter
+	// rejects everything, and cancels the context after the given number of pages. Like the SDK, it fails once the
+	// context is done.
+	endlessPages := func(cancel context.CancelFunc, cancelAfter int, calls *int) func(aws.Context) (*dynamodb.QueryOutput, error) {
+		return func(reqCtx aws.Context) (*dynamodb.QueryOutput, error) {
+			if _, ok := reqCtx.Deadline(); !ok {
+				t.Fatal("expected the request context to have the caller's deadline")
+			}
+			if err := reqCtx.Err(); err != nil {
+				return nil, err
+			}
+			*calls++
+			if *calls == cancelAfter {
+				cancel()
+			}
+			return &dynamodb.QueryOutput{
+				Count:            aws.Int64(0),
+				LastEvaluatedKey: map[string]*dynamodb.AttributeValue{"id": {N: aws.String("1")}},
+			}, nil
+		}
+	}
+
+	t.Run("Scan stops paging", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		calls := 0
+		pages := endlessPages(cancel, 3, &calls)
+		c := newMockConn(&mockDynamoDB{
+			tables: tables,
+			scan: func(ctx aws.Context, in *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+				page, err := pages(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return &dynamodb.ScanOutput{Count: page.Count, LastEvaluatedKey: page.LastEvaluatedKey}, nil
+			},
+		})
+		rows, err := c.QueryContext(ctx, "SELECT * FROM items WHERE size > 10", nil)
+		require.NoError(t, err)
+		defer rows.Close()
+		err = rows.Next(make([]driver.Value, 1))
+		require.Equal(t, context.Canceled, err)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("COUNT stops paging", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		calls := 0
+		pages := endlessPages(cancel, 2, &calls)
+		c := newMockConn(&mockDynamoDB{
+			tables: tables,
+			query: func(ctx aws.Context, in *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+				return pages(ctx)
+			},
+		})
+		_, err := c.QueryContext(ctx, `SELECT COUNT(*) FROM items WHERE id = 1`, nil)
+		require.Equal(t, context.Canceled, err)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("expired deadline aborts a write", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+		c := newMockConn(&mockDynamoDB{
+			tables: tables,
+			putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+				return nil, ctx.Err()
+			},
+		})
+		_, err := c.ExecContext(ctx, `REPLACE INTO items VALUES ({"id": 1})`, nil)
+		require.Equal(t, context.DeadlineExceeded, err)
+	})
+}
@@ -0,0 +1,166 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/mightyguava/dynamosql/querybuilder"
+)
+
+const (
+	// maxTransactItems is the maximum number of items in a single TransactWriteItems call.
+	maxTransactItems = 25
+	// maxTransactSize is the maximum aggregate size of the items in a single TransactWriteItems call.
+	maxTransactSize = 4 * 1024 * 1024
+)
+
+// tx buffers the writes executed on it and flushes them as a single TransactWriteItems call on Commit.
+type tx struct {
+	ctx   context.Context
+	conn  *conn
+	items []*dynamodb.TransactWriteItem
+	size  int
+}
+
+var _ driver.Tx = &tx{}
+
+func (t *tx) add(stmt querybuilder.ExecStmt, args []driver.NamedValue) (driver.Result, error) {
+	if t.conn.tx != t {
+		return nil, sql.ErrTxDone
+	}
+	tw, ok := stmt.(querybuilder.TransactWriteStmt)
+	if !ok {
+		return nil, errors.New("statement cannot be used in a transaction")
+	}
+	items, err := tw.TransactWriteItems(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(t.items)+len(items) > maxTransactItems {
+		return nil, fmt.Errorf("transaction exceeds the DynamoDB limit of %d items", maxTransactItems)
+	}
+	size := t.size
+	for _, item := range items {
+		size += transactWriteItemSize(item)
+	}
+	if size > maxTransactSize {
+		return nil, fmt.Errorf("transaction exceeds the DynamoDB limit of %d bytes", maxTransactSize)
+	}
+	t.items = append(t.items, items...)
+	t.size = size
+	return querybuilder.NewDriverResult(len(items)), nil
+}
+
+func (t *tx) Commit() error {
+	if t.conn.tx != t {
+		return sql.ErrTxDone
+	}
+	t.conn.tx = nil
+	if len(t.items) == 0 {
+		return nil
+	}
+	_, err := t.conn.dynamo.TransactWriteItemsWithContext(t.ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: t.items,
+	})
+	return translateError(err)
+}
+
+func (t *tx) Rollback() error {
+	if t.conn.tx != t {
+		return sql.ErrTxDone
+	}
+	t.conn.tx = nil
+	return nil
+}
+
+// transactWriteItemSize approximates the size DynamoDB counts towards the transaction limit for a write: its item or
+// key, and the expressions, attribute names and values sent with it, such as the large values of an UPDATE's SET.
+func transactWriteItemSize(item *dynamodb.TransactWriteItem) int {
+	switch {
+	case item.Put != nil:
+		put := item.Put
+		return attributeMapSize(put.Item) + expressionSize(put.ConditionExpression, put.ExpressionAttributeNames, put.ExpressionAttributeValues)
+	case item.Update != nil:
+		update := item.Update
+		return attributeMapSize(update.Key) + len(aws.StringValue(update.UpdateExpression)) +
+			expressionSize(update.ConditionExpression, update.ExpressionAttributeNames, update.ExpressionAttributeValues)
+	case item.Delete != nil:
+		del := item.Delete
+		return attributeMapSize(del.Key) + expressionSize(del.ConditionExpression, del.ExpressionAttributeNames, del.ExpressionAttributeValues)
+	case item.ConditionCheck != nil:
+		check := item.ConditionCheck
+		return attributeMapSize(check.Key) + expressionSize(check.ConditionExpression, check.ExpressionAttributeNames, check.ExpressionAttributeValues)
+	default:
+		return 0
+	}
+}
+
+// expressionSize is the size of an expression and the attribute names and values it refers to.
+func expressionSize(expr *string, names map[string]*string, values map[string]*dynamodb.AttributeValue) int {
+	size := len(aws.StringValue(expr)) + attributeMapSize(values)
+	for placeholder, name := range names {
+		size += len(placeholder) + len(aws.StringValue(name))
+	}
+	return size
+}
+
+func attributeMapSize(m map[string]*dynamodb.AttributeValue) int {
+	size := 0
+	for name, av := range m {
+		size += len(name) + attributeValueSize(av)
+	}
+	return size
+}
+
+// attributeValueSize follows the sizing rules in the DynamoDB developer guide. Numbers are counted by their
+// string length, which over-estimates slightly.
+func attributeValueSize(av *dynamodb.AttributeValue) int {
+	switch {
+	case av.S != nil:
+		return len(*av.S)
+	case av.N != nil:
+		return len(*av.N)
+	case av.B != nil:
+		return len(av.B)
+	case av.BOOL != nil, av.NULL != nil:
+		return 1
+	case av.SS != nil:
+		size := 0
+		for _, s := range av.SS {
+			size += len(*s)
+		}
+		return size
+	case av.NS != nil:
+		size := 0
+		for _, n := range av.NS {
+			size += len(*n)
+		}
+		return size
+	case av.BS != nil:
+		size := 0
+		for _, b := range av.BS {
+			size += len(b)
+		}
+		return size
+	case av.L != nil:
+		size := 3
+		for _, v := range av.L {
+			size += 1 + attributeValueSize(v)
+		}
+		return size
+	case av.M != nil:
+		size := 3
+		for name, v := range av.M {
+			size += 1 + len(name) + attributeValueSize(v)
+		}
+		return size
+	default:
+		return 0
+	}
+}
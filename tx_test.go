@@ -0,0 +1,192 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction(t *testing.T) {
+	tables := map[string]*dynamodb.CreateTableInput{
+		"movies": {
+			TableName: aws.String("movies"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("title"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		},
+	}
+	ctx := context.Background()
+	insert := func(title string) string {
+		return fmt.Sprintf(`INSERT INTO movies VALUES ('{"title":%q}')`, title)
+	}
+
+	t.Run("commit flushes writes in one call", func(t *testing.T) {
+		var calls []*dynamodb.TransactWriteItemsInput
+		c := newMockConn(&mockDynamoDB{tables: tables, transact: func(ctx aws.Context, in *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+			calls = append(calls, in)
+			return &dynamodb.TransactWriteItemsOutput{}, nil
+		}})
+		tx, err := c.BeginTx(ctx, driver.TxOptions{})
+		require.NoError(t, err)
+		for _, title := range []string{"Rush Hour", "Die Hard"} {
+			res, err := c.ExecContext(ctx, insert(title), nil)
+			require.NoError(t, err)
+			n, _ := res.RowsAffected()
+			require.Equal(t, int64(1), n)
+			_, err = res.LastInsertId()
+			require.Equal(t, ErrNoLastInsertID, err)
+		}
+		require.Empty(t, calls)
+		require.NoError(t, tx.Commit())
+		require.Len(t, calls, 1)
+		require.Len(t, calls[0].TransactItems, 2)
+		require.Equal(t, "Die Hard", *calls[0].TransactItems[1].Put.Item["title"].S)
+		require.Equal(t, sql.ErrTxDone, tx.Commit())
+	})
+
+	t.Run("rollback discards writes", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables})
+		tx, err := c.BeginTx(ctx, driver.TxOptions{})
+		require.NoError(t, err)
+		_, err = c.ExecContext(ctx, insert("Rush Hour"), nil)
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback())
+		require.Nil(t, c.tx)
+		require.Equal(t, sql.ErrTxDone, tx.Rollback())
+	})
+
+	t.Run("rejects SELECT", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables})
+		_, err := c.BeginTx(ctx, driver.TxOptions{})
+		require.NoError(t, err)
+		_, err = c.QueryContext(ctx, `SELECT * FROM movies WHERE title = "Rush Hour"`, nil)
+		require.EqualError(t, err, "SELECT is not supported in a transaction, DynamoDB transactions cannot mix reads and writes")
+	})
+
+	t.Run("rejects nested transactions", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables})
+		_, err := c.BeginTx(ctx, driver.TxOptions{})
+		require.NoError(t, err)
+		_, err = c.BeginTx(ctx, driver.TxOptions{})
+		require.EqualError(t, err, "a transaction is already in progress")
+	})
+
+	t.Run("rejects unsupported options", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables})
+		_, err := c.BeginTx(ctx, driver.TxOptions{ReadOnly: true})
+		require.EqualError(t, err, "read-only transactions are not supported")
+		_, err = c.BeginTx(ctx, driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelReadCommitted)})
+		require.EqualError(t, err, "isolation level Read Committed is not supported, DynamoDB transactions are serializable")
+	})
+
+	t.Run("enforces the item limit", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables})
+		_, err := c.BeginTx(ctx, driver.TxOptions{})
+		require.NoError(t, err)
+		for i := 0; i < 25; i++ {
+			_, err = c.ExecContext(ctx, insert(fmt.Sprint(i)), nil)
+			require.NoError(t, err)
+		}
+		_, err = c.ExecContext(ctx, insert("26"), nil)
+		require.EqualError(t, err, "transaction exceeds the DynamoDB limit of 25 items")
+	})
+
+	t.Run("enforces the size limit", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables})
+		_, err := c.BeginTx(ctx, driver.TxOptions{})
+		require.NoError(t, err)
+		doc := func(title string) []driver.NamedValue {
+			large := strings.Repeat("x", 1024*1024)
+			return []driver.NamedValue{{Ordinal: 1, Value: map[string]interface{}{"title": title, "data": large}}}
+		}
+		for i := 0; i < 3; i++ {
+			_, err = c.ExecContext(ctx, `INSERT INTO movies VALUES (?)`, doc(fmt.Sprint(i)))
+			require.NoError(t, err)
+		}
+		_, err = c.ExecContext(ctx, `INSERT INTO movies VALUES (?)`, doc("3"))
+		require.EqualError(t, err, "transaction exceeds the DynamoDB limit of 4194304 bytes")
+	})
+
+	t.Run("counts the values of updates towards the size limit", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables})
+		_, err := c.BeginTx(ctx, driver.TxOptions{})
+		require.NoError(t, err)
+		large := strings.Repeat("x", 1024*1024)
+		for i := 0; i < 3; i++ {
+			_, err = c.ExecContext(ctx, `UPDATE movies SET data = ? WHERE title = ?`, []driver.NamedValue{
+				{Ordinal: 1, Value: large},
+				{Ordinal: 2, Value: fmt.Sprint(i)},
+			})
+			require.NoError(t, err)
+		}
+		_, err = c.ExecContext(ctx, `DELETE FROM movies WHERE title = ? AND data = ?`, []driver.NamedValue{
+			{Ordinal: 1, Value: "3"},
+			{Ordinal: 2, Value: large},
+		})
+		require.EqualError(t, err, "transaction exceeds the DynamoDB limit of 4194304 bytes")
+	})
+
+	t.Run("returns the item of a failed condition", func(t *testing.T) {
+		existing := map[string]*dynamodb.AttributeValue{"title": {S: aws.String("Rush Hour")}, "year": {N: aws.String("1998")}}
+		var calls []*dynamodb.TransactWriteItemsInput
+		m := &mockDynamoDB{tables: tables, transact: func(ctx aws.Context, in *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+			calls = append(calls, in)
+			return nil, &dynamodb.TransactionCanceledException{
+				Message_: aws.String("Transaction cancelled"),
+				CancellationReasons: []*dynamodb.CancellationReason{
+					{Code: aws.String("None")},
+					{Code: aws.String("ConditionalCheckFailed"), Item: existing},
+				},
+			}
+		}}
+		connector, err := New(Config{DynamoDB: m, ReturnItemOnConditionFailure: true}).OpenConnector("")
+		require.NoError(t, err)
+		db := sql.OpenDB(connector)
+		defer db.Close()
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		for _, title := range []string{"Die Hard", "Rush Hour"} {
+			_, err = tx.ExecContext(ctx, insert(title))
+			require.NoError(t, err)
+		}
+		err = tx.Commit()
+		require.True(t, errors.Is(err, ErrConditionFailed), "%+v", err)
+		item, ok := ConditionFailedItem(err)
+		require.True(t, ok)
+		require.Equal(t, existing, item)
+		require.Len(t, calls, 1)
+		for _, item := range calls[0].TransactItems {
+			require.Equal(t, "ALL_OLD", *item.Put.ReturnValuesOnConditionCheckFailure)
+		}
+	})
+
+	t.Run("cancellations without an item fail with ErrConditionFailed", func(t *testing.T) {
+		cancelled := &dynamodb.TransactionCanceledException{
+			Message_:            aws.String("Transaction cancelled"),
+			CancellationReasons: []*dynamodb.CancellationReason{{Code: aws.String("ConditionalCheckFailed")}},
+		}
+		c := newMockConn(&mockDynamoDB{tables: tables, transact: func(ctx aws.Context, in *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+			require.Nil(t, in.TransactItems[0].Put.ReturnValuesOnConditionCheckFailure)
+			return nil, cancelled
+		}})
+		tx, err := c.BeginTx(ctx, driver.TxOptions{})
+		require.NoError(t, err)
+		_, err = c.ExecContext(ctx, insert("Rush Hour"), nil)
+		require.NoError(t, err)
+		err = tx.Commit()
+		require.True(t, errors.Is(err, ErrConditionFailed), "%+v", err)
+		var cancelErr *dynamodb.TransactionCanceledException
+		require.True(t, errors.As(err, &cancelErr))
+		require.Equal(t, cancelled, cancelErr)
+		_, ok := ConditionFailedItem(err)
+		require.False(t, ok)
+	})
+}
@@ -1,10 +1,15 @@
 package dynamosql
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 )
@@ -27,3 +32,228 @@ func (d documentScanner) Scan(src interface{}) error {
 	}
 	return dynamodbattribute.UnmarshalMap(srcMap, d.v)
 }
+
+// Set returns a sql.Scanner that scans a string, number or binary set into v, which must be a *[]string, *[]int64,
+// *[]float64 or *[][]byte. Sets are returned as []string or [][]byte, which database/sql can only scan into the
+// same type, so Set is needed to scan a number set into a slice of numbers. An absent attribute scans as a nil
+// slice.
+func Set(v interface{}) sql.Scanner {
+	return setScanner{v: v}
+}
+
+type setScanner struct {
+	v interface{}
+}
+
+func (s setScanner) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		if rv := reflect.ValueOf(s.v); rv.Kind() == reflect.Ptr && rv.Elem().Kind() == reflect.Slice {
+			rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+			return nil
+		}
+	case []string:
+		switch dest := s.v.(type) {
+		case *[]string:
+			*dest = src
+			return nil
+		case *[]int64:
+			out := make([]int64, len(src))
+			for i, n := range src {
+				v, err := strconv.ParseInt(n, 10, 64)
+				if err != nil {
+					return fmt.Errorf("dynamosql.Set(): cannot scan %q into an int64: %w", n, err)
+				}
+				out[i] = v
+			}
+			*dest = out
+			return nil
+		case *[]float64:
+			out := make([]float64, len(src))
+			for i, n := range src {
+				v, err := strconv.ParseFloat(n, 64)
+				if err != nil {
+					return fmt.Errorf("dynamosql.Set(): cannot scan %q into a float64: %w", n, err)
+				}
+				out[i] = v
+			}
+			*dest = out
+			return nil
+		}
+	case [][]byte:
+		if dest, ok := s.v.(*[][]byte); ok {
+			*dest = src
+			return nil
+		}
+	}
+	return fmt.Errorf("dynamosql.Set() cannot scan %s into %s", reflect.TypeOf(src), reflect.TypeOf(s.v))
+}
+
+// JSON returns a sql.Scanner that scans a map or list attribute, or a whole item, into v as JSON. v must be a *[]byte,
+// *json.RawMessage or *string. An absent attribute scans as a nil slice or empty string. See AttributeValueToJSON for
+// how attributes are rendered.
+func JSON(v interface{}) sql.Scanner {
+	return jsonScanner{v: v}
+}
+
+type jsonScanner struct {
+	v interface{}
+}
+
+func (j jsonScanner) Scan(src interface{}) error {
+	var (
+		data []byte
+		err  error
+	)
+	switch src := src.(type) {
+	case nil:
+	case map[string]*dynamodb.AttributeValue:
+		data, err = AttributeValueToJSON(&dynamodb.AttributeValue{M: src})
+	case []*dynamodb.AttributeValue:
+		data, err = AttributeValueToJSON(&dynamodb.AttributeValue{L: src})
+	case map[string]interface{}, []interface{}:
+		// Collections already converted by AlwaysConvertCollectionsToGoType.
+		data, err = json.Marshal(jsonNumbers(src))
+	default:
+		return fmt.Errorf("dynamosql.JSON() can only be used to Scan a map or list, not %s", reflect.TypeOf(src))
+	}
+	if err != nil {
+		return fmt.Errorf("dynamosql.JSON(): %w", err)
+	}
+	switch dest := j.v.(type) {
+	case *[]byte:
+		*dest = data
+	case *json.RawMessage:
+		*dest = data
+	case *string:
+		*dest = string(data)
+	default:
+		return fmt.Errorf("dynamosql.JSON() cannot scan into %s", reflect.TypeOf(j.v))
+	}
+	return nil
+}
+
+// jsonNumbers replaces the dynamodbattribute.Numbers of collections converted with Config.NumberAsString by
+// json.Numbers, which json.Marshal writes as numbers rather than strings.
+func jsonNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case dynamodbattribute.Number:
+		return json.Number(v)
+	case []dynamodbattribute.Number:
+		out := make([]json.Number, len(v))
+		for i, n := range v {
+			out[i] = json.Number(n)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			out[i] = jsonNumbers(elem)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			out[k] = jsonNumbers(elem)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// AttributeValueToJSON renders an attribute value as JSON. Maps are objects with sorted keys and lists are arrays.
+// Numbers are written exactly as DynamoDB stores them, binaries as base64 strings, and sets as arrays of their
+// elements.
+func AttributeValueToJSON(av *dynamodb.AttributeValue) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, av); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeJSON(buf *bytes.Buffer, av *dynamodb.AttributeValue) error {
+	switch {
+	case av == nil || av.NULL != nil:
+		buf.WriteString("null")
+	case av.S != nil:
+		return writeJSONValue(buf, *av.S)
+	case av.N != nil:
+		return writeJSONNumber(buf, *av.N)
+	case av.B != nil:
+		return writeJSONValue(buf, av.B)
+	case av.BOOL != nil:
+		buf.WriteString(strconv.FormatBool(*av.BOOL))
+	case av.M != nil:
+		keys := make([]string, 0, len(av.M))
+		for k := range av.M {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONValue(buf, k); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeJSON(buf, av.M[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case av.L != nil:
+		buf.WriteByte('[')
+		for i, elem := range av.L {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case av.SS != nil:
+		return writeJSONValue(buf, aws.StringValueSlice(av.SS))
+	case av.NS != nil:
+		buf.WriteByte('[')
+		for i, n := range av.NS {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONNumber(buf, aws.StringValue(n)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case av.BS != nil:
+		return writeJSONValue(buf, av.BS)
+	default:
+		// An attribute value with no type set, such as the zero value.
+		buf.WriteString("null")
+	}
+	return nil
+}
+
+func writeJSONValue(buf *bytes.Buffer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// writeJSONNumber writes a number unchanged, so that it keeps its precision, after checking that it is a valid JSON
+// number.
+func writeJSONNumber(buf *bytes.Buffer, n string) error {
+	var number json.Number
+	if err := json.Unmarshal([]byte(n), &number); err != nil || number.String() != n {
+		return fmt.Errorf("invalid number %q", n)
+	}
+	buf.WriteString(n)
+	return nil
+}
@@ -0,0 +1,156 @@
+package dynamosql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+func TestSetRoundTrip(t *testing.T) {
+	var stored map[string]*dynamodb.AttributeValue
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{
+			"items": {
+				TableName: aws.String("items"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				},
+			},
+		},
+		putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			stored = in.Item
+			return &dynamodb.PutItemOutput{}, nil
+		},
+		getItem: func(ctx aws.Context, in *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			return &dynamodb.GetItemOutput{Item: stored}, nil
+		},
+	}
+	db := sql.OpenDB(&connector{driver: &Driver{}, dynamo: m, tables: schema.NewTableLoader(m)})
+	defer db.Close()
+
+	type item struct {
+		PK      string    `dynamodbav:"pk"`
+		Tags    []string  `dynamodbav:"tags,stringset"`
+		Scores  []int64   `dynamodbav:"scores,numberset"`
+		Ratings []float64 `dynamodbav:"ratings,numberset"`
+		Blobs   [][]byte  `dynamodbav:"blobs"`
+	}
+	in := item{
+		PK:      "a",
+		Tags:    []string{"action", "comedy"},
+		Scores:  []int64{1, 20},
+		Ratings: []float64{2.5, 4},
+		Blobs:   [][]byte{[]byte("x"), []byte("y")},
+	}
+	_, err := db.Exec(`INSERT INTO items VALUES (?)`, in)
+	require.NoError(t, err)
+	require.NotNil(t, stored["tags"].SS)
+	require.NotNil(t, stored["scores"].NS)
+	require.NotNil(t, stored["blobs"].BS)
+
+	var (
+		tags    []string
+		scores  []int64
+		ratings []float64
+		blobs   [][]byte
+	)
+	row := db.QueryRow(`SELECT tags, scores, ratings, blobs FROM items WHERE pk = "a"`)
+	require.NoError(t, row.Scan(&tags, Set(&scores), Set(&ratings), &blobs))
+	require.Equal(t, in.Tags, tags)
+	require.Equal(t, in.Scores, scores)
+	require.Equal(t, in.Ratings, ratings)
+	require.Equal(t, in.Blobs, blobs)
+
+	// Number sets are returned as strings, to keep their precision.
+	var raw interface{}
+	require.NoError(t, db.QueryRow(`SELECT scores FROM items WHERE pk = "a"`).Scan(&raw))
+	require.Equal(t, []string{"1", "20"}, raw)
+
+	require.NoError(t, db.QueryRow(`SELECT tags, missing FROM items WHERE pk = "a"`).Scan(Set(&tags), Set(&scores)))
+	require.Equal(t, in.Tags, tags)
+	require.Nil(t, scores)
+
+	err = db.QueryRow(`SELECT ratings FROM items WHERE pk = "a"`).Scan(Set(&scores))
+	require.EqualError(t, err, `sql: Scan error on column index 0, name "ratings": dynamosql.Set(): cannot scan "2.5" into an int64: strconv.ParseInt: parsing "2.5": invalid syntax`)
+	err = db.QueryRow(`SELECT blobs FROM items WHERE pk = "a"`).Scan(Set(&tags))
+	require.EqualError(t, err, `sql: Scan error on column index 0, name "blobs": dynamosql.Set() cannot scan [][]uint8 into *[]string`)
+}
+
+func TestJSON(t *testing.T) {
+	var stored map[string]*dynamodb.AttributeValue
+	m := &mockDynamoDB{
+		tables: map[string]*dynamodb.CreateTableInput{
+			"items": {
+				TableName: aws.String("items"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				},
+			},
+		},
+		putItem: func(ctx aws.Context, in *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			stored = in.Item
+			return &dynamodb.PutItemOutput{}, nil
+		},
+		getItem: func(ctx aws.Context, in *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			return &dynamodb.GetItemOutput{Item: stored}, nil
+		},
+	}
+
+	// A document written as a literal reads back as the same JSON, with its keys sorted.
+	doc := `{"a":[1,-2.5,1234567,{"b":[[[],{}],null,true,"say \"hi\""]}],"c":{"d":{"e":{"f":[false,""]}}}}`
+	db := sql.OpenDB(&connector{driver: &Driver{}, dynamo: m, tables: schema.NewTableLoader(m)})
+	defer db.Close()
+	_, err := db.Exec(`INSERT INTO items VALUES ({"pk": "x", "doc": ` + doc + `})`)
+	require.NoError(t, err)
+
+	var (
+		raw  json.RawMessage
+		list []byte
+		item string
+	)
+	require.NoError(t, db.QueryRow(`SELECT doc, doc.a FROM items WHERE pk = "x"`).Scan(JSON(&raw), JSON(&list)))
+	require.Equal(t, doc, string(raw))
+	require.Equal(t, `[1,-2.5,1234567,{"b":[[[],{}],null,true,"say \"hi\""]}]`, string(list))
+	require.NoError(t, db.QueryRow(`SELECT * FROM items WHERE pk = "x"`).Scan(JSON(&item)))
+	require.Equal(t, `{"doc":`+doc+`,"pk":"x"}`, item)
+
+	// Sets are arrays and binaries base64.
+	stored["pk"] = &dynamodb.AttributeValue{S: aws.String("x")}
+	stored["doc"] = &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"ss": {SS: aws.StringSlice([]string{"a", "b"})},
+		"ns": {NS: aws.StringSlice([]string{"1", "2.5"})},
+		"bs": {BS: [][]byte{[]byte("hi")}},
+		"b":  {B: []byte("hi")},
+	}}
+	require.NoError(t, db.QueryRow(`SELECT doc FROM items WHERE pk = "x"`).Scan(JSON(&raw)))
+	require.Equal(t, `{"b":"aGk=","bs":["aGk="],"ns":[1,2.5],"ss":["a","b"]}`, string(raw))
+
+	// Collections converted to Go types render the same, including numbers kept as strings.
+	converted := sql.OpenDB(&connector{
+		driver: &Driver{}, dynamo: m, tables: schema.NewTableLoader(m), mapToGoType: true, numberAsString: true,
+	})
+	defer converted.Close()
+	stored["doc"] = &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+		"n":    {N: aws.String("12345678901234567890")},
+		"list": {L: []*dynamodb.AttributeValue{{NS: aws.StringSlice([]string{"3"})}, {S: aws.String("s")}}},
+	}}
+	require.NoError(t, converted.QueryRow(`SELECT doc FROM items WHERE pk = "x"`).Scan(JSON(&raw)))
+	require.Equal(t, `{"list":[[3],"s"],"n":12345678901234567890}`, string(raw))
+
+	// Absent attributes scan as nil, and scalars can't be scanned.
+	require.NoError(t, db.QueryRow(`SELECT missing FROM items WHERE pk = "x"`).Scan(JSON(&list)))
+	require.Nil(t, list)
+	err = db.QueryRow(`SELECT pk FROM items WHERE pk = "x"`).Scan(JSON(&raw))
+	require.EqualError(t, err, `sql: Scan error on column index 0, name "pk": dynamosql.JSON() can only be used to Scan a map or list, not string`)
+}
+
+func TestAttributeValueToJSON(t *testing.T) {
+	_, err := AttributeValueToJSON(&dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{{N: aws.String("0x10")}}})
+	require.EqualError(t, err, `invalid number "0x10"`)
+}
@@ -0,0 +1,175 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateExec(t *testing.T) {
+	tables := map[string]*dynamodb.CreateTableInput{
+		"movies": {
+			TableName: aws.String("movies"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("title"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		},
+	}
+	ctx := context.Background()
+	const appendTag = `UPDATE movies SET tags = list_append(tags, :tags) WHERE title = :title`
+	args := []driver.NamedValue{
+		{Name: "tags", Value: &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{{S: aws.String("action")}}}},
+		{Name: "title", Value: "Rush Hour"},
+	}
+
+	t.Run("updates the item with UpdateItem", func(t *testing.T) {
+		var updates []*dynamodb.UpdateItemInput
+		c := newMockConn(&mockDynamoDB{tables: tables, updateItem: func(ctx aws.Context, in *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			updates = append(updates, in)
+			return &dynamodb.UpdateItemOutput{}, nil
+		}})
+		res, err := c.ExecContext(ctx, appendTag, args)
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(1), n)
+		require.Len(t, updates, 1)
+		require.Equal(t, "SET tags = list_append(tags, :tags)", *updates[0].UpdateExpression)
+		require.Equal(t, "attribute_exists(title)", *updates[0].ConditionExpression)
+		require.Equal(t, "Rush Hour", *updates[0].Key["title"].S)
+		require.Len(t, updates[0].ExpressionAttributeValues, 1)
+	})
+
+	t.Run("missing items are not updated", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, updateItem: func(ctx aws.Context, in *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+		}})
+		res, err := c.ExecContext(ctx, appendTag, args)
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(0), n)
+	})
+
+	t.Run("RETURNING ALL_NEW returns the updated item", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, updateItem: func(ctx aws.Context, in *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			require.Equal(t, dynamodb.ReturnValueAllNew, *in.ReturnValues)
+			return &dynamodb.UpdateItemOutput{Attributes: map[string]*dynamodb.AttributeValue{
+				"title": {S: aws.String("Rush Hour")},
+				"tags":  {SS: aws.StringSlice([]string{"action"})},
+				"year":  {N: aws.String("1998")},
+			}}, nil
+		}})
+		rows, err := c.QueryContext(ctx, appendTag+" RETURNING ALL_NEW", args)
+		require.NoError(t, err)
+		require.Equal(t, []string{"tags", "title", "year"}, rows.Columns())
+		dest := make([]driver.Value, 3)
+		require.NoError(t, rows.Next(dest))
+		require.Equal(t, []driver.Value{[]string{"action"}, "Rush Hour", int64(1998)}, dest)
+		require.Equal(t, io.EOF, rows.Next(dest))
+	})
+
+	t.Run("RETURNING UPDATED_OLD returns only the updated attributes", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, updateItem: func(ctx aws.Context, in *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			require.Equal(t, dynamodb.ReturnValueUpdatedOld, *in.ReturnValues)
+			return &dynamodb.UpdateItemOutput{Attributes: map[string]*dynamodb.AttributeValue{"tags": {L: []*dynamodb.AttributeValue{}}}}, nil
+		}})
+		rows, err := c.QueryContext(ctx, appendTag+" RETURNING UPDATED_OLD", args)
+		require.NoError(t, err)
+		require.Equal(t, []string{"tags"}, rows.Columns())
+	})
+
+	t.Run("RETURNING on a missing item has no rows", func(t *testing.T) {
+		c := newMockConn(&mockDynamoDB{tables: tables, updateItem: func(ctx aws.Context, in *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+		}})
+		rows, err := c.QueryContext(ctx, appendTag+" RETURNING ALL_NEW", args)
+		require.NoError(t, err)
+		require.Empty(t, rows.Columns())
+		require.Equal(t, io.EOF, rows.Next(nil))
+	})
+
+	t.Run("updates are buffered in a transaction", func(t *testing.T) {
+		var calls []*dynamodb.TransactWriteItemsInput
+		c := newMockConn(&mockDynamoDB{tables: tables, transact: func(ctx aws.Context, in *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+			calls = append(calls, in)
+			return &dynamodb.TransactWriteItemsOutput{}, nil
+		}})
+		tx, err := c.BeginTx(ctx, driver.TxOptions{})
+		require.NoError(t, err)
+		_, err = c.ExecContext(ctx, appendTag, args)
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+		require.Len(t, calls, 1)
+		update := calls[0].TransactItems[0].Update
+		require.Equal(t, "SET tags = list_append(tags, :tags)", *update.UpdateExpression)
+		require.Equal(t, "Rush Hour", *update.Key["title"].S)
+	})
+
+	t.Run("a version check is part of the condition", func(t *testing.T) {
+		const update = `UPDATE movies SET plot = :plot, version = version + 1 WHERE title = :title AND version = :expected`
+		args := []driver.NamedValue{
+			{Name: "plot", Value: "A heist"},
+			{Name: "title", Value: "Heat"},
+			{Name: "expected", Value: 3},
+		}
+		current := "3"
+		m := &mockDynamoDB{tables: tables, updateItem: func(ctx aws.Context, in *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			require.Equal(t, map[string]*dynamodb.AttributeValue{"title": {S: aws.String("Heat")}}, in.Key)
+			require.Equal(t, "attribute_exists(title) AND version = :expected", *in.ConditionExpression)
+			if *in.ExpressionAttributeValues[":expected"].N != current {
+				return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+			}
+			current = "4"
+			return &dynamodb.UpdateItemOutput{}, nil
+		}}
+		c := newMockConn(m)
+
+		res, err := c.ExecContext(ctx, update, args)
+		require.NoError(t, err)
+		n, _ := res.RowsAffected()
+		require.Equal(t, int64(1), n)
+
+		// The second update expects the version the first one replaced.
+		_, err = c.ExecContext(ctx, update, args)
+		require.True(t, errors.Is(err, ErrConditionFailed), "%+v", err)
+	})
+
+	t.Run("ReturnItemOnConditionFailure returns the item of a failed version check", func(t *testing.T) {
+		existing := map[string]*dynamodb.AttributeValue{"title": {S: aws.String("Heat")}, "version": {N: aws.String("4")}}
+		m := &mockDynamoDB{tables: tables, updateItem: func(ctx aws.Context, in *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			require.Equal(t, "ALL_OLD", aws.StringValue(in.ReturnValuesOnConditionCheckFailure))
+			return nil, &dynamodb.ConditionalCheckFailedException{Message_: aws.String("The conditional request failed"), Item: existing}
+		}}
+		connector, err := New(Config{DynamoDB: m, ReturnItemOnConditionFailure: true}).OpenConnector("")
+		require.NoError(t, err)
+		c, err := connector.Connect(ctx)
+		require.NoError(t, err)
+		_, err = c.(driver.ExecerContext).ExecContext(ctx, `UPDATE movies SET plot = :plot WHERE title = :title AND version = :expected`, []driver.NamedValue{
+			{Name: "plot", Value: "A heist"},
+			{Name: "title", Value: "Heat"},
+			{Name: "expected", Value: 3},
+		})
+		require.True(t, errors.Is(err, ErrConditionFailed), "%+v", err)
+		item, ok := ConditionFailedItem(err)
+		require.True(t, ok)
+		require.Equal(t, existing, item)
+	})
+
+	t.Run("ErrorOnMissingItem fails on missing items", func(t *testing.T) {
+		m := &mockDynamoDB{tables: tables, updateItem: func(ctx aws.Context, in *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+		}}
+		connector, err := New(Config{DynamoDB: m, ErrorOnMissingItem: true}).OpenConnector("")
+		require.NoError(t, err)
+		c, err := connector.Connect(ctx)
+		require.NoError(t, err)
+		_, err = c.(driver.ExecerContext).ExecContext(ctx, appendTag, args)
+		require.True(t, errors.Is(err, ErrConditionFailed), "%+v", err)
+	})
+}